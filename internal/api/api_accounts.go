@@ -38,6 +38,10 @@ func (s *Server) getNonce(c echo.Context) error {
 		return err
 	}
 
+	if account.IsPlaceholder() {
+		return echo.ErrNotFound
+	}
+
 	resp := toNonceResponse(account)
 	return c.JSON(http.StatusOK, resp)
 }
@@ -60,11 +64,13 @@ func (s *Server) register(c echo.Context) error {
 	}
 
 	ctx := context.Background()
-	_, err = s.ds.Accounts.GetByAddress(ctx, address)
-	if err == nil {
+	// accounts which received a token on chain before signing up are kept
+	// as placeholders and get claimed here
+	placeholder, err := s.ds.Accounts.GetByAddress(ctx, address)
+	if err == nil && !placeholder.IsPlaceholder() {
 		return c.JSON(http.StatusPreconditionFailed, echo.Map{"message": ErrAddressAlreadyRegistered.Error()})
 	}
-	if err != datastore.ErrAccountNotFound {
+	if err != nil && err != datastore.ErrAccountNotFound {
 		return err
 	}
 
@@ -84,6 +90,19 @@ func (s *Server) register(c echo.Context) error {
 		time.Sleep(time.Millisecond * 200)
 	}
 
+	if placeholder != nil {
+		err = s.ds.Accounts.Update(ctx, placeholder, datastore.UpdateAccountFields{
+			Username:            pointer.ToString(username),
+			EncryptionPublicKey: pointer.ToString(req.EncryptionPublicKey),
+		})
+		if err != nil {
+			return err
+		}
+
+		resp := toRegisterResponse(placeholder)
+		return c.JSON(http.StatusOK, resp)
+	}
+
 	account := &model.Account{
 		Address:             address,
 		Username:            dbr.NewNullString(username),
@@ -123,6 +142,10 @@ func (s *Server) auth(c echo.Context) error {
 		return err
 	}
 
+	if account.IsPlaceholder() {
+		return c.JSON(http.StatusPreconditionFailed, echo.Map{"message": ErrAddressNotRegistered.Error()})
+	}
+
 	nonce := NoncePrefix + account.Nonce.String
 	pkHex, err := verifySignature(req.Signature, nonce, account.Address)
 	if err != nil {
@@ -0,0 +1,55 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/videocoin/marketplace/internal/listener"
+	"github.com/videocoin/marketplace/internal/network"
+)
+
+// EventReplayer processes the chain events of a block range once more.
+type EventReplayer interface {
+	Replay(ctx context.Context, from, to uint64) (int64, error)
+}
+
+func (s *Server) replayChainEvents(c echo.Context) error {
+	req := new(ReplayChainEventsRequest)
+	err := c.Bind(req)
+	if err != nil {
+		return echo.ErrBadRequest
+	}
+
+	if req.FromBlock > req.ToBlock {
+		return c.JSON(http.StatusPreconditionFailed, echo.Map{"message": listener.ErrInvalidBlockRange.Error()})
+	}
+
+	net, err := s.networks.Get(req.Network)
+	if err != nil {
+		return c.JSON(http.StatusPreconditionFailed, echo.Map{"message": err.Error()})
+	}
+
+	replayer, ok := s.replayers[net.Name]
+	if !ok {
+		return c.JSON(http.StatusPreconditionFailed, echo.Map{"message": network.ErrUnknownNetwork.Error()})
+	}
+
+	logger := s.logger.
+		WithField("network", net.Name).
+		WithField("from_block", req.FromBlock).
+		WithField("to_block", req.ToBlock)
+
+	count, err := replayer.Replay(context.Background(), req.FromBlock, req.ToBlock)
+	if err != nil {
+		if err == listener.ErrInvalidBlockRange {
+			return c.JSON(http.StatusPreconditionFailed, echo.Map{"message": err.Error()})
+		}
+		logger.WithError(err).Error("failed to replay chain events")
+		return err
+	}
+
+	logger.WithField("events", count).Info("chain events have been scheduled for replay")
+
+	return c.JSON(http.StatusOK, &ReplayChainEventsResponse{Events: count})
+}
@@ -1,21 +1,15 @@
 package api
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
-	"errors"
-	"fmt"
-	"math/big"
 	"net/http"
 	"strconv"
 	"strings"
 
-	"github.com/ethereum/go-ethereum/common"
 	"github.com/gocraft/dbr/v2"
 	"github.com/labstack/echo/v4"
 	"github.com/videocoin/marketplace/internal/drm"
-	"github.com/videocoin/marketplace/internal/token"
 	pkgyt "github.com/videocoin/marketplace/pkg/youtube"
 
 	"github.com/AlekSi/pointer"
@@ -39,37 +33,62 @@ func (s *Server) createAsset(c echo.Context) error {
 		return echo.ErrBadRequest
 	}
 
-	if req.Media == nil || len(req.Media) == 0 {
-		return c.JSON(http.StatusPreconditionFailed, echo.Map{"message": "invalid media"})
+	ctx := context.Background()
+
+	asset, err := s.newAsset(ctx, account, req)
+	if err != nil {
+		return err
 	}
 
-	mediaIds := make([]string, 0)
-	for _, media := range req.Media {
-		mediaIds = append(mediaIds, media.ID)
+	err = s.publishAssetMedia(asset)
+	if err != nil {
+		return err
+	}
+
+	err = s.ds.InTx(ctx, func(ctx context.Context) error {
+		return s.saveAsset(ctx, asset)
+	})
+	if err != nil {
+		logger.WithError(err).Error("failed to create asset")
+		return err
+	}
+
+	logger.
+		WithField("asset_id", asset.ID).
+		WithField("job_id", asset.JobID.String).
+		Info("asset process job has been queued")
+
+	resp := toAssetResponse(asset)
+	return c.JSON(http.StatusOK, resp)
+}
+
+// newAsset validates the request and builds the asset it creates.
+func (s *Server) newAsset(ctx context.Context, account *model.Account, req *CreateAssetRequest) (*model.Asset, error) {
+	if req.Media == nil || len(req.Media) == 0 {
+		return nil, echo.NewHTTPError(http.StatusPreconditionFailed, "invalid media")
 	}
 
-	ctx := context.Background()
 	mediaItems := make([]*model.Media, 0)
 
 	for _, mediaItem := range req.Media {
 		if mediaItem.ID == "" {
-			return c.JSON(http.StatusPreconditionFailed, echo.Map{"message": "media not found"})
+			return nil, echo.NewHTTPError(http.StatusPreconditionFailed, "media not found")
 		}
 		media, err := s.ds.Media.GetByID(ctx, mediaItem.ID)
 		if err != nil {
 			if err == datastore.ErrMediaNotFound {
-				return c.JSON(http.StatusPreconditionFailed, echo.Map{"message": "media not found"})
+				return nil, echo.NewHTTPError(http.StatusPreconditionFailed, "media not found")
 			}
 
-			return err
+			return nil, err
 		}
 
 		if media.CreatedByID != account.ID {
-			return c.JSON(http.StatusPreconditionFailed, echo.Map{"message": "media file not found"})
+			return nil, echo.NewHTTPError(http.StatusPreconditionFailed, "media file not found")
 		}
 
 		if media.Status != model.MediaStatusReady || media.AssetID.Int64 != 0 {
-			return c.JSON(http.StatusPreconditionFailed, echo.Map{"message": "media not available"})
+			return nil, echo.NewHTTPError(http.StatusPreconditionFailed, "media not available")
 		}
 
 		mediaItems = append(mediaItems, media)
@@ -77,7 +96,7 @@ func (s *Server) createAsset(c echo.Context) error {
 
 	assetName := strings.TrimSpace(req.Name)
 	if assetName == "" {
-		return c.JSON(http.StatusPreconditionFailed, echo.Map{"message": "missing name"})
+		return nil, echo.NewHTTPError(http.StatusPreconditionFailed, "missing name")
 	}
 
 	assetDesc := ""
@@ -87,30 +106,60 @@ func (s *Server) createAsset(c echo.Context) error {
 
 	ytLink := ""
 	if req.YTVideoLink != nil && *req.YTVideoLink != "" {
+		var err error
 		ytLink, err = pkgyt.ValidateVideoURL(*req.YTVideoLink)
 		if err != nil {
-			return c.JSON(http.StatusPreconditionFailed, echo.Map{"message": "wrong youtube link"})
+			return nil, echo.NewHTTPError(http.StatusPreconditionFailed, "wrong youtube link")
 		}
 	}
 
-	drmKey, drmMeta, err := drm.GenerateDRMKey(account.EncryptionPublicKey.String)
+	if req.Supply < 0 {
+		return nil, echo.NewHTTPError(http.StatusPreconditionFailed, "invalid supply")
+	}
+
+	if req.Royalty > model.MaxRoyalty {
+		return nil, echo.NewHTTPError(http.StatusPreconditionFailed, "invalid royalty")
+	}
+
+	net, err := s.networks.Get(req.Network)
 	if err != nil {
-		logger.WithError(err).Error("failed to generate drm key")
-		return echo.ErrInternalServerError
+		return nil, echo.NewHTTPError(http.StatusPreconditionFailed, err.Error())
 	}
 
-	drmMetaJSON, _ := json.Marshal(drmMeta)
+	m, err := s.minters.Get(net.Name)
+	if err != nil {
+		return nil, err
+	}
 
-	if !req.Locked {
-		for _, media := range mediaItems {
-			logger.Infof("mark object %s as public", media.Key)
-			err = s.storage.MakePublic(media.Key)
-			if err != nil {
-				return err
-			}
+	schema := model.ContractSchemaTypeERC721
+	contractAddress := m.ContractAddress()
+	if req.Supply > 1 {
+		if !m.SupportsERC1155() {
+			return nil, echo.NewHTTPError(http.StatusPreconditionFailed, "editions are not supported")
 		}
+
+		schema = model.ContractSchemaTypeERC1155
+		contractAddress = m.ERC1155ContractAddress()
+	}
+
+	if req.LazyMint {
+		if schema == model.ContractSchemaTypeERC1155 {
+			return nil, echo.NewHTTPError(http.StatusPreconditionFailed, "lazy minting is not supported for editions")
+		}
+
+		if !m.SupportsLazyMint() {
+			return nil, echo.NewHTTPError(http.StatusPreconditionFailed, "lazy minting is not supported")
+		}
+	}
+
+	drmKey, drmMeta, err := drm.GenerateDRMKey(account.EncryptionPublicKey.String)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to generate drm key")
+		return nil, echo.ErrInternalServerError
 	}
 
+	drmMetaJSON, _ := json.Marshal(drmMeta)
+
 	asset := &model.Asset{
 		CreatedByID: account.ID,
 		OwnerID:     account.ID,
@@ -123,115 +172,90 @@ func (s *Server) createAsset(c echo.Context) error {
 
 		DRMKey:  drmKey,
 		DRMMeta: string(drmMetaJSON),
+		DRMKID:  dbr.NewNullString(drmMeta.KID),
 
-		ContractAddress: dbr.NewNullString(strings.ToLower(s.minter.ContractAddress().Hex())),
+		ContractAddress: dbr.NewNullString(strings.ToLower(contractAddress.Hex())),
+		Network:         net.Name,
+		LazyMint:        req.LazyMint,
+		Schema:          schema,
+		Supply:          req.Supply,
 		OnSale:          false,
 		Royalty:         req.Royalty,
 		Price:           req.InstantSalePrice,
 		PutOnSalePrice:  dbr.NewNullFloat64(req.PutOnSalePrice),
 		CurrentBid:      dbr.NewNullFloat64(req.PutOnSalePrice),
-	}
 
-	err = s.ds.Assets.Create(ctx, asset)
-	if err != nil {
-		return err
+		Media:     mediaItems,
+		CreatedBy: account,
+		Owner:     account,
 	}
 
-	err = s.ds.Media.BindToAsset(ctx, mediaIds, asset.ID)
-	if err != nil {
-		return err
-	}
+	return asset, nil
+}
 
-	asset.Media = mediaItems
-	asset.CreatedBy = account
-	asset.Owner = account
-
-	go func() {
-		if asset.Locked {
-			for _, media := range mediaItems {
-				media.CreatedBy = asset.CreatedBy
-
-				if media.Featured {
-					continue
-				}
-
-				err = s.mp.EncryptMedia(ctx, media, drmMeta)
-				if err != nil {
-					logger.
-						WithError(err).
-						Error("failed to encrypt media")
-					_ = s.ds.Assets.MarkStatusAsFailed(context.Background(), asset)
-					return
-				}
-			}
-		}
+// publishAssetMedia makes the media of an unlocked asset public, the media
+// of locked assets stays private.
+func (s *Server) publishAssetMedia(asset *model.Asset) error {
+	if asset.Locked {
+		return nil
+	}
 
-		tokenURI := pointer.ToString("")
-		tokenJSON, _ := token.ToTokenJSON(asset)
-		tokenCID, err := s.storage.PushPath(
-			fmt.Sprintf("%d.json", asset.ID),
-			bytes.NewBuffer(tokenJSON),
-			true,
-		)
+	for _, media := range asset.Media {
+		s.logger.Infof("mark object %s as public", media.Key)
+		err := s.storage.MakePublic(media.Key)
 		if err != nil {
-			logger.WithError(err).Error("failed to upload token json to storage")
-			return
+			return err
 		}
+	}
 
-		logger := s.logger.WithField("token_cid", tokenCID)
-		logger.Info("updating token url")
+	return nil
+}
 
-		err = s.ds.Assets.Update(ctx, asset, datastore.AssetUpdatedFields{
-			TokenCID: pointer.ToString(tokenCID),
-		})
-		if err != nil {
-			logger.WithError(err).Error("failed to update asset token cid")
-			return
-		}
+// saveAsset creates the asset with its media and queues its processing.
+func (s *Server) saveAsset(ctx context.Context, asset *model.Asset) error {
+	err := s.ds.Assets.Create(ctx, asset)
+	if err != nil {
+		return err
+	}
 
-		tokenURI = asset.GetTokenUrl()
-		if tokenURI == nil {
-			logger.WithError(err).Error("failed to get asset token uri")
-			return
-		}
+	err = s.ds.AssetHolders.Credit(ctx, asset.ID, asset.CreatedByID, asset.Supply)
+	if err != nil {
+		return err
+	}
 
-		logger = logger.WithField("token_uri", tokenURI)
-		logger.Info("minting")
+	err = s.ds.AssetKeyEnvelopes.Put(ctx, &model.AssetKeyEnvelope{
+		AssetID:   asset.ID,
+		AccountID: asset.OwnerID,
+		Role:      model.KeyEnvelopeRoleOwner,
+		Envelope:  asset.DRMKey,
+	})
+	if err != nil {
+		return err
+	}
 
-		mintTx, err := s.minter.Mint(
-			ctx,
-			common.HexToAddress(account.Address),
-			big.NewInt(asset.ID),
-			*tokenURI,
-		)
-		if err != nil {
-			logger.WithError(err).Error("failed to mint")
-			return
-		}
+	mediaIds := make([]string, 0, len(asset.Media))
+	for _, media := range asset.Media {
+		mediaIds = append(mediaIds, media.ID)
+	}
 
-		if mintTx == nil {
-			logger.WithError(errors.New("mint tx is nil")).Error("failed to mint")
-		}
+	err = s.ds.Media.BindToAsset(ctx, mediaIds, asset.ID)
+	if err != nil {
+		return err
+	}
 
-		err = s.ds.Assets.Update(ctx, asset, datastore.AssetUpdatedFields{
-			MintTxID: pointer.ToString(mintTx.Hash().Hex()),
-		})
-		if err != nil {
-			logger.WithError(err).Error("failed to update mint tx id")
-			return
-		}
+	job, err := model.NewAssetProcessJob(asset)
+	if err != nil {
+		return err
+	}
 
-		err = s.ds.Assets.Update(ctx, asset, datastore.AssetUpdatedFields{
-			Status: pointer.ToString(string(model.MediaStatusReady)),
-		})
-		if err != nil {
-			logger.WithError(err).Error("failed to mark asset as ready")
-			return
-		}
-	}()
+	err = s.ds.Jobs.Create(ctx, job)
+	if err != nil {
+		return err
+	}
 
-	resp := toAssetResponse(asset)
-	return c.JSON(http.StatusOK, resp)
+	return s.ds.Assets.Update(ctx, asset, datastore.AssetUpdatedFields{
+		JobID: pointer.ToString(job.ID),
+	})
 }
 
 func (s *Server) getAssets(c echo.Context) error {
@@ -247,6 +271,23 @@ func (s *Server) getAssets(c echo.Context) error {
 		},
 	}
 
+	if network := c.FormValue("network"); network != "" {
+		fltr.Network = pointer.ToString(network)
+	}
+
+	if minted, err := strconv.ParseBool(c.FormValue("minted")); err == nil {
+		fltr.Minted = pointer.ToBool(minted)
+	}
+
+	orderBy := c.FormValue("order_by")
+	switch orderBy {
+	case "created_at", "price", "current_price":
+		fltr.Sort.Field = orderBy
+	}
+	if c.FormValue("order_direction") == "asc" {
+		fltr.Sort.IsAsc = true
+	}
+
 	ctx := context.Background()
 	assets, err := s.ds.GetAssetsList(ctx, fltr, limitOpts)
 	if err != nil {
@@ -351,10 +392,45 @@ func (s *Server) getAsset(c echo.Context) error {
 
 	asset.Media = media
 
+	auction, err := s.ds.Auctions.GetLatestByAssetID(ctx, asset.ID)
+	if err != nil && err != datastore.ErrAuctionNotFound {
+		return err
+	}
+	asset.Auction = auction
+
+	if asset.IsEdition() {
+		err = s.joinHoldersToAsset(ctx, asset)
+		if err != nil {
+			return err
+		}
+	}
+
 	resp := toAssetResponse(asset)
 	return c.JSON(http.StatusOK, resp)
 }
 
+func (s *Server) joinHoldersToAsset(ctx context.Context, asset *model.Asset) error {
+	holders, err := s.ds.AssetHolders.ListByAssetID(ctx, asset.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, holder := range holders {
+		account, err := s.ds.Accounts.GetByID(ctx, holder.AccountID)
+		if err != nil {
+			if err == datastore.ErrAccountNotFound {
+				continue
+			}
+			return err
+		}
+		holder.Account = account
+	}
+
+	asset.Holders = holders
+
+	return nil
+}
+
 func (s *Server) getAssetByContractAddressAndTokenID(c echo.Context) error {
 	ctx := context.Background()
 
@@ -401,6 +477,13 @@ func (s *Server) getAssetByContractAddressAndTokenID(c echo.Context) error {
 
 	asset.Media = media
 
+	if asset.IsEdition() {
+		err = s.joinHoldersToAsset(ctx, asset)
+		if err != nil {
+			return err
+		}
+	}
+
 	resp := toAssetResponse(asset)
 	return c.JSON(http.StatusOK, resp)
 }
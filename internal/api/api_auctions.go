@@ -0,0 +1,71 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/videocoin/marketplace/internal/datastore"
+	"github.com/videocoin/marketplace/internal/model"
+)
+
+func (s *Server) getAssetAuction(c echo.Context) error {
+	ctx := context.Background()
+
+	assetID, _ := strconv.ParseInt(c.Param("asset_id"), 10, 64)
+	if assetID == 0 {
+		return echo.ErrNotFound
+	}
+
+	auction, err := s.auctions.Get(ctx, assetID)
+	if err != nil {
+		if err == datastore.ErrAuctionNotFound {
+			return echo.ErrNotFound
+		}
+		return err
+	}
+
+	bidders := map[int64]*model.Account{}
+	for _, bid := range auction.Bids {
+		bidder, ok := bidders[bid.BidderID]
+		if !ok {
+			bidder, err = s.ds.Accounts.GetByID(ctx, bid.BidderID)
+			if err != nil && err != datastore.ErrAccountNotFound {
+				return err
+			}
+			bidders[bid.BidderID] = bidder
+		}
+		bid.Bidder = bidder
+	}
+
+	var winningOrder *model.Order
+	var tokensByAddress map[string]*model.Token
+
+	var winner *model.AuctionBid
+	for _, bid := range auction.Bids {
+		if auction.WinningBidID.Valid && auction.WinningBidID.Int64 == bid.ID {
+			winner = bid
+		}
+	}
+
+	if winner != nil {
+		orders, err := s.ds.GetOrderList(ctx, &datastore.OrderFilter{Ids: []int64{winner.OrderID}}, nil)
+		if err != nil {
+			return err
+		}
+
+		if len(orders) > 0 {
+			winningOrder = orders[0]
+		}
+
+		tokens, _ := s.ds.Tokens.List(ctx, nil, nil)
+		tokensByAddress = map[string]*model.Token{}
+		for _, token := range tokens {
+			tokensByAddress[token.Address] = token
+		}
+	}
+
+	resp := toAuctionResponse(auction, winningOrder, tokensByAddress)
+	return c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,213 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/videocoin/marketplace/internal/datastore"
+	"github.com/videocoin/marketplace/internal/model"
+)
+
+// createAssetsBatch creates the assets of a drop in a single transaction.
+// Their tokens are minted together once all of them have been processed.
+func (s *Server) createAssetsBatch(c echo.Context) error {
+	ctxAccount := c.Get("account")
+	account := ctxAccount.(*model.Account)
+
+	logger := s.logger.
+		WithField("account_id", account.ID).
+		WithField("address", account.Address)
+	logger.Info("creating assets batch")
+
+	req := new(CreateAssetsBatchRequest)
+	err := c.Bind(req)
+	if err != nil {
+		logger.WithError(err).Warning("failed to bind request")
+		return echo.ErrBadRequest
+	}
+
+	if len(req.Items) == 0 {
+		return echo.NewHTTPError(http.StatusPreconditionFailed, "missing items")
+	}
+
+	if len(req.Items) > model.MaxAssetBatchSize {
+		return echo.NewHTTPError(
+			http.StatusPreconditionFailed,
+			fmt.Sprintf("too many items, at most %d are allowed", model.MaxAssetBatchSize),
+		)
+	}
+
+	net, err := s.networks.Get(req.Network)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusPreconditionFailed, err.Error())
+	}
+
+	ctx := context.Background()
+
+	assets := make([]*model.Asset, 0, len(req.Items))
+	mediaIds := map[string]bool{}
+	for i, item := range req.Items {
+		if item == nil {
+			return echo.NewHTTPError(http.StatusPreconditionFailed, fmt.Sprintf("item %d: invalid item", i))
+		}
+
+		if item.Network != "" && item.Network != net.Name {
+			return echo.NewHTTPError(http.StatusPreconditionFailed, fmt.Sprintf("item %d: network mismatch", i))
+		}
+		item.Network = net.Name
+
+		for _, media := range item.Media {
+			if media == nil || media.ID == "" {
+				continue
+			}
+			if mediaIds[media.ID] {
+				return echo.NewHTTPError(http.StatusPreconditionFailed, fmt.Sprintf("item %d: media used twice", i))
+			}
+			mediaIds[media.ID] = true
+		}
+
+		asset, err := s.newAsset(ctx, account, item)
+		if err != nil {
+			if httpErr, ok := err.(*echo.HTTPError); ok && httpErr.Code == http.StatusPreconditionFailed {
+				return echo.NewHTTPError(httpErr.Code, fmt.Sprintf("item %d: %v", i, httpErr.Message))
+			}
+			return err
+		}
+
+		assets = append(assets, asset)
+	}
+
+	for _, asset := range assets {
+		err = s.publishAssetMedia(asset)
+		if err != nil {
+			return err
+		}
+	}
+
+	batch := &model.AssetBatch{
+		CreatedByID: account.ID,
+		Network:     net.Name,
+		Size:        len(assets),
+		Assets:      assets,
+	}
+
+	err = s.ds.InTx(ctx, func(ctx context.Context) error {
+		err := s.ds.AssetBatches.Create(ctx, batch)
+		if err != nil {
+			return err
+		}
+
+		for _, asset := range assets {
+			asset.BatchID.Int64 = batch.ID
+			asset.BatchID.Valid = true
+
+			err = s.saveAsset(ctx, asset)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		logger.WithError(err).Error("failed to create assets batch")
+		return err
+	}
+
+	logger.
+		WithField("batch_id", batch.ID).
+		WithField("size", batch.Size).
+		Info("assets batch has been queued")
+
+	resp, err := s.toAssetBatchResponse(ctx, batch)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// getAssetsBatch reports the progress of every item of the batch.
+func (s *Server) getAssetsBatch(c echo.Context) error {
+	ctxAccount := c.Get("account")
+	account := ctxAccount.(*model.Account)
+
+	batchID, err := strconv.ParseInt(c.Param("batch_id"), 10, 64)
+	if err != nil {
+		return echo.ErrBadRequest
+	}
+
+	ctx := context.Background()
+
+	batch, err := s.ds.AssetBatches.GetByID(ctx, batchID)
+	if err != nil {
+		if err == datastore.ErrAssetBatchNotFound {
+			return echo.ErrNotFound
+		}
+		return err
+	}
+
+	if batch.CreatedByID != account.ID {
+		return echo.ErrNotFound
+	}
+
+	batch.Assets, err = s.ds.Assets.List(ctx, &datastore.AssetsFilter{
+		BatchID: &batch.ID,
+		Sort:    &datastore.SortOption{Field: "id", IsAsc: true},
+	}, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.toAssetBatchResponse(ctx, batch)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// toAssetBatchResponse reports the items along with the mint transactions
+// sent for them.
+func (s *Server) toAssetBatchResponse(ctx context.Context, batch *model.AssetBatch) (*AssetBatchResponse, error) {
+	resp := &AssetBatchResponse{
+		ID:        batch.ID,
+		Network:   batch.Network,
+		Size:      batch.Size,
+		CreatedAt: batch.CreatedAt,
+		Items:     make([]*AssetBatchItemResponse, 0, len(batch.Assets)),
+		Progress:  map[AssetBatchItemProgress]int{},
+	}
+
+	for _, asset := range batch.Assets {
+		var chainTx *model.ChainTx
+		if !asset.LazyMint && !asset.IsMinted() && asset.Status != model.AssetStatusFailed {
+			kind := model.ChainTxKindMint
+			if asset.IsEdition() {
+				kind = model.ChainTxKindMintEdition
+			}
+
+			var err error
+			chainTx, err = s.ds.ChainTxs.GetLatestByAssetID(ctx, kind, asset.ID)
+			if err != nil && err != datastore.ErrChainTxNotFound {
+				return nil, err
+			}
+		}
+
+		item := toAssetBatchItemResponse(asset, chainTx)
+		resp.Items = append(resp.Items, item)
+		resp.Progress[item.Progress]++
+	}
+
+	resp.Status = AssetBatchStatusCompleted
+	if resp.Progress[AssetBatchItemProgressProcessing] > 0 {
+		resp.Status = AssetBatchStatusProcessing
+	} else if resp.Progress[AssetBatchItemProgressQueued] > 0 || resp.Progress[AssetBatchItemProgressMinting] > 0 {
+		resp.Status = AssetBatchStatusMinting
+	}
+
+	return resp, nil
+}
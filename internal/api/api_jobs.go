@@ -0,0 +1,32 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/videocoin/marketplace/internal/datastore"
+	"github.com/videocoin/marketplace/internal/model"
+)
+
+func (s *Server) getJob(c echo.Context) error {
+	ctxAccount := c.Get("account")
+	account := ctxAccount.(*model.Account)
+
+	ctx := context.Background()
+
+	job, err := s.ds.Jobs.GetByID(ctx, c.Param("job_id"))
+	if err != nil {
+		if err == datastore.ErrJobNotFound {
+			return echo.ErrNotFound
+		}
+		return err
+	}
+
+	if job.CreatedByID != account.ID {
+		return echo.ErrNotFound
+	}
+
+	resp := toJobResponse(job)
+	return c.JSON(http.StatusOK, resp)
+}
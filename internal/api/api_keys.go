@@ -0,0 +1,242 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gocraft/dbr/v2"
+	"github.com/labstack/echo/v4"
+	"github.com/videocoin/marketplace/internal/datastore"
+	"github.com/videocoin/marketplace/internal/drm"
+	"github.com/videocoin/marketplace/internal/model"
+)
+
+// getAssetKeys returns the drm key envelopes of the asset the account may
+// see: its own and, while it holds the token, the ones of the delegates it
+// has authorized. Holders of editions don't see each other's delegates.
+func (s *Server) getAssetKeys(c echo.Context) error {
+	ctxAccount := c.Get("account")
+	account := ctxAccount.(*model.Account)
+
+	ctx := context.Background()
+
+	asset, err := s.getAssetByParam(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	envelopes, err := s.ds.AssetKeyEnvelopes.ListByAssetID(ctx, asset.ID)
+	if err != nil {
+		return err
+	}
+
+	holder, err := s.isAssetHolder(ctx, asset, account)
+	if err != nil {
+		return err
+	}
+
+	visible := make([]*model.AssetKeyEnvelope, 0, len(envelopes))
+	for _, envelope := range envelopes {
+		granted := envelope.GrantedByID.Valid && envelope.GrantedByID.Int64 == account.ID
+		if envelope.AccountID == account.ID || (holder && granted) {
+			visible = append(visible, envelope)
+		}
+	}
+
+	if len(visible) == 0 {
+		return echo.ErrNotFound
+	}
+
+	for _, envelope := range visible {
+		envelope.Account, err = s.ds.Accounts.GetByID(ctx, envelope.AccountID)
+		if err != nil {
+			return err
+		}
+	}
+
+	resp := toAssetKeyEnvelopesResponse(visible)
+	return c.JSON(http.StatusOK, resp)
+}
+
+// addAssetDelegate seals the content key to a delegate of the holder, the
+// media isn't encrypted again.
+func (s *Server) addAssetDelegate(c echo.Context) error {
+	ctxAccount := c.Get("account")
+	account := ctxAccount.(*model.Account)
+
+	logger := s.logger.
+		WithField("account_id", account.ID).
+		WithField("address", account.Address)
+
+	req := new(AddAssetDelegateRequest)
+	err := c.Bind(req)
+	if err != nil {
+		logger.WithError(err).Warning("failed to bind request")
+		return echo.ErrBadRequest
+	}
+
+	ctx := context.Background()
+
+	asset, err := s.getAssetByParam(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	err = s.checkAssetHolder(ctx, asset, account)
+	if err != nil {
+		return err
+	}
+
+	delegate, err := s.ds.Accounts.GetByAddress(ctx, req.Address)
+	if err != nil {
+		if err == datastore.ErrAccountNotFound {
+			return echo.NewHTTPError(http.StatusPreconditionFailed, "delegate not found")
+		}
+		return err
+	}
+
+	if delegate.ID == account.ID {
+		return echo.NewHTTPError(http.StatusPreconditionFailed, "invalid delegate")
+	}
+
+	if delegate.IsPlaceholder() {
+		return echo.NewHTTPError(http.StatusPreconditionFailed, "delegate has no encryption key")
+	}
+
+	existing, err := s.ds.AssetKeyEnvelopes.Get(ctx, asset.ID, delegate.ID, model.KeyEnvelopeRoleDelegate)
+	if err != nil && err != datastore.ErrAssetKeyEnvelopeNotFound {
+		return err
+	}
+	if err == nil && existing.GrantedByID.Int64 != account.ID {
+		return echo.NewHTTPError(http.StatusPreconditionFailed, "delegate has been authorized by another holder")
+	}
+	if err == datastore.ErrAssetKeyEnvelopeNotFound {
+		count, err := s.ds.AssetKeyEnvelopes.CountGrantedBy(ctx, asset.ID, account.ID)
+		if err != nil {
+			return err
+		}
+		if count >= model.MaxAssetDelegates {
+			return echo.NewHTTPError(http.StatusPreconditionFailed, "too many delegates")
+		}
+	}
+
+	drmMeta := new(drm.Metadata)
+	err = json.Unmarshal([]byte(asset.DRMMeta), drmMeta)
+	if err != nil {
+		logger.WithError(err).WithField("asset_id", asset.ID).Error("failed to unmarshal drm meta")
+		return echo.ErrInternalServerError
+	}
+
+	sealed, err := drm.SealKey(drmMeta, delegate.EncryptionPublicKey.String)
+	if err != nil {
+		logger.WithError(err).WithField("asset_id", asset.ID).Error("failed to seal drm key")
+		return echo.ErrInternalServerError
+	}
+
+	envelope := &model.AssetKeyEnvelope{
+		AssetID:     asset.ID,
+		AccountID:   delegate.ID,
+		Role:        model.KeyEnvelopeRoleDelegate,
+		Envelope:    sealed,
+		GrantedByID: dbr.NewNullInt64(account.ID),
+		Account:     delegate,
+	}
+
+	err = s.ds.AssetKeyEnvelopes.Put(ctx, envelope)
+	if err != nil {
+		return err
+	}
+
+	logger.
+		WithField("asset_id", asset.ID).
+		WithField("delegate_id", delegate.ID).
+		Info("delegate has been added")
+
+	resp := toAssetKeyEnvelopeResponse(envelope)
+	return c.JSON(http.StatusOK, resp)
+}
+
+// revokeAssetDelegate drops the envelope of a delegate the holder has
+// authorized, which can no longer request the content key.
+func (s *Server) revokeAssetDelegate(c echo.Context) error {
+	ctxAccount := c.Get("account")
+	account := ctxAccount.(*model.Account)
+
+	ctx := context.Background()
+
+	asset, err := s.getAssetByParam(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	err = s.checkAssetHolder(ctx, asset, account)
+	if err != nil {
+		return err
+	}
+
+	delegate, err := s.ds.Accounts.GetByAddress(ctx, c.Param("address"))
+	if err != nil {
+		if err == datastore.ErrAccountNotFound {
+			return echo.ErrNotFound
+		}
+		return err
+	}
+
+	envelope, err := s.ds.AssetKeyEnvelopes.Get(ctx, asset.ID, delegate.ID, model.KeyEnvelopeRoleDelegate)
+	if err != nil {
+		if err == datastore.ErrAssetKeyEnvelopeNotFound {
+			return echo.ErrNotFound
+		}
+		return err
+	}
+	if !envelope.GrantedByID.Valid || envelope.GrantedByID.Int64 != account.ID {
+		return echo.ErrNotFound
+	}
+
+	ok, err := s.ds.AssetKeyEnvelopes.Delete(ctx, asset.ID, delegate.ID, model.KeyEnvelopeRoleDelegate)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return echo.ErrNotFound
+	}
+
+	s.logger.
+		WithField("account_id", account.ID).
+		WithField("asset_id", asset.ID).
+		WithField("delegate_id", delegate.ID).
+		Info("delegate has been revoked")
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (s *Server) getAssetByParam(ctx context.Context, c echo.Context) (*model.Asset, error) {
+	assetID, _ := strconv.ParseInt(c.Param("asset_id"), 10, 64)
+	if assetID == 0 {
+		return nil, echo.ErrNotFound
+	}
+
+	asset, err := s.ds.Assets.GetByID(ctx, assetID)
+	if err != nil {
+		if err == datastore.ErrAssetNotFound {
+			return nil, echo.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return asset, nil
+}
+
+func (s *Server) checkAssetHolder(ctx context.Context, asset *model.Asset, account *model.Account) error {
+	ok, err := s.isAssetHolder(ctx, asset, account)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return echo.ErrForbidden
+	}
+
+	return nil
+}
@@ -0,0 +1,301 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/labstack/echo/v4"
+	"github.com/videocoin/marketplace/internal/datastore"
+	"github.com/videocoin/marketplace/internal/drm"
+	"github.com/videocoin/marketplace/internal/model"
+)
+
+// getClearKeyLicense hands the content keys out to the current holders of
+// the tokens, the creators and the delegates of the holders, in the license
+// format of Clear Key so dash.js and Shaka Player use it as it is. The token
+// metadata just references the kids, so former owners lose access to the
+// content once they sell it.
+func (s *Server) getClearKeyLicense(c echo.Context) error {
+	ctxAccount := c.Get("account")
+	account := ctxAccount.(*model.Account)
+
+	logger := s.logger.
+		WithField("account_id", account.ID).
+		WithField("address", account.Address)
+
+	req := new(drm.ClearKeyRequest)
+	err := c.Bind(req)
+	if err != nil {
+		logger.WithError(err).Warning("failed to bind request")
+		return echo.ErrBadRequest
+	}
+
+	if len(req.KIDs) == 0 {
+		return echo.NewHTTPError(http.StatusPreconditionFailed, "missing kids")
+	}
+
+	ctx := context.Background()
+
+	license := &drm.ClearKeyLicense{
+		Keys: make([]*drm.ClearKey, 0, len(req.KIDs)),
+		Type: drm.ClearKeySessionTemporary,
+	}
+
+	for _, reqKID := range req.KIDs {
+		kid, err := drm.DecodeKID(reqKID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusPreconditionFailed, err.Error())
+		}
+
+		asset, err := s.ds.Assets.GetByDRMKID(ctx, kid)
+		if err != nil {
+			if err == datastore.ErrAssetNotFound {
+				return echo.ErrNotFound
+			}
+			return err
+		}
+
+		ok, err := s.hasContentAccess(ctx, asset, account)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			logger.
+				WithField("asset_id", asset.ID).
+				Warning("license requested without access to the content")
+			return echo.ErrForbidden
+		}
+
+		drmMeta := new(drm.Metadata)
+		err = json.Unmarshal([]byte(asset.DRMMeta), drmMeta)
+		if err != nil {
+			logger.WithError(err).WithField("asset_id", asset.ID).Error("failed to unmarshal drm meta")
+			return echo.ErrInternalServerError
+		}
+
+		key, err := drm.NewClearKey(drmMeta)
+		if err != nil {
+			logger.WithError(err).WithField("asset_id", asset.ID).Error("failed to create clear key")
+			return echo.ErrInternalServerError
+		}
+
+		license.Keys = append(license.Keys, key)
+
+		logger.
+			WithField("asset_id", asset.ID).
+			WithField("kid", kid).
+			Info("license has been issued")
+	}
+
+	return c.JSON(http.StatusOK, license)
+}
+
+// hlsTokenAuth passes the jwt of the token query param on as a bearer
+// token, native HLS players can't set the headers of the playlist and key
+// requests.
+func hlsTokenAuth(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := c.Request()
+		token := c.QueryParam("token")
+		if token != "" && req.Header.Get("Authorization") == "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		return next(c)
+	}
+}
+
+// getHLSPlaylist serves the playlists of the AES-128 packaging to the
+// accounts which may play the content, with the uris of the key and the
+// media playlists carrying the token of the request.
+func (s *Server) getHLSPlaylist(c echo.Context) error {
+	name := c.Param("playlist")
+	if path.Base(name) != name || path.Ext(name) != ".m3u8" {
+		return echo.ErrNotFound
+	}
+
+	media, asset, err := s.getHLSAESMedia(c)
+	if err != nil {
+		return err
+	}
+
+	r, err := s.storage.ObjReader(path.Join(path.Dir(media.EncryptedHLSAESKey), name))
+	if err != nil {
+		s.logger.
+			WithError(err).
+			WithField("media_id", media.ID).
+			WithField("playlist", name).
+			Warning("failed to read hls playlist")
+		return echo.ErrNotFound
+	}
+	defer r.Close()
+
+	playlist, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	s.logger.
+		WithField("asset_id", asset.ID).
+		WithField("media_id", media.ID).
+		WithField("playlist", name).
+		Debug("hls playlist has been served")
+
+	c.Response().Header().Set("Cache-Control", "no-store")
+
+	return c.Blob(
+		http.StatusOK,
+		"application/vnd.apple.mpegurl",
+		[]byte(drm.RewriteHLSPlaylist(string(playlist), c.QueryString(), media.GetEncryptedHLSFileUrl)),
+	)
+}
+
+// getHLSKey hands the content key of the AES-128 packaging out to the
+// accounts which may play the content.
+func (s *Server) getHLSKey(c echo.Context) error {
+	media, asset, err := s.getHLSAESMedia(c)
+	if err != nil {
+		return err
+	}
+
+	logger := s.logger.
+		WithField("asset_id", asset.ID).
+		WithField("media_id", media.ID)
+
+	drmMeta := new(drm.Metadata)
+	err = json.Unmarshal([]byte(asset.DRMMeta), drmMeta)
+	if err != nil {
+		logger.WithError(err).Error("failed to unmarshal drm meta")
+		return echo.ErrInternalServerError
+	}
+
+	key, err := drm.HLSAESKey(drmMeta)
+	if err != nil {
+		logger.WithError(err).Error("failed to get hls key")
+		return echo.ErrInternalServerError
+	}
+
+	logger.Info("hls key has been issued")
+
+	c.Response().Header().Set("Cache-Control", "no-store")
+
+	return c.Blob(http.StatusOK, "application/octet-stream", key)
+}
+
+// getHLSAESMedia returns the media of the request packaged with AES-128 and
+// its asset, once the account is checked to have access to the content.
+func (s *Server) getHLSAESMedia(c echo.Context) (*model.Media, *model.Asset, error) {
+	account := c.Get("account").(*model.Account)
+	ctx := context.Background()
+
+	media, err := s.ds.Media.GetByID(ctx, c.Param("media_id"))
+	if err != nil {
+		if err == datastore.ErrMediaNotFound {
+			return nil, nil, echo.ErrNotFound
+		}
+		return nil, nil, err
+	}
+
+	if !media.HasEncryptedHLSAES() || !media.AssetID.Valid {
+		return nil, nil, echo.ErrNotFound
+	}
+
+	asset, err := s.ds.Assets.GetByID(ctx, media.AssetID.Int64)
+	if err != nil {
+		if err == datastore.ErrAssetNotFound {
+			return nil, nil, echo.ErrNotFound
+		}
+		return nil, nil, err
+	}
+
+	ok, err := s.hasContentAccess(ctx, asset, account)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !ok {
+		s.logger.
+			WithField("account_id", account.ID).
+			WithField("asset_id", asset.ID).
+			Warning("hls requested without access to the content")
+		return nil, nil, echo.ErrForbidden
+	}
+
+	return media, asset, nil
+}
+
+// hasContentAccess reports whether the account may play the content of the
+// asset: its holders and the recipients of a creator or delegate envelope.
+func (s *Server) hasContentAccess(ctx context.Context, asset *model.Asset, account *model.Account) (bool, error) {
+	for _, role := range []model.KeyEnvelopeRole{model.KeyEnvelopeRoleCreator, model.KeyEnvelopeRoleDelegate} {
+		_, err := s.ds.AssetKeyEnvelopes.Get(ctx, asset.ID, account.ID, role)
+		if err == nil {
+			return true, nil
+		}
+		if err != datastore.ErrAssetKeyEnvelopeNotFound {
+			return false, err
+		}
+	}
+
+	return s.isAssetHolder(ctx, asset, account)
+}
+
+// isAssetHolder reports whether the account currently holds the token.
+// Minted tokens are checked on chain, the database is used for the ones
+// which haven't been minted yet or when the node can't be reached.
+func (s *Server) isAssetHolder(ctx context.Context, asset *model.Asset, account *model.Account) (bool, error) {
+	if asset.IsMinted() {
+		ok, err := s.isTokenHolder(ctx, asset, account)
+		if err == nil {
+			return ok, nil
+		}
+
+		s.logger.
+			WithError(err).
+			WithField("asset_id", asset.ID).
+			WithField("network", asset.Network).
+			Warning("failed to check token holder on chain")
+	}
+
+	if !asset.IsEdition() {
+		return asset.OwnerID == account.ID, nil
+	}
+
+	balance, err := s.ds.AssetHolders.GetBalance(ctx, asset.ID, account.ID)
+	if err != nil {
+		return false, err
+	}
+
+	return balance > 0, nil
+}
+
+func (s *Server) isTokenHolder(ctx context.Context, asset *model.Asset, account *model.Account) (bool, error) {
+	m, err := s.minters.Get(asset.Network)
+	if err != nil {
+		return false, err
+	}
+
+	address := common.HexToAddress(account.Address)
+	id := big.NewInt(asset.ID)
+
+	if asset.IsEdition() {
+		balance, err := m.BalanceOf1155(ctx, address, id)
+		if err != nil {
+			return false, err
+		}
+
+		return balance.Sign() > 0, nil
+	}
+
+	owner, err := m.OwnerOf(ctx, id)
+	if err != nil {
+		return false, err
+	}
+
+	return strings.EqualFold(owner.Hex(), address.Hex()), nil
+}
@@ -34,6 +34,24 @@ func (s *Server) uploadMediaFile(ctx context.Context, file *multipart.FileHeader
 	return nil
 }
 
+// storeUpload pushes the upload to storage, the upload job reads it from
+// there and may run on another host than the API.
+func (s *Server) storeUpload(meta *model.AssetMeta, public bool) (string, error) {
+	localPath, key := meta.LocalDest, meta.DestKey
+	if meta.DestSourceKey != "" {
+		localPath, key = meta.LocalSourceDest, meta.DestSourceKey
+	}
+	defer os.Remove(localPath)
+
+	f, err := os.Open(localPath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	return s.storage.PushPath(key, f, public)
+}
+
 func (s *Server) handleUploadMediaFile(ctx context.Context, file *multipart.FileHeader) (*model.AssetMeta, error) {
 	meta := model.NewAssetMeta(file.Filename, file.Header.Get("Content-Type"))
 	meta.Size = file.Size
@@ -53,6 +71,12 @@ func (s *Server) handleUploadMediaFile(ctx context.Context, file *multipart.File
 		return nil, ErrInvalidMedia
 	}
 
+	// video that cannot be streamed is transcoded by the upload job, the
+	// media is created as the mp4 it is going to be
+	if meta.NeedsNormalization() {
+		meta.Normalize()
+	}
+
 	return meta, nil
 }
 
@@ -78,6 +102,13 @@ func (s *Server) uploadMedia(c echo.Context) error {
 		if err == ErrUnsupportedContentType {
 			return c.JSON(http.StatusPreconditionFailed, echo.Map{"message": err.Error()})
 		}
+		return err
+	}
+
+	cid, err := s.storeUpload(meta, featured)
+	if err != nil {
+		logger.WithError(err).Error("failed to store upload")
+		return err
 	}
 
 	media := &model.Media{
@@ -94,63 +125,55 @@ func (s *Server) uploadMedia(c echo.Context) error {
 		Key:          meta.DestKey,
 		ThumbnailKey: meta.DestThumbKey,
 		EncryptedKey: meta.DestEncKey,
-	}
+		PreviewKey:   meta.DestPreviewKey,
 
-	err = s.ds.Media.Create(ctx, media)
-	if err != nil {
-		logger.WithError(err).Error("failed to create media")
-		return c.JSON(http.StatusBadRequest, echo.Map{"message": ErrInvalidMedia.Error()})
+		SourceContentType: meta.SourceContentType,
+		SourceKey:         meta.DestSourceKey,
+	}
+	if media.IsNormalized() {
+		media.SourceCID = dbr.NewNullString(cid)
+	} else {
+		media.CID = dbr.NewNullString(cid)
 	}
 
-	logger.WithField("media_id", media.ID)
-
-	go func() {
-		logger = logger.
-			WithField("from", meta.LocalDest).
-			WithField("to", meta.DestKey)
-
-		logger.Info("uploading media to storage")
-
-		f, err := os.Open(meta.LocalDest)
-		if err != nil {
-			logger.WithError(err).Error("failed to open media")
-			return
-		}
-		defer f.Close()
-
-		cid, err := s.storage.PushPath(meta.DestKey, f, featured)
+	var job *model.Job
+	err = s.ds.InTx(ctx, func(ctx context.Context) error {
+		err := s.ds.Media.Create(ctx, media)
 		if err != nil {
-			logger.WithError(err).Error("failed to push media to storage")
-			return
+			logger.WithError(err).Error("failed to create media")
+			return echo.NewHTTPError(http.StatusBadRequest, ErrInvalidMedia.Error())
 		}
 
-		logger = logger.WithField("cid", cid)
-		logger.Info("media has been uploaded to storage")
-
-		err = s.ds.Media.Update(ctx, media, datastore.MediaUpdatedFields{
-			CID: pointer.ToString(cid),
-		})
+		job, err = model.NewMediaUploadJob(media, meta)
 		if err != nil {
-			logger.WithError(err).Error("failed to update media CID")
-			return
+			logger.WithError(err).Error("failed to build media upload job")
+			return err
 		}
 
-		logger.Info("generating thumbnail")
-
-		err = s.mp.GenerateThumbnail(ctx, media, meta)
+		err = s.ds.Jobs.Create(ctx, job)
 		if err != nil {
-			logger.WithError(err).Error("failed to generate media thumbnail")
-			return
+			logger.WithError(err).Error("failed to create media upload job")
+			return err
 		}
 
 		err = s.ds.Media.Update(ctx, media, datastore.MediaUpdatedFields{
-			Status: pointer.ToString(string(model.MediaStatusReady)),
+			JobID: pointer.ToString(job.ID),
 		})
 		if err != nil {
-			logger.WithError(err).Error("failed to mark media as ready")
-			return
+			logger.WithError(err).Error("failed to update media job id")
+			return err
 		}
-	}()
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.
+		WithField("media_id", media.ID).
+		WithField("job_id", job.ID).
+		Info("media upload job has been queued")
 
 	resp := toMediaResponse(media, !media.Featured)
 	return c.JSON(http.StatusOK, resp)
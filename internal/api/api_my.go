@@ -79,3 +79,79 @@ func (s *Server) getMySoldAssets(c echo.Context) error {
 	resp := toAssetsResponse(arts, countResp)
 	return c.JSON(http.StatusOK, resp)
 }
+
+// getMyEarnings returns what the account earned per asset, as royalties on
+// the assets it created and as proceeds of the editions it sold.
+func (s *Server) getMyEarnings(c echo.Context) error {
+	ctxAccount := c.Get("account")
+	account := ctxAccount.(*model.Account)
+
+	offset, _ := strconv.ParseUint(c.FormValue("offset"), 10, 64)
+	limit, _ := strconv.ParseUint(c.FormValue("limit"), 10, 64)
+	limitOpts := datastore.NewLimitOpts(offset, limit)
+
+	ctx := context.Background()
+	earnings, err := s.ds.Ledger.ListEarnings(ctx, account.ID, limitOpts)
+	if err != nil {
+		return err
+	}
+
+	err = s.ds.JoinAssetToEarnings(ctx, earnings)
+	if err != nil {
+		return err
+	}
+
+	tc, _ := s.ds.Ledger.CountEarnings(ctx, account.ID)
+	countResp := &ItemsCountResponse{
+		TotalCount: tc,
+		Offset:     *limitOpts.Offset,
+		Limit:      *limitOpts.Limit,
+	}
+
+	resp := toEarningsResponse(earnings, countResp)
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (s *Server) getMySales(c echo.Context) error {
+	ctxAccount := c.Get("account")
+	account := ctxAccount.(*model.Account)
+
+	offset, _ := strconv.ParseUint(c.FormValue("offset"), 10, 64)
+	limit, _ := strconv.ParseUint(c.FormValue("limit"), 10, 64)
+	limitOpts := datastore.NewLimitOpts(offset, limit)
+
+	fltr := &datastore.LedgerFilter{
+		AccountID: pointer.ToInt64(account.ID),
+		Sort: &datastore.SortOption{
+			Field: "created_at",
+			IsAsc: false,
+		},
+	}
+
+	reqAssetID := c.FormValue("asset_id")
+	if reqAssetID != "" {
+		assetID, _ := strconv.ParseInt(reqAssetID, 10, 64)
+		fltr.AssetID = pointer.ToInt64(assetID)
+	}
+
+	ctx := context.Background()
+	entries, err := s.ds.Ledger.List(ctx, fltr, limitOpts)
+	if err != nil {
+		return err
+	}
+
+	err = s.ds.JoinAssetToLedger(ctx, entries)
+	if err != nil {
+		return err
+	}
+
+	tc, _ := s.ds.Ledger.Count(ctx, fltr)
+	countResp := &ItemsCountResponse{
+		TotalCount: tc,
+		Offset:     *limitOpts.Offset,
+		Limit:      *limitOpts.Limit,
+	}
+
+	resp := toSalesResponse(entries, account.ID, countResp)
+	return c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,12 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+func (s *Server) getNetworks(c echo.Context) error {
+	resp := toNetworksResponse(s.networks.List())
+	return c.JSON(http.StatusOK, resp)
+}
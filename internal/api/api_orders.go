@@ -3,6 +3,7 @@ package api
 import (
 	"context"
 	"github.com/gocraft/dbr/v2"
+	"math/big"
 	"net/http"
 	"strconv"
 	"strings"
@@ -67,6 +68,35 @@ func (s *Server) postOrder(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusPreconditionFailed, "invalid taker address")
 	}
 
+	// the order is signed by its maker, only the maker can post it
+	if !strings.EqualFold(order.WyvernOrder.Maker.Address, account.Address) {
+		return echo.NewHTTPError(http.StatusForbidden, "maker is not the authenticated account")
+	}
+	maker := account
+
+	err = order.WyvernOrder.Verify()
+	if err != nil {
+		s.logger.
+			WithField("hash", req.Hash).
+			WithField("maker", order.WyvernOrder.Maker.Address).
+			WithError(err).
+			Warning("failed to verify order")
+		return echo.NewHTTPError(http.StatusPreconditionFailed, err.Error())
+	}
+
+	err = order.WyvernOrder.ValidateDutchAuction()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusPreconditionFailed, err.Error())
+	}
+
+	err = order.WyvernOrder.ValidateEnglishAuction()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusPreconditionFailed, err.Error())
+	}
+
+	orderHash, _ := order.WyvernOrder.OrderHash()
+	order.WyvernOrder.Hash = strings.ToLower(orderHash.Hex())
+
 	tokenID, _ := strconv.ParseInt(order.WyvernOrder.Metadata.Asset.ID, 10, 64)
 	asset, err := s.ds.Assets.GetByTokenID(ctx, tokenID)
 	if err != nil {
@@ -76,10 +106,52 @@ func (s *Server) postOrder(c echo.Context) error {
 		return err
 	}
 
-	if order.Side == wyvern.Sell {
-		if asset.OwnerID != account.ID {
+	net, err := s.networks.Get(asset.Network)
+	if err != nil {
+		return err
+	}
+
+	if !net.IsExchange(order.WyvernOrder.Exchange) {
+		return echo.NewHTTPError(http.StatusPreconditionFailed, "invalid exchange")
+	}
+
+	if !net.AcceptsPaymentToken(order.WyvernOrder.PaymentToken) {
+		return echo.NewHTTPError(http.StatusPreconditionFailed, "unsupported payment token")
+	}
+
+	order.Network = asset.Network
+
+	quantity := int64(1)
+	if asset.IsEdition() {
+		quantity, err = strconv.ParseInt(order.WyvernOrder.Metadata.Asset.Quantity, 10, 64)
+		if err != nil || quantity <= 0 || quantity > asset.Supply {
+			return echo.NewHTTPError(http.StatusPreconditionFailed, "invalid quantity")
+		}
+
+		amount, err := order.WyvernOrder.TokenAmount()
+		if err == nil && amount.Cmp(big.NewInt(quantity)) != 0 {
+			return echo.NewHTTPError(http.StatusPreconditionFailed, "quantity does not match calldata")
+		}
+
+		order.WyvernOrder.Metadata.Schema = wyvern.SchemaERC1155
+	}
+
+	if order.WyvernOrder.Side == wyvern.Sell {
+		balance, err := s.ds.AssetHolders.GetBalance(ctx, asset.ID, maker.ID)
+		if err != nil {
+			return err
+		}
+
+		if balance < quantity {
 			return echo.ErrForbidden
 		}
+
+		if s.feeRecipient != "" {
+			err = order.WyvernOrder.ValidateSellerFees(s.feeRecipient, s.sellerFeeBps(asset, maker))
+			if err != nil {
+				return echo.NewHTTPError(http.StatusPreconditionFailed, err.Error())
+			}
+		}
 	}
 
 	if asset.StatusIsTransferred() {
@@ -112,23 +184,10 @@ func (s *Server) postOrder(c echo.Context) error {
 		}
 	}
 
-	maker := &model.Account{
-		Address: wyvern.NullAddress,
-	}
 	taker := &model.Account{
 		Address: wyvern.NullAddress,
 	}
 
-	if order.WyvernOrder.Maker.Address != wyvern.NullAddress {
-		maker, err = s.ds.Accounts.GetByAddress(ctx, order.WyvernOrder.Maker.Address)
-		if err != nil {
-			if err == datastore.ErrAccountNotFound {
-				return echo.NewHTTPError(http.StatusPreconditionFailed, "maker not found")
-			}
-			return err
-		}
-	}
-
 	if order.WyvernOrder.Taker.Address != wyvern.NullAddress {
 		taker, err = s.ds.Accounts.GetByAddress(ctx, order.WyvernOrder.Taker.Address)
 		if err != nil {
@@ -156,11 +215,73 @@ func (s *Server) postOrder(c echo.Context) error {
 		}
 	}
 
-	order.WyvernOrder.Metadata.Asset.Quantity = "1"
+	order.Quantity = quantity
+	order.WyvernOrder.Metadata.Asset.Quantity = strconv.FormatInt(quantity, 10)
 	order.Hash = strings.ToLower(order.WyvernOrder.Hash)
 
-	err = s.ds.Orders.Create(ctx, order)
+	if order.WyvernOrder.Side == wyvern.Buy && asset.OnSale && asset.IsDutchAuction() {
+		err = s.checkDutchAuctionBid(ctx, asset, order)
+		if err != nil {
+			return err
+		}
+	}
+
+	// a lazily minted asset isn't sold below the price its voucher has been
+	// signed for
+	if order.WyvernOrder.Side == wyvern.Buy && asset.IsUnminted() && asset.MintVoucher != nil {
+		basePrice, err := ethutil.ParseBigInt(order.WyvernOrder.BasePrice)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusPreconditionFailed, "invalid base price")
+		}
+
+		voucherPrice, err := ethutil.ParseBigInt(asset.MintVoucher.Price)
+		if err != nil {
+			return err
+		}
+
+		if basePrice.Cmp(voucherPrice) < 0 {
+			return echo.NewHTTPError(http.StatusPreconditionFailed, "price is below the mint voucher price")
+		}
+	}
+
+	err = s.ds.InTx(ctx, func(ctx context.Context) error {
+		err := s.ds.Orders.Create(ctx, order)
+		if err != nil {
+			return err
+		}
+
+		// the exchange transfers the token from the seller, a lazily minted
+		// one is minted to its creator before the order can be matched
+		if asset.IsUnminted() {
+			job, err := model.NewAssetRedeemJob(asset)
+			if err != nil {
+				return err
+			}
+
+			err = s.ds.Jobs.Create(ctx, job)
+			if err != nil {
+				return err
+			}
+
+			s.logger.
+				WithField("asset_id", asset.ID).
+				WithField("job_id", job.ID).
+				Info("mint voucher redemption has been scheduled")
+		}
+
+		if order.Side == wyvern.Buy && s.auctions != nil {
+			_, _, err = s.auctions.PlaceBid(ctx, asset, order)
+			if err != nil && err != datastore.ErrAuctionNotFound {
+				return err
+			}
+		}
+
+		return nil
+	})
 	if err != nil {
+		if isAuctionBidError(err) {
+			return echo.NewHTTPError(http.StatusPreconditionFailed, err.Error())
+		}
 		return err
 	}
 
@@ -226,10 +347,28 @@ func (s *Server) postOrder(c echo.Context) error {
 	}
 
 	if order.Side == wyvern.Sell {
-		err = s.ds.Assets.Update(ctx, asset, datastore.AssetUpdatedFields{
+		fields := datastore.AssetUpdatedFields{
 			PaymnetTokenAddress: pointer.ToString(order.PaymentTokenAddress),
 			OnSale:              pointer.ToBool(true),
-		})
+			SaleKind:            &order.WyvernOrder.SaleKind,
+		}
+
+		if order.WyvernOrder.SaleKind == wyvern.DutchAuction {
+			basePrice, _ := ethutil.ParseBigInt(order.WyvernOrder.BasePrice)
+			extra, _ := ethutil.ParseBigInt(order.WyvernOrder.Extra)
+			listingTime, _ := strconv.ParseInt(order.WyvernOrder.ListingTime, 10, 64)
+			expirationTime, _ := strconv.ParseInt(order.WyvernOrder.ExpirationTime, 10, 64)
+
+			price, _ := ethutil.WeiToEther(basePrice).Float64()
+			priceExtra, _ := ethutil.WeiToEther(extra).Float64()
+
+			fields.Price = pointer.ToFloat64(price)
+			fields.PriceExtra = pointer.ToFloat64(priceExtra)
+			fields.ListingTime = pointer.ToTime(time.Unix(listingTime, 0))
+			fields.ExpirationTime = pointer.ToTime(time.Unix(expirationTime, 0))
+		}
+
+		err = s.ds.Assets.Update(ctx, asset, fields)
 		if err != nil {
 			s.logger.
 				WithField("asset_id", asset.ID).
@@ -237,6 +376,17 @@ func (s *Server) postOrder(c echo.Context) error {
 				Error("failed to put on sale")
 			return err
 		}
+
+		if order.IsAuction() && s.auctions != nil {
+			_, err = s.auctions.Open(ctx, asset, order)
+			if err != nil {
+				s.logger.
+					WithField("asset_id", asset.ID).
+					WithError(err).
+					Error("failed to open auction")
+				return err
+			}
+		}
 	}
 
 	return c.JSON(http.StatusOK, resp)
@@ -300,6 +450,10 @@ func (s *Server) getOrders(c echo.Context) error {
 		fltr.PaymentTokenAddress = pointer.ToString(reqPaymentTokenAddress)
 	}
 
+	if network := c.FormValue("network"); network != "" {
+		fltr.Network = pointer.ToString(network)
+	}
+
 	reqMaker := strings.ToLower(c.FormValue("maker"))
 	if reqMaker != "" && ethcommon.IsHexAddress(reqMaker) {
 		maker, _ := s.ds.Accounts.GetByAddress(ctx, reqMaker)
@@ -347,3 +501,70 @@ func returnEmptyOrders(c echo.Context) error {
 	orders := make([]*model.Order, 0)
 	return c.JSON(http.StatusOK, toOrdersResponse(orders, nil, nil))
 }
+
+func isAuctionBidError(err error) bool {
+	switch err {
+	case model.ErrAuctionNotOpen,
+		model.ErrAuctionBidTooLow,
+		model.ErrAuctionBidPaymentToken,
+		model.ErrAuctionBidBySeller:
+		return true
+	}
+	return false
+}
+
+// checkDutchAuctionBid rejects buy orders below the current price of the
+// Dutch auction sell order of the asset.
+func (s *Server) checkDutchAuctionBid(ctx context.Context, asset *model.Asset, order *model.Order) error {
+	sellOrders, err := s.ds.Orders.List(ctx, &datastore.OrderFilter{
+		TokenID:   pointer.ToInt64(asset.ID),
+		Side:      pointer.ToInt(int(wyvern.Sell)),
+		SaleKind:  pointer.ToInt(int(wyvern.DutchAuction)),
+		IsArchive: pointer.ToBool(false),
+		Sort: &datastore.SortOption{
+			Field: "created_date",
+			IsAsc: false,
+		},
+	}, nil)
+	if err != nil {
+		return err
+	}
+
+	var sellOrder *model.Order
+	for _, item := range sellOrders {
+		if !item.IsCanceled() && !item.IsProcessed() {
+			sellOrder = item
+			break
+		}
+	}
+
+	if sellOrder == nil {
+		return nil
+	}
+
+	currentPrice, err := sellOrder.WyvernOrder.CurrentPrice(time.Now())
+	if err != nil {
+		return err
+	}
+
+	bid, err := ethutil.ParseBigInt(order.WyvernOrder.BasePrice)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusPreconditionFailed, "invalid base price")
+	}
+
+	if bid.Cmp(currentPrice) < 0 {
+		return echo.NewHTTPError(http.StatusPreconditionFailed, "bid is lower than the current price")
+	}
+
+	return nil
+}
+
+// sellerFeeBps returns the relayer fee a sell order of the account has to
+// pay: the platform fee, plus the creator royalty on secondary sales.
+func (s *Server) sellerFeeBps(asset *model.Asset, seller *model.Account) int64 {
+	bps := s.platformFeeBps
+	if seller.ID != asset.CreatedByID {
+		bps += asset.RoyaltyBps()
+	}
+	return bps
+}
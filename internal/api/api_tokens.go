@@ -7,6 +7,7 @@ import (
 	"github.com/videocoin/marketplace/internal/datastore"
 	"net/http"
 	"strconv"
+	"strings"
 )
 
 func (s *Server) getTokens(c echo.Context) error {
@@ -24,6 +25,15 @@ func (s *Server) getTokens(c echo.Context) error {
 	if address != "" {
 		fltr.Address = pointer.ToString(address)
 	}
+	if name := c.FormValue("network"); name != "" {
+		net, err := s.networks.Get(name)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusPreconditionFailed, err.Error())
+		}
+		for _, token := range net.PaymentTokens {
+			fltr.Addresses = append(fltr.Addresses, strings.ToLower(token))
+		}
+	}
 
 	tokens, err := s.ds.Tokens.List(ctx, fltr, limitOpts)
 	if err != nil {
@@ -1,9 +1,13 @@
 package api
 
 import (
+	"strings"
+
 	"github.com/sirupsen/logrus"
+	"github.com/videocoin/marketplace/internal/auction"
 	"github.com/videocoin/marketplace/internal/datastore"
 	"github.com/videocoin/marketplace/internal/minter"
+	"github.com/videocoin/marketplace/internal/network"
 	"github.com/videocoin/marketplace/internal/storage"
 	"github.com/videocoin/marketplace/internal/mediaprocessor"
 )
@@ -52,9 +56,51 @@ func WithStorage(storage *storage.Storage) ServerOption {
 	}
 }
 
-func WithMinter(m *minter.Minter) ServerOption {
+// WithNetworks sets the networks assets can be minted on, with the minter
+// of each of them.
+func WithNetworks(networks *network.Registry, minters minter.Minters) ServerOption {
+	return func(s *Server) error {
+		s.networks = networks
+		s.minters = minters
+		return nil
+	}
+}
+
+func WithAuctions(m *auction.Manager) ServerOption {
+	return func(s *Server) error {
+		s.auctions = m
+		return nil
+	}
+}
+
+// WithStaticDir serves the files of dir under the url path, it is used by
+// the local storage backend. Every stored object becomes public, so it is
+// meant for offline and test setups only.
+func WithStaticDir(path string, dir string) ServerOption {
 	return func(s *Server) error {
-		s.minter = m
+		s.staticPath = path
+		s.staticDir = dir
 		return nil
 	}
-}
\ No newline at end of file
+}
+
+// WithFees makes sell orders pay the platform fee, plus the creator royalty
+// on secondary sales, as the relayer fee to the fee recipient. Fees are not
+// enforced without a fee recipient.
+func WithFees(feeRecipient string, platformFeeBps int64) ServerOption {
+	return func(s *Server) error {
+		s.feeRecipient = strings.ToLower(feeRecipient)
+		s.platformFeeBps = platformFeeBps
+		return nil
+	}
+}
+
+// WithAdmin enables the admin endpoints for requests carrying the token,
+// chain events are replayed by the listener of the requested network.
+func WithAdmin(token string, replayers map[string]EventReplayer) ServerOption {
+	return func(s *Server) error {
+		s.adminToken = token
+		s.replayers = replayers
+		return nil
+	}
+}
@@ -44,6 +44,20 @@ type CreateAssetRequest struct {
 	InstantSalePrice float64              `json:"instant_sale_price"`
 	PutOnSalePrice   float64              `json:"put_on_sale_price"`
 	Locked           bool                 `json:"locked"`
+	Supply           int64                `json:"supply"`
+	Network          string               `json:"network"`
+	LazyMint         bool                 `json:"lazy_mint"`
+}
+
+// CreateAssetsBatchRequest creates the assets of a drop at once, all of
+// them are minted on the network of the batch.
+type CreateAssetsBatchRequest struct {
+	Network string                `json:"network"`
+	Items   []*CreateAssetRequest `json:"items"`
+}
+
+type AddAssetDelegateRequest struct {
+	Address string `json:"address"`
 }
 
 type PostOrderRequest struct {
@@ -83,6 +97,7 @@ type PostOrderRequest struct {
 
 type OrderResponse struct {
 	BasePrice                  string                   `json:"base_price"`
+	CurrentPrice               string                   `json:"current_price"`
 	Calldata                   string                   `json:"calldata"`
 	CreatedDate                *time.Time               `json:"created_date"`
 	EnglishAuctionReservePrice string                   `json:"english_auction_reserve_price"`
@@ -102,6 +117,7 @@ type OrderResponse struct {
 	PaymentTokenContract       *TokenResponse           `json:"payment_token_contract"`
 	PaymentToken               string                   `json:"payment_token"`
 	Quantity                   string                   `json:"quantity"`
+	FilledQuantity             string                   `json:"filled_quantity"`
 	ReplacementPattern         string                   `json:"replacement_pattern"`
 	SaleKind                   wyvern.SaleKind          `json:"sale_kind"`
 	Salt                       string                   `json:"salt"`
@@ -115,4 +131,11 @@ type OrderResponse struct {
 	V                          int                      `json:"v"`
 	R                          string                   `json:"r"`
 	S                          string                   `json:"s"`
+	Network                    string                   `json:"network"`
+}
+
+type ReplayChainEventsRequest struct {
+	FromBlock uint64 `json:"from_block"`
+	ToBlock   uint64 `json:"to_block"`
+	Network   string `json:"network"`
 }
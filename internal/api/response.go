@@ -1,13 +1,17 @@
 package api
 
 import (
+	"fmt"
 	"github.com/videocoin/marketplace/internal/wyvern"
+	"math/big"
+	"path"
 	"strconv"
 	"time"
 
 	"github.com/AlekSi/pointer"
 	"github.com/jinzhu/copier"
 	"github.com/videocoin/marketplace/internal/model"
+	"github.com/videocoin/marketplace/internal/network"
 )
 
 type NonceResponse struct {
@@ -73,26 +77,61 @@ type AssetCollectionResponse struct {
 }
 
 type MediaResponse struct {
-	ID           string            `json:"id"`
-	Name         string            `json:"name"`
-	ContentType  string            `json:"content_type"`
-	MediaType    string            `json:"media_type"`
-	Duration     int64             `json:"duration"`
-	Size         int64             `json:"size"`
-	Status       model.MediaStatus `json:"status"`
-	URL          string            `json:"url"`
-	Creator      *AccountResponse  `json:"creator"`
-	Featured     bool              `json:"featured"`
-	ThumbnailURL string            `json:"thumbnail_url"`
+	ID           string                  `json:"id"`
+	Name         string                  `json:"name"`
+	ContentType  string                  `json:"content_type"`
+	MediaType    string                  `json:"media_type"`
+	Duration     int64                   `json:"duration"`
+	Size         int64                   `json:"size"`
+	Status       model.MediaStatus       `json:"status"`
+	URL          string                  `json:"url"`
+	Creator      *AccountResponse        `json:"creator"`
+	Featured     bool                    `json:"featured"`
+	ThumbnailURL string                  `json:"thumbnail_url"`
+	PreviewURL   *string                 `json:"preview_url"`
+	DashURL      *string                 `json:"dash_url"`
+	HLSURL       *string                 `json:"hls_url"`
+	HLSAESURL    *string                 `json:"hls_aes_url"`
+	Renditions   []*model.MediaRendition `json:"renditions"`
+	JobID        *string                 `json:"job_id"`
 }
 
 type AssetAuctionResponse struct {
-	IsOpen              bool       `json:"is_open"`
-	StartedAt           *time.Time `json:"started_at"`
-	Duration            int        `json:"duration"`
-	CurrentBid          *float64   `json:"current_bid"`
-	PurchasedBid        *float64   `json:"purchased_bid"`
-	PaymentTokenAddress *string    `json:"payment_token_address"`
+	ID                  *int64               `json:"id,omitempty"`
+	Status              *model.AuctionStatus `json:"status,omitempty"`
+	IsOpen              bool                 `json:"is_open"`
+	StartedAt           *time.Time           `json:"started_at"`
+	EndAt               *time.Time           `json:"end_at,omitempty"`
+	Duration            int                  `json:"duration"`
+	CurrentBid          *float64             `json:"current_bid"`
+	PurchasedBid        *float64             `json:"purchased_bid"`
+	PaymentTokenAddress *string              `json:"payment_token_address"`
+}
+
+type AuctionBidResponse struct {
+	ID        int64            `json:"id"`
+	CreatedAt *time.Time       `json:"created_at"`
+	OrderID   int64            `json:"order_id"`
+	Amount    string           `json:"amount"`
+	Bidder    *AccountResponse `json:"bidder"`
+}
+
+type AuctionResponse struct {
+	ID                  int64                 `json:"id"`
+	AssetID             int64                 `json:"asset_id"`
+	Status              model.AuctionStatus   `json:"status"`
+	IsOpen              bool                  `json:"is_open"`
+	StartAt             *time.Time            `json:"start_at"`
+	EndAt               *time.Time            `json:"end_at"`
+	PaymentTokenAddress string                `json:"payment_token_address"`
+	StartPrice          string                `json:"start_price"`
+	ReserveMet          bool                  `json:"reserve_met"`
+	MinBidIncrementBps  int                   `json:"min_bid_increment_bps"`
+	ExtensionWindow     int                   `json:"extension_window"`
+	MinNextBid          string                `json:"min_next_bid"`
+	Bids                []*AuctionBidResponse `json:"bids"`
+	WinningBid          *AuctionBidResponse   `json:"winning_bid"`
+	WinningOrder        *OrderResponse        `json:"winning_order"`
 }
 
 type AssetResponse struct {
@@ -106,17 +145,30 @@ type AssetResponse struct {
 	URL          string  `json:"url"`
 	ThumbnailURL *string `json:"thumbnail_url"`
 	EncryptedURL *string `json:"encrypted_url"`
+	PreviewURL   *string `json:"preview_url"`
 	TokenURL     *string `json:"token_url"`
 
+	ChainTokenURL  *string `json:"chain_token_url"`
+	TokenURLSynced bool    `json:"token_url_synced"`
+	TokenURLTxID   *string `json:"token_url_tx_id"`
+
+	OwnerKeyMissing bool   `json:"owner_key_missing"`
+	Network         string `json:"network"`
+
+	LazyMint    bool                 `json:"lazy_mint"`
+	Minted      bool                 `json:"minted"`
+	MintVoucher *MintVoucherResponse `json:"mint_voucher"`
+
 	IPFSURL          string  `json:"ipfs_url"`
 	IPFSThumbnailURL *string `json:"ipfs_thumbnail_url"`
 	IPFSEncryptedURL *string `json:"ipfs_encrypted_url"`
+	IPFSPreviewURL   *string `json:"ipfs_preview_url"`
 
 	YTVideoID  *string                  `json:"yt_video_id"`
 	Creator    *AccountResponse         `json:"creator"`
 	Owner      *AccountResponse         `json:"owner"`
 	Contract   *AssetContractResponse   `json:"asset_contract"`
-	DRMKey     *string                  `json:"drm_key"`
+	DRMKID     *string                  `json:"drm_kid"`
 	Collection *AssetCollectionResponse `json:"collection"`
 
 	OnSale           bool    `json:"on_sale"`
@@ -129,6 +181,27 @@ type AssetResponse struct {
 
 	Media   []*MediaResponse      `json:"media"`
 	Auction *AssetAuctionResponse `json:"auction"`
+
+	SaleKind     wyvern.SaleKind            `json:"sale_kind"`
+	CurrentPrice float64                    `json:"current_price"`
+	DutchAuction *AssetDutchAuctionResponse `json:"dutch_auction"`
+
+	Supply  int64                  `json:"supply"`
+	Holders []*AssetHolderResponse `json:"holders,omitempty"`
+
+	JobID *string `json:"job_id"`
+}
+
+type AssetHolderResponse struct {
+	Account *AccountResponse `json:"account"`
+	Balance int64            `json:"balance"`
+}
+
+type AssetDutchAuctionResponse struct {
+	StartPrice     float64    `json:"start_price"`
+	EndPrice       float64    `json:"end_price"`
+	ListingTime    *time.Time `json:"listing_time"`
+	ExpirationTime *time.Time `json:"expiration_time"`
 }
 
 type AssetsResponse struct {
@@ -150,6 +223,26 @@ type TokenResponse struct {
 	USDPrice *float64 `json:"usd_price"`
 }
 
+type MintVoucherResponse struct {
+	ChainID         uint64 `json:"chain_id"`
+	ContractAddress string `json:"contract_address"`
+	TokenID         string `json:"token_id"`
+	URI             string `json:"uri"`
+	Creator         string `json:"creator"`
+	Price           string `json:"price"`
+	RoyaltyBps      int64  `json:"royalty_bps"`
+	Signature       string `json:"signature"`
+}
+
+type NetworkResponse struct {
+	Name                    string   `json:"name"`
+	ChainID                 uint64   `json:"chain_id"`
+	ERC721ContractAddress   string   `json:"erc721_contract_address"`
+	ERC1155ContractAddress  string   `json:"erc1155_contract_address"`
+	ExchangeContractAddress string   `json:"exchange_contract_address"`
+	PaymentTokens           []string `json:"payment_tokens"`
+}
+
 type OrdersResponse struct {
 	Orders []*OrderResponse `json:"orders"`
 	Count  int64            `json:"count"`
@@ -162,6 +255,65 @@ type ItemsCountResponse struct {
 	Limit      uint64
 }
 
+type JobResponse struct {
+	ID          string          `json:"id"`
+	Type        model.JobType   `json:"type"`
+	Status      model.JobStatus `json:"status"`
+	Checkpoints []string        `json:"checkpoints"`
+	Attempts    int             `json:"attempts"`
+	MaxAttempts int             `json:"max_attempts"`
+	LastError   *string         `json:"last_error"`
+	RunAt       *time.Time      `json:"run_at"`
+	CreatedAt   *time.Time      `json:"created_at"`
+	UpdatedAt   *time.Time      `json:"updated_at"`
+}
+
+type AssetKeyEnvelopeResponse struct {
+	Account     *AccountResponse      `json:"account"`
+	Role        model.KeyEnvelopeRole `json:"role"`
+	Envelope    string                `json:"envelope"`
+	GrantedByID *int64                `json:"granted_by_id"`
+	CreatedAt   *time.Time            `json:"created_at"`
+}
+
+type AssetBatchStatus string
+type AssetBatchItemProgress string
+
+const (
+	AssetBatchStatusProcessing AssetBatchStatus = "processing"
+	AssetBatchStatusMinting    AssetBatchStatus = "minting"
+	AssetBatchStatusCompleted  AssetBatchStatus = "completed"
+
+	// AssetBatchItemProgressProcessing items are being encrypted and
+	// published, queued ones wait for the rest of the batch to be minted.
+	AssetBatchItemProgressProcessing AssetBatchItemProgress = "processing"
+	AssetBatchItemProgressQueued     AssetBatchItemProgress = "queued"
+	AssetBatchItemProgressMinting    AssetBatchItemProgress = "minting"
+	AssetBatchItemProgressMinted     AssetBatchItemProgress = "minted"
+	AssetBatchItemProgressListed     AssetBatchItemProgress = "listed"
+	AssetBatchItemProgressFailed     AssetBatchItemProgress = "failed"
+)
+
+type AssetBatchItemResponse struct {
+	AssetID    int64                  `json:"asset_id"`
+	Name       string                 `json:"name"`
+	Status     model.AssetStatus      `json:"status"`
+	Progress   AssetBatchItemProgress `json:"progress"`
+	LazyMint   bool                   `json:"lazy_mint"`
+	JobID      *string                `json:"job_id"`
+	MintTxHash *string                `json:"mint_tx_hash"`
+}
+
+type AssetBatchResponse struct {
+	ID        int64                          `json:"id"`
+	Network   string                         `json:"network"`
+	Size      int                            `json:"size"`
+	Status    AssetBatchStatus               `json:"status"`
+	Progress  map[AssetBatchItemProgress]int `json:"progress"`
+	Items     []*AssetBatchItemResponse      `json:"items"`
+	CreatedAt *time.Time                     `json:"created_at"`
+}
+
 type ActivityItemResponse struct {
 	IsNew     bool           `json:"is_new"`
 	CreatedAt *time.Time     `json:"created_at"`
@@ -179,6 +331,47 @@ type ActivityResponse struct {
 	Next       bool                    `json:"next"`
 }
 
+type EarningsItemResponse struct {
+	Asset        *AssetResponse `json:"asset"`
+	PaymentToken string         `json:"payment_token"`
+	Sales        int64          `json:"sales"`
+	Royalties    string         `json:"royalties"`
+	Proceeds     string         `json:"proceeds"`
+	Total        string         `json:"total"`
+}
+
+type EarningsResponse struct {
+	Items      []*EarningsItemResponse `json:"items"`
+	TotalCount int64                   `json:"total_count"`
+	Count      int64                   `json:"count"`
+	Prev       bool                    `json:"prev"`
+	Next       bool                    `json:"next"`
+}
+
+type SaleResponse struct {
+	ID             int64          `json:"id"`
+	CreatedAt      *time.Time     `json:"created_at"`
+	Asset          *AssetResponse `json:"asset"`
+	OrderID        int64          `json:"order_id"`
+	TxHash         string         `json:"tx_hash"`
+	PaymentToken   string         `json:"payment_token"`
+	Quantity       int64          `json:"quantity"`
+	Price          string         `json:"price"`
+	RoyaltyBps     int64          `json:"royalty_bps"`
+	Royalty        string         `json:"royalty"`
+	PlatformFee    string         `json:"platform_fee"`
+	SellerProceeds string         `json:"seller_proceeds"`
+	Earned         string         `json:"earned"`
+}
+
+type SalesResponse struct {
+	Items      []*SaleResponse `json:"items"`
+	TotalCount int64           `json:"total_count"`
+	Count      int64           `json:"count"`
+	Prev       bool            `json:"prev"`
+	Next       bool            `json:"next"`
+}
+
 func toNonceResponse(account *model.Account) *NonceResponse {
 	return &NonceResponse{
 		Nonce: NoncePrefix + account.Nonce.String,
@@ -273,6 +466,10 @@ func toAssetResponse(asset *model.Asset) *AssetResponse {
 		Sold:             !asset.OnSale && asset.StatusIsTransferred(),
 		Locked:           asset.Locked,
 		IsAction:         asset.IsAuction(),
+		OwnerKeyMissing:  asset.OwnerKeyMissing,
+		Network:          asset.Network,
+		LazyMint:         asset.LazyMint,
+		Minted:           asset.IsMinted(),
 		Auction: &AssetAuctionResponse{
 			IsOpen:              false,
 			StartedAt:           asset.CreatedAt,
@@ -281,23 +478,81 @@ func toAssetResponse(asset *model.Asset) *AssetResponse {
 		},
 	}
 
+	// the auction state is only known from the stored auction below
 	if asset.IsAuction() {
 		auctionStartedAt := asset.AuctionStartedAt
 		if auctionStartedAt == nil || auctionStartedAt.IsZero() {
 			auctionStartedAt = asset.CreatedAt
 		}
 		resp.Auction = &AssetAuctionResponse{
-			IsOpen:              model.AuctionIsOpen(auctionStartedAt, model.DefaultAuctionDuration),
+			IsOpen:              false,
 			StartedAt:           auctionStartedAt,
-			Duration:            model.DefaultAuctionDuration,
 			CurrentBid:          pointer.ToFloat64(asset.CurrentBid.Float64),
 			PurchasedBid:        pointer.ToFloat64(asset.PurchasedBid.Float64),
 			PaymentTokenAddress: pointer.ToString(asset.PaymentTokenAddress.String),
 		}
 	}
 
-	if asset.DRMKey != "" {
-		resp.DRMKey = pointer.ToString(asset.DRMKey)
+	if asset.IsUnminted() && asset.MintVoucher != nil {
+		resp.MintVoucher = toMintVoucherResponse(asset.MintVoucher)
+	}
+
+	resp.TokenURLSynced = asset.IsTokenURISynced()
+	if asset.ChainTokenURI.Valid {
+		resp.ChainTokenURL = pointer.ToString(asset.ChainTokenURI.String)
+	}
+	if asset.TokenURITxID.Valid {
+		resp.TokenURLTxID = pointer.ToString(asset.TokenURITxID.String)
+	}
+
+	resp.SaleKind = wyvern.FixedPrice
+	resp.CurrentPrice = asset.Price
+	if asset.IsDutchAuction() {
+		resp.SaleKind = wyvern.DutchAuction
+		resp.CurrentPrice = asset.CurrentPrice(time.Now())
+		resp.DutchAuction = &AssetDutchAuctionResponse{
+			StartPrice:     asset.Price,
+			EndPrice:       asset.Price - asset.PriceExtra,
+			ListingTime:    asset.ListingTime,
+			ExpirationTime: asset.ExpirationTime,
+		}
+	}
+
+	if asset.Auction != nil {
+		auction := asset.Auction
+		resp.IsAction = true
+		resp.Auction = &AssetAuctionResponse{
+			ID:                  pointer.ToInt64(auction.ID),
+			Status:              &auction.Status,
+			IsOpen:              auction.IsOpen(time.Now()),
+			StartedAt:           auction.StartAt,
+			EndAt:               auction.EndAt,
+			Duration:            int(auction.EndAt.Sub(*auction.StartAt).Seconds()),
+			CurrentBid:          pointer.ToFloat64(asset.CurrentBid.Float64),
+			PurchasedBid:        pointer.ToFloat64(asset.PurchasedBid.Float64),
+			PaymentTokenAddress: pointer.ToString(auction.PaymentTokenAddress),
+		}
+	}
+
+	if asset.Schema != "" {
+		resp.Contract.SchemaName = asset.Schema.String()
+	}
+
+	resp.Supply = asset.Supply
+	for _, holder := range asset.Holders {
+		item := &AssetHolderResponse{Balance: holder.Balance}
+		if holder.Account != nil {
+			item.Account = toAccountResponse(holder.Account)
+		}
+		resp.Holders = append(resp.Holders, item)
+	}
+
+	if asset.DRMKID.Valid {
+		resp.DRMKID = pointer.ToString(asset.DRMKID.String)
+	}
+
+	if asset.JobID.Valid {
+		resp.JobID = pointer.ToString(asset.JobID.String)
 	}
 
 	if asset.ContractAddress.Valid {
@@ -318,6 +573,9 @@ func toAssetResponse(asset *model.Asset) *AssetResponse {
 	resp.EncryptedURL = asset.GetEncryptedUrl()
 	resp.IPFSEncryptedURL = asset.GetIpfsEncryptedUrl()
 
+	resp.PreviewURL = asset.GetPreviewUrl()
+	resp.IPFSPreviewURL = asset.GetIpfsPreviewUrl()
+
 	if asset.CreatedBy != nil {
 		resp.Creator = toAccountResponse(asset.CreatedBy)
 	}
@@ -406,9 +664,53 @@ func toTokensResponse(tokens []*model.Token) []*TokenResponse {
 
 	return resp
 }
+func toMintVoucherResponse(voucher *model.MintVoucher) *MintVoucherResponse {
+	return &MintVoucherResponse{
+		ChainID:         voucher.ChainID,
+		ContractAddress: voucher.ContractAddress,
+		TokenID:         voucher.TokenID,
+		URI:             voucher.URI,
+		Creator:         voucher.Creator,
+		Price:           voucher.Price,
+		RoyaltyBps:      voucher.RoyaltyBps,
+		Signature:       voucher.Signature,
+	}
+}
+
+func toNetworksResponse(networks []*network.Network) []*NetworkResponse {
+	resp := make([]*NetworkResponse, 0)
+	for _, n := range networks {
+		paymentTokens := n.PaymentTokens
+		if paymentTokens == nil {
+			paymentTokens = []string{}
+		}
+
+		resp = append(resp, &NetworkResponse{
+			Name:                    n.Name,
+			ChainID:                 n.ChainID,
+			ERC721ContractAddress:   n.ERC721ContractAddress,
+			ERC1155ContractAddress:  n.ERC1155ContractAddress,
+			ExchangeContractAddress: n.ExchangeContractAddress,
+			PaymentTokens:           paymentTokens,
+		})
+	}
+
+	return resp
+}
+
 func toOrderResponse(order *model.Order, tokens map[string]*model.Token) *OrderResponse {
 	item := new(OrderResponse)
 	_ = copier.Copy(item, order.WyvernOrder)
+
+	item.CurrentPrice = item.BasePrice
+	currentPrice, err := order.WyvernOrder.CurrentPrice(time.Now())
+	if err == nil {
+		item.CurrentPrice = currentPrice.String()
+	}
+
+	item.FilledQuantity = strconv.FormatInt(order.FilledQuantity, 10)
+	item.Network = order.Network
+
 	if order.WyvernOrder.Maker != nil {
 		item.Maker = toAccountResponseFromWyvernAccount(order.WyvernOrder.Maker)
 	}
@@ -429,6 +731,54 @@ func toOrderResponse(order *model.Order, tokens map[string]*model.Token) *OrderR
 	return item
 }
 
+func toAuctionBidResponse(bid *model.AuctionBid) *AuctionBidResponse {
+	resp := &AuctionBidResponse{
+		ID:        bid.ID,
+		CreatedAt: bid.CreatedAt,
+		OrderID:   bid.OrderID,
+		Amount:    bid.Amount,
+	}
+
+	if bid.Bidder != nil {
+		resp.Bidder = toAccountResponse(bid.Bidder)
+	}
+
+	return resp
+}
+
+func toAuctionResponse(auction *model.Auction, winningOrder *model.Order, tokens map[string]*model.Token) *AuctionResponse {
+	resp := &AuctionResponse{
+		ID:                  auction.ID,
+		AssetID:             auction.AssetID,
+		Status:              auction.Status,
+		IsOpen:              auction.IsOpen(time.Now()),
+		StartAt:             auction.StartAt,
+		EndAt:               auction.EndAt,
+		PaymentTokenAddress: auction.PaymentTokenAddress,
+		StartPrice:          auction.StartPrice,
+		ReserveMet:          auction.ReserveMet(),
+		MinBidIncrementBps:  auction.MinBidIncrementBps,
+		ExtensionWindow:     auction.ExtensionWindow,
+		MinNextBid:          auction.MinNextBid().String(),
+		Bids:                make([]*AuctionBidResponse, 0, len(auction.Bids)),
+	}
+
+	for _, bid := range auction.Bids {
+		bidResp := toAuctionBidResponse(bid)
+		resp.Bids = append(resp.Bids, bidResp)
+
+		if auction.WinningBidID.Valid && auction.WinningBidID.Int64 == bid.ID {
+			resp.WinningBid = bidResp
+		}
+	}
+
+	if winningOrder != nil {
+		resp.WinningOrder = toOrderResponse(winningOrder, tokens)
+	}
+
+	return resp
+}
+
 func toOrdersResponse(orders []*model.Order, tokens map[string]*model.Token, count *ItemsCountResponse) *OrdersResponse {
 	resp := &OrdersResponse{
 		Orders: make([]*OrderResponse, 0),
@@ -467,6 +817,121 @@ func toMediaResponse(media *model.Media, locked bool) *MediaResponse {
 		resp.Creator = toAccountResponse(media.CreatedBy)
 	}
 
+	resp.Renditions = media.Renditions
+	if resp.Renditions == nil {
+		resp.Renditions = make([]*model.MediaRendition, 0)
+	}
+
+	if media.HasPreview() {
+		resp.PreviewURL = pointer.ToString(media.GetCachedPreviewUrl())
+	}
+
+	// both manifests are protected with Clear Key, players pick the one of
+	// the format they prefer. Safari and iOS play the AES-128 playlists the
+	// license endpoint serves instead, the jwt of the player is appended to
+	// their api path as the token query param.
+	if !media.Featured && locked && (media.IsVideo() || media.IsAudio()) {
+		resp.DashURL = pointer.ToString(media.GetCachedEncryptedUrl())
+		if media.HasEncryptedHLS() {
+			resp.HLSURL = pointer.ToString(media.GetCachedEncryptedHLSUrl())
+		}
+		if media.HasEncryptedHLSAES() {
+			resp.HLSAESURL = pointer.ToString(
+				fmt.Sprintf("/api/v1/license/hls/%s/%s", media.ID, path.Base(media.EncryptedHLSAESKey)),
+			)
+		}
+	}
+
+	if media.JobID.Valid {
+		resp.JobID = pointer.ToString(media.JobID.String)
+	}
+
+	return resp
+}
+
+func toJobResponse(job *model.Job) *JobResponse {
+	resp := &JobResponse{
+		ID:          job.ID,
+		Type:        job.Type,
+		Status:      job.Status,
+		Checkpoints: job.Checkpoints,
+		Attempts:    job.Attempts,
+		MaxAttempts: job.MaxAttempts,
+		RunAt:       job.RunAt,
+		CreatedAt:   job.CreatedAt,
+		UpdatedAt:   job.UpdatedAt,
+	}
+
+	if resp.Checkpoints == nil {
+		resp.Checkpoints = []string{}
+	}
+
+	if job.LastError.Valid {
+		resp.LastError = pointer.ToString(job.LastError.String)
+	}
+
+	return resp
+}
+
+func toAssetKeyEnvelopeResponse(envelope *model.AssetKeyEnvelope) *AssetKeyEnvelopeResponse {
+	resp := &AssetKeyEnvelopeResponse{
+		Role:      envelope.Role,
+		Envelope:  envelope.Envelope,
+		CreatedAt: envelope.CreatedAt,
+	}
+
+	if envelope.Account != nil {
+		resp.Account = toAccountResponse(envelope.Account)
+	}
+
+	if envelope.GrantedByID.Valid {
+		resp.GrantedByID = pointer.ToInt64(envelope.GrantedByID.Int64)
+	}
+
+	return resp
+}
+
+func toAssetKeyEnvelopesResponse(envelopes []*model.AssetKeyEnvelope) []*AssetKeyEnvelopeResponse {
+	resp := make([]*AssetKeyEnvelopeResponse, 0, len(envelopes))
+	for _, envelope := range envelopes {
+		resp = append(resp, toAssetKeyEnvelopeResponse(envelope))
+	}
+
+	return resp
+}
+
+// toAssetBatchItemResponse reports the progress of a batch item, chainTx
+// is the mint transaction sent for it if it hasn't been confirmed yet.
+func toAssetBatchItemResponse(asset *model.Asset, chainTx *model.ChainTx) *AssetBatchItemResponse {
+	resp := &AssetBatchItemResponse{
+		AssetID:  asset.ID,
+		Name:     asset.Name.String,
+		Status:   asset.Status,
+		LazyMint: asset.LazyMint,
+		Progress: AssetBatchItemProgressProcessing,
+	}
+
+	if asset.JobID.Valid {
+		resp.JobID = pointer.ToString(asset.JobID.String)
+	}
+
+	switch {
+	case asset.Status == model.AssetStatusFailed:
+		resp.Progress = AssetBatchItemProgressFailed
+	case asset.IsMinted():
+		resp.Progress = AssetBatchItemProgressMinted
+		resp.MintTxHash = pointer.ToString(asset.MintTxID.String)
+	case asset.LazyMint:
+		if asset.MintVoucher != nil {
+			resp.Progress = AssetBatchItemProgressListed
+		}
+	case chainTx != nil:
+		resp.Progress = AssetBatchItemProgressMinting
+		resp.MintTxHash = pointer.ToString(chainTx.TxHash)
+	case asset.TokenCID.Valid:
+		resp.Progress = AssetBatchItemProgressQueued
+	}
+
 	return resp
 }
 
@@ -512,3 +977,106 @@ func toActivityResponse(items []*model.Activity, count *ItemsCountResponse) *Act
 
 	return resp
 }
+
+func toEarningsItemResponse(item *model.Earnings) *EarningsItemResponse {
+	resp := &EarningsItemResponse{
+		PaymentToken: item.PaymentTokenAddress,
+		Sales:        item.Sales,
+		Royalties:    item.Royalties,
+		Proceeds:     item.Proceeds,
+		Total:        sumAmounts(item.Royalties, item.Proceeds),
+	}
+
+	if item.Asset != nil {
+		resp.Asset = toAssetResponse(item.Asset)
+	}
+
+	return resp
+}
+
+func toEarningsResponse(items []*model.Earnings, count *ItemsCountResponse) *EarningsResponse {
+	resp := &EarningsResponse{
+		Items: make([]*EarningsItemResponse, 0),
+	}
+
+	for _, item := range items {
+		resp.Items = append(resp.Items, toEarningsItemResponse(item))
+	}
+
+	resp.Count = int64(len(resp.Items))
+	if count != nil {
+		resp.TotalCount = count.TotalCount
+		resp.Prev = resp.Count > 0 && count.Offset > 0
+		resp.Next = resp.Count > 0 && resp.TotalCount > (resp.Count+int64(count.Offset))
+	}
+
+	return resp
+}
+
+// toSaleResponse describes a sale from the point of view of the account,
+// which earned the royalty as the creator and the proceeds as the seller.
+func toSaleResponse(entry *model.LedgerEntry, accountID int64) *SaleResponse {
+	resp := &SaleResponse{
+		ID:             entry.ID,
+		CreatedAt:      entry.CreatedAt,
+		OrderID:        entry.OrderID,
+		TxHash:         entry.TxHash,
+		PaymentToken:   entry.PaymentTokenAddress,
+		Quantity:       entry.Quantity,
+		Price:          entry.Price,
+		RoyaltyBps:     entry.RoyaltyBps,
+		Royalty:        entry.Royalty,
+		PlatformFee:    entry.PlatformFee,
+		SellerProceeds: entry.SellerProceeds,
+	}
+
+	var earned []string
+	if entry.CreatorID.Valid && entry.CreatorID.Int64 == accountID {
+		earned = append(earned, entry.Royalty)
+	}
+	if entry.SellerID.Valid && entry.SellerID.Int64 == accountID {
+		earned = append(earned, entry.SellerProceeds)
+	}
+	resp.Earned = sumAmounts(earned...)
+
+	if entry.Asset != nil {
+		resp.Asset = toAssetResponse(entry.Asset)
+	}
+
+	return resp
+}
+
+func toSalesResponse(entries []*model.LedgerEntry, accountID int64, count *ItemsCountResponse) *SalesResponse {
+	resp := &SalesResponse{
+		Items: make([]*SaleResponse, 0),
+	}
+
+	for _, entry := range entries {
+		resp.Items = append(resp.Items, toSaleResponse(entry, accountID))
+	}
+
+	resp.Count = int64(len(resp.Items))
+	if count != nil {
+		resp.TotalCount = count.TotalCount
+		resp.Prev = resp.Count > 0 && count.Offset > 0
+		resp.Next = resp.Count > 0 && resp.TotalCount > (resp.Count+int64(count.Offset))
+	}
+
+	return resp
+}
+
+// sumAmounts adds up wei amounts, invalid amounts count as zero.
+func sumAmounts(amounts ...string) string {
+	total := new(big.Int)
+	for _, amount := range amounts {
+		value, ok := new(big.Int).SetString(amount, 10)
+		if ok {
+			total.Add(total, value)
+		}
+	}
+	return total.String()
+}
+
+type ReplayChainEventsResponse struct {
+	Events int64 `json:"events"`
+}
@@ -7,10 +7,12 @@ import (
 	"github.com/labstack/echo/v4/middleware"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/sirupsen/logrus"
+	"github.com/videocoin/marketplace/internal/auction"
 	"github.com/videocoin/marketplace/internal/auth"
 	"github.com/videocoin/marketplace/internal/datastore"
 	"github.com/videocoin/marketplace/internal/mediaprocessor"
 	"github.com/videocoin/marketplace/internal/minter"
+	"github.com/videocoin/marketplace/internal/network"
 	"github.com/videocoin/marketplace/internal/storage"
 	"github.com/videocoin/marketplace/pkg/logger"
 	"net/http"
@@ -24,7 +26,17 @@ type Server struct {
 	storage    *storage.Storage
 	mp         *mediaprocessor.MediaProcessor
 	e          *echo.Echo
-	minter     *minter.Minter
+	minters    minter.Minters
+	networks   *network.Registry
+	auctions   *auction.Manager
+	staticPath string
+	staticDir  string
+
+	feeRecipient   string
+	platformFeeBps int64
+
+	adminToken string
+	replayers  map[string]EventReplayer
 }
 
 func NewServer(ctx context.Context, opts ...ServerOption) (*Server, error) {
@@ -52,6 +64,10 @@ func (s *Server) route() {
 	s.e.Use(logger.NewEchoLogrus())
 
 	s.e.GET("/healthz", s.health)
+
+	if s.staticDir != "" {
+		s.e.Static(s.staticPath, s.staticDir)
+	}
 	s.e.GET("/metrics", echo.WrapHandler(promhttp.Handler()))
 
 	v1 := s.e.Group("/api/v1")
@@ -73,6 +89,10 @@ func (s *Server) route() {
 	assetsGroup.GET("", s.getAssets)
 	assetsGroup.POST("", s.createAsset, auth.JWTAuth(s.logger, s.ds, s.authSecret))
 	assetsGroup.GET("/:asset_id", s.getAsset)
+	assetsGroup.GET("/:asset_id/auction", s.getAssetAuction)
+	assetsGroup.GET("/:asset_id/keys", s.getAssetKeys, auth.JWTAuth(s.logger, s.ds, s.authSecret))
+	assetsGroup.POST("/:asset_id/delegates", s.addAssetDelegate, auth.JWTAuth(s.logger, s.ds, s.authSecret))
+	assetsGroup.DELETE("/:asset_id/delegates/:address", s.revokeAssetDelegate, auth.JWTAuth(s.logger, s.ds, s.authSecret))
 
 	mediaGroup := v1.Group("/media")
 	mediaGroup.POST("/upload", s.uploadMedia, auth.JWTAuth(s.logger, s.ds, s.authSecret))
@@ -80,6 +100,7 @@ func (s *Server) route() {
 
 	v1.GET("/asset/:contract_address/:token_id", s.getAssetByContractAddressAndTokenID)
 	v1.GET("/tokens", s.getTokens)
+	v1.GET("/networks", s.getNetworks)
 	s.e.POST("/wyvern/v1/orders/post", s.postOrder, auth.JWTAuth(s.logger, s.ds, s.authSecret))
 	s.e.GET("/wyvern/v1/orders", s.getOrders)
 
@@ -88,6 +109,11 @@ func (s *Server) route() {
 	myGroup.GET("", s.getMyAssets)
 	myGroup.GET("/sold", s.getMySoldAssets)
 
+	earningsGroup := v1.Group("/my/earnings")
+	earningsGroup.Use(auth.JWTAuth(s.logger, s.ds, s.authSecret))
+	earningsGroup.GET("", s.getMyEarnings)
+	earningsGroup.GET("/sales", s.getMySales)
+
 	creatorsGroup := v1.Group("/creators")
 	creatorsGroup.GET("", s.GetCreators)
 	creatorsGroup.GET("/:creator_id", s.GetCreator)
@@ -98,9 +124,33 @@ func (s *Server) route() {
 	spotlightGroup.GET("/assets/live", s.getSpotlightLiveAssets)
 	spotlightGroup.GET("/creators/featured", s.getSpotlightFeaturedCreators)
 
+	licenseGroup := v1.Group("/license")
+	licenseGroup.Use(auth.JWTAuth(s.logger, s.ds, s.authSecret))
+	licenseGroup.POST("/clearkey", s.getClearKeyLicense)
+
+	hlsGroup := v1.Group("/license/hls")
+	hlsGroup.Use(hlsTokenAuth, auth.JWTAuth(s.logger, s.ds, s.authSecret))
+	hlsGroup.GET("/:media_id/key", s.getHLSKey)
+	hlsGroup.GET("/:media_id/:playlist", s.getHLSPlaylist)
+
+	batchesGroup := v1.Group("/batches")
+	batchesGroup.Use(auth.JWTAuth(s.logger, s.ds, s.authSecret))
+	batchesGroup.POST("", s.createAssetsBatch)
+	batchesGroup.GET("/:batch_id", s.getAssetsBatch)
+
+	jobsGroup := v1.Group("/jobs")
+	jobsGroup.Use(auth.JWTAuth(s.logger, s.ds, s.authSecret))
+	jobsGroup.GET("/:job_id", s.getJob)
+
 	activityGroup := v1.Group("/activity")
 	activityGroup.Use(auth.JWTAuth(s.logger, s.ds, s.authSecret))
 	activityGroup.GET("", s.getActivity)
+
+	if s.adminToken != "" && len(s.replayers) > 0 {
+		adminGroup := v1.Group("/admin")
+		adminGroup.Use(auth.AdminAuth(s.adminToken))
+		adminGroup.POST("/chain-events/replay", s.replayChainEvents)
+	}
 }
 
 func (s *Server) health(c echo.Context) error {
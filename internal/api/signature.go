@@ -18,15 +18,9 @@ func verifySignature(signature string, nonce string, address string) (string, er
 		return "", err
 	}
 
-	if len(decodedSig) < 65 || (decodedSig[64] != 27 && decodedSig[64] != 28) {
-		return "", ErrInvalidSignature
-	}
-
-	decodedSig[64] -= 27
-
-	recoveredPublicKey, err := crypto.SigToPub(ethutil.SignHash([]byte(nonce)), decodedSig)
+	recoveredPublicKey, err := ethutil.RecoverPubKey(ethutil.SignHash([]byte(nonce)), decodedSig)
 	if err != nil {
-		return "", err
+		return "", ErrInvalidSignature
 	}
 
 	pubKeyHex := hex.EncodeToString(crypto.FromECDSAPub(recoveredPublicKey))
@@ -11,7 +11,7 @@ import (
 
 var (
 	SupportedContentTypes = []string{
-		"video/mp4", "video/quicktime",
+		"video/mp4", "video/quicktime", "video/webm", "video/x-matroska",
 		"image/jpeg", "image/png", "image/gif", "image/webp",
 		"audio/mpeg", "text/plain", "application/pdf",
 		"application/zip"}
@@ -2,27 +2,35 @@ package app
 
 import (
 	"context"
+	"math/big"
 
 	"github.com/videocoin/marketplace/internal/mediaprocessor"
 
+	"github.com/ethereum/go-ethereum/params"
 	"github.com/grpc-ecosystem/go-grpc-middleware/logging/logrus/ctxlogrus"
 	"github.com/sirupsen/logrus"
 	"github.com/videocoin/marketplace/internal/api"
+	"github.com/videocoin/marketplace/internal/auction"
 	"github.com/videocoin/marketplace/internal/datastore"
+	"github.com/videocoin/marketplace/internal/jobs"
 	"github.com/videocoin/marketplace/internal/listener"
 	"github.com/videocoin/marketplace/internal/minter"
+	"github.com/videocoin/marketplace/internal/network"
 	"github.com/videocoin/marketplace/internal/orderbook"
 	"github.com/videocoin/marketplace/internal/storage"
 )
 
 type App struct {
-	cfg    *Config
-	logger *logrus.Entry
-	stop   chan bool
-	ds     *datastore.Datastore
-	mp     *mediaprocessor.MediaProcessor
-	api    *api.Server
-	el     *listener.ExchangeListener
+	cfg     *Config
+	logger  *logrus.Entry
+	stop    chan bool
+	ds      *datastore.Datastore
+	mp      *mediaprocessor.MediaProcessor
+	api     *api.Server
+	els     []*listener.ExchangeListener
+	minters minter.Minters
+	jp      *jobs.Pool
+	am      *auction.Manager
 }
 
 func NewApp(ctx context.Context, cfg *Config) (*App, error) {
@@ -35,32 +43,71 @@ func NewApp(ctx context.Context, cfg *Config) (*App, error) {
 	}
 
 	storageOpts := make([]storage.Option, 0)
-	storageOpts = append(storageOpts, storage.WithGCPStorage(cfg.GCPBucket))
-	logger.WithField("gcp_bucket", cfg.GCPBucket).Info("initializing cache storage")
 
-	if cfg.StorageBackend == storage.NftStorage {
+	localConfig := &storage.LocalConfig{
+		Dir:     cfg.StorageLocalDir,
+		BaseURL: cfg.StorageLocalBaseURL,
+	}
+	s3Config := &storage.S3Config{
+		Endpoint:  cfg.S3Endpoint,
+		Region:    cfg.S3Region,
+		Bucket:    cfg.S3Bucket,
+		AccessKey: cfg.S3AccessKey,
+		SecretKey: cfg.S3SecretKey,
+		PublicURL: cfg.S3PublicURL,
+	}
+
+	switch cfg.CacheBackend {
+	case storage.GCS:
+		storageOpts = append(storageOpts, storage.WithGCPStorage(cfg.GCPBucket))
+		logger.WithField("gcp_bucket", cfg.GCPBucket).Info("initializing cache storage")
+	case storage.Local:
+		storageOpts = append(storageOpts, storage.WithLocalCache(localConfig))
+		logger.WithField("dir", cfg.StorageLocalDir).Info("initializing local cache storage")
+	case storage.S3:
+		storageOpts = append(storageOpts, storage.WithS3Cache(s3Config))
+		logger.WithField("s3_bucket", cfg.S3Bucket).Info("initializing s3 cache storage")
+	default:
+		return nil, storage.ErrUnknownStorageBackend
+	}
+
+	switch cfg.StorageBackend {
+	case storage.NftStorage:
 		storageOpts = append(storageOpts, storage.WithNftStorage(&storage.NftStorageConfig{
 			ApiKey: cfg.NftStorageApiKey,
 		}))
-		logger.WithField("storage", "nftstorage").Info("initializing storage backend")
-	} else {
+	case storage.Local:
+		storageOpts = append(storageOpts, storage.WithLocal(localConfig))
+	case storage.S3:
+		storageOpts = append(storageOpts, storage.WithS3(s3Config))
+	default:
 		storageOpts = append(storageOpts, storage.WithTextile(&storage.TextileConfig{
 			AuthKey:       cfg.TextileAuthKey,
 			AuthSecret:    cfg.TextileAuthSecret,
 			ThreadID:      cfg.TextileThreadID,
 			BucketRootKey: cfg.TextileBucketRootKey,
 		}))
-		logger.WithField("storage", "textile").Info("initializing storage backend")
 	}
+	logger.WithField("storage", cfg.StorageBackend).Info("initializing storage backend")
+
 	storageCli, err := storage.NewStorage(storageOpts...)
 	if err != nil {
 		return nil, err
 	}
 
+	renditionLadder, err := mediaprocessor.ParseRenditionLadder(cfg.RenditionLadder)
+	if err != nil {
+		return nil, err
+	}
+
 	mpOpts := []mediaprocessor.Option{
 		mediaprocessor.WithLogger(logger.WithField("system", "mediaprocessor")),
 		mediaprocessor.WithDatastore(ds),
 		mediaprocessor.WithStorage(storageCli),
+		mediaprocessor.WithPreviewDuration(cfg.PreviewDuration),
+		mediaprocessor.WithPreviewStart(cfg.PreviewStart),
+		mediaprocessor.WithPreviewWatermark(cfg.PreviewWatermark, cfg.PreviewWatermarkFont),
+		mediaprocessor.WithRenditionLadder(renditionLadder),
 	}
 
 	mc, err := mediaprocessor.NewMediaProcessor(ctx, mpOpts...)
@@ -68,26 +115,72 @@ func NewApp(ctx context.Context, cfg *Config) (*App, error) {
 		return nil, err
 	}
 
-	m, err := minter.NewMinter(
-		cfg.BlockchainURL,
-		cfg.BlockchainId,
-		cfg.ERC721ContractAddress,
-		cfg.ERC721ContractKeyFile,
-		cfg.ERC721ContractKeyPass,
+	networks, err := cfg.Networks()
+	if err != nil {
+		return nil, err
+	}
+
+	registry, err := network.NewRegistry(networks)
+	if err != nil {
+		return nil, err
+	}
+
+	minterOpts := []minter.Option{
+		minter.WithDatastore(ds),
+		minter.WithConfirmInterval(cfg.MinterConfirmInterval),
+		minter.WithStuckAfter(cfg.MinterStuckAfter),
+		minter.WithFeeBumpPercent(cfg.MinterFeeBumpPercent),
+		minter.WithMaxBatchSize(cfg.MinterMaxBatchSize),
+		minter.WithBatchMint(cfg.MinterBatchMintMethod),
+		minter.WithBatchMint1155(cfg.MinterBatchMint1155Method),
+	}
+	if cfg.MinterMaxGasPriceGwei > 0 {
+		maxGasPrice := new(big.Int).Mul(big.NewInt(cfg.MinterMaxGasPriceGwei), big.NewInt(params.GWei))
+		minterOpts = append(minterOpts, minter.WithMaxGasPrice(maxGasPrice))
+	}
+
+	minters := make(minter.Minters, len(networks))
+	for _, n := range registry.List() {
+		opts := append([]minter.Option{
+			minter.WithLogger(logger.WithField("system", "minter").WithField("network", n.Name)),
+			minter.WithNetwork(n.Name),
+		}, minterOpts...)
+
+		m, err := minter.NewMinter(
+			n.RPCURL,
+			n.ChainID,
+			n.ERC721ContractAddress,
+			n.ERC1155ContractAddress,
+			n.KeyFile,
+			n.KeyPass,
+			opts...,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		minters[n.Name] = m
+	}
+
+	jp, err := jobs.NewPool(
+		ctx,
+		jobs.WithLogger(logger.WithField("system", "jobs")),
+		jobs.WithDatastore(ds),
+		jobs.WithMediaProcessor(mc),
+		jobs.WithStorage(storageCli),
+		jobs.WithMinters(minters),
+		jobs.WithWorkers(cfg.JobWorkers),
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	apiSrv, err := api.NewServer(
+	am, err := auction.NewManager(
 		ctx,
-		api.WithAddr(cfg.Addr),
-		api.WithLogger(logger.WithField("system", "assets")),
-		api.WithAuthSecret(cfg.AuthSecret),
-		api.WithDatastore(ds),
-		api.WithStorage(storageCli),
-		api.WithMediaConverter(mc),
-		api.WithMinter(m),
+		auction.WithLogger(logger.WithField("system", "auction")),
+		auction.WithDatastore(ds),
+		auction.WithMinBidIncrementBps(cfg.AuctionMinBidIncrementBps),
+		auction.WithExtensionWindow(cfg.AuctionExtensionWindow),
 	)
 	if err != nil {
 		return nil, err
@@ -95,35 +188,68 @@ func NewApp(ctx context.Context, cfg *Config) (*App, error) {
 
 	ob, err := orderbook.NewOderBook(
 		ctx,
-		orderbook.WithMinter(m),
+		orderbook.WithMinters(minters),
 		orderbook.WithDatastore(ds),
-		orderbook.WithMediaProcessor(mc),
-		orderbook.WithStorage(storageCli),
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	el, err := listener.NewExchangeListener(
+	els := make([]*listener.ExchangeListener, 0, len(networks))
+	replayers := make(map[string]api.EventReplayer, len(networks))
+	for _, n := range registry.List() {
+		el, err := listener.NewExchangeListener(
+			ctx,
+			listener.WithLogger(logger.WithField("system", "exchange-listener").WithField("network", n.Name)),
+			listener.WithNetwork(n.Name),
+			listener.WithBlockchainURL(n.RPCURL),
+			listener.WithScanFrom(n.ScanFrom),
+			listener.WithConfirmations(n.Confirmations),
+			listener.WithLogStep(cfg.BlockchainLogStep),
+			listener.WithPollInterval(cfg.BlockchainPollInterval),
+			listener.WithContractAddress(n.ExchangeContractAddress),
+			listener.WithNFTContractAddress(n.ERC721ContractAddress),
+			listener.WithNFT1155ContractAddress(n.ERC1155ContractAddress),
+			listener.WithDatastore(ds),
+			listener.WithOrderbook(ob),
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		els = append(els, el)
+		replayers[n.Name] = el
+	}
+
+	apiSrv, err := api.NewServer(
 		ctx,
-		listener.WithBlockchainURL(cfg.BlockchainURL),
-		listener.WithScanFrom(cfg.BlockchainScanFrom),
-		listener.WithContractAddress(cfg.ERC721AuctionContractAddress),
-		listener.WithDatastore(ds),
-		listener.WithOrderbook(ob),
+		api.WithAddr(cfg.Addr),
+		api.WithLogger(logger.WithField("system", "assets")),
+		api.WithAuthSecret(cfg.AuthSecret),
+		api.WithDatastore(ds),
+		api.WithStorage(storageCli),
+		api.WithMediaConverter(mc),
+		api.WithNetworks(registry, minters),
+		api.WithStaticDir(cfg.StorageLocalURLPath, localStorageDir(storageCli)),
+		api.WithAuctions(am),
+		api.WithFees(cfg.FeeRecipient, cfg.PlatformFeeBps),
+		api.WithAdmin(cfg.AdminToken, replayers),
 	)
 	if err != nil {
 		return nil, err
 	}
 
 	return &App{
-		cfg:    cfg,
-		logger: ctxlogrus.Extract(ctx),
-		stop:   make(chan bool, 1),
-		ds:     ds,
-		mp:     mc,
-		api:    apiSrv,
-		el:     el,
+		cfg:     cfg,
+		logger:  ctxlogrus.Extract(ctx),
+		stop:    make(chan bool, 1),
+		ds:      ds,
+		mp:      mc,
+		api:     apiSrv,
+		els:     els,
+		minters: minters,
+		jp:      jp,
+		am:      am,
 	}, nil
 }
 
@@ -132,8 +258,24 @@ func (s *App) Start(errCh chan error) {
 		s.api.Start(errCh)
 	}()
 
+	for _, el := range s.els {
+		go func(el *listener.ExchangeListener) {
+			el.Start(errCh)
+		}(el)
+	}
+
+	for _, m := range s.minters {
+		go func(m *minter.Minter) {
+			m.Start(errCh)
+		}(m)
+	}
+
+	go func() {
+		s.jp.Start(errCh)
+	}()
+
 	go func() {
-		s.el.Start(errCh)
+		s.am.Start(errCh)
 	}()
 
 	select {
@@ -150,11 +292,41 @@ func (s *App) Stop() error {
 		s.logger.WithError(err).Error("failed to stop api server")
 	}
 
-	err = s.el.Stop()
+	for _, el := range s.els {
+		err = el.Stop()
+		if err != nil {
+			s.logger.WithError(err).Error("failed to stop exchange listener")
+		}
+	}
+
+	for _, m := range s.minters {
+		err = m.Stop()
+		if err != nil {
+			s.logger.WithError(err).Error("failed to stop minter")
+		}
+	}
+
+	err = s.jp.Stop()
+	if err != nil {
+		s.logger.WithError(err).Error("failed to stop job workers")
+	}
+
+	err = s.am.Stop()
 	if err != nil {
-		s.logger.WithError(err).Error("failed to stop exchange listener")
+		s.logger.WithError(err).Error("failed to stop auction manager")
 	}
 
 	s.stop <- true
 	return nil
 }
+
+// localStorageDir returns the directory the api has to serve when objects
+// are kept on the local filesystem.
+func localStorageDir(s *storage.Storage) string {
+	for _, b := range []storage.Backend{s.Backend(), s.Cache()} {
+		if local, ok := b.(*storage.LocalBackend); ok {
+			return local.Dir()
+		}
+	}
+	return ""
+}
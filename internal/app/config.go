@@ -1,5 +1,11 @@
 package app
 
+import (
+	"time"
+
+	"github.com/videocoin/marketplace/internal/network"
+)
+
 type Config struct {
 	Name    string `envconfig:"-"`
 	Version string `envconfig:"-"`
@@ -7,8 +13,28 @@ type Config struct {
 	Addr       string `envconfig:"ADDR" default:"0.0.0.0:8088"`
 	DBURI      string `envconfig:"DBURI" default:"host=127.0.0.1 port=5432 dbname=marketplace sslmode=disable"`
 	AuthSecret string `envconfig:"AUTH_SECRET" default:"secret"`
+	AdminToken string `envconfig:"ADMIN_TOKEN" required:"false"`
+	JobWorkers int    `envconfig:"JOB_WORKERS" default:"4"`
+
+	AuctionMinBidIncrementBps int           `envconfig:"AUCTION_MIN_BID_INCREMENT_BPS" default:"500"`
+	AuctionExtensionWindow    time.Duration `envconfig:"AUCTION_EXTENSION_WINDOW" default:"10m"`
+
+	FeeRecipient   string `envconfig:"FEE_RECIPIENT" required:"false"`
+	PlatformFeeBps int64  `envconfig:"PLATFORM_FEE_BPS" default:"250"`
 
 	StorageBackend string `envconfig:"STORAGE_BACKEND" required:"true" default:"textile"`
+	CacheBackend   string `envconfig:"CACHE_BACKEND" default:"gcs"`
+
+	StorageLocalDir     string `envconfig:"STORAGE_LOCAL_DIR" default:"./data/storage"`
+	StorageLocalURLPath string `envconfig:"STORAGE_LOCAL_URL_PATH" default:"/storage"`
+	StorageLocalBaseURL string `envconfig:"STORAGE_LOCAL_BASE_URL" default:"http://localhost:8088/storage"`
+
+	S3Endpoint  string `envconfig:"S3_ENDPOINT" default:"http://localhost:9000"`
+	S3Region    string `envconfig:"S3_REGION" default:"us-east-1"`
+	S3Bucket    string `envconfig:"S3_BUCKET" default:"marketplace"`
+	S3AccessKey string `envconfig:"S3_ACCESS_KEY" required:"false"`
+	S3SecretKey string `envconfig:"S3_SECRET_KEY" required:"false"`
+	S3PublicURL string `envconfig:"S3_PUBLIC_URL" required:"false"`
 
 	TextileAuthKey       string `envconfig:"TEXTILE_AUTH_KEY" required:"false"`
 	TextileAuthSecret    string `envconfig:"TEXTILE_AUTH_SECRET" required:"false"`
@@ -19,11 +45,60 @@ type Config struct {
 
 	GCPBucket string `envconfig:"GCP_BUCKET" default:"assets-marketplace-dev-videocoin-net"`
 
-	BlockchainURL                string `envconfig:"BLOCKCHAIN_URL" default:"http://localhost:8545"`
-	BlockchainScanFrom           uint64 `envconfig:"BLOCKCHAIN_SCAN_FROM" default:"0"`
-	BlockchainId                 uint64 `envconfig:"BLOCKCHAIN_ID" default:"4"`
-	ERC721ContractAddress        string `envconfig:"ERC721_CONTRACT_ADDRESS"`
-	ERC721AuctionContractAddress string `envconfig:"ERC721_AUCTION_CONTRACT_ADDRESS"`
-	ERC721ContractKeyFile        string `envconfig:"ERC721_CONTRACT_KEY"`
-	ERC721ContractKeyPass        string `envconfig:"ERC721_CONTRACT_KEY_PASS"`
+	// NetworksFile lists the networks as JSON. Without it the marketplace
+	// runs on the single network described by the variables below.
+	NetworksFile      string `envconfig:"NETWORKS_FILE" required:"false"`
+	BlockchainNetwork string `envconfig:"BLOCKCHAIN_NETWORK" default:"default"`
+
+	BlockchainURL                string        `envconfig:"BLOCKCHAIN_URL" default:"http://localhost:8545"`
+	BlockchainScanFrom           uint64        `envconfig:"BLOCKCHAIN_SCAN_FROM" default:"0"`
+	BlockchainConfirmations      uint64        `envconfig:"BLOCKCHAIN_CONFIRMATIONS" default:"12"`
+	BlockchainLogStep            uint64        `envconfig:"BLOCKCHAIN_LOG_STEP" default:"1000"`
+	BlockchainPollInterval       time.Duration `envconfig:"BLOCKCHAIN_POLL_INTERVAL" default:"5s"`
+	BlockchainId                 uint64        `envconfig:"BLOCKCHAIN_ID" default:"4"`
+	ERC721ContractAddress        string        `envconfig:"ERC721_CONTRACT_ADDRESS"`
+	ERC721AuctionContractAddress string        `envconfig:"ERC721_AUCTION_CONTRACT_ADDRESS"`
+	ERC1155ContractAddress       string        `envconfig:"ERC1155_CONTRACT_ADDRESS"`
+	ERC721ContractKeyFile        string        `envconfig:"ERC721_CONTRACT_KEY"`
+	ERC721ContractKeyPass        string        `envconfig:"ERC721_CONTRACT_KEY_PASS"`
+
+	MinterConfirmInterval time.Duration `envconfig:"MINTER_CONFIRM_INTERVAL" default:"15s"`
+	MinterStuckAfter      time.Duration `envconfig:"MINTER_STUCK_AFTER" default:"3m"`
+	MinterFeeBumpPercent  int64         `envconfig:"MINTER_FEE_BUMP_PERCENT" default:"15"`
+	MinterMaxGasPriceGwei int64         `envconfig:"MINTER_MAX_GAS_PRICE_GWEI" default:"0"`
+	MinterMaxBatchSize    int           `envconfig:"MINTER_MAX_BATCH_SIZE" default:"20"`
+	// MinterBatchMintMethod and MinterBatchMint1155Method name the method,
+	// mint, mintBatch or multicall, the deployed contracts mint tokens with.
+	MinterBatchMintMethod     string `envconfig:"MINTER_BATCH_MINT_METHOD" default:"mintBatch"`
+	MinterBatchMint1155Method string `envconfig:"MINTER_BATCH_MINT_1155_METHOD" default:"mintBatch"`
+
+	PreviewDuration      time.Duration `envconfig:"PREVIEW_DURATION" default:"15s"`
+	PreviewStart         time.Duration `envconfig:"PREVIEW_START" default:"0s"`
+	PreviewWatermark     string        `envconfig:"PREVIEW_WATERMARK"`
+	PreviewWatermarkFont string        `envconfig:"PREVIEW_WATERMARK_FONT"`
+
+	RenditionLadder string `envconfig:"RENDITION_LADDER" default:"1080:5000,720:2800,480:1400,360:800"`
+}
+
+// Networks returns the networks of the networks file or the single network
+// configured by the environment.
+func (cfg *Config) Networks() ([]*network.Network, error) {
+	if cfg.NetworksFile != "" {
+		return network.LoadFile(cfg.NetworksFile)
+	}
+
+	return []*network.Network{
+		{
+			Name:                    cfg.BlockchainNetwork,
+			ChainID:                 cfg.BlockchainId,
+			RPCURL:                  cfg.BlockchainURL,
+			ERC721ContractAddress:   cfg.ERC721ContractAddress,
+			ERC1155ContractAddress:  cfg.ERC1155ContractAddress,
+			ExchangeContractAddress: cfg.ERC721AuctionContractAddress,
+			KeyFile:                 cfg.ERC721ContractKeyFile,
+			KeyPass:                 cfg.ERC721ContractKeyPass,
+			ScanFrom:                cfg.BlockchainScanFrom,
+			Confirmations:           cfg.BlockchainConfirmations,
+		},
+	}, nil
 }
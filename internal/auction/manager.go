@@ -0,0 +1,274 @@
+package auction
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AlekSi/pointer"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/logrus/ctxlogrus"
+	"github.com/sirupsen/logrus"
+	"github.com/videocoin/marketplace/internal/datastore"
+	"github.com/videocoin/marketplace/internal/model"
+	"github.com/videocoin/marketplace/pkg/ethutil"
+)
+
+// Manager runs English auctions: it opens them for sell orders, validates
+// bids and closes the auctions once they are over.
+type Manager struct {
+	logger             *logrus.Entry
+	ds                 *datastore.Datastore
+	minBidIncrementBps int
+	extensionWindow    time.Duration
+	checkInterval      time.Duration
+	t                  *time.Ticker
+}
+
+func NewManager(ctx context.Context, opts ...Option) (*Manager, error) {
+	m := &Manager{
+		logger:             ctxlogrus.Extract(ctx).WithField("system", "auction"),
+		minBidIncrementBps: model.DefaultAuctionMinBidIncrementBps,
+		extensionWindow:    model.DefaultAuctionExtensionWindow * time.Second,
+		checkInterval:      10 * time.Second,
+	}
+
+	for _, o := range opts {
+		if err := o(m); err != nil {
+			return nil, err
+		}
+	}
+
+	m.t = time.NewTicker(m.checkInterval)
+
+	return m, nil
+}
+
+// Open starts an auction for the sell order. The auction runs from the order
+// listing time to its expiration time, which the sell order must have, and
+// is never extended past it. The reserve price is taken from
+// EnglishAuctionReservePrice and defaults to the base price.
+func (m *Manager) Open(ctx context.Context, asset *model.Asset, order *model.Order) (*model.Auction, error) {
+	now := time.Now()
+
+	startAt := parseUnixTime(order.WyvernOrder.ListingTime)
+	if startAt == nil || startAt.Before(now) {
+		startAt = pointer.ToTime(now)
+	}
+
+	endAt := parseUnixTime(order.WyvernOrder.ExpirationTime)
+	if endAt == nil {
+		return nil, model.ErrAuctionNoExpiration
+	}
+
+	startPrice, err := ethutil.ParseBigInt(order.WyvernOrder.BasePrice)
+	if err != nil {
+		return nil, err
+	}
+
+	reservePrice := startPrice
+	if order.WyvernOrder.EnglishAuctionReservePrice != "" {
+		reservePrice, err = ethutil.ParseBigInt(order.WyvernOrder.EnglishAuctionReservePrice)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	auction := &model.Auction{
+		AssetID:             asset.ID,
+		SellerID:            order.CreatedByID,
+		SellOrderID:         order.ID,
+		PaymentTokenAddress: strings.ToLower(order.PaymentTokenAddress),
+		StartPrice:          startPrice.String(),
+		ReservePrice:        reservePrice.String(),
+		MinBidIncrementBps:  m.minBidIncrementBps,
+		ExtensionWindow:     int(m.extensionWindow.Seconds()),
+		StartAt:             startAt,
+		EndAt:               endAt,
+		ExpireAt:            endAt,
+	}
+
+	err = m.ds.InTx(ctx, func(ctx context.Context) error {
+		prev, err := m.ds.Auctions.GetOpenByAssetID(ctx, asset.ID)
+		if err != nil && err != datastore.ErrAuctionNotFound {
+			return err
+		}
+
+		if prev != nil {
+			err = m.ds.Auctions.MarkStatusAsCancelled(ctx, prev)
+			if err != nil {
+				return err
+			}
+		}
+
+		return m.ds.Auctions.Create(ctx, auction)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	m.logger.
+		WithField("auction_id", auction.ID).
+		WithField("asset_id", asset.ID).
+		WithField("end_at", auction.EndAt).
+		Info("auction has been opened")
+
+	return auction, nil
+}
+
+// PlaceBid validates the buy order against the open auction of the asset and
+// records it as a bid. It returns datastore.ErrAuctionNotFound when the asset
+// is not on auction.
+func (m *Manager) PlaceBid(ctx context.Context, asset *model.Asset, order *model.Order) (*model.Auction, *model.AuctionBid, error) {
+	amount, err := ethutil.ParseBigInt(order.WyvernOrder.BasePrice)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var auction *model.Auction
+	var bid *model.AuctionBid
+
+	err = m.ds.InTx(ctx, func(ctx context.Context) error {
+		auction, err = m.ds.Auctions.GetOpenByAssetID(ctx, asset.ID)
+		if err != nil {
+			return err
+		}
+
+		auction.Bids, err = m.ds.AuctionBids.ListByAuctionID(ctx, auction.ID)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		err = auction.ValidateBid(order.CreatedByID, strings.ToLower(order.PaymentTokenAddress), amount, now)
+		if err != nil {
+			return err
+		}
+
+		bid = &model.AuctionBid{
+			AuctionID: auction.ID,
+			OrderID:   order.ID,
+			BidderID:  order.CreatedByID,
+			Amount:    amount.String(),
+		}
+		err = m.ds.AuctionBids.Create(ctx, bid)
+		if err != nil {
+			return err
+		}
+
+		auction.Bids = append([]*model.AuctionBid{bid}, auction.Bids...)
+
+		if auction.Extend(now) {
+			err = m.ds.Auctions.UpdateEndAt(ctx, auction, *auction.EndAt)
+			if err != nil {
+				return err
+			}
+
+			m.logger.
+				WithField("auction_id", auction.ID).
+				WithField("end_at", auction.EndAt).
+				Info("auction has been extended")
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return auction, bid, nil
+}
+
+// Get returns the latest auction of the asset with its bids.
+func (m *Manager) Get(ctx context.Context, assetID int64) (*model.Auction, error) {
+	auction, err := m.ds.Auctions.GetLatestByAssetID(ctx, assetID)
+	if err != nil {
+		return nil, err
+	}
+
+	auction.Bids, err = m.ds.AuctionBids.ListByAuctionID(ctx, auction.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return auction, nil
+}
+
+// Close ends the auction. The highest bid wins when it meets the reserve
+// price, its buy order is then left for the seller to match.
+func (m *Manager) Close(ctx context.Context, auction *model.Auction) error {
+	logger := m.logger.
+		WithField("auction_id", auction.ID).
+		WithField("asset_id", auction.AssetID)
+
+	bids, err := m.ds.AuctionBids.ListByAuctionID(ctx, auction.ID)
+	if err != nil {
+		return err
+	}
+	auction.Bids = bids
+
+	if !auction.ReserveMet() {
+		logger.Info("auction has ended unsold")
+		return m.ds.Auctions.MarkStatusAsUnsold(ctx, auction)
+	}
+
+	winner := auction.HighestBid()
+	err = m.ds.Auctions.MarkStatusAsEnded(ctx, auction, winner)
+	if err != nil {
+		return err
+	}
+
+	logger.
+		WithField("bid_id", winner.ID).
+		WithField("order_id", winner.OrderID).
+		WithField("amount", winner.Amount).
+		Info("auction has ended")
+
+	return nil
+}
+
+func (m *Manager) closeExpired(ctx context.Context) error {
+	auctions, err := m.ds.Auctions.ListExpired(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+
+	for _, auction := range auctions {
+		err = m.Close(ctx, auction)
+		if err != nil {
+			m.logger.
+				WithField("auction_id", auction.ID).
+				WithError(err).
+				Error("failed to close auction")
+		}
+	}
+
+	return nil
+}
+
+func (m *Manager) Start(errCh chan error) {
+	m.logger.Info("starting auction manager")
+
+	for range m.t.C {
+		err := m.closeExpired(context.Background())
+		if err != nil {
+			m.logger.WithError(err).Error("failed to close expired auctions")
+			continue
+		}
+	}
+}
+
+func (m *Manager) Stop() error {
+	m.logger.Info("stopping auction manager")
+	m.t.Stop()
+	return nil
+}
+
+func parseUnixTime(value string) *time.Time {
+	ts, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || ts <= 0 {
+		return nil
+	}
+
+	return pointer.ToTime(time.Unix(ts, 0))
+}
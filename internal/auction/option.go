@@ -0,0 +1,45 @@
+package auction
+
+import (
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/videocoin/marketplace/internal/datastore"
+)
+
+type Option func(*Manager) error
+
+func WithLogger(logger *logrus.Entry) Option {
+	return func(m *Manager) error {
+		m.logger = logger
+		return nil
+	}
+}
+
+func WithDatastore(ds *datastore.Datastore) Option {
+	return func(m *Manager) error {
+		m.ds = ds
+		return nil
+	}
+}
+
+func WithMinBidIncrementBps(bps int) Option {
+	return func(m *Manager) error {
+		m.minBidIncrementBps = bps
+		return nil
+	}
+}
+
+func WithExtensionWindow(window time.Duration) Option {
+	return func(m *Manager) error {
+		m.extensionWindow = window
+		return nil
+	}
+}
+
+func WithCheckInterval(interval time.Duration) Option {
+	return func(m *Manager) error {
+		m.checkInterval = interval
+		return nil
+	}
+}
@@ -2,7 +2,7 @@
 // File: @openzeppelin/contracts/introspection/IERC165.sol
 
 
-pragma solidity >=0.6.0 <0.8.0;
+pragma solidity >=0.6.0 <0.9.0;
 
 /**
  * @dev Interface of the ERC165 standard, as defined in the
@@ -28,7 +28,7 @@ interface IERC165 {
 // File: @openzeppelin/contracts/token/ERC1155/IERC1155.sol
 
 
-pragma solidity >=0.6.2 <0.8.0;
+pragma solidity >=0.6.2 <0.9.0;
 
 
 /**
@@ -132,7 +132,7 @@ interface IERC1155 is IERC165 {
 // File: @openzeppelin/contracts/token/ERC1155/IERC1155MetadataURI.sol
 
 
-pragma solidity >=0.6.2 <0.8.0;
+pragma solidity >=0.6.2 <0.9.0;
 
 
 /**
@@ -154,7 +154,7 @@ interface IERC1155MetadataURI is IERC1155 {
 // File: @openzeppelin/contracts/token/ERC1155/IERC1155Receiver.sol
 
 
-pragma solidity >=0.6.0 <0.8.0;
+pragma solidity >=0.6.0 <0.9.0;
 
 
 /**
@@ -212,7 +212,7 @@ interface IERC1155Receiver is IERC165 {
 // File: @openzeppelin/contracts/utils/Context.sol
 
 
-pragma solidity >=0.6.0 <0.8.0;
+pragma solidity >=0.6.0 <0.9.0;
 
 /*
  * @dev Provides information about the current execution context, including the
@@ -226,7 +226,7 @@ pragma solidity >=0.6.0 <0.8.0;
  */
 abstract contract Context {
     function _msgSender() internal view virtual returns (address payable) {
-        return msg.sender;
+        return payable(msg.sender);
     }
 
     function _msgData() internal view virtual returns (bytes memory) {
@@ -238,7 +238,7 @@ abstract contract Context {
 // File: @openzeppelin/contracts/introspection/ERC165.sol
 
 
-pragma solidity >=0.6.0 <0.8.0;
+pragma solidity >=0.6.0 <0.9.0;
 
 
 /**
@@ -293,7 +293,7 @@ abstract contract ERC165 is IERC165 {
 // File: @openzeppelin/contracts/math/SafeMath.sol
 
 
-pragma solidity >=0.6.0 <0.8.0;
+pragma solidity >=0.6.0 <0.9.0;
 
 /**
  * @dev Wrappers over Solidity's arithmetic operations with added overflow
@@ -509,7 +509,7 @@ library SafeMath {
 // File: @openzeppelin/contracts/utils/Address.sol
 
 
-pragma solidity >=0.6.2 <0.8.0;
+pragma solidity >=0.6.2 <0.9.0;
 
 /**
  * @dev Collection of functions related to the address type
@@ -700,7 +700,7 @@ library Address {
 // File: @openzeppelin/contracts/token/ERC1155/ERC1155.sol
 
 
-pragma solidity >=0.6.0 <0.8.0;
+pragma solidity >=0.6.0 <0.9.0;
 
 
 
@@ -1114,7 +1114,7 @@ contract ERC1155 is Context, ERC165, IERC1155, IERC1155MetadataURI {
 
 // File: contracts/NFT1155.sol
 
-pragma solidity 0.7.6;
+pragma solidity ^0.8.0;
 
 
 /**
@@ -1141,4 +1141,25 @@ contract NFT1155 is ERC1155 {
 	function mint(address to, uint256 tokenId) public {
 		_mint(to, tokenId, 1, "");
 	}
+
+    /**
+     *
+     * @dev Mint editions of several tokens with a single transaction.
+     */
+	function mintBatch(address to, uint256[] memory tokenIds, uint256[] memory amounts, bytes memory data) public {
+		_mintBatch(to, tokenIds, amounts, data);
+	}
+
+    /**
+     *
+     * @dev Call several methods of the contract with a single transaction.
+     */
+	function multicall(bytes[] memory data) public returns (bytes[] memory results) {
+		results = new bytes[](data.length);
+		for (uint256 i = 0; i < data.length; i++) {
+			(bool success, bytes memory result) = address(this).delegatecall(data[i]);
+			require(success, "NFT1155: call failed");
+			results[i] = result;
+		}
+	}
 }
@@ -27,10 +27,10 @@ var (
 )
 
 // NFT721ABI is the input ABI used to generate the binding from.
-const NFT721ABI = "[{\"inputs\":[{\"internalType\":\"string\",\"name\":\"name\",\"type\":\"string\"},{\"internalType\":\"string\",\"name\":\"symbol\",\"type\":\"string\"},{\"internalType\":\"addresspayable\",\"name\":\"admin\",\"type\":\"address\"}],\"stateMutability\":\"nonpayable\",\"type\":\"constructor\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"owner\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"approved\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"uint256\",\"name\":\"tokenId\",\"type\":\"uint256\"}],\"name\":\"Approval\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"owner\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"operator\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"bool\",\"name\":\"approved\",\"type\":\"bool\"}],\"name\":\"ApprovalForAll\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"bytes32\",\"name\":\"role\",\"type\":\"bytes32\"},{\"indexed\":true,\"internalType\":\"bytes32\",\"name\":\"previousAdminRole\",\"type\":\"bytes32\"},{\"indexed\":true,\"internalType\":\"bytes32\",\"name\":\"newAdminRole\",\"type\":\"bytes32\"}],\"name\":\"RoleAdminChanged\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"bytes32\",\"name\":\"role\",\"type\":\"bytes32\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"account\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"sender\",\"type\":\"address\"}],\"name\":\"RoleGranted\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"bytes32\",\"name\":\"role\",\"type\":\"bytes32\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"account\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"sender\",\"type\":\"address\"}],\"name\":\"RoleRevoked\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"from\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"uint256\",\"name\":\"tokenId\",\"type\":\"uint256\"}],\"name\":\"Transfer\",\"type\":\"event\"},{\"inputs\":[],\"name\":\"DEFAULT_ADMIN_ROLE\",\"outputs\":[{\"internalType\":\"bytes32\",\"name\":\"\",\"type\":\"bytes32\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"OPERATOR_ROLE\",\"outputs\":[{\"internalType\":\"bytes32\",\"name\":\"\",\"type\":\"bytes32\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"account\",\"type\":\"address\"}],\"name\":\"addAdmin\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"account\",\"type\":\"address\"}],\"name\":\"addOperator\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"tokenId\",\"type\":\"uint256\"}],\"name\":\"approve\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"owner\",\"type\":\"address\"}],\"name\":\"balanceOf\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"baseURI\",\"outputs\":[{\"internalType\":\"string\",\"name\":\"\",\"type\":\"string\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"tokenId\",\"type\":\"uint256\"}],\"name\":\"getApproved\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"role\",\"type\":\"bytes32\"}],\"name\":\"getRoleAdmin\",\"outputs\":[{\"internalType\":\"bytes32\",\"name\":\"\",\"type\":\"bytes32\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"role\",\"type\":\"bytes32\"},{\"internalType\":\"uint256\",\"name\":\"index\",\"type\":\"uint256\"}],\"name\":\"getRoleMember\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"role\",\"type\":\"bytes32\"}],\"name\":\"getRoleMemberCount\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"role\",\"type\":\"bytes32\"},{\"internalType\":\"address\",\"name\":\"account\",\"type\":\"address\"}],\"name\":\"grantRole\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"role\",\"type\":\"bytes32\"},{\"internalType\":\"address\",\"name\":\"account\",\"type\":\"address\"}],\"name\":\"hasRole\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"account\",\"type\":\"address\"}],\"name\":\"isAdmin\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"owner\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"operator\",\"type\":\"address\"}],\"name\":\"isApprovedForAll\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"account\",\"type\":\"address\"}],\"name\":\"isOperator\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"tokenId\",\"type\":\"uint256\"},{\"internalType\":\"string\",\"name\":\"tokenURI\",\"type\":\"string\"}],\"name\":\"mint\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"name\",\"outputs\":[{\"internalType\":\"string\",\"name\":\"\",\"type\":\"string\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"tokenId\",\"type\":\"uint256\"}],\"name\":\"ownerOf\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"account\",\"type\":\"address\"}],\"name\":\"removeOperator\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"renounceAdmin\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"role\",\"type\":\"bytes32\"},{\"internalType\":\"address\",\"name\":\"account\",\"type\":\"address\"}],\"name\":\"renounceRole\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"role\",\"type\":\"bytes32\"},{\"internalType\":\"address\",\"name\":\"account\",\"type\":\"address\"}],\"name\":\"revokeRole\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"from\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"tokenId\",\"type\":\"uint256\"}],\"name\":\"safeTransferFrom\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"from\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"tokenId\",\"type\":\"uint256\"},{\"internalType\":\"bytes\",\"name\":\"_data\",\"type\":\"bytes\"}],\"name\":\"safeTransferFrom\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"operator\",\"type\":\"address\"},{\"internalType\":\"bool\",\"name\":\"approved\",\"type\":\"bool\"}],\"name\":\"setApprovalForAll\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes4\",\"name\":\"interfaceId\",\"type\":\"bytes4\"}],\"name\":\"supportsInterface\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"symbol\",\"outputs\":[{\"internalType\":\"string\",\"name\":\"\",\"type\":\"string\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"index\",\"type\":\"uint256\"}],\"name\":\"tokenByIndex\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"owner\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"index\",\"type\":\"uint256\"}],\"name\":\"tokenOfOwnerByIndex\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"tokenId\",\"type\":\"uint256\"}],\"name\":\"tokenURI\",\"outputs\":[{\"internalType\":\"string\",\"name\":\"\",\"type\":\"string\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"totalSupply\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"from\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"tokenId\",\"type\":\"uint256\"}],\"name\":\"transferFrom\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"tokenId\",\"type\":\"uint256\"},{\"internalType\":\"string\",\"name\":\"tokenURI\",\"type\":\"string\"}],\"name\":\"updateTokenURI\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"}]"
+const NFT721ABI = "[{\"inputs\":[{\"internalType\":\"string\",\"name\":\"name\",\"type\":\"string\"},{\"internalType\":\"string\",\"name\":\"symbol\",\"type\":\"string\"},{\"internalType\":\"addresspayable\",\"name\":\"admin\",\"type\":\"address\"}],\"stateMutability\":\"nonpayable\",\"type\":\"constructor\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"owner\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"approved\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"uint256\",\"name\":\"tokenId\",\"type\":\"uint256\"}],\"name\":\"Approval\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"owner\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"operator\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"bool\",\"name\":\"approved\",\"type\":\"bool\"}],\"name\":\"ApprovalForAll\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"bytes32\",\"name\":\"role\",\"type\":\"bytes32\"},{\"indexed\":true,\"internalType\":\"bytes32\",\"name\":\"previousAdminRole\",\"type\":\"bytes32\"},{\"indexed\":true,\"internalType\":\"bytes32\",\"name\":\"newAdminRole\",\"type\":\"bytes32\"}],\"name\":\"RoleAdminChanged\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"bytes32\",\"name\":\"role\",\"type\":\"bytes32\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"account\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"sender\",\"type\":\"address\"}],\"name\":\"RoleGranted\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"bytes32\",\"name\":\"role\",\"type\":\"bytes32\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"account\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"sender\",\"type\":\"address\"}],\"name\":\"RoleRevoked\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"from\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"uint256\",\"name\":\"tokenId\",\"type\":\"uint256\"}],\"name\":\"Transfer\",\"type\":\"event\"},{\"inputs\":[],\"name\":\"DEFAULT_ADMIN_ROLE\",\"outputs\":[{\"internalType\":\"bytes32\",\"name\":\"\",\"type\":\"bytes32\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"OPERATOR_ROLE\",\"outputs\":[{\"internalType\":\"bytes32\",\"name\":\"\",\"type\":\"bytes32\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"account\",\"type\":\"address\"}],\"name\":\"addAdmin\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"account\",\"type\":\"address\"}],\"name\":\"addOperator\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"tokenId\",\"type\":\"uint256\"}],\"name\":\"approve\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"owner\",\"type\":\"address\"}],\"name\":\"balanceOf\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"baseURI\",\"outputs\":[{\"internalType\":\"string\",\"name\":\"\",\"type\":\"string\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"tokenId\",\"type\":\"uint256\"}],\"name\":\"getApproved\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"role\",\"type\":\"bytes32\"}],\"name\":\"getRoleAdmin\",\"outputs\":[{\"internalType\":\"bytes32\",\"name\":\"\",\"type\":\"bytes32\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"role\",\"type\":\"bytes32\"},{\"internalType\":\"uint256\",\"name\":\"index\",\"type\":\"uint256\"}],\"name\":\"getRoleMember\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"role\",\"type\":\"bytes32\"}],\"name\":\"getRoleMemberCount\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"role\",\"type\":\"bytes32\"},{\"internalType\":\"address\",\"name\":\"account\",\"type\":\"address\"}],\"name\":\"grantRole\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"role\",\"type\":\"bytes32\"},{\"internalType\":\"address\",\"name\":\"account\",\"type\":\"address\"}],\"name\":\"hasRole\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"account\",\"type\":\"address\"}],\"name\":\"isAdmin\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"owner\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"operator\",\"type\":\"address\"}],\"name\":\"isApprovedForAll\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"account\",\"type\":\"address\"}],\"name\":\"isOperator\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"tokenId\",\"type\":\"uint256\"},{\"internalType\":\"string\",\"name\":\"tokenURI\",\"type\":\"string\"}],\"name\":\"mint\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"internalType\":\"uint256[]\",\"name\":\"tokenIds\",\"type\":\"uint256[]\"},{\"internalType\":\"string[]\",\"name\":\"tokenURIs\",\"type\":\"string[]\"}],\"name\":\"mintBatch\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes[]\",\"name\":\"data\",\"type\":\"bytes[]\"}],\"name\":\"multicall\",\"outputs\":[{\"internalType\":\"bytes[]\",\"name\":\"results\",\"type\":\"bytes[]\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"name\",\"outputs\":[{\"internalType\":\"string\",\"name\":\"\",\"type\":\"string\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"tokenId\",\"type\":\"uint256\"}],\"name\":\"ownerOf\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"account\",\"type\":\"address\"}],\"name\":\"removeOperator\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"renounceAdmin\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"role\",\"type\":\"bytes32\"},{\"internalType\":\"address\",\"name\":\"account\",\"type\":\"address\"}],\"name\":\"renounceRole\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"role\",\"type\":\"bytes32\"},{\"internalType\":\"address\",\"name\":\"account\",\"type\":\"address\"}],\"name\":\"revokeRole\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"from\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"tokenId\",\"type\":\"uint256\"}],\"name\":\"safeTransferFrom\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"from\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"tokenId\",\"type\":\"uint256\"},{\"internalType\":\"bytes\",\"name\":\"_data\",\"type\":\"bytes\"}],\"name\":\"safeTransferFrom\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"operator\",\"type\":\"address\"},{\"internalType\":\"bool\",\"name\":\"approved\",\"type\":\"bool\"}],\"name\":\"setApprovalForAll\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes4\",\"name\":\"interfaceId\",\"type\":\"bytes4\"}],\"name\":\"supportsInterface\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"symbol\",\"outputs\":[{\"internalType\":\"string\",\"name\":\"\",\"type\":\"string\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"index\",\"type\":\"uint256\"}],\"name\":\"tokenByIndex\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"owner\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"index\",\"type\":\"uint256\"}],\"name\":\"tokenOfOwnerByIndex\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"tokenId\",\"type\":\"uint256\"}],\"name\":\"tokenURI\",\"outputs\":[{\"internalType\":\"string\",\"name\":\"\",\"type\":\"string\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"totalSupply\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"from\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"tokenId\",\"type\":\"uint256\"}],\"name\":\"transferFrom\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"tokenId\",\"type\":\"uint256\"},{\"internalType\":\"string\",\"name\":\"tokenURI\",\"type\":\"string\"}],\"name\":\"updateTokenURI\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"}]"
 
 // NFT721Bin is the compiled bytecode used for deploying new contracts.
-var NFT721Bin = "0x60806040523480156200001157600080fd5b5060405162003f6c38038062003f6c833981810160405260608110156200003757600080fd5b81019080805160405193929190846401000000008211156200005857600080fd5b838201915060208201858111156200006f57600080fd5b82518660018202830111640100000000821117156200008d57600080fd5b8083526020830192505050908051906020019080838360005b83811015620000c3578082015181840152602081019050620000a6565b50505050905090810190601f168015620000f15780820380516001836020036101000a031916815260200191505b50604052602001805160405193929190846401000000008211156200011557600080fd5b838201915060208201858111156200012c57600080fd5b82518660018202830111640100000000821117156200014a57600080fd5b8083526020830192505050908051906020019080838360005b838110156200018057808201518184015260208101905062000163565b50505050905090810190601f168015620001ae5780820380516001836020036101000a031916815260200191505b5060405260200180519060200190929190505050808383620001dd6301ffc9a760e01b6200027860201b60201c565b8160069080519060200190620001f592919062000518565b5080600790805190602001906200020e92919062000518565b50620002276380ac58cd60e01b6200027860201b60201c565b6200023f635b5e139f60e01b6200027860201b60201c565b6200025763780e9d6360e01b6200027860201b60201c565b50506200026e6000801b826200038160201b60201c565b50505050620005ce565b63ffffffff60e01b817bffffffffffffffffffffffffffffffffffffffffffffffffffffffff1916141562000315576040517f08c379a000000000000000000000000000000000000000000000000000000000815260040180806020018281038252601c8152602001807f4552433136353a20696e76616c696420696e746572666163652069640000000081525060200191505060405180910390fd5b6001600080837bffffffffffffffffffffffffffffffffffffffffffffffffffffffff19167bffffffffffffffffffffffffffffffffffffffffffffffffffffffff1916815260200190815260200160002060006101000a81548160ff02191690831515021790555050565b6200039382826200039760201b60201c565b5050565b620003c681600a60008581526020019081526020016000206000016200043b60201b620020d51790919060201c565b156200043757620003dc6200047360201b60201c565b73ffffffffffffffffffffffffffffffffffffffff168173ffffffffffffffffffffffffffffffffffffffff16837f2f8788117e7eff1d82e926ec794901d17c78024a50270940304540a733656f0d60405160405180910390a45b5050565b60006200046b836000018373ffffffffffffffffffffffffffffffffffffffff1660001b6200047b60201b60201c565b905092915050565b600033905090565b60006200048f8383620004f560201b60201c565b620004ea578260000182908060018154018082558091505060019003906000526020600020016000909190919091505582600001805490508360010160008481526020019081526020016000208190555060019050620004ef565b600090505b92915050565b600080836001016000848152602001908152602001600020541415905092915050565b828054600181600116156101000203166002900490600052602060002090601f0160209004810192826200055057600085556200059c565b82601f106200056b57805160ff19168380011785556200059c565b828001600101855582156200059c579182015b828111156200059b5782518255916020019190600101906200057e565b5b509050620005ab9190620005af565b5090565b5b80821115620005ca576000816000905550600101620005b0565b5090565b61398e80620005de6000396000f3fe608060405234801561001057600080fd5b50600436106102065760003560e01c8063704802751161011a578063a22cb465116100ad578063ca15c8731161007c578063ca15c87314610cf8578063d3fc986414610d3a578063d547741f14610e1f578063e985e9c514610e6d578063f5b541a614610ee757610206565b8063a22cb46514610ab8578063ac8a584a14610b08578063b88d4fde14610b4c578063c87b56dd14610c5157610206565b806391d14854116100e957806391d148541461096f57806395d89b41146109d35780639870d7fe14610a56578063a217fddf14610a9a57610206565b8063704802751461086757806370a08231146108ab5780638bad0c0a146109035780639010d07c1461090d57610206565b806324d7806c1161019d57806342842e0e1161016c57806342842e0e146106825780634f6ccce7146106f05780636352211e146107325780636c0360eb1461078a5780636d70f7ae1461080d57610206565b806324d7806c1461052a5780632f2ff15d146105845780632f745c59146105d257806336568abe1461063457610206565b806318160ddd116101d957806318160ddd1461039757806318e97fd1146103b557806323b872dd1461047a578063248a9ca3146104e857610206565b806301ffc9a71461020b57806306fdde031461026e578063081812fc146102f1578063095ea7b314610349575b600080fd5b6102566004803603602081101561022157600080fd5b8101908080357bffffffffffffffffffffffffffffffffffffffffffffffffffffffff19169060200190929190505050610f05565b60405180821515815260200191505060405180910390f35b610276610f6c565b6040518080602001828103825283818151815260200191508051906020019080838360005b838110156102b657808201518184015260208101905061029b565b50505050905090810190601f1680156102e35780820380516001836020036101000a031916815260200191505b509250505060405180910390f35b61031d6004803603602081101561030757600080fd5b810190808035906020019092919050505061100e565b604051808273ffffffffffffffffffffffffffffffffffffffff16815260200191505060405180910390f35b6103956004803603604081101561035f57600080fd5b81019080803573ffffffffffffffffffffffffffffffffffffffff169060200190929190803590602001909291905050506110a9565b005b61039f6111ed565b6040518082815260200191505060405180910390f35b610478600480360360408110156103cb57600080fd5b8101908080359060200190929190803590602001906401000000008111156103f257600080fd5b82018360208201111561040457600080fd5b8035906020019184600183028401116401000000008311171561042657600080fd5b91908080601f016020809104026020016040519081016040528093929190818152602001838380828437600081840152601f19601f8201169050808301925050505050505091929192905050506111fe565b005b6104e66004803603606081101561049057600080fd5b81019080803573ffffffffffffffffffffffffffffffffffffffff169060200190929190803573ffffffffffffffffffffffffffffffffffffffff16906020019092919080359060200190929190505050611271565b005b610514600480360360208110156104fe57600080fd5b81019080803590602001909291905050506112e7565b6040518082815260200191505060405180910390f35b61056c6004803603602081101561054057600080fd5b81019080803573ffffffffffffffffffffffffffffffffffffffff169060200190929190505050611307565b60405180821515815260200191505060405180910390f35b6105d06004803603604081101561059a57600080fd5b8101908080359060200190929190803573ffffffffffffffffffffffffffffffffffffffff16906020019092919050505061131d565b005b61061e600480360360408110156105e857600080fd5b81019080803573ffffffffffffffffffffffffffffffffffffffff169060200190929190803590602001909291905050506113a7565b6040518082815260200191505060405180910390f35b6106806004803603604081101561064a57600080fd5b8101908080359060200190929190803573ffffffffffffffffffffffffffffffffffffffff169060200190929190505050611402565b005b6106ee6004803603606081101561069857600080fd5b81019080803573ffffffffffffffffffffffffffffffffffffffff169060200190929190803573ffffffffffffffffffffffffffffffffffffffff1690602001909291908035906020019092919050505061149b565b005b61071c6004803603602081101561070657600080fd5b81019080803590602001909291905050506114bb565b6040518082815260200191505060405180910390f35b61075e6004803603602081101561074857600080fd5b81019080803590602001909291905050506114de565b604051808273ffffffffffffffffffffffffffffffffffffffff16815260200191505060405180910390f35b610792611515565b6040518080602001828103825283818151815260200191508051906020019080838360005b838110156107d25780820151818401526020810190506107b7565b50505050905090810190601f1680156107ff5780820380516001836020036101000a031916815260200191505b509250505060405180910390f35b61084f6004803603602081101561082357600080fd5b81019080803573ffffffffffffffffffffffffffffffffffffffff1690602001909291905050506115b7565b60405180821515815260200191505060405180910390f35b6108a96004803603602081101561087d57600080fd5b81019080803573ffffffffffffffffffffffffffffffffffffffff1690602001909291905050506115ea565b005b6108ed600480360360208110156108c157600080fd5b81019080803573ffffffffffffffffffffffffffffffffffffffff16906020019092919050505061167c565b6040518082815260200191505060405180910390f35b61090b611751565b005b6109436004803603604081101561092357600080fd5b8101908080359060200190929190803590602001909291905050506117e9565b604051808273ffffffffffffffffffffffffffffffffffffffff16815260200191505060405180910390f35b6109bb6004803603604081101561098557600080fd5b8101908080359060200190929190803573ffffffffffffffffffffffffffffffffffffffff16906020019092919050505061181b565b60405180821515815260200191505060405180910390f35b6109db61184d565b6040518080602001828103825283818151815260200191508051906020019080838360005b83811015610a1b578082015181840152602081019050610a00565b50505050905090810190601f168015610a485780820380516001836020036101000a031916815260200191505b509250505060405180910390f35b610a9860048036036020811015610a6c57600080fd5b81019080803573ffffffffffffffffffffffffffffffffffffffff1690602001909291905050506118ef565b005b610aa261199e565b6040518082815260200191505060405180910390f35b610b0660048036036040811015610ace57600080fd5b81019080803573ffffffffffffffffffffffffffffffffffffffff1690602001909291908035151590602001909291905050506119a5565b005b610b4a60048036036020811015610b1e57600080fd5b81019080803573ffffffffffffffffffffffffffffffffffffffff169060200190929190505050611b5b565b005b610c4f60048036036080811015610b6257600080fd5b81019080803573ffffffffffffffffffffffffffffffffffffffff169060200190929190803573ffffffffffffffffffffffffffffffffffffffff1690602001909291908035906020019092919080359060200190640100000000811115610bc957600080fd5b820183602082011115610bdb57600080fd5b80359060200191846001830284011164010000000083111715610bfd57600080fd5b91908080601f016020809104026020016040519081016040528093929190818152602001838380828437600081840152601f19601f820116905080830192505050505050509192919290505050611c0a565b005b610c7d60048036036020811015610c6757600080fd5b8101908080359060200190929190505050611c82565b6040518080602001828103825283818151815260200191508051906020019080838360005b83811015610cbd578082015181840152602081019050610ca2565b50505050905090810190601f168015610cea5780820380516001836020036101000a031916815260200191505b509250505060405180910390f35b610d2460048036036020811015610d0e57600080fd5b8101908080359060200190929190505050611f53565b6040518082815260200191505060405180910390f35b610e1d60048036036060811015610d5057600080fd5b81019080803573ffffffffffffffffffffffffffffffffffffffff1690602001909291908035906020019092919080359060200190640100000000811115610d9757600080fd5b820183602082011115610da957600080fd5b80359060200191846001830284011164010000000083111715610dcb57600080fd5b91908080601f016020809104026020016040519081016040528093929190818152602001838380828437600081840152601f19601f820116905080830192505050505050509192919290505050611f7a565b005b610e6b60048036036040811015610e3557600080fd5b8101908080359060200190929190803573ffffffffffffffffffffffffffffffffffffffff169060200190929190505050611f93565b005b610ecf60048036036040811015610e8357600080fd5b81019080803573ffffffffffffffffffffffffffffffffffffffff169060200190929190803573ffffffffffffffffffffffffffffffffffffffff16906020019092919050505061201d565b60405180821515815260200191505060405180910390f35b610eef6120b1565b6040518082815260200191505060405180910390f35b6000806000837bffffffffffffffffffffffffffffffffffffffffffffffffffffffff19167bffffffffffffffffffffffffffffffffffffffffffffffffffffffff1916815260200190815260200160002060009054906101000a900460ff169050919050565b606060068054600181600116156101000203166002900480601f0160208091040260200160405190810160405280929190818152602001828054600181600116156101000203166002900480156110045780601f10610fd957610100808354040283529160200191611004565b820191906000526020600020905b815481529060010190602001808311610fe757829003601f168201915b5050505050905090565b600061101982612105565b61106e576040517f08c379a000000000000000000000000000000000000000000000000000000000815260040180806020018281038252602c815260200180613807602c913960400191505060405180910390fd5b6004600083815260200190815260200160002060009054906101000a900473ffffffffffffffffffffffffffffffffffffffff169050919050565b60006110b4826114de565b90508073ffffffffffffffffffffffffffffffffffffffff168373ffffffffffffffffffffffffffffffffffffffff16141561113b576040517f08c379a00000000000000000000000000000000000000000000000000000000081526004018080602001828103825260218152602001806138b76021913960400191505060405180910390fd5b8073ffffffffffffffffffffffffffffffffffffffff1661115a612122565b73ffffffffffffffffffffffffffffffffffffffff161480611189575061118881611183612122565b61201d565b5b6111de576040517f08c379a000000000000000000000000000000000000000000000000000000000815260040180806020018281038252603881526020018061375a6038913960400191505060405180910390fd5b6111e8838361212a565b505050565b60006111f960026121e3565b905090565b61120e611209612122565b6115b7565b611263576040517f08c379a00000000000000000000000000000000000000000000000000000000081526004018080602001828103825260218152602001806139096021913960400191505060405180910390fd5b61126d82826121f8565b5050565b61128261127c612122565b82612282565b6112d7576040517f08c379a00000000000000000000000000000000000000000000000000000000081526004018080602001828103825260318152602001806138d86031913960400191505060405180910390fd5b6112e2838383612376565b505050565b6000600a6000838152602001908152602001600020600201549050919050565b60006113166000801b8361181b565b9050919050565b611344600a60008481526020019081526020016000206002015461133f612122565b61181b565b611399576040517f08c379a000000000000000000000000000000000000000000000000000000000815260040180806020018281038252602f815260200180613653602f913960400191505060405180910390fd5b6113a382826125b9565b5050565b60006113fa82600160008673ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff16815260200190815260200160002061264d90919063ffffffff16565b905092915050565b61140a612122565b73ffffffffffffffffffffffffffffffffffffffff168173ffffffffffffffffffffffffffffffffffffffff161461148d576040517f08c379a000000000000000000000000000000000000000000000000000000000815260040180806020018281038252602f81526020018061392a602f913960400191505060405180910390fd5b6114978282612667565b5050565b6114b683838360405180602001604052806000815250611c0a565b505050565b6000806114d28360026126fb90919063ffffffff16565b50905080915050919050565b600061150e826040518060600160405280602981526020016137bc6029913960026127279092919063ffffffff16565b9050919050565b606060098054600181600116156101000203166002900480601f0160208091040260200160405190810160405280929190818152602001828054600181600116156101000203166002900480156115ad5780601f10611582576101008083540402835291602001916115ad565b820191906000526020600020905b81548152906001019060200180831161159057829003601f168201915b5050505050905090565b60006115e37f523a704056dcd17bcf83bed8b68c59416dac1119be77755efe3bde0a64e46e0c8361181b565b9050919050565b6115fa6115f5612122565b611307565b61166c576040517f08c379a000000000000000000000000000000000000000000000000000000000815260040180806020018281038252601e8152602001807f4f70657261626c653a207265737472696374656420746f2061646d696e73000081525060200191505060405180910390fd5b6116796000801b8261131d565b50565b60008073ffffffffffffffffffffffffffffffffffffffff168273ffffffffffffffffffffffffffffffffffffffff161415611703576040517f08c379a000000000000000000000000000000000000000000000000000000000815260040180806020018281038252602a815260200180613792602a913960400191505060405180910390fd5b61174a600160008473ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff168152602001908152602001600020612746565b9050919050565b61176161175c612122565b611307565b6117d3576040517f08c379a000000000000000000000000000000000000000000000000000000000815260040180806020018281038252601e8152602001807f4f70657261626c653a207265737472696374656420746f2061646d696e73000081525060200191505060405180910390fd5b6117e76000801b6117e2612122565b611402565b565b600061181382600a600086815260200190815260200160002060000161275b90919063ffffffff16565b905092915050565b600061184582600a600086815260200190815260200160002060000161277590919063ffffffff16565b905092915050565b606060078054600181600116156101000203166002900480601f0160208091040260200160405190810160405280929190818152602001828054600181600116156101000203166002900480156118e55780601f106118ba576101008083540402835291602001916118e5565b820191906000526020600020905b8154815290600101906020018083116118c857829003601f168201915b5050505050905090565b6118ff6118fa612122565b611307565b611971576040517f08c379a000000000000000000000000000000000000000000000000000000000815260040180806020018281038252601e8152602001807f4f70657261626c653a207265737472696374656420746f2061646d696e73000081525060200191505060405180910390fd5b61199b7f523a704056dcd17bcf83bed8b68c59416dac1119be77755efe3bde0a64e46e0c8261131d565b50565b6000801b81565b6119ad612122565b73ffffffffffffffffffffffffffffffffffffffff168273ffffffffffffffffffffffffffffffffffffffff161415611a4e576040517f08c379a00000000000000000000000000000000000000000000000000000000081526004018080602001828103825260198152602001807f4552433732313a20617070726f766520746f2063616c6c65720000000000000081525060200191505060405180910390fd5b8060056000611a5b612122565b73ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff16815260200190815260200160002060008473ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff16815260200190815260200160002060006101000a81548160ff0219169083151502179055508173ffffffffffffffffffffffffffffffffffffffff16611b08612122565b73ffffffffffffffffffffffffffffffffffffffff167f17307eab39ab6107e8899845ad3d59bd9653f200f220920489ca2b5937696c318360405180821515815260200191505060405180910390a35050565b611b6b611b66612122565b611307565b611bdd576040517f08c379a000000000000000000000000000000000000000000000000000000000815260040180806020018281038252601e8152602001807f4f70657261626c653a207265737472696374656420746f2061646d696e73000081525060200191505060405180910390fd5b611c077f523a704056dcd17bcf83bed8b68c59416dac1119be77755efe3bde0a64e46e0c82611f93565b50565b611c1b611c15612122565b83612282565b611c70576040517f08c379a00000000000000000000000000000000000000000000000000000000081526004018080602001828103825260318152602001806138d86031913960400191505060405180910390fd5b611c7c848484846127a5565b50505050565b6060611c8d82612105565b611ce2576040517f08c379a000000000000000000000000000000000000000000000000000000000815260040180806020018281038252602f815260200180613888602f913960400191505060405180910390fd5b6000600860008481526020019081526020016000208054600181600116156101000203166002900480601f016020809104026020016040519081016040528092919081815260200182805460018160011615610100020316600290048015611d8b5780601f10611d6057610100808354040283529160200191611d8b565b820191906000526020600020905b815481529060010190602001808311611d6e57829003601f168201915b505050505090506000611d9c611515565b9050600081511415611db2578192505050611f4e565b600082511115611e835780826040516020018083805190602001908083835b60208310611df45780518252602082019150602081019050602083039250611dd1565b6001836020036101000a03801982511681845116808217855250505050505090500182805190602001908083835b60208310611e455780518252602082019150602081019050602083039250611e22565b6001836020036101000a0380198251168184511680821785525050505050509050019250505060405160208183030381529060405292505050611f4e565b80611e8d85612817565b6040516020018083805190602001908083835b60208310611ec35780518252602082019150602081019050602083039250611ea0565b6001836020036101000a03801982511681845116808217855250505050505090500182805190602001908083835b60208310611f145780518252602082019150602081019050602083039250611ef1565b6001836020036101000a03801982511681845116808217855250505050505090500192505050604051602081830303815290604052925050505b919050565b6000611f73600a600084815260200190815260200160002060000161295e565b9050919050565b611f848383612973565b611f8e82826121f8565b505050565b611fba600a600084815260200190815260200160002060020154611fb5612122565b61181b565b61200f576040517f08c379a000000000000000000000000000000000000000000000000000000000815260040180806020018281038252603081526020018061372a6030913960400191505060405180910390fd5b6120198282612667565b5050565b6000600560008473ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff16815260200190815260200160002060008373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff16815260200190815260200160002060009054906101000a900460ff16905092915050565b7f523a704056dcd17bcf83bed8b68c59416dac1119be77755efe3bde0a64e46e0c81565b60006120fd836000018373ffffffffffffffffffffffffffffffffffffffff1660001b612b67565b905092915050565b600061211b826002612bd790919063ffffffff16565b9050919050565b600033905090565b816004600083815260200190815260200160002060006101000a81548173ffffffffffffffffffffffffffffffffffffffff021916908373ffffffffffffffffffffffffffffffffffffffff160217905550808273ffffffffffffffffffffffffffffffffffffffff1661219d836114de565b73ffffffffffffffffffffffffffffffffffffffff167f8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b92560405160405180910390a45050565b60006121f182600001612bf1565b9050919050565b61220182612105565b612256576040517f08c379a000000000000000000000000000000000000000000000000000000000815260040180806020018281038252602c815260200180613833602c913960400191505060405180910390fd5b8060086000848152602001908152602001600020908051906020019061227d929190613585565b505050565b600061228d82612105565b6122e2576040517f08c379a000000000000000000000000000000000000000000000000000000000815260040180806020018281038252602c8152602001806136fe602c913960400191505060405180910390fd5b60006122ed836114de565b90508073ffffffffffffffffffffffffffffffffffffffff168473ffffffffffffffffffffffffffffffffffffffff16148061235c57508373ffffffffffffffffffffffffffffffffffffffff166123448461100e565b73ffffffffffffffffffffffffffffffffffffffff16145b8061236d575061236c818561201d565b5b91505092915050565b8273ffffffffffffffffffffffffffffffffffffffff16612396826114de565b73ffffffffffffffffffffffffffffffffffffffff1614612402576040517f08c379a000000000000000000000000000000000000000000000000000000000815260040180806020018281038252602981526020018061385f6029913960400191505060405180910390fd5b600073ffffffffffffffffffffffffffffffffffffffff168273ffffffffffffffffffffffffffffffffffffffff161415612488576040517f08c379a00000000000000000000000000000000000000000000000000000000081526004018080602001828103825260248152602001806136b46024913960400191505060405180910390fd5b612493838383612c02565b61249e60008261212a565b6124ef81600160008673ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff168152602001908152602001600020612c0790919063ffffffff16565b5061254181600160008573ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff168152602001908152602001600020612c2190919063ffffffff16565b5061255881836002612c3b9092919063ffffffff16565b50808273ffffffffffffffffffffffffffffffffffffffff168473ffffffffffffffffffffffffffffffffffffffff167fddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef60405160405180910390a4505050565b6125e181600a60008581526020019081526020016000206000016120d590919063ffffffff16565b15612649576125ee612122565b73ffffffffffffffffffffffffffffffffffffffff168173ffffffffffffffffffffffffffffffffffffffff16837f2f8788117e7eff1d82e926ec794901d17c78024a50270940304540a733656f0d60405160405180910390a45b5050565b600061265c8360000183612c70565b60001c905092915050565b61268f81600a6000858152602001908152602001600020600001612cf390919063ffffffff16565b156126f75761269c612122565b73ffffffffffffffffffffffffffffffffffffffff168173ffffffffffffffffffffffffffffffffffffffff16837ff6391f5c32d9c69d2a47ea670b442974b53935d1edc7fd64eb21e047a839171b60405160405180910390a45b5050565b60008060008061270e8660000186612d23565b915091508160001c8160001c9350935050509250929050565b600061273a846000018460001b84612dbc565b60001c90509392505050565b600061275482600001612eb2565b9050919050565b600061276a8360000183612c70565b60001c905092915050565b600061279d836000018373ffffffffffffffffffffffffffffffffffffffff1660001b612ec3565b905092915050565b6127b0848484612376565b6127bc84848484612ee6565b612811576040517f08c379a00000000000000000000000000000000000000000000000000000000081526004018080602001828103825260328152602001806136826032913960400191505060405180910390fd5b50505050565b6060600082141561285f576040518060400160405280600181526020017f30000000000000000000000000000000000000000000000000000000000000008152509050612959565b600082905060005b60008214612889578080600101915050600a828161288157fe5b049150612867565b60008167ffffffffffffffff811180156128a257600080fd5b506040519080825280601f01601f1916602001820160405280156128d55781602001600182028036833780820191505090505b50905060006001830390508593505b6000841461295157600a84816128f657fe5b0660300160f81b8282806001900393508151811061291057fe5b60200101907effffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff1916908160001a905350600a848161294957fe5b0493506128e4565b819450505050505b919050565b600061296c82600001612eb2565b9050919050565b600073ffffffffffffffffffffffffffffffffffffffff168273ffffffffffffffffffffffffffffffffffffffff161415612a16576040517f08c379a00000000000000000000000000000000000000000000000000000000081526004018080602001828103825260208152602001807f4552433732313a206d696e7420746f20746865207a65726f206164647265737381525060200191505060405180910390fd5b612a1f81612105565b15612a92576040517f08c379a000000000000000000000000000000000000000000000000000000000815260040180806020018281038252601c8152602001807f4552433732313a20746f6b656e20616c7265616479206d696e7465640000000081525060200191505060405180910390fd5b612a9e60008383612c02565b612aef81600160008573ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff168152602001908152602001600020612c2190919063ffffffff16565b50612b0681836002612c3b9092919063ffffffff16565b50808273ffffffffffffffffffffffffffffffffffffffff16600073ffffffffffffffffffffffffffffffffffffffff167fddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef60405160405180910390a45050565b6000612b738383612ec3565b612bcc578260000182908060018154018082558091505060019003906000526020600020016000909190919091505582600001805490508360010160008481526020019081526020016000208190555060019050612bd1565b600090505b92915050565b6000612be9836000018360001b6130ff565b905092915050565b600081600001805490509050919050565b505050565b6000612c19836000018360001b613122565b905092915050565b6000612c33836000018360001b612b67565b905092915050565b6000612c67846000018460001b8473ffffffffffffffffffffffffffffffffffffffff1660001b61320a565b90509392505050565b600081836000018054905011612cd1576040517f08c379a00000000000000000000000000000000000000000000000000000000081526004018080602001828103825260228152602001806136316022913960400191505060405180910390fd5b826000018281548110612ce057fe5b9060005260206000200154905092915050565b6000612d1b836000018373ffffffffffffffffffffffffffffffffffffffff1660001b613122565b905092915050565b60008082846000018054905011612d85576040517f08c379a00000000000000000000000000000000000000000000000000000000081526004018080602001828103825260228152602001806137e56022913960400191505060405180910390fd5b6000846000018481548110612d9657fe5b906000526020600020906002020190508060000154816001015492509250509250929050565b60008084600101600085815260200190815260200160002054905060008114158390612e83576040517f08c379a00000000000000000000000000000000000000000000000000000000081526004018080602001828103825283818151815260200191508051906020019080838360005b83811015612e48578082015181840152602081019050612e2d565b50505050905090810190601f168015612e755780820380516001836020036101000a031916815260200191505b509250505060405180910390fd5b50846000016001820381548110612e9657fe5b9060005260206000209060020201600101549150509392505050565b600081600001805490509050919050565b600080836001016000848152602001908152602001600020541415905092915050565b6000612f078473ffffffffffffffffffffffffffffffffffffffff166132e6565b612f1457600190506130f7565b600061307e63150b7a0260e01b612f29612122565b888787604051602401808573ffffffffffffffffffffffffffffffffffffffff1681526020018473ffffffffffffffffffffffffffffffffffffffff16815260200183815260200180602001828103825283818151815260200191508051906020019080838360005b83811015612fad578082015181840152602081019050612f92565b50505050905090810190601f168015612fda5780820380516001836020036101000a031916815260200191505b5095505050505050604051602081830303815290604052907bffffffffffffffffffffffffffffffffffffffffffffffffffffffff19166020820180517bffffffffffffffffffffffffffffffffffffffffffffffffffffffff8381831617835250505050604051806060016040528060328152602001613682603291398773ffffffffffffffffffffffffffffffffffffffff166132f99092919063ffffffff16565b9050600081806020019051602081101561309757600080fd5b8101908080519060200190929190505050905063150b7a0260e01b7bffffffffffffffffffffffffffffffffffffffffffffffffffffffff1916817bffffffffffffffffffffffffffffffffffffffffffffffffffffffff191614925050505b949350505050565b600080836001016000848152602001908152602001600020541415905092915050565b600080836001016000848152602001908152602001600020549050600081146131fe576000600182039050600060018660000180549050039050600086600001828154811061316d57fe5b906000526020600020015490508087600001848154811061318a57fe5b90600052602060002001819055506001830187600101600083815260200190815260200160002081905550866000018054806131c257fe5b60019003818190600052602060002001600090559055866001016000878152602001908152602001600020600090556001945050505050613204565b60009150505b92915050565b60008084600101600085815260200190815260200160002054905060008114156132b1578460000160405180604001604052808681526020018581525090806001815401808255809150506001900390600052602060002090600202016000909190919091506000820151816000015560208201518160010155505084600001805490508560010160008681526020019081526020016000208190555060019150506132df565b828560000160018303815481106132c457fe5b90600052602060002090600202016001018190555060009150505b9392505050565b600080823b905060008111915050919050565b60606133088484600085613311565b90509392505050565b60608247101561336c576040517f08c379a00000000000000000000000000000000000000000000000000000000081526004018080602001828103825260268152602001806136d86026913960400191505060405180910390fd5b613375856132e6565b6133e7576040517f08c379a000000000000000000000000000000000000000000000000000000000815260040180806020018281038252601d8152602001807f416464726573733a2063616c6c20746f206e6f6e2d636f6e747261637400000081525060200191505060405180910390fd5b6000808673ffffffffffffffffffffffffffffffffffffffff1685876040518082805190602001908083835b602083106134365780518252602082019150602081019050602083039250613413565b6001836020036101000a03801982511681845116808217855250505050505090500191505060006040518083038185875af1925050503d8060008114613498576040519150601f19603f3d011682016040523d82523d6000602084013e61349d565b606091505b50915091506134ad8282866134b9565b92505050949350505050565b606083156134c95782905061357e565b6000835111156134dc5782518084602001fd5b816040517f08c379a00000000000000000000000000000000000000000000000000000000081526004018080602001828103825283818151815260200191508051906020019080838360005b83811015613543578082015181840152602081019050613528565b50505050905090810190601f1680156135705780820380516001836020036101000a031916815260200191505b509250505060405180910390fd5b9392505050565b828054600181600116156101000203166002900490600052602060002090601f0160209004810192826135bb5760008555613602565b82601f106135d457805160ff1916838001178555613602565b82800160010185558215613602579182015b828111156136015782518255916020019190600101906135e6565b5b50905061360f9190613613565b5090565b5b8082111561362c576000816000905550600101613614565b509056fe456e756d657261626c655365743a20696e646578206f7574206f6620626f756e6473416363657373436f6e74726f6c3a2073656e646572206d75737420626520616e2061646d696e20746f206772616e744552433732313a207472616e7366657220746f206e6f6e20455243373231526563656976657220696d706c656d656e7465724552433732313a207472616e7366657220746f20746865207a65726f2061646472657373416464726573733a20696e73756666696369656e742062616c616e636520666f722063616c6c4552433732313a206f70657261746f7220717565727920666f72206e6f6e6578697374656e7420746f6b656e416363657373436f6e74726f6c3a2073656e646572206d75737420626520616e2061646d696e20746f207265766f6b654552433732313a20617070726f76652063616c6c6572206973206e6f74206f776e6572206e6f7220617070726f76656420666f7220616c6c4552433732313a2062616c616e636520717565727920666f7220746865207a65726f20616464726573734552433732313a206f776e657220717565727920666f72206e6f6e6578697374656e7420746f6b656e456e756d657261626c654d61703a20696e646578206f7574206f6620626f756e64734552433732313a20617070726f76656420717565727920666f72206e6f6e6578697374656e7420746f6b656e4552433732314d657461646174613a2055524920736574206f66206e6f6e6578697374656e7420746f6b656e4552433732313a207472616e73666572206f6620746f6b656e2074686174206973206e6f74206f776e4552433732314d657461646174613a2055524920717565727920666f72206e6f6e6578697374656e7420746f6b656e4552433732313a20617070726f76616c20746f2063757272656e74206f776e65724552433732313a207472616e736665722063616c6c6572206973206e6f74206f776e6572206e6f7220617070726f7665644f70657261626c653a207265737472696374656420746f206f70657261746f7273416363657373436f6e74726f6c3a2063616e206f6e6c792072656e6f756e636520726f6c657320666f722073656c66a2646970667358221220ac674b2eb511663e499084a3be9a78f8b179a2bdc00012647879581c99fd030764736f6c63430007060033"
+var NFT721Bin = "0x60806040523480156200001157600080fd5b5060405162002ca238038062002ca28339810160408190526200003491620002de565b808383620000496301ffc9a760e01b620000b7565b6006620000578382620003fa565b506007620000668282620003fa565b50620000796380ac58cd60e01b620000b7565b6200008b635b5e139f60e01b620000b7565b6200009d63780e9d6360e01b620000b7565b50620000ad90506000826200013b565b50505050620004c6565b6001600160e01b03198082169003620001165760405162461bcd60e51b815260206004820152601c60248201527f4552433136353a20696e76616c696420696e7465726661636520696400000000604482015260640160405180910390fd5b6001600160e01b0319166000908152602081905260409020805460ff19166001179055565b6200014782826200014b565b5050565b6000828152600a60205260409020620001659082620001a7565b15620001475760405133906001600160a01b0383169084907f2f8788117e7eff1d82e926ec794901d17c78024a50270940304540a733656f0d90600090a45050565b6000620001be836001600160a01b038416620001c7565b90505b92915050565b60008181526001830160205260408120546200021057508154600181810184556000848152602080822090930184905584548482528286019093526040902091909155620001c1565b506000620001c1565b634e487b7160e01b600052604160045260246000fd5b600082601f8301126200024157600080fd5b81516001600160401b03808211156200025e576200025e62000219565b604051601f8301601f19908116603f0116810190828211818310171562000289576200028962000219565b81604052838152602092508683858801011115620002a657600080fd5b600091505b83821015620002ca5785820183015181830184015290820190620002ab565b600093810190920192909252949350505050565b600080600060608486031215620002f457600080fd5b83516001600160401b03808211156200030c57600080fd5b6200031a878388016200022f565b945060208601519150808211156200033157600080fd5b5062000340868287016200022f565b604086015190935090506001600160a01b03811681146200036057600080fd5b809150509250925092565b600181811c908216806200038057607f821691505b602082108103620003a157634e487b7160e01b600052602260045260246000fd5b50919050565b601f821115620003f557600081815260208120601f850160051c81016020861015620003d05750805b601f850160051c820191505b81811015620003f157828155600101620003dc565b5050505b505050565b81516001600160401b0381111562000416576200041662000219565b6200042e816200042784546200036b565b84620003a7565b602080601f8311600181146200046657600084156200044d5750858301515b600019600386901b1c1916600185901b178555620003f1565b600085815260208120601f198616915b82811015620004975788860151825594840194600190910190840162000476565b5085821015620004b65787850151600019600388901b60f8161c191681555b5050505050600190811b01905550565b6127cc80620004d66000396000f3fe608060405234801561001057600080fd5b506004361061021c5760003560e01c80637048027511610125578063ac8a584a116100ad578063ca15c8731161007c578063ca15c873146104ad578063d3fc9864146104c0578063d547741f146104d3578063e985e9c5146104e6578063f5b541a61461052257600080fd5b8063ac8a584a14610454578063ac9650d814610467578063b88d4fde14610487578063c87b56dd1461049a57600080fd5b806391d14854116100f457806391d148541461040b57806395d89b411461041e5780639870d7fe14610426578063a217fddf14610439578063a22cb4651461044157600080fd5b806370480275146103ca57806370a08231146103dd5780638bad0c0a146103f05780639010d07c146103f857600080fd5b806324d7806c116101a857806342842e0e1161017757806342842e0e146103765780634f6ccce7146103895780636352211e1461039c5780636c0360eb146103af5780636d70f7ae146103b757600080fd5b806324d7806c1461032a5780632f2ff15d1461033d5780632f745c591461035057806336568abe1461036357600080fd5b8063146d9ddc116101ef578063146d9ddc146102b857806318160ddd146102cb57806318e97fd1146102e157806323b872dd146102f4578063248a9ca31461030757600080fd5b806301ffc9a71461022157806306fdde0314610263578063081812fc14610278578063095ea7b3146102a3575b600080fd5b61024e61022f366004611da7565b6001600160e01b03191660009081526020819052604090205460ff1690565b60405190151581526020015b60405180910390f35b61026b610537565b60405161025a9190611e14565b61028b610286366004611e27565b6105c9565b6040516001600160a01b03909116815260200161025a565b6102b66102b1366004611e5c565b610656565b005b6102b66102c6366004611ff1565b61076b565b6102d3610829565b60405190815260200161025a565b6102b66102ef3660046120bc565b61083a565b6102b6610302366004612103565b6108a7565b6102d3610315366004611e27565b6000908152600a602052604090206002015490565b61024e61033836600461213f565b6108d8565b6102b661034b36600461215a565b6108ea565b6102d361035e366004611e5c565b610974565b6102b661037136600461215a565b61099d565b6102b6610384366004612103565b610a17565b6102d3610397366004611e27565b610a32565b61028b6103aa366004611e27565b610a48565b61026b610a70565b61024e6103c536600461213f565b610a7f565b6102b66103d836600461213f565b610a99565b6102d36103eb36600461213f565b610acc565b6102b6610b58565b61028b610406366004612186565b610b8a565b61024e61041936600461215a565b610ba2565b61026b610bba565b6102b661043436600461213f565b610bc9565b6102d3600081565b6102b661044f3660046121a8565b610c06565b6102b661046236600461213f565b610cca565b61047a6104753660046121e4565b610d07565b60405161025a9190612295565b6102b66104953660046122f7565b610e5a565b61026b6104a8366004611e27565b610e8c565b6102d36104bb366004611e27565b610ffd565b6102b66104ce36600461235f565b611014565b6102b66104e136600461215a565b611028565b61024e6104f43660046123ac565b6001600160a01b03918216600090815260056020908152604080832093909416825291909152205460ff1690565b6102d360008051602061274e83398151915281565b606060068054610546906123d6565b80601f0160208091040260200160405190810160405280929190818152602001828054610572906123d6565b80156105bf5780601f10610594576101008083540402835291602001916105bf565b820191906000526020600020905b8154815290600101906020018083116105a257829003601f168201915b5050505050905090565b60006105d4826110a9565b61063a5760405162461bcd60e51b815260206004820152602c60248201527f4552433732313a20617070726f76656420717565727920666f72206e6f6e657860448201526b34b9ba32b73a103a37b5b2b760a11b60648201526084015b60405180910390fd5b506000908152600460205260409020546001600160a01b031690565b600061066182610a48565b9050806001600160a01b0316836001600160a01b0316036106ce5760405162461bcd60e51b815260206004820152602160248201527f4552433732313a20617070726f76616c20746f2063757272656e74206f776e656044820152603960f91b6064820152608401610631565b336001600160a01b03821614806106ea57506106ea81336104f4565b61075c5760405162461bcd60e51b815260206004820152603860248201527f4552433732313a20617070726f76652063616c6c6572206973206e6f74206f7760448201527f6e6572206e6f7220617070726f76656420666f7220616c6c00000000000000006064820152608401610631565b61076683836110b6565b505050565b80518251146107c85760405162461bcd60e51b8152602060048201526024808201527f4e46543732313a2069647320616e642055524973206c656e677468206d69736d6044820152630c2e8c6d60e31b6064820152608401610631565b60005b825181101561082357610811848483815181106107ea576107ea61240a565b60200260200101518484815181106108045761080461240a565b6020026020010151611014565b8061081b81612436565b9150506107cb565b50505050565b60006108356002611124565b905090565b61084333610a7f565b6108995760405162461bcd60e51b815260206004820152602160248201527f4f70657261626c653a207265737472696374656420746f206f70657261746f726044820152607360f81b6064820152608401610631565b6108a3828261112e565b5050565b6108b133826111b0565b6108cd5760405162461bcd60e51b81526004016106319061244f565b61076683838361129a565b60006108e48183610ba2565b92915050565b6000828152600a60205260409020600201546109069033610ba2565b61096a5760405162461bcd60e51b815260206004820152602f60248201527f416363657373436f6e74726f6c3a2073656e646572206d75737420626520616e60448201526e0818591b5a5b881d1bc819dc985b9d608a1b6064820152608401610631565b6108a3828261141b565b6001600160a01b03821660009081526001602052604081206109969083611474565b9392505050565b6001600160a01b0381163314610a0d5760405162461bcd60e51b815260206004820152602f60248201527f416363657373436f6e74726f6c3a2063616e206f6e6c792072656e6f756e636560448201526e103937b632b9903337b91039b2b63360891b6064820152608401610631565b6108a38282611480565b61076683838360405180602001604052806000815250610e5a565b600080610a406002846114d9565b509392505050565b60006108e48260405180606001604052806029815260200161276e60299139600291906114f5565b606060098054610546906123d6565b60006108e460008051602061274e83398151915283610ba2565b610aa2336108d8565b610abe5760405162461bcd60e51b8152600401610631906124a0565b610ac96000826108ea565b50565b60006001600160a01b038216610b375760405162461bcd60e51b815260206004820152602a60248201527f4552433732313a2062616c616e636520717565727920666f7220746865207a65604482015269726f206164647265737360b01b6064820152608401610631565b6001600160a01b03821660009081526001602052604090206108e490611124565b610b61336108d8565b610b7d5760405162461bcd60e51b8152600401610631906124a0565b610b8860003361099d565b565b6000828152600a602052604081206109969083611474565b6000828152600a602052604081206109969083611502565b606060078054610546906123d6565b610bd2336108d8565b610bee5760405162461bcd60e51b8152600401610631906124a0565b610ac960008051602061274e833981519152826108ea565b336001600160a01b03831603610c5e5760405162461bcd60e51b815260206004820152601960248201527f4552433732313a20617070726f766520746f2063616c6c6572000000000000006044820152606401610631565b3360008181526005602090815260408083206001600160a01b03871680855290835292819020805460ff191686151590811790915590519081529192917f17307eab39ab6107e8899845ad3d59bd9653f200f220920489ca2b5937696c31910160405180910390a35050565b610cd3336108d8565b610cef5760405162461bcd60e51b8152600401610631906124a0565b610ac960008051602061274e83398151915282611028565b6060815167ffffffffffffffff811115610d2357610d23611e86565b604051908082528060200260200182016040528015610d5657816020015b6060815260200190600190039081610d415790505b50905060005b8251811015610e5457600080306001600160a01b0316858481518110610d8457610d8461240a565b6020026020010151604051610d9991906124d7565b600060405180830381855af49150503d8060008114610dd4576040519150601f19603f3d011682016040523d82523d6000602084013e610dd9565b606091505b509150915081610e215760405162461bcd60e51b81526020600482015260136024820152721391950dcc8c4e8818d85b1b0819985a5b1959606a1b6044820152606401610631565b80848481518110610e3457610e3461240a565b602002602001018190525050508080610e4c90612436565b915050610d5c565b50919050565b610e6433836111b0565b610e805760405162461bcd60e51b81526004016106319061244f565b61082384848484611524565b6060610e97826110a9565b610efb5760405162461bcd60e51b815260206004820152602f60248201527f4552433732314d657461646174613a2055524920717565727920666f72206e6f60448201526e3732bc34b9ba32b73a103a37b5b2b760891b6064820152608401610631565b60008281526008602052604081208054610f14906123d6565b80601f0160208091040260200160405190810160405280929190818152602001828054610f40906123d6565b8015610f8d5780601f10610f6257610100808354040283529160200191610f8d565b820191906000526020600020905b815481529060010190602001808311610f7057829003601f168201915b505050505090506000610f9e610a70565b90508051600003610fb0575092915050565b815115610fe2578082604051602001610fca9291906124f3565b60405160208183030381529060405292505050919050565b80610fec856115a2565b604051602001610fca9291906124f3565b6000818152600a602052604081206108e490611124565b61101e83836116bc565b610766828261112e565b6000828152600a60205260409020600201546110449033610ba2565b610a0d5760405162461bcd60e51b815260206004820152603060248201527f416363657373436f6e74726f6c3a2073656e646572206d75737420626520616e60448201526f2061646d696e20746f207265766f6b6560801b6064820152608401610631565b60006108e46002836117d4565b600081815260046020526040902080546001600160a01b0319166001600160a01b03841690811790915581906110eb82610a48565b6001600160a01b03167f8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b92560405160405180910390a45050565b60006108e4825490565b611137826110a9565b6111985760405162461bcd60e51b815260206004820152602c60248201527f4552433732314d657461646174613a2055524920736574206f66206e6f6e657860448201526b34b9ba32b73a103a37b5b2b760a11b6064820152608401610631565b60008281526008602052604090206107668282612570565b60006111bb826110a9565b61121c5760405162461bcd60e51b815260206004820152602c60248201527f4552433732313a206f70657261746f7220717565727920666f72206e6f6e657860448201526b34b9ba32b73a103a37b5b2b760a11b6064820152608401610631565b600061122783610a48565b9050806001600160a01b0316846001600160a01b031614806112625750836001600160a01b0316611257846105c9565b6001600160a01b0316145b8061129257506001600160a01b0380821660009081526005602090815260408083209388168352929052205460ff165b949350505050565b826001600160a01b03166112ad82610a48565b6001600160a01b0316146113155760405162461bcd60e51b815260206004820152602960248201527f4552433732313a207472616e73666572206f6620746f6b656e2074686174206960448201526839903737ba1037bbb760b91b6064820152608401610631565b6001600160a01b0382166113775760405162461bcd60e51b8152602060048201526024808201527f4552433732313a207472616e7366657220746f20746865207a65726f206164646044820152637265737360e01b6064820152608401610631565b6113826000826110b6565b6001600160a01b03831660009081526001602052604090206113a490826117ec565b506001600160a01b03821660009081526001602052604090206113c790826117f8565b506113d460028284611804565b5080826001600160a01b0316846001600160a01b03167fddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef60405160405180910390a4505050565b6000828152600a60205260409020611433908261181a565b156108a35760405133906001600160a01b0383169084907f2f8788117e7eff1d82e926ec794901d17c78024a50270940304540a733656f0d90600090a45050565b6000610996838361182f565b6000828152600a6020526040902061149890826118b5565b156108a35760405133906001600160a01b0383169084907ff6391f5c32d9c69d2a47ea670b442974b53935d1edc7fd64eb21e047a839171b90600090a45050565b60008080806114e886866118ca565b9097909650945050505050565b6000611292848484611967565b6001600160a01b03811660009081526001830160205260408120541515610996565b61152f84848461129a565b61153b848484846119d0565b6108235760405162461bcd60e51b815260206004820152603260248201527f4552433732313a207472616e7366657220746f206e6f6e20455243373231526560448201527131b2b4bb32b91034b6b83632b6b2b73a32b960711b6064820152608401610631565b6060816000036115c95750506040805180820190915260018152600360fc1b602082015290565b8160005b81156115f357806115dd81612436565b91506115ec9050600a83612646565b91506115cd565b60008167ffffffffffffffff81111561160e5761160e611e86565b6040519080825280601f01601f191660200182016040528015611638576020820181803683370190505b509050600061164860018461265a565b90508593505b83156116b35761165f600a8561266d565b61166a906030612681565b60f81b828261167881612694565b93508151811061168a5761168a61240a565b60200101906001600160f81b031916908160001a9053506116ac600a85612646565b935061164e565b50949350505050565b6001600160a01b0382166117125760405162461bcd60e51b815260206004820181905260248201527f4552433732313a206d696e7420746f20746865207a65726f20616464726573736044820152606401610631565b61171b816110a9565b156117685760405162461bcd60e51b815260206004820152601c60248201527f4552433732313a20746f6b656e20616c7265616479206d696e746564000000006044820152606401610631565b6001600160a01b038216600090815260016020526040902061178a90826117f8565b5061179760028284611804565b5060405181906001600160a01b038416906000907fddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef908290a45050565b60008181526001830160205260408120541515610996565b60006109968383611aa1565b60006109968383611b94565b600061129284846001600160a01b038516611be3565b6000610996836001600160a01b038416611b94565b8154600090821061188d5760405162461bcd60e51b815260206004820152602260248201527f456e756d657261626c655365743a20696e646578206f7574206f6620626f756e604482015261647360f01b6064820152608401610631565b8260000182815481106118a2576118a261240a565b9060005260206000200154905092915050565b6000610996836001600160a01b038416611aa1565b81546000908190831061192a5760405162461bcd60e51b815260206004820152602260248201527f456e756d657261626c654d61703a20696e646578206f7574206f6620626f756e604482015261647360f01b6064820152608401610631565b60008460000184815481106119415761194161240a565b906000526020600020906002020190508060000154816001015492509250509250929050565b600082815260018401602052604081205482816119975760405162461bcd60e51b81526004016106319190611e14565b50846119a460018361265a565b815481106119b4576119b461240a565b9060005260206000209060020201600101549150509392505050565b60006001600160a01b0384163b6119e957506001611292565b6000611a6a630a85bd0160e11b33888787604051602401611a0d94939291906126ab565b604051602081830303815290604052906001600160e01b0319166020820180516001600160e01b03838183161783525050505060405180606001604052806032815260200161271c603291396001600160a01b0388169190611c86565b9050600081806020019051810190611a8291906126e8565b6001600160e01b031916630a85bd0160e11b1492505050949350505050565b60008181526001830160205260408120548015611b8a576000611ac560018361265a565b8554909150600090611ad99060019061265a565b90506000866000018281548110611af257611af261240a565b9060005260206000200154905080876000018481548110611b1557611b1561240a565b600091825260209091200155611b2c836001612681565b60008281526001890160205260409020558654879080611b4e57611b4e612705565b600190038181906000526020600020016000905590558660010160008781526020019081526020016000206000905560019450505050506108e4565b60009150506108e4565b6000818152600183016020526040812054611bdb575081546001818101845560008481526020808220909301849055845484825282860190935260409020919091556108e4565b5060006108e4565b6000828152600184016020526040812054808203611c4a575050604080518082018252838152602080820184815286546001818101895560008981528481209551600290930290950191825591519082015586548684528188019092529290912055610996565b8285611c5760018461265a565b81548110611c6757611c6761240a565b9060005260206000209060020201600101819055506000915050610996565b6060611292848460008585843b611cdf5760405162461bcd60e51b815260206004820152601d60248201527f416464726573733a2063616c6c20746f206e6f6e2d636f6e74726163740000006044820152606401610631565b600080866001600160a01b03168587604051611cfb91906124d7565b60006040518083038185875af1925050503d8060008114611d38576040519150601f19603f3d011682016040523d82523d6000602084013e611d3d565b606091505b5091509150611d4d828286611d58565b979650505050505050565b60608315611d67575081610996565b825115611d775782518084602001fd5b8160405162461bcd60e51b81526004016106319190611e14565b6001600160e01b031981168114610ac957600080fd5b600060208284031215611db957600080fd5b813561099681611d91565b60005b83811015611ddf578181015183820152602001611dc7565b50506000910152565b60008151808452611e00816020860160208601611dc4565b601f01601f19169290920160200192915050565b6020815260006109966020830184611de8565b600060208284031215611e3957600080fd5b5035919050565b80356001600160a01b0381168114611e5757600080fd5b919050565b60008060408385031215611e6f57600080fd5b611e7883611e40565b946020939093013593505050565b634e487b7160e01b600052604160045260246000fd5b604051601f8201601f1916810167ffffffffffffffff81118282101715611ec557611ec5611e86565b604052919050565b600067ffffffffffffffff821115611ee757611ee7611e86565b5060051b60200190565b600082601f830112611f0257600080fd5b813567ffffffffffffffff811115611f1c57611f1c611e86565b611f2f601f8201601f1916602001611e9c565b818152846020838601011115611f4457600080fd5b816020850160208301376000918101602001919091529392505050565b600082601f830112611f7257600080fd5b81356020611f87611f8283611ecd565b611e9c565b82815260059290921b84018101918181019086841115611fa657600080fd5b8286015b84811015611fe657803567ffffffffffffffff811115611fca5760008081fd5b611fd88986838b0101611ef1565b845250918301918301611faa565b509695505050505050565b60008060006060848603121561200657600080fd5b61200f84611e40565b925060208085013567ffffffffffffffff8082111561202d57600080fd5b818701915087601f83011261204157600080fd5b813561204f611f8282611ecd565b81815260059190911b8301840190848101908a83111561206e57600080fd5b938501935b8285101561208c57843582529385019390850190612073565b9650505060408701359250808311156120a457600080fd5b50506120b286828701611f61565b9150509250925092565b600080604083850312156120cf57600080fd5b82359150602083013567ffffffffffffffff8111156120ed57600080fd5b6120f985828601611ef1565b9150509250929050565b60008060006060848603121561211857600080fd5b61212184611e40565b925061212f60208501611e40565b9150604084013590509250925092565b60006020828403121561215157600080fd5b61099682611e40565b6000806040838503121561216d57600080fd5b8235915061217d60208401611e40565b90509250929050565b6000806040838503121561219957600080fd5b50508035926020909101359150565b600080604083850312156121bb57600080fd5b6121c483611e40565b9150602083013580151581146121d957600080fd5b809150509250929050565b600060208083850312156121f757600080fd5b823567ffffffffffffffff8082111561220f57600080fd5b818501915085601f83011261222357600080fd5b8135612231611f8282611ecd565b81815260059190911b8301840190848101908883111561225057600080fd5b8585015b838110156122885780358581111561226c5760008081fd5b61227a8b89838a0101611ef1565b845250918601918601612254565b5098975050505050505050565b6000602080830181845280855180835260408601915060408160051b870101925083870160005b828110156122ea57603f198886030184526122d8858351611de8565b945092850192908501906001016122bc565b5092979650505050505050565b6000806000806080858703121561230d57600080fd5b61231685611e40565b935061232460208601611e40565b925060408501359150606085013567ffffffffffffffff81111561234757600080fd5b61235387828801611ef1565b91505092959194509250565b60008060006060848603121561237457600080fd5b61237d84611e40565b925060208401359150604084013567ffffffffffffffff8111156123a057600080fd5b6120b286828701611ef1565b600080604083850312156123bf57600080fd5b6123c883611e40565b915061217d60208401611e40565b600181811c908216806123ea57607f821691505b602082108103610e5457634e487b7160e01b600052602260045260246000fd5b634e487b7160e01b600052603260045260246000fd5b634e487b7160e01b600052601160045260246000fd5b60006001820161244857612448612420565b5060010190565b60208082526031908201527f4552433732313a207472616e736665722063616c6c6572206973206e6f74206f6040820152701ddb995c881b9bdc88185c1c1c9bdd9959607a1b606082015260800190565b6020808252601e908201527f4f70657261626c653a207265737472696374656420746f2061646d696e730000604082015260600190565b600082516124e9818460208701611dc4565b9190910192915050565b60008351612505818460208801611dc4565b835190830190612519818360208801611dc4565b01949350505050565b601f82111561076657600081815260208120601f850160051c810160208610156125495750805b601f850160051c820191505b8181101561256857828155600101612555565b505050505050565b815167ffffffffffffffff81111561258a5761258a611e86565b61259e8161259884546123d6565b84612522565b602080601f8311600181146125d357600084156125bb5750858301515b600019600386901b1c1916600185901b178555612568565b600085815260208120601f198616915b82811015612602578886015182559484019460019091019084016125e3565b50858210156126205787850151600019600388901b60f8161c191681555b5050505050600190811b01905550565b634e487b7160e01b600052601260045260246000fd5b60008261265557612655612630565b500490565b818103818111156108e4576108e4612420565b60008261267c5761267c612630565b500690565b808201808211156108e4576108e4612420565b6000816126a3576126a3612420565b506000190190565b6001600160a01b03858116825284166020820152604081018390526080606082018190526000906126de90830184611de8565b9695505050505050565b6000602082840312156126fa57600080fd5b815161099681611d91565b634e487b7160e01b600052603160045260246000fdfe4552433732313a207472616e7366657220746f206e6f6e20455243373231526563656976657220696d706c656d656e746572523a704056dcd17bcf83bed8b68c59416dac1119be77755efe3bde0a64e46e0c4552433732313a206f776e657220717565727920666f72206e6f6e6578697374656e7420746f6b656ea26469706673582212205534ad42428fb1fb2055afe1e4238e29b5d4b44b1eb7b1728d0a1c13dc49f41364736f6c63430008150033"
 
 // DeployNFT721 deploys a new Ethereum contract, binding an instance of NFT721 to it.
 func DeployNFT721(auth *bind.TransactOpts, backend bind.ContractBackend, name string, symbol string, admin common.Address) (common.Address, *types.Transaction, *NFT721, error) {
@@ -913,6 +913,48 @@ func (_NFT721 *NFT721TransactorSession) Mint(to common.Address, tokenId *big.Int
 	return _NFT721.Contract.Mint(&_NFT721.TransactOpts, to, tokenId, tokenURI)
 }
 
+// MintBatch is a paid mutator transaction binding the contract method 0x146d9ddc.
+//
+// Solidity: function mintBatch(address to, uint256[] tokenIds, string[] tokenURIs) returns()
+func (_NFT721 *NFT721Transactor) MintBatch(opts *bind.TransactOpts, to common.Address, tokenIds []*big.Int, tokenURIs []string) (*types.Transaction, error) {
+	return _NFT721.contract.Transact(opts, "mintBatch", to, tokenIds, tokenURIs)
+}
+
+// MintBatch is a paid mutator transaction binding the contract method 0x146d9ddc.
+//
+// Solidity: function mintBatch(address to, uint256[] tokenIds, string[] tokenURIs) returns()
+func (_NFT721 *NFT721Session) MintBatch(to common.Address, tokenIds []*big.Int, tokenURIs []string) (*types.Transaction, error) {
+	return _NFT721.Contract.MintBatch(&_NFT721.TransactOpts, to, tokenIds, tokenURIs)
+}
+
+// MintBatch is a paid mutator transaction binding the contract method 0x146d9ddc.
+//
+// Solidity: function mintBatch(address to, uint256[] tokenIds, string[] tokenURIs) returns()
+func (_NFT721 *NFT721TransactorSession) MintBatch(to common.Address, tokenIds []*big.Int, tokenURIs []string) (*types.Transaction, error) {
+	return _NFT721.Contract.MintBatch(&_NFT721.TransactOpts, to, tokenIds, tokenURIs)
+}
+
+// Multicall is a paid mutator transaction binding the contract method 0xac9650d8.
+//
+// Solidity: function multicall(bytes[] data) returns(bytes[] results)
+func (_NFT721 *NFT721Transactor) Multicall(opts *bind.TransactOpts, data [][]byte) (*types.Transaction, error) {
+	return _NFT721.contract.Transact(opts, "multicall", data)
+}
+
+// Multicall is a paid mutator transaction binding the contract method 0xac9650d8.
+//
+// Solidity: function multicall(bytes[] data) returns(bytes[] results)
+func (_NFT721 *NFT721Session) Multicall(data [][]byte) (*types.Transaction, error) {
+	return _NFT721.Contract.Multicall(&_NFT721.TransactOpts, data)
+}
+
+// Multicall is a paid mutator transaction binding the contract method 0xac9650d8.
+//
+// Solidity: function multicall(bytes[] data) returns(bytes[] results)
+func (_NFT721 *NFT721TransactorSession) Multicall(data [][]byte) (*types.Transaction, error) {
+	return _NFT721.Contract.Multicall(&_NFT721.TransactOpts, data)
+}
+
 // RemoveOperator is a paid mutator transaction binding the contract method 0xac8a584a.
 //
 // Solidity: function removeOperator(address account) returns()
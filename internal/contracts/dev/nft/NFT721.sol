@@ -2,7 +2,7 @@
 // File: @openzeppelin/contracts/utils/Context.sol
 
 
-pragma solidity >=0.6.0 <0.8.0;
+pragma solidity >=0.6.0 <0.9.0;
 
 /*
  * @dev Provides information about the current execution context, including the
@@ -16,7 +16,7 @@ pragma solidity >=0.6.0 <0.8.0;
  */
 abstract contract Context {
     function _msgSender() internal view virtual returns (address payable) {
-        return msg.sender;
+        return payable(msg.sender);
     }
 
     function _msgData() internal view virtual returns (bytes memory) {
@@ -28,7 +28,7 @@ abstract contract Context {
 // File: @openzeppelin/contracts/introspection/IERC165.sol
 
 
-pragma solidity >=0.6.0 <0.8.0;
+pragma solidity >=0.6.0 <0.9.0;
 
 /**
  * @dev Interface of the ERC165 standard, as defined in the
@@ -54,7 +54,7 @@ interface IERC165 {
 // File: @openzeppelin/contracts/token/ERC721/IERC721.sol
 
 
-pragma solidity >=0.6.2 <0.8.0;
+pragma solidity >=0.6.2 <0.9.0;
 
 
 /**
@@ -184,7 +184,7 @@ interface IERC721 is IERC165 {
 // File: @openzeppelin/contracts/token/ERC721/IERC721Metadata.sol
 
 
-pragma solidity >=0.6.2 <0.8.0;
+pragma solidity >=0.6.2 <0.9.0;
 
 
 /**
@@ -212,7 +212,7 @@ interface IERC721Metadata is IERC721 {
 // File: @openzeppelin/contracts/token/ERC721/IERC721Enumerable.sol
 
 
-pragma solidity >=0.6.2 <0.8.0;
+pragma solidity >=0.6.2 <0.9.0;
 
 
 /**
@@ -242,7 +242,7 @@ interface IERC721Enumerable is IERC721 {
 // File: @openzeppelin/contracts/token/ERC721/IERC721Receiver.sol
 
 
-pragma solidity >=0.6.0 <0.8.0;
+pragma solidity >=0.6.0 <0.9.0;
 
 /**
  * @title ERC721 token receiver interface
@@ -265,7 +265,7 @@ interface IERC721Receiver {
 // File: @openzeppelin/contracts/introspection/ERC165.sol
 
 
-pragma solidity >=0.6.0 <0.8.0;
+pragma solidity >=0.6.0 <0.9.0;
 
 
 /**
@@ -320,7 +320,7 @@ abstract contract ERC165 is IERC165 {
 // File: @openzeppelin/contracts/math/SafeMath.sol
 
 
-pragma solidity >=0.6.0 <0.8.0;
+pragma solidity >=0.6.0 <0.9.0;
 
 /**
  * @dev Wrappers over Solidity's arithmetic operations with added overflow
@@ -536,7 +536,7 @@ library SafeMath {
 // File: @openzeppelin/contracts/utils/Address.sol
 
 
-pragma solidity >=0.6.2 <0.8.0;
+pragma solidity >=0.6.2 <0.9.0;
 
 /**
  * @dev Collection of functions related to the address type
@@ -727,7 +727,7 @@ library Address {
 // File: @openzeppelin/contracts/utils/EnumerableSet.sol
 
 
-pragma solidity >=0.6.0 <0.8.0;
+pragma solidity >=0.6.0 <0.9.0;
 
 /**
  * @dev Library for managing
@@ -1026,7 +1026,7 @@ library EnumerableSet {
 // File: @openzeppelin/contracts/utils/EnumerableMap.sol
 
 
-pragma solidity >=0.6.0 <0.8.0;
+pragma solidity >=0.6.0 <0.9.0;
 
 /**
  * @dev Library for managing an enumerable variant of Solidity's
@@ -1294,7 +1294,7 @@ library EnumerableMap {
 // File: @openzeppelin/contracts/utils/Strings.sol
 
 
-pragma solidity >=0.6.0 <0.8.0;
+pragma solidity >=0.6.0 <0.9.0;
 
 /**
  * @dev String operations.
@@ -1330,7 +1330,7 @@ library Strings {
 // File: @openzeppelin/contracts/token/ERC721/ERC721.sol
 
 
-pragma solidity >=0.6.0 <0.8.0;
+pragma solidity >=0.6.0 <0.9.0;
 
 
 
@@ -1804,7 +1804,7 @@ contract ERC721 is Context, ERC165, IERC721, IERC721Metadata, IERC721Enumerable
 // File: @openzeppelin/contracts/access/AccessControl.sol
 
 
-pragma solidity >=0.6.0 <0.8.0;
+pragma solidity >=0.6.0 <0.9.0;
 
 
 
@@ -2021,7 +2021,7 @@ abstract contract AccessControl is Context {
 
 // File: contracts/Operable.sol
 
-pragma solidity 0.7.6;
+pragma solidity ^0.8.0;
 
 
 /**
@@ -2084,7 +2084,7 @@ abstract contract Operable is AccessControl {
 
 // File: contracts/NFT721.sol
 
-pragma solidity 0.7.6;
+pragma solidity ^0.8.0;
 
 
 
@@ -2112,6 +2112,31 @@ contract NFT721 is ERC721, Operable {
 		_setTokenURI(tokenId, tokenURI);
 	}
 
+    /**
+     *
+     * @dev Mint several tokens to the same owner with a single transaction.
+     */
+	function mintBatch(address to, uint256[] memory tokenIds, string[] memory tokenURIs) public {
+		require(tokenIds.length == tokenURIs.length, "NFT721: ids and URIs length mismatch");
+
+		for (uint256 i = 0; i < tokenIds.length; i++) {
+			mint(to, tokenIds[i], tokenURIs[i]);
+		}
+	}
+
+    /**
+     *
+     * @dev Call several methods of the contract with a single transaction.
+     */
+	function multicall(bytes[] memory data) public returns (bytes[] memory results) {
+		results = new bytes[](data.length);
+		for (uint256 i = 0; i < data.length; i++) {
+			(bool success, bytes memory result) = address(this).delegatecall(data[i]);
+			require(success, "NFT721: call failed");
+			results[i] = result;
+		}
+	}
+
     /**
      *
      * @dev Only operator is allowed to update token URI.
@@ -27,10 +27,10 @@ var (
 )
 
 // NFT1155ABI is the input ABI used to generate the binding from.
-const NFT1155ABI = "[{\"inputs\":[{\"internalType\":\"string\",\"name\":\"uri_\",\"type\":\"string\"}],\"stateMutability\":\"nonpayable\",\"type\":\"constructor\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"account\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"operator\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"bool\",\"name\":\"approved\",\"type\":\"bool\"}],\"name\":\"ApprovalForAll\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"operator\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"from\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint256[]\",\"name\":\"ids\",\"type\":\"uint256[]\"},{\"indexed\":false,\"internalType\":\"uint256[]\",\"name\":\"values\",\"type\":\"uint256[]\"}],\"name\":\"TransferBatch\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"operator\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"from\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"id\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"value\",\"type\":\"uint256\"}],\"name\":\"TransferSingle\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"string\",\"name\":\"value\",\"type\":\"string\"},{\"indexed\":true,\"internalType\":\"uint256\",\"name\":\"id\",\"type\":\"uint256\"}],\"name\":\"URI\",\"type\":\"event\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"account\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"id\",\"type\":\"uint256\"}],\"name\":\"balanceOf\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address[]\",\"name\":\"accounts\",\"type\":\"address[]\"},{\"internalType\":\"uint256[]\",\"name\":\"ids\",\"type\":\"uint256[]\"}],\"name\":\"balanceOfBatch\",\"outputs\":[{\"internalType\":\"uint256[]\",\"name\":\"\",\"type\":\"uint256[]\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"account\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"operator\",\"type\":\"address\"}],\"name\":\"isApprovedForAll\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"tokenId\",\"type\":\"uint256\"}],\"name\":\"mint\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"from\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"internalType\":\"uint256[]\",\"name\":\"ids\",\"type\":\"uint256[]\"},{\"internalType\":\"uint256[]\",\"name\":\"amounts\",\"type\":\"uint256[]\"},{\"internalType\":\"bytes\",\"name\":\"data\",\"type\":\"bytes\"}],\"name\":\"safeBatchTransferFrom\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"from\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"id\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"amount\",\"type\":\"uint256\"},{\"internalType\":\"bytes\",\"name\":\"data\",\"type\":\"bytes\"}],\"name\":\"safeTransferFrom\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"operator\",\"type\":\"address\"},{\"internalType\":\"bool\",\"name\":\"approved\",\"type\":\"bool\"}],\"name\":\"setApprovalForAll\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes4\",\"name\":\"interfaceId\",\"type\":\"bytes4\"}],\"name\":\"supportsInterface\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"name\":\"uri\",\"outputs\":[{\"internalType\":\"string\",\"name\":\"\",\"type\":\"string\"}],\"stateMutability\":\"view\",\"type\":\"function\"}]"
+const NFT1155ABI = "[{\"inputs\":[{\"internalType\":\"string\",\"name\":\"uri_\",\"type\":\"string\"}],\"stateMutability\":\"nonpayable\",\"type\":\"constructor\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"account\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"operator\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"bool\",\"name\":\"approved\",\"type\":\"bool\"}],\"name\":\"ApprovalForAll\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"operator\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"from\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint256[]\",\"name\":\"ids\",\"type\":\"uint256[]\"},{\"indexed\":false,\"internalType\":\"uint256[]\",\"name\":\"values\",\"type\":\"uint256[]\"}],\"name\":\"TransferBatch\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"operator\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"from\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"id\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"value\",\"type\":\"uint256\"}],\"name\":\"TransferSingle\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"string\",\"name\":\"value\",\"type\":\"string\"},{\"indexed\":true,\"internalType\":\"uint256\",\"name\":\"id\",\"type\":\"uint256\"}],\"name\":\"URI\",\"type\":\"event\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"account\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"id\",\"type\":\"uint256\"}],\"name\":\"balanceOf\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address[]\",\"name\":\"accounts\",\"type\":\"address[]\"},{\"internalType\":\"uint256[]\",\"name\":\"ids\",\"type\":\"uint256[]\"}],\"name\":\"balanceOfBatch\",\"outputs\":[{\"internalType\":\"uint256[]\",\"name\":\"\",\"type\":\"uint256[]\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"account\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"operator\",\"type\":\"address\"}],\"name\":\"isApprovedForAll\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"tokenId\",\"type\":\"uint256\"}],\"name\":\"mint\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"internalType\":\"uint256[]\",\"name\":\"tokenIds\",\"type\":\"uint256[]\"},{\"internalType\":\"uint256[]\",\"name\":\"amounts\",\"type\":\"uint256[]\"},{\"internalType\":\"bytes\",\"name\":\"data\",\"type\":\"bytes\"}],\"name\":\"mintBatch\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes[]\",\"name\":\"data\",\"type\":\"bytes[]\"}],\"name\":\"multicall\",\"outputs\":[{\"internalType\":\"bytes[]\",\"name\":\"results\",\"type\":\"bytes[]\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"from\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"internalType\":\"uint256[]\",\"name\":\"ids\",\"type\":\"uint256[]\"},{\"internalType\":\"uint256[]\",\"name\":\"amounts\",\"type\":\"uint256[]\"},{\"internalType\":\"bytes\",\"name\":\"data\",\"type\":\"bytes\"}],\"name\":\"safeBatchTransferFrom\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"from\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"id\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"amount\",\"type\":\"uint256\"},{\"internalType\":\"bytes\",\"name\":\"data\",\"type\":\"bytes\"}],\"name\":\"safeTransferFrom\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"operator\",\"type\":\"address\"},{\"internalType\":\"bool\",\"name\":\"approved\",\"type\":\"bool\"}],\"name\":\"setApprovalForAll\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes4\",\"name\":\"interfaceId\",\"type\":\"bytes4\"}],\"name\":\"supportsInterface\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"name\":\"uri\",\"outputs\":[{\"internalType\":\"string\",\"name\":\"\",\"type\":\"string\"}],\"stateMutability\":\"view\",\"type\":\"function\"}]"
 
 // NFT1155Bin is the compiled bytecode used for deploying new contracts.
-var NFT1155Bin = "0x60806040523480156200001157600080fd5b50604051620025c8380380620025c8833981810160405260208110156200003757600080fd5b81019080805160405193929190846401000000008211156200005857600080fd5b838201915060208201858111156200006f57600080fd5b82518660018202830111640100000000821117156200008d57600080fd5b8083526020830192505050908051906020019080838360005b83811015620000c3578082015181840152602081019050620000a6565b50505050905090810190601f168015620000f15780820380516001836020036101000a031916815260200191505b5060405250505080620001116301ffc9a760e01b6200015a60201b60201c565b62000122816200026360201b60201c565b6200013a63d9b67a2660e01b6200015a60201b60201c565b62000152630e89341c60e01b6200015a60201b60201c565b505062000335565b63ffffffff60e01b817bffffffffffffffffffffffffffffffffffffffffffffffffffffffff19161415620001f7576040517f08c379a000000000000000000000000000000000000000000000000000000000815260040180806020018281038252601c8152602001807f4552433136353a20696e76616c696420696e746572666163652069640000000081525060200191505060405180910390fd5b6001600080837bffffffffffffffffffffffffffffffffffffffffffffffffffffffff19167bffffffffffffffffffffffffffffffffffffffffffffffffffffffff1916815260200190815260200160002060006101000a81548160ff02191690831515021790555050565b80600390805190602001906200027b9291906200027f565b5050565b828054600181600116156101000203166002900490600052602060002090601f016020900481019282620002b7576000855562000303565b82601f10620002d257805160ff191683800117855562000303565b8280016001018555821562000303579182015b8281111562000302578251825591602001919060010190620002e5565b5b50905062000312919062000316565b5090565b5b808211156200033157600081600090555060010162000317565b5090565b61228380620003456000396000f3fe608060405234801561001057600080fd5b50600436106100925760003560e01c806340c10f191161006657806340c10f19146104265780634e1273f414610474578063a22cb46514610615578063e985e9c514610665578063f242432a146106df57610092565b8062fdd58e1461009757806301ffc9a7146100f95780630e89341c1461015c5780632eb2c2d614610203575b600080fd5b6100e3600480360360408110156100ad57600080fd5b81019080803573ffffffffffffffffffffffffffffffffffffffff169060200190929190803590602001909291905050506107ee565b6040518082815260200191505060405180910390f35b6101446004803603602081101561010f57600080fd5b8101908080357bffffffffffffffffffffffffffffffffffffffffffffffffffffffff191690602001909291905050506108ce565b60405180821515815260200191505060405180910390f35b6101886004803603602081101561017257600080fd5b8101908080359060200190929190505050610935565b6040518080602001828103825283818151815260200191508051906020019080838360005b838110156101c85780820151818401526020810190506101ad565b50505050905090810190601f1680156101f55780820380516001836020036101000a031916815260200191505b509250505060405180910390f35b610424600480360360a081101561021957600080fd5b81019080803573ffffffffffffffffffffffffffffffffffffffff169060200190929190803573ffffffffffffffffffffffffffffffffffffffff1690602001909291908035906020019064010000000081111561027657600080fd5b82018360208201111561028857600080fd5b803590602001918460208302840111640100000000831117156102aa57600080fd5b919080806020026020016040519081016040528093929190818152602001838360200280828437600081840152601f19601f8201169050808301925050505050505091929192908035906020019064010000000081111561030a57600080fd5b82018360208201111561031c57600080fd5b8035906020019184602083028401116401000000008311171561033e57600080fd5b919080806020026020016040519081016040528093929190818152602001838360200280828437600081840152601f19601f8201169050808301925050505050505091929192908035906020019064010000000081111561039e57600080fd5b8201836020820111156103b057600080fd5b803590602001918460018302840111640100000000831117156103d257600080fd5b91908080601f016020809104026020016040519081016040528093929190818152602001838380828437600081840152601f19601f8201169050808301925050505050505091929192905050506109d9565b005b6104726004803603604081101561043c57600080fd5b81019080803573ffffffffffffffffffffffffffffffffffffffff16906020019092919080359060200190929190505050610e64565b005b6105be6004803603604081101561048a57600080fd5b81019080803590602001906401000000008111156104a757600080fd5b8201836020820111156104b957600080fd5b803590602001918460208302840111640100000000831117156104db57600080fd5b919080806020026020016040519081016040528093929190818152602001838360200280828437600081840152601f19601f8201169050808301925050505050505091929192908035906020019064010000000081111561053b57600080fd5b82018360208201111561054d57600080fd5b8035906020019184602083028401116401000000008311171561056f57600080fd5b919080806020026020016040519081016040528093929190818152602001838360200280828437600081840152601f19601f820116905080830192505050505050509192919290505050610e84565b6040518080602001828103825283818151815260200191508051906020019060200280838360005b838110156106015780820151818401526020810190506105e6565b505050509050019250505060405180910390f35b6106636004803603604081101561062b57600080fd5b81019080803573ffffffffffffffffffffffffffffffffffffffff169060200190929190803515159060200190929190505050610f96565b005b6106c76004803603604081101561067b57600080fd5b81019080803573ffffffffffffffffffffffffffffffffffffffff169060200190929190803573ffffffffffffffffffffffffffffffffffffffff16906020019092919050505061112f565b60405180821515815260200191505060405180910390f35b6107ec600480360360a08110156106f557600080fd5b81019080803573ffffffffffffffffffffffffffffffffffffffff169060200190929190803573ffffffffffffffffffffffffffffffffffffffff16906020019092919080359060200190929190803590602001909291908035906020019064010000000081111561076657600080fd5b82018360208201111561077857600080fd5b8035906020019184600183028401116401000000008311171561079a57600080fd5b91908080601f016020809104026020016040519081016040528093929190818152602001838380828437600081840152601f19601f8201169050808301925050505050505091929192905050506111c3565b005b60008073ffffffffffffffffffffffffffffffffffffffff168373ffffffffffffffffffffffffffffffffffffffff161415610875576040517f08c379a000000000000000000000000000000000000000000000000000000000815260040180806020018281038252602b8152602001806120de602b913960400191505060405180910390fd5b6001600083815260200190815260200160002060008473ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff16815260200190815260200160002054905092915050565b6000806000837bffffffffffffffffffffffffffffffffffffffffffffffffffffffff19167bffffffffffffffffffffffffffffffffffffffffffffffffffffffff1916815260200190815260200160002060009054906101000a900460ff169050919050565b606060038054600181600116156101000203166002900480601f0160208091040260200160405190810160405280929190818152602001828054600181600116156101000203166002900480156109cd5780601f106109a2576101008083540402835291602001916109cd565b820191906000526020600020905b8154815290600101906020018083116109b057829003601f168201915b50505050509050919050565b8151835114610a33576040517f08c379a00000000000000000000000000000000000000000000000000000000081526004018080602001828103825260288152602001806122056028913960400191505060405180910390fd5b600073ffffffffffffffffffffffffffffffffffffffff168473ffffffffffffffffffffffffffffffffffffffff161415610ab9576040517f08c379a00000000000000000000000000000000000000000000000000000000081526004018080602001828103825260258152602001806121326025913960400191505060405180910390fd5b610ac1611538565b73ffffffffffffffffffffffffffffffffffffffff168573ffffffffffffffffffffffffffffffffffffffff161480610b075750610b0685610b01611538565b61112f565b5b610b5c576040517f08c379a00000000000000000000000000000000000000000000000000000000081526004018080602001828103825260328152602001806121576032913960400191505060405180910390fd5b6000610b66611538565b9050610b76818787878787611540565b60005b8451811015610d47576000858281518110610b9057fe5b602002602001015190506000858381518110610ba857fe5b60200260200101519050610c2f816040518060600160405280602a8152602001612189602a91396001600086815260200190815260200160002060008d73ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff168152602001908152602001600020546115489092919063ffffffff16565b6001600084815260200190815260200160002060008b73ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff16815260200190815260200160002081905550610ce6816001600085815260200190815260200160002060008b73ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020019081526020016000205461160290919063ffffffff16565b6001600084815260200190815260200160002060008a73ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff168152602001908152602001600020819055505050806001019050610b79565b508473ffffffffffffffffffffffffffffffffffffffff168673ffffffffffffffffffffffffffffffffffffffff168273ffffffffffffffffffffffffffffffffffffffff167f4a39dc06d4c0dbc64b70af90fd698a233a518aa5d07e595d983b8c0526c8f7fb8787604051808060200180602001838103835285818151815260200191508051906020019060200280838360005b83811015610df7578082015181840152602081019050610ddc565b50505050905001838103825284818151815260200191508051906020019060200280838360005b83811015610e39578082015181840152602081019050610e1e565b5050505090500194505050505060405180910390a4610e5c81878787878761168a565b505050505050565b610e808282600160405180602001604052806000815250611a19565b5050565b60608151835114610ee0576040517f08c379a00000000000000000000000000000000000000000000000000000000081526004018080602001828103825260298152602001806121dc6029913960400191505060405180910390fd5b6000835167ffffffffffffffff81118015610efa57600080fd5b50604051908082528060200260200182016040528015610f295781602001602082028036833780820191505090505b50905060005b8451811015610f8b57610f68858281518110610f4757fe5b6020026020010151858381518110610f5b57fe5b60200260200101516107ee565b828281518110610f7457fe5b602002602001018181525050806001019050610f2f565b508091505092915050565b8173ffffffffffffffffffffffffffffffffffffffff16610fb5611538565b73ffffffffffffffffffffffffffffffffffffffff161415611022576040517f08c379a00000000000000000000000000000000000000000000000000000000081526004018080602001828103825260298152602001806121b36029913960400191505060405180910390fd5b806002600061102f611538565b73ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff16815260200190815260200160002060008473ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff16815260200190815260200160002060006101000a81548160ff0219169083151502179055508173ffffffffffffffffffffffffffffffffffffffff166110dc611538565b73ffffffffffffffffffffffffffffffffffffffff167f17307eab39ab6107e8899845ad3d59bd9653f200f220920489ca2b5937696c318360405180821515815260200191505060405180910390a35050565b6000600260008473ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff16815260200190815260200160002060008373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff16815260200190815260200160002060009054906101000a900460ff16905092915050565b600073ffffffffffffffffffffffffffffffffffffffff168473ffffffffffffffffffffffffffffffffffffffff161415611249576040517f08c379a00000000000000000000000000000000000000000000000000000000081526004018080602001828103825260258152602001806121326025913960400191505060405180910390fd5b611251611538565b73ffffffffffffffffffffffffffffffffffffffff168573ffffffffffffffffffffffffffffffffffffffff161480611297575061129685611291611538565b61112f565b5b6112ec576040517f08c379a00000000000000000000000000000000000000000000000000000000081526004018080602001828103825260298152602001806121096029913960400191505060405180910390fd5b60006112f6611538565b905061131681878761130788611c1c565b61131088611c1c565b87611540565b611393836040518060600160405280602a8152602001612189602a91396001600088815260200190815260200160002060008a73ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff168152602001908152602001600020546115489092919063ffffffff16565b6001600086815260200190815260200160002060008873ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020019081526020016000208190555061144a836001600087815260200190815260200160002060008873ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020019081526020016000205461160290919063ffffffff16565b6001600086815260200190815260200160002060008773ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff168152602001908152602001600020819055508473ffffffffffffffffffffffffffffffffffffffff168673ffffffffffffffffffffffffffffffffffffffff168273ffffffffffffffffffffffffffffffffffffffff167fc3d58168c5ae7397731d063d5bbf3d657854427343f4c083240f7aacaa2d0f628787604051808381526020018281526020019250505060405180910390a4611530818787878787611c8d565b505050505050565b600033905090565b505050505050565b60008383111582906115f5576040517f08c379a00000000000000000000000000000000000000000000000000000000081526004018080602001828103825283818151815260200191508051906020019080838360005b838110156115ba57808201518184015260208101905061159f565b50505050905090810190601f1680156115e75780820380516001836020036101000a031916815260200191505b509250505060405180910390fd5b5082840390509392505050565b600080828401905083811015611680576040517f08c379a000000000000000000000000000000000000000000000000000000000815260040180806020018281038252601b8152602001807f536166654d6174683a206164646974696f6e206f766572666c6f77000000000081525060200191505060405180910390fd5b8091505092915050565b6116a98473ffffffffffffffffffffffffffffffffffffffff16611f9a565b15611a11578373ffffffffffffffffffffffffffffffffffffffff1663bc197c8187878686866040518663ffffffff1660e01b8152600401808673ffffffffffffffffffffffffffffffffffffffff1681526020018573ffffffffffffffffffffffffffffffffffffffff168152602001806020018060200180602001848103845287818151815260200191508051906020019060200280838360005b83811015611761578082015181840152602081019050611746565b50505050905001848103835286818151815260200191508051906020019060200280838360005b838110156117a3578082015181840152602081019050611788565b50505050905001848103825285818151815260200191508051906020019080838360005b838110156117e25780820151818401526020810190506117c7565b50505050905090810190601f16801561180f5780820380516001836020036101000a031916815260200191505b5098505050505050505050602060405180830381600087803b15801561183457600080fd5b505af192505050801561186857506040513d602081101561185457600080fd5b810190808051906020019092919050505060015b61197257611874611fcb565b8061187f5750611921565b806040517f08c379a00000000000000000000000000000000000000000000000000000000081526004018080602001828103825283818151815260200191508051906020019080838360005b838110156118e65780820151818401526020810190506118cb565b50505050905090810190601f1680156119135780820380516001836020036101000a031916815260200191505b509250505060405180910390fd5b6040517f08c379a00000000000000000000000000000000000000000000000000000000081526004018080602001828103825260348152602001806120826034913960400191505060405180910390fd5b63bc197c8160e01b7bffffffffffffffffffffffffffffffffffffffffffffffffffffffff1916817bffffffffffffffffffffffffffffffffffffffffffffffffffffffff191614611a0f576040517f08c379a00000000000000000000000000000000000000000000000000000000081526004018080602001828103825260288152602001806120b66028913960400191505060405180910390fd5b505b505050505050565b600073ffffffffffffffffffffffffffffffffffffffff168473ffffffffffffffffffffffffffffffffffffffff161415611a9f576040517f08c379a000000000000000000000000000000000000000000000000000000000815260040180806020018281038252602181526020018061222d6021913960400191505060405180910390fd5b6000611aa9611538565b9050611aca81600087611abb88611c1c565b611ac488611c1c565b87611540565b611b2d836001600087815260200190815260200160002060008873ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020019081526020016000205461160290919063ffffffff16565b6001600086815260200190815260200160002060008773ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff168152602001908152602001600020819055508473ffffffffffffffffffffffffffffffffffffffff16600073ffffffffffffffffffffffffffffffffffffffff168273ffffffffffffffffffffffffffffffffffffffff167fc3d58168c5ae7397731d063d5bbf3d657854427343f4c083240f7aacaa2d0f628787604051808381526020018281526020019250505060405180910390a4611c1581600087878787611c8d565b5050505050565b60606000600167ffffffffffffffff81118015611c3857600080fd5b50604051908082528060200260200182016040528015611c675781602001602082028036833780820191505090505b5090508281600081518110611c7857fe5b60200260200101818152505080915050919050565b611cac8473ffffffffffffffffffffffffffffffffffffffff16611f9a565b15611f92578373ffffffffffffffffffffffffffffffffffffffff1663f23a6e6187878686866040518663ffffffff1660e01b8152600401808673ffffffffffffffffffffffffffffffffffffffff1681526020018573ffffffffffffffffffffffffffffffffffffffff16815260200184815260200183815260200180602001828103825283818151815260200191508051906020019080838360005b83811015611d65578082015181840152602081019050611d4a565b50505050905090810190601f168015611d925780820380516001836020036101000a031916815260200191505b509650505050505050602060405180830381600087803b158015611db557600080fd5b505af1925050508015611de957506040513d6020811015611dd557600080fd5b810190808051906020019092919050505060015b611ef357611df5611fcb565b80611e005750611ea2565b806040517f08c379a00000000000000000000000000000000000000000000000000000000081526004018080602001828103825283818151815260200191508051906020019080838360005b83811015611e67578082015181840152602081019050611e4c565b50505050905090810190601f168015611e945780820380516001836020036101000a031916815260200191505b509250505060405180910390fd5b6040517f08c379a00000000000000000000000000000000000000000000000000000000081526004018080602001828103825260348152602001806120826034913960400191505060405180910390fd5b63f23a6e6160e01b7bffffffffffffffffffffffffffffffffffffffffffffffffffffffff1916817bffffffffffffffffffffffffffffffffffffffffffffffffffffffff191614611f90576040517f08c379a00000000000000000000000000000000000000000000000000000000081526004018080602001828103825260288152602001806120b66028913960400191505060405180910390fd5b505b505050505050565b600080823b905060008111915050919050565b6000601f19601f8301169050919050565b60008160e01c9050919050565b600060443d1015611fdb5761207e565b60046000803e611fec600051611fbe565b6308c379a08114611ffd575061207e565b60405160043d036004823e80513d602482011167ffffffffffffffff821117156120295750505061207e565b808201805167ffffffffffffffff81111561204857505050505061207e565b8060208301013d85018111156120635750505050505061207e565b61206c82611fad565b60208401016040528296505050505050505b9056fe455243313135353a207472616e7366657220746f206e6f6e2045524331313535526563656976657220696d706c656d656e746572455243313135353a204552433131353552656365697665722072656a656374656420746f6b656e73455243313135353a2062616c616e636520717565727920666f7220746865207a65726f2061646472657373455243313135353a2063616c6c6572206973206e6f74206f776e6572206e6f7220617070726f766564455243313135353a207472616e7366657220746f20746865207a65726f2061646472657373455243313135353a207472616e736665722063616c6c6572206973206e6f74206f776e6572206e6f7220617070726f766564455243313135353a20696e73756666696369656e742062616c616e636520666f72207472616e73666572455243313135353a2073657474696e6720617070726f76616c2073746174757320666f722073656c66455243313135353a206163636f756e747320616e6420696473206c656e677468206d69736d61746368455243313135353a2069647320616e6420616d6f756e7473206c656e677468206d69736d61746368455243313135353a206d696e7420746f20746865207a65726f2061646472657373a26469706673582212201ab6f0fccdec7212d79cba4ce3238337c6d1f7b0d658f6dda6ceb6841590880d64736f6c63430007060033"
+var NFT1155Bin = "0x60806040523480156200001157600080fd5b5060405162001e0438038062001e0483398101604081905262000034916200012a565b80620000476301ffc9a760e01b6200007e565b620000528162000102565b62000064636cdb3d1360e11b6200007e565b620000766303a24d0760e21b6200007e565b50506200035a565b6001600160e01b03198082169003620000dd5760405162461bcd60e51b815260206004820152601c60248201527f4552433136353a20696e76616c696420696e7465726661636520696400000000604482015260640160405180910390fd5b6001600160e01b0319166000908152602081905260409020805460ff19166001179055565b60036200011082826200028e565b5050565b634e487b7160e01b600052604160045260246000fd5b600060208083850312156200013e57600080fd5b82516001600160401b03808211156200015657600080fd5b818501915085601f8301126200016b57600080fd5b81518181111562000180576200018062000114565b604051601f8201601f19908116603f01168101908382118183101715620001ab57620001ab62000114565b816040528281528886848701011115620001c457600080fd5b600093505b82841015620001e85784840186015181850187015292850192620001c9565b600086848301015280965050505050505092915050565b600181811c908216806200021457607f821691505b6020821081036200023557634e487b7160e01b600052602260045260246000fd5b50919050565b601f8211156200028957600081815260208120601f850160051c81016020861015620002645750805b601f850160051c820191505b81811015620002855782815560010162000270565b5050505b505050565b81516001600160401b03811115620002aa57620002aa62000114565b620002c281620002bb8454620001ff565b846200023b565b602080601f831160018114620002fa5760008415620002e15750858301515b600019600386901b1c1916600185901b17855562000285565b600085815260208120601f198616915b828110156200032b578886015182559484019460019091019084016200030a565b50858210156200034a5787850151600019600388901b60f8161c191681555b5050505050600190811b01905550565b611a9a806200036a6000396000f3fe608060405234801561001057600080fd5b50600436106100a85760003560e01c806340c10f191161007157806340c10f19146101585780634e1273f41461016b578063a22cb4651461018b578063ac9650d81461019e578063e985e9c5146101be578063f242432a146101fa57600080fd5b8062fdd58e146100ad57806301ffc9a7146100d35780630e89341c146101105780631f7fdffa146101305780632eb2c2d614610145575b600080fd5b6100c06100bb36600461105f565b61020d565b6040519081526020015b60405180910390f35b6101006100e13660046110a2565b6001600160e01b03191660009081526020819052604090205460ff1690565b60405190151581526020016100ca565b61012361011e3660046110bf565b6102a9565b6040516100ca9190611128565b61014361013e366004611287565b61033d565b005b610143610153366004611320565b61034f565b61014361016636600461105f565b6105a2565b61017e6101793660046113ca565b6105c2565b6040516100ca91906114d0565b6101436101993660046114e3565b6106ec565b6101b16101ac36600461151f565b6107c2565b6040516100ca91906115db565b6101006101cc36600461163d565b6001600160a01b03918216600090815260026020908152604080832093909416825291909152205460ff1690565b610143610208366004611670565b610916565b60006001600160a01b03831661027e5760405162461bcd60e51b815260206004820152602b60248201527f455243313135353a2062616c616e636520717565727920666f7220746865207a60448201526a65726f206164647265737360a81b60648201526084015b60405180910390fd5b5060008181526001602090815260408083206001600160a01b03861684529091529020545b92915050565b6060600380546102b8906116d5565b80601f01602080910402602001604051908101604052809291908181526020018280546102e4906116d5565b80156103315780601f1061030657610100808354040283529160200191610331565b820191906000526020600020905b81548152906001019060200180831161031457829003601f168201915b50505050509050919050565b61034984848484610ac9565b50505050565b81518351146103705760405162461bcd60e51b815260040161027590611709565b6001600160a01b0384166103965760405162461bcd60e51b815260040161027590611751565b6001600160a01b0385163314806103b257506103b285336101cc565b6104195760405162461bcd60e51b815260206004820152603260248201527f455243313135353a207472616e736665722063616c6c6572206973206e6f74206044820152711bdddb995c881b9bdc88185c1c1c9bdd995960721b6064820152608401610275565b3360005b845181101561053457600085828151811061043a5761043a611796565b60200260200101519050600085838151811061045857610458611796565b602002602001015190506104c5816040518060600160405280602a8152602001611a3b602a91396001600086815260200190815260200160002060008d6001600160a01b03166001600160a01b0316815260200190815260200160002054610c669092919063ffffffff16565b60008381526001602090815260408083206001600160a01b038e811685529252808320939093558a16815220546104fc9082610c9d565b60009283526001602090815260408085206001600160a01b038c168652909152909220919091555061052d816117c2565b905061041d565b50846001600160a01b0316866001600160a01b0316826001600160a01b03167f4a39dc06d4c0dbc64b70af90fd698a233a518aa5d07e595d983b8c0526c8f7fb87876040516105849291906117db565b60405180910390a461059a818787878787610d03565b505050505050565b6105be8282600160405180602001604052806000815250610e67565b5050565b606081518351146106275760405162461bcd60e51b815260206004820152602960248201527f455243313135353a206163636f756e747320616e6420696473206c656e677468604482015268040dad2e6dac2e8c6d60bb1b6064820152608401610275565b6000835167ffffffffffffffff8111156106435761064361113b565b60405190808252806020026020018201604052801561066c578160200160208202803683370190505b50905060005b84518110156106e4576106b785828151811061069057610690611796565b60200260200101518583815181106106aa576106aa611796565b602002602001015161020d565b8282815181106106c9576106c9611796565b60209081029190910101526106dd816117c2565b9050610672565b509392505050565b6001600160a01b03821633036107565760405162461bcd60e51b815260206004820152602960248201527f455243313135353a2073657474696e6720617070726f76616c20737461747573604482015268103337b91039b2b63360b91b6064820152608401610275565b3360008181526002602090815260408083206001600160a01b03871680855290835292819020805460ff191686151590811790915590519081529192917f17307eab39ab6107e8899845ad3d59bd9653f200f220920489ca2b5937696c31910160405180910390a35050565b6060815167ffffffffffffffff8111156107de576107de61113b565b60405190808252806020026020018201604052801561081157816020015b60608152602001906001900390816107fc5790505b50905060005b825181101561091057600080306001600160a01b031685848151811061083f5761083f611796565b60200260200101516040516108549190611809565b600060405180830381855af49150503d806000811461088f576040519150601f19603f3d011682016040523d82523d6000602084013e610894565b606091505b5091509150816108dd5760405162461bcd60e51b81526020600482015260146024820152731391950c4c4d4d4e8818d85b1b0819985a5b195960621b6044820152606401610275565b808484815181106108f0576108f0611796565b602002602001018190525050508080610908906117c2565b915050610817565b50919050565b6001600160a01b03841661093c5760405162461bcd60e51b815260040161027590611751565b6001600160a01b038516331480610958575061095885336101cc565b6109b65760405162461bcd60e51b815260206004820152602960248201527f455243313135353a2063616c6c6572206973206e6f74206f776e6572206e6f7260448201526808185c1c1c9bdd995960ba1b6064820152608401610275565b336109d68187876109c688610f3d565b6109cf88610f3d565b5050505050565b610a1d836040518060600160405280602a8152602001611a3b602a913960008781526001602090815260408083206001600160a01b038d1684529091529020549190610c66565b60008581526001602090815260408083206001600160a01b038b81168552925280832093909355871681522054610a549084610c9d565b60008581526001602090815260408083206001600160a01b038a811680865291845293829020949094558051888152918201879052898316928516917fc3d58168c5ae7397731d063d5bbf3d657854427343f4c083240f7aacaa2d0f62910160405180910390a461059a818787878787610f88565b6001600160a01b038416610aef5760405162461bcd60e51b815260040161027590611825565b8151835114610b105760405162461bcd60e51b815260040161027590611709565b3360005b8451811015610bfe57610b9860016000878481518110610b3657610b36611796565b602002602001015181526020019081526020016000206000886001600160a01b03166001600160a01b0316815260200190815260200160002054858381518110610b8257610b82611796565b6020026020010151610c9d90919063ffffffff16565b60016000878481518110610bae57610bae611796565b602002602001015181526020019081526020016000206000886001600160a01b03166001600160a01b03168152602001908152602001600020819055508080610bf6906117c2565b915050610b14565b50846001600160a01b031660006001600160a01b0316826001600160a01b03167f4a39dc06d4c0dbc64b70af90fd698a233a518aa5d07e595d983b8c0526c8f7fb8787604051610c4f9291906117db565b60405180910390a46109cf81600087878787610d03565b60008184841115610c8a5760405162461bcd60e51b81526004016102759190611128565b50610c958385611866565b949350505050565b600080610caa8385611879565b905083811015610cfc5760405162461bcd60e51b815260206004820152601b60248201527f536166654d6174683a206164646974696f6e206f766572666c6f7700000000006044820152606401610275565b9392505050565b6001600160a01b0384163b1561059a5760405163bc197c8160e01b81526001600160a01b0385169063bc197c8190610d47908990899088908890889060040161188c565b6020604051808303816000875af1925050508015610d82575060408051601f3d908101601f19168201909252610d7f918101906118ea565b60015b610e2e57610d8e611907565b806308c379a003610dc75750610da2611923565b80610dad5750610dc9565b8060405162461bcd60e51b81526004016102759190611128565b505b60405162461bcd60e51b815260206004820152603460248201527f455243313135353a207472616e7366657220746f206e6f6e20455243313135356044820152732932b1b2b4bb32b91034b6b83632b6b2b73a32b960611b6064820152608401610275565b6001600160e01b0319811663bc197c8160e01b14610e5e5760405162461bcd60e51b8152600401610275906119ad565b50505050505050565b6001600160a01b038416610e8d5760405162461bcd60e51b815260040161027590611825565b33610e9e816000876109c688610f3d565b60008481526001602090815260408083206001600160a01b0389168452909152902054610ecb9084610c9d565b60008581526001602090815260408083206001600160a01b038a8116808652918452828520959095558151898152928301889052938516917fc3d58168c5ae7397731d063d5bbf3d657854427343f4c083240f7aacaa2d0f62910160405180910390a46109cf81600087878787610f88565b60408051600180825281830190925260609160009190602080830190803683370190505090508281600081518110610f7757610f77611796565b602090810291909101015292915050565b6001600160a01b0384163b1561059a5760405163f23a6e6160e01b81526001600160a01b0385169063f23a6e6190610fcc90899089908890889088906004016119f5565b6020604051808303816000875af1925050508015611007575060408051601f3d908101601f19168201909252611004918101906118ea565b60015b61101357610d8e611907565b6001600160e01b0319811663f23a6e6160e01b14610e5e5760405162461bcd60e51b8152600401610275906119ad565b80356001600160a01b038116811461105a57600080fd5b919050565b6000806040838503121561107257600080fd5b61107b83611043565b946020939093013593505050565b6001600160e01b03198116811461109f57600080fd5b50565b6000602082840312156110b457600080fd5b8135610cfc81611089565b6000602082840312156110d157600080fd5b5035919050565b60005b838110156110f35781810151838201526020016110db565b50506000910152565b600081518084526111148160208601602086016110d8565b601f01601f19169290920160200192915050565b602081526000610cfc60208301846110fc565b634e487b7160e01b600052604160045260246000fd5b601f8201601f1916810167ffffffffffffffff811182821017156111775761117761113b565b6040525050565b600067ffffffffffffffff8211156111985761119861113b565b5060051b60200190565b600082601f8301126111b357600080fd5b813560206111c08261117e565b6040516111cd8282611151565b83815260059390931b85018201928281019150868411156111ed57600080fd5b8286015b8481101561120857803583529183019183016111f1565b509695505050505050565b600082601f83011261122457600080fd5b813567ffffffffffffffff81111561123e5761123e61113b565b604051611255601f8301601f191660200182611151565b81815284602083860101111561126a57600080fd5b816020850160208301376000918101602001919091529392505050565b6000806000806080858703121561129d57600080fd5b6112a685611043565b9350602085013567ffffffffffffffff808211156112c357600080fd5b6112cf888389016111a2565b945060408701359150808211156112e557600080fd5b6112f1888389016111a2565b9350606087013591508082111561130757600080fd5b5061131487828801611213565b91505092959194509250565b600080600080600060a0868803121561133857600080fd5b61134186611043565b945061134f60208701611043565b9350604086013567ffffffffffffffff8082111561136c57600080fd5b61137889838a016111a2565b9450606088013591508082111561138e57600080fd5b61139a89838a016111a2565b935060808801359150808211156113b057600080fd5b506113bd88828901611213565b9150509295509295909350565b600080604083850312156113dd57600080fd5b823567ffffffffffffffff808211156113f557600080fd5b818501915085601f83011261140957600080fd5b813560206114168261117e565b6040516114238282611151565b83815260059390931b850182019282810191508984111561144357600080fd5b948201945b838610156114685761145986611043565b82529482019490820190611448565b9650508601359250508082111561147e57600080fd5b5061148b858286016111a2565b9150509250929050565b600081518084526020808501945080840160005b838110156114c5578151875295820195908201906001016114a9565b509495945050505050565b602081526000610cfc6020830184611495565b600080604083850312156114f657600080fd5b6114ff83611043565b91506020830135801515811461151457600080fd5b809150509250929050565b6000602080838503121561153257600080fd5b823567ffffffffffffffff8082111561154a57600080fd5b818501915085601f83011261155e57600080fd5b81356115698161117e565b6040516115768282611151565b82815260059290921b840185019185810191508883111561159657600080fd5b8585015b838110156115ce578035858111156115b25760008081fd5b6115c08b89838a0101611213565b84525091860191860161159a565b5098975050505050505050565b6000602080830181845280855180835260408601915060408160051b870101925083870160005b8281101561163057603f1988860301845261161e8583516110fc565b94509285019290850190600101611602565b5092979650505050505050565b6000806040838503121561165057600080fd5b61165983611043565b915061166760208401611043565b90509250929050565b600080600080600060a0868803121561168857600080fd5b61169186611043565b945061169f60208701611043565b93506040860135925060608601359150608086013567ffffffffffffffff8111156116c957600080fd5b6113bd88828901611213565b600181811c908216806116e957607f821691505b60208210810361091057634e487b7160e01b600052602260045260246000fd5b60208082526028908201527f455243313135353a2069647320616e6420616d6f756e7473206c656e677468206040820152670dad2e6dac2e8c6d60c31b606082015260800190565b60208082526025908201527f455243313135353a207472616e7366657220746f20746865207a65726f206164604082015264647265737360d81b606082015260800190565b634e487b7160e01b600052603260045260246000fd5b634e487b7160e01b600052601160045260246000fd5b6000600182016117d4576117d46117ac565b5060010190565b6040815260006117ee6040830185611495565b82810360208401526118008185611495565b95945050505050565b6000825161181b8184602087016110d8565b9190910192915050565b60208082526021908201527f455243313135353a206d696e7420746f20746865207a65726f206164647265736040820152607360f81b606082015260800190565b818103818111156102a3576102a36117ac565b808201808211156102a3576102a36117ac565b6001600160a01b0386811682528516602082015260a0604082018190526000906118b890830186611495565b82810360608401526118ca8186611495565b905082810360808401526118de81856110fc565b98975050505050505050565b6000602082840312156118fc57600080fd5b8151610cfc81611089565b600060033d11156119205760046000803e5060005160e01c5b90565b600060443d10156119315790565b6040516003193d81016004833e81513d67ffffffffffffffff816024840111818411171561196157505050505090565b82850191508151818111156119795750505050505090565b843d87010160208285010111156119935750505050505090565b6119a260208286010187611151565b509095945050505050565b60208082526028908201527f455243313135353a204552433131353552656365697665722072656a656374656040820152676420746f6b656e7360c01b606082015260800190565b6001600160a01b03868116825285166020820152604081018490526060810183905260a060808201819052600090611a2f908301846110fc565b97965050505050505056fe455243313135353a20696e73756666696369656e742062616c616e636520666f72207472616e73666572a2646970667358221220324fd2b4ef58c9db53e1ce309e3158136c4e3fe72ece4bf2fc7431c378cf14a364736f6c63430008150033"
 
 // DeployNFT1155 deploys a new Ethereum contract, binding an instance of NFT1155 to it.
 func DeployNFT1155(auth *bind.TransactOpts, backend bind.ContractBackend, uri_ string) (common.Address, *types.Transaction, *NFT1155, error) {
@@ -364,6 +364,48 @@ func (_NFT1155 *NFT1155TransactorSession) Mint(to common.Address, tokenId *big.I
 	return _NFT1155.Contract.Mint(&_NFT1155.TransactOpts, to, tokenId)
 }
 
+// MintBatch is a paid mutator transaction binding the contract method 0x1f7fdffa.
+//
+// Solidity: function mintBatch(address to, uint256[] tokenIds, uint256[] amounts, bytes data) returns()
+func (_NFT1155 *NFT1155Transactor) MintBatch(opts *bind.TransactOpts, to common.Address, tokenIds []*big.Int, amounts []*big.Int, data []byte) (*types.Transaction, error) {
+	return _NFT1155.contract.Transact(opts, "mintBatch", to, tokenIds, amounts, data)
+}
+
+// MintBatch is a paid mutator transaction binding the contract method 0x1f7fdffa.
+//
+// Solidity: function mintBatch(address to, uint256[] tokenIds, uint256[] amounts, bytes data) returns()
+func (_NFT1155 *NFT1155Session) MintBatch(to common.Address, tokenIds []*big.Int, amounts []*big.Int, data []byte) (*types.Transaction, error) {
+	return _NFT1155.Contract.MintBatch(&_NFT1155.TransactOpts, to, tokenIds, amounts, data)
+}
+
+// MintBatch is a paid mutator transaction binding the contract method 0x1f7fdffa.
+//
+// Solidity: function mintBatch(address to, uint256[] tokenIds, uint256[] amounts, bytes data) returns()
+func (_NFT1155 *NFT1155TransactorSession) MintBatch(to common.Address, tokenIds []*big.Int, amounts []*big.Int, data []byte) (*types.Transaction, error) {
+	return _NFT1155.Contract.MintBatch(&_NFT1155.TransactOpts, to, tokenIds, amounts, data)
+}
+
+// Multicall is a paid mutator transaction binding the contract method 0xac9650d8.
+//
+// Solidity: function multicall(bytes[] data) returns(bytes[] results)
+func (_NFT1155 *NFT1155Transactor) Multicall(opts *bind.TransactOpts, data [][]byte) (*types.Transaction, error) {
+	return _NFT1155.contract.Transact(opts, "multicall", data)
+}
+
+// Multicall is a paid mutator transaction binding the contract method 0xac9650d8.
+//
+// Solidity: function multicall(bytes[] data) returns(bytes[] results)
+func (_NFT1155 *NFT1155Session) Multicall(data [][]byte) (*types.Transaction, error) {
+	return _NFT1155.Contract.Multicall(&_NFT1155.TransactOpts, data)
+}
+
+// Multicall is a paid mutator transaction binding the contract method 0xac9650d8.
+//
+// Solidity: function multicall(bytes[] data) returns(bytes[] results)
+func (_NFT1155 *NFT1155TransactorSession) Multicall(data [][]byte) (*types.Transaction, error) {
+	return _NFT1155.Contract.Multicall(&_NFT1155.TransactOpts, data)
+}
+
 // SafeBatchTransferFrom is a paid mutator transaction binding the contract method 0x2eb2c2d6.
 //
 // Solidity: function safeBatchTransferFrom(address from, address to, uint256[] ids, uint256[] amounts, bytes data) returns()
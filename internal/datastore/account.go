@@ -24,6 +24,8 @@ type UpdateAccountFields struct {
 	YTUsername *string
 	ImageCID   *string
 	CoverCID   *string
+
+	EncryptionPublicKey *string
 }
 
 func (f *UpdateAccountFields) IsEmpty() bool {
@@ -34,7 +36,8 @@ func (f *UpdateAccountFields) IsEmpty() bool {
 		f.CoverCID == nil &&
 		f.Bio == nil &&
 		f.CustomURL == nil &&
-		f.YTUsername == nil
+		f.YTUsername == nil &&
+		f.EncryptionPublicKey == nil
 }
 
 type AccountDatastore struct {
@@ -384,6 +387,11 @@ func (ds *AccountDatastore) Update(ctx context.Context, account *model.Account,
 		account.CoverCID = dbr.NewNullString(*fields.CoverCID)
 	}
 
+	if fields.EncryptionPublicKey != nil {
+		stmt.Set("enc_public_key", dbr.NewNullString(*fields.EncryptionPublicKey))
+		account.EncryptionPublicKey = dbr.NewNullString(*fields.EncryptionPublicKey)
+	}
+
 	_, err = stmt.Where("id = ?", account.ID).ExecContext(ctx)
 	if err != nil {
 		return err
@@ -138,3 +138,30 @@ func (ds *ActivityDatastore) Count(ctx context.Context, fltr *ActivityFilter) (i
 
 	return count, nil
 }
+
+func (ds *ActivityDatastore) DeleteByOrderID(ctx context.Context, orderID int64, typeIds []string) error {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	_, err = tx.
+		DeleteFrom(ds.table).
+		Where("order_id = ? AND type_id IN ?", orderID, typeIds).
+		ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
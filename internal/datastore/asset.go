@@ -8,6 +8,8 @@ import (
 	"github.com/AlekSi/pointer"
 	"github.com/gocraft/dbr/v2"
 	"github.com/videocoin/marketplace/internal/model"
+	"github.com/videocoin/marketplace/internal/network"
+	"github.com/videocoin/marketplace/internal/wyvern"
 	"github.com/videocoin/marketplace/pkg/dbrutil"
 )
 
@@ -21,6 +23,7 @@ type AssetUpdatedFields struct {
 	YTVideoLink         *string
 	ContractAddress     *string
 	MintTxID            *string
+	MintVoucher         *model.MintVoucher
 	OnSale              *bool
 	Price               *float64
 	PutOnSalePrice      *float64
@@ -28,13 +31,42 @@ type AssetUpdatedFields struct {
 	Status              *string
 	DRMKey              *string
 	DRMMeta             *string
+	DRMKID              *string
 	EK                  *string
 	OwnerID             *int64
+	OwnerKeyMissing     *bool
 	TokenCID            *string
+	ChainTokenURI       *string
+	TokenURITxID        *string
 	CurrentBid          *float64
 	PurchasedBid        *float64
 	PaymnetTokenAddress *string
 	AuctionStartedAt    *time.Time
+	JobID               *string
+	SaleKind            *wyvern.SaleKind
+	PriceExtra          *float64
+	ListingTime         *time.Time
+	ExpirationTime      *time.Time
+}
+
+// currentPriceExpr mirrors model.Asset.CurrentPrice, so assets can be sorted by their live price.
+const currentPriceExpr = `(CASE WHEN sale_kind = 1 AND expiration_time > listing_time THEN
+	price - price_extra * LEAST(GREATEST(EXTRACT(EPOCH FROM (now() - listing_time)), 0), EXTRACT(EPOCH FROM (expiration_time - listing_time)))
+		/ EXTRACT(EPOCH FROM (expiration_time - listing_time))
+	ELSE price END)`
+
+// ownedByExpr matches the assets an account holds a balance of, editions
+// included.
+const ownedByExpr = `id IN (SELECT asset_id FROM asset_holders WHERE account_id = ? AND balance > 0)`
+
+// applyMintedFilter matches the assets which can be bought as tokens, that
+// is minted ones and lazily minted ones with a signed voucher, or the ones
+// which haven't been minted yet.
+func applyMintedFilter(stmt *dbr.SelectStmt, minted bool) *dbr.SelectStmt {
+	if minted {
+		return stmt.Where("(mint_tx_id IS NOT NULL OR (lazy_mint AND mint_voucher IS NOT NULL))")
+	}
+	return stmt.Where("mint_tx_id IS NULL")
 }
 
 type AssetDatastore struct {
@@ -73,13 +105,26 @@ func (ds *AssetDatastore) Create(ctx context.Context, asset *model.Asset) error
 		asset.AuctionStartedAt = asset.CreatedAt
 	}
 
+	if asset.Schema == "" {
+		asset.Schema = model.ContractSchemaTypeERC721
+	}
+
+	if asset.Supply <= 0 {
+		asset.Supply = 1
+	}
+
+	if asset.Network == "" {
+		asset.Network = network.DefaultName
+	}
+
 	cols := []string{
 		"created_at", "created_by_id", "owner_id", "status",
 		"name", "description", "yt_video_link",
-		"drm_key", "drm_meta",
+		"drm_key", "drm_meta", "drm_kid",
 		"contract_address", "on_sale", "royalty", "price",
 		"locked", "put_on_sale_price", "current_bid",
-		"auction_started_at",
+		"auction_started_at", "schema", "supply", "network",
+		"lazy_mint", "batch_id",
 	}
 	err = tx.
 		InsertInto(ds.table).
@@ -158,6 +203,39 @@ func (ds *AssetDatastore) GetByTokenID(ctx context.Context, id int64) (*model.As
 	return asset, nil
 }
 
+// GetByDRMKID returns the asset whose media is encrypted with the key.
+func (ds *AssetDatastore) GetByDRMKID(ctx context.Context, kid string) (*model.Asset, error) {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return nil, err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	asset := new(model.Asset)
+	err = tx.
+		Select("*").
+		From(ds.table).
+		Where("drm_kid = ?", kid).
+		LoadOneContext(ctx, asset)
+	if err != nil {
+		if err == dbr.ErrNotFound {
+			return nil, ErrAssetNotFound
+		}
+		return nil, err
+	}
+
+	return asset, nil
+}
+
 func (ds *AssetDatastore) GetByJobID(ctx context.Context, id string) (*model.Asset, error) {
 	var err error
 	tx, ok := dbrutil.DbTxFromContext(ctx)
@@ -232,6 +310,11 @@ func (ds *AssetDatastore) Update(ctx context.Context, asset *model.Asset, fields
 		asset.MintTxID = dbr.NewNullString(*fields.MintTxID)
 	}
 
+	if fields.MintVoucher != nil {
+		stmt.Set("mint_voucher", fields.MintVoucher)
+		asset.MintVoucher = fields.MintVoucher
+	}
+
 	if fields.OnSale != nil {
 		stmt.Set("on_sale", *fields.OnSale)
 		asset.OnSale = *fields.OnSale
@@ -277,6 +360,11 @@ func (ds *AssetDatastore) Update(ctx context.Context, asset *model.Asset, fields
 		asset.DRMMeta = *fields.DRMMeta
 	}
 
+	if fields.DRMKID != nil {
+		stmt.Set("drm_kid", *fields.DRMKID)
+		asset.DRMKID = dbr.NewNullString(*fields.DRMKID)
+	}
+
 	if fields.DRMKey != nil {
 		stmt.Set("drm_key", *fields.DRMKey)
 		asset.DRMKey = *fields.DRMKey
@@ -292,11 +380,51 @@ func (ds *AssetDatastore) Update(ctx context.Context, asset *model.Asset, fields
 		asset.TokenCID = dbr.NewNullString(*fields.TokenCID)
 	}
 
+	if fields.OwnerKeyMissing != nil {
+		stmt.Set("owner_key_missing", *fields.OwnerKeyMissing)
+		asset.OwnerKeyMissing = *fields.OwnerKeyMissing
+	}
+
+	if fields.ChainTokenURI != nil {
+		stmt.Set("chain_token_uri", dbr.NewNullString(*fields.ChainTokenURI))
+		asset.ChainTokenURI = dbr.NewNullString(*fields.ChainTokenURI)
+	}
+
+	if fields.TokenURITxID != nil {
+		stmt.Set("token_uri_tx_id", dbr.NewNullString(*fields.TokenURITxID))
+		asset.TokenURITxID = dbr.NewNullString(*fields.TokenURITxID)
+	}
+
 	if fields.Status != nil {
 		stmt.Set("status", *fields.Status)
 		asset.Status = model.AssetStatus(*fields.Status)
 	}
 
+	if fields.JobID != nil {
+		stmt.Set("job_id", *fields.JobID)
+		asset.JobID = dbr.NewNullString(*fields.JobID)
+	}
+
+	if fields.SaleKind != nil {
+		stmt.Set("sale_kind", *fields.SaleKind)
+		asset.SaleKind = *fields.SaleKind
+	}
+
+	if fields.PriceExtra != nil {
+		stmt.Set("price_extra", *fields.PriceExtra)
+		asset.PriceExtra = *fields.PriceExtra
+	}
+
+	if fields.ListingTime != nil {
+		stmt.Set("listing_time", *fields.ListingTime)
+		asset.ListingTime = fields.ListingTime
+	}
+
+	if fields.ExpirationTime != nil {
+		stmt.Set("expiration_time", *fields.ExpirationTime)
+		asset.ExpirationTime = fields.ExpirationTime
+	}
+
 	_, err = stmt.Where("id = ?", asset.ID).ExecContext(ctx)
 	if err != nil {
 		return err
@@ -305,6 +433,47 @@ func (ds *AssetDatastore) Update(ctx context.Context, asset *model.Asset, fields
 	return nil
 }
 
+// Restore puts back the asset fields saved in the snapshot.
+func (ds *AssetDatastore) Restore(ctx context.Context, snapshot *model.AssetSnapshot) error {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	stmt := tx.
+		Update(ds.table).
+		Set("owner_id", snapshot.OwnerID).
+		Set("on_sale", snapshot.OnSale).
+		Set("status", snapshot.Status).
+		Set("purchased_bid", snapshot.PurchasedBid).
+		Set("token_cid", snapshot.TokenCID).
+		Set("drm_key", snapshot.DRMKey).
+		Set("drm_meta", snapshot.DRMMeta).
+		Set("owner_key_missing", snapshot.OwnerKeyMissing)
+
+	// snapshots taken before the kid got its own column don't have it
+	if snapshot.DRMKID != "" {
+		stmt = stmt.Set("drm_kid", snapshot.DRMKID)
+	}
+
+	_, err = stmt.Where("id = ?", snapshot.ID).ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (ds *AssetDatastore) MarkStatusAs(ctx context.Context, asset *model.Asset, status model.AssetStatus) error {
 	var err error
 	tx, ok := dbrutil.DbTxFromContext(ctx)
@@ -379,7 +548,13 @@ func (ds *AssetDatastore) List(ctx context.Context, fltr *AssetsFilter, limit *L
 			selectStmt = selectStmt.Where("created_by_id = ?", *fltr.CreatedByID)
 		}
 		if fltr.OwnerID != nil {
-			selectStmt = selectStmt.Where("owner_id = ?", *fltr.OwnerID)
+			selectStmt = selectStmt.Where(ownedByExpr, *fltr.OwnerID)
+		}
+		if fltr.Network != nil {
+			selectStmt = selectStmt.Where("network = ?", *fltr.Network)
+		}
+		if fltr.BatchID != nil {
+			selectStmt = selectStmt.Where("batch_id = ?", *fltr.BatchID)
 		}
 		if len(fltr.Statuses) > 0 {
 			selectStmt = selectStmt.Where("status IN ?", fltr.Statuses)
@@ -390,15 +565,19 @@ func (ds *AssetDatastore) List(ctx context.Context, fltr *AssetsFilter, limit *L
 		if fltr.OnSale != nil && *fltr.OnSale {
 			selectStmt = selectStmt.Where("on_sale = ?", *fltr.OnSale)
 		}
-		if fltr.Minted != nil && *fltr.Minted {
-			selectStmt = selectStmt.Where("mint_tx_id IS NOT NULL")
+		if fltr.Minted != nil {
+			selectStmt = applyMintedFilter(selectStmt, *fltr.Minted)
 		}
 		if fltr.Sold != nil && *fltr.Sold {
 			selectStmt = selectStmt.
 				Where("(on_sale = ? AND status = ?) OR (created_by_id != owner_id)", false, model.AssetStatusTransferred)
 		}
 		if fltr.Sort != nil && fltr.Sort.Field != "" {
-			selectStmt = selectStmt.OrderDir(fltr.Sort.Field, fltr.Sort.IsAsc)
+			field := fltr.Sort.Field
+			if field == "current_price" {
+				field = currentPriceExpr
+			}
+			selectStmt = selectStmt.OrderDir(field, fltr.Sort.IsAsc)
 		}
 	}
 
@@ -443,7 +622,13 @@ func (ds *AssetDatastore) Count(ctx context.Context, fltr *AssetsFilter) (int64,
 			selectStmt = selectStmt.Where("created_by_id = ?", *fltr.CreatedByID)
 		}
 		if fltr.OwnerID != nil {
-			selectStmt = selectStmt.Where("owner_id = ?", *fltr.OwnerID)
+			selectStmt = selectStmt.Where(ownedByExpr, *fltr.OwnerID)
+		}
+		if fltr.Network != nil {
+			selectStmt = selectStmt.Where("network = ?", *fltr.Network)
+		}
+		if fltr.BatchID != nil {
+			selectStmt = selectStmt.Where("batch_id = ?", *fltr.BatchID)
 		}
 		if len(fltr.Statuses) > 0 {
 			selectStmt = selectStmt.Where("status IN ?", fltr.Statuses)
@@ -457,8 +642,8 @@ func (ds *AssetDatastore) Count(ctx context.Context, fltr *AssetsFilter) (int64,
 		if fltr.Sold != nil && *fltr.Sold {
 			selectStmt = selectStmt.Where("on_sale = ? AND status = ?", false, model.AssetStatusTransferred)
 		}
-		if fltr.Minted != nil && *fltr.Minted {
-			selectStmt = selectStmt.Where("mint_tx_id IS NOT NULL")
+		if fltr.Minted != nil {
+			selectStmt = applyMintedFilter(selectStmt, *fltr.Minted)
 		}
 	}
 
@@ -0,0 +1,138 @@
+package datastore
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/AlekSi/pointer"
+	"github.com/gocraft/dbr/v2"
+	"github.com/videocoin/marketplace/internal/model"
+	"github.com/videocoin/marketplace/pkg/dbrutil"
+)
+
+var (
+	ErrAssetBatchNotFound = errors.New("asset batch not found")
+)
+
+type AssetBatchDatastore struct {
+	conn  *dbr.Connection
+	table string
+}
+
+func NewAssetBatchDatastore(ctx context.Context, conn *dbr.Connection) (*AssetBatchDatastore, error) {
+	return &AssetBatchDatastore{
+		conn:  conn,
+		table: "asset_batches",
+	}, nil
+}
+
+func (ds *AssetBatchDatastore) Create(ctx context.Context, batch *model.AssetBatch) error {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	if batch.CreatedAt == nil || batch.CreatedAt.IsZero() {
+		batch.CreatedAt = pointer.ToTime(time.Now())
+	}
+	batch.UpdatedAt = batch.CreatedAt
+
+	cols := []string{"created_at", "updated_at", "created_by_id", "network", "size"}
+	err = tx.
+		InsertInto(ds.table).
+		Columns(cols...).
+		Record(batch).
+		Returning("id").
+		LoadContext(ctx, batch)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (ds *AssetBatchDatastore) GetByID(ctx context.Context, id int64) (*model.AssetBatch, error) {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return nil, err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	batch := new(model.AssetBatch)
+	err = tx.
+		Select("*").
+		From(ds.table).
+		Where("id = ?", id).
+		LoadOneContext(ctx, batch)
+	if err != nil {
+		if err == dbr.ErrNotFound {
+			return nil, ErrAssetBatchNotFound
+		}
+		return nil, err
+	}
+
+	return batch, nil
+}
+
+// SetMintJobID sets the mint job of the batch unless it already has one.
+// It reports whether the job has been set, the last two assets of a batch
+// may be processed at the same time and both try to queue its mint.
+func (ds *AssetBatchDatastore) SetMintJobID(ctx context.Context, batch *model.AssetBatch, jobID string) (bool, error) {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return false, err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	res, err := tx.
+		Update(ds.table).
+		Set("mint_job_id", jobID).
+		Set("updated_at", time.Now()).
+		Where("id = ? AND mint_job_id IS NULL", batch.ID).
+		ExecContext(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	if affected == 0 {
+		return false, nil
+	}
+
+	batch.MintJobID = dbr.NewNullString(jobID)
+
+	return true, nil
+}
@@ -0,0 +1,244 @@
+package datastore
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gocraft/dbr/v2"
+	"github.com/videocoin/marketplace/internal/model"
+	"github.com/videocoin/marketplace/pkg/dbrutil"
+)
+
+var (
+	ErrAssetHolderNotFound       = errors.New("asset holder not found")
+	ErrAssetHolderBalanceTooLow  = errors.New("asset holder balance too low")
+	ErrAssetHolderInvalidBalance = errors.New("invalid asset holder balance")
+)
+
+type AssetHolderDatastore struct {
+	conn  *dbr.Connection
+	table string
+}
+
+func NewAssetHolderDatastore(ctx context.Context, conn *dbr.Connection) (*AssetHolderDatastore, error) {
+	return &AssetHolderDatastore{
+		conn:  conn,
+		table: "asset_holders",
+	}, nil
+}
+
+func (ds *AssetHolderDatastore) Get(ctx context.Context, assetID, accountID int64) (*model.AssetHolder, error) {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return nil, err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	holder := new(model.AssetHolder)
+	err = tx.
+		Select("*").
+		From(ds.table).
+		Where("asset_id = ? AND account_id = ?", assetID, accountID).
+		LoadOneContext(ctx, holder)
+	if err != nil {
+		if err == dbr.ErrNotFound {
+			return nil, ErrAssetHolderNotFound
+		}
+		return nil, err
+	}
+
+	return holder, nil
+}
+
+// GetBalance returns the balance the account holds of the asset, zero if the
+// account is not a holder.
+func (ds *AssetHolderDatastore) GetBalance(ctx context.Context, assetID, accountID int64) (int64, error) {
+	holder, err := ds.Get(ctx, assetID, accountID)
+	if err != nil {
+		if err == ErrAssetHolderNotFound {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	return holder.Balance, nil
+}
+
+func (ds *AssetHolderDatastore) ListByAssetID(ctx context.Context, assetID int64) ([]*model.AssetHolder, error) {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return nil, err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	holders := make([]*model.AssetHolder, 0)
+	_, err = tx.
+		Select("*").
+		From(ds.table).
+		Where("asset_id = ? AND balance > 0", assetID).
+		OrderDir("balance", false).
+		LoadContext(ctx, &holders)
+	if err != nil {
+		return nil, err
+	}
+
+	return holders, nil
+}
+
+// Credit adds amount to the balance the account holds of the asset.
+func (ds *AssetHolderDatastore) Credit(ctx context.Context, assetID, accountID int64, amount int64) error {
+	if amount <= 0 {
+		return ErrAssetHolderInvalidBalance
+	}
+
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	query := `INSERT INTO asset_holders (asset_id, account_id, balance, updated_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT (asset_id, account_id) DO UPDATE SET balance = asset_holders.balance + EXCLUDED.balance, updated_at = EXCLUDED.updated_at`
+	_, err = tx.InsertBySql(query, assetID, accountID, amount, time.Now()).ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Debit subtracts amount from the balance the account holds of the asset.
+// The holder is removed once the balance drops to zero.
+func (ds *AssetHolderDatastore) Debit(ctx context.Context, assetID, accountID int64, amount int64) error {
+	if amount <= 0 {
+		return ErrAssetHolderInvalidBalance
+	}
+
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	res, err := tx.
+		Update(ds.table).
+		Set("balance", dbr.Expr("balance - ?", amount)).
+		Set("updated_at", time.Now()).
+		Where("asset_id = ? AND account_id = ? AND balance >= ?", assetID, accountID, amount).
+		ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return ErrAssetHolderBalanceTooLow
+	}
+
+	_, err = tx.
+		DeleteFrom(ds.table).
+		Where("asset_id = ? AND account_id = ? AND balance = 0", assetID, accountID).
+		ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Transfer moves amount of the asset from one holder to another.
+func (ds *AssetHolderDatastore) Transfer(ctx context.Context, assetID, fromID, toID int64, amount int64) error {
+	err := ds.Debit(ctx, assetID, fromID, amount)
+	if err != nil {
+		return err
+	}
+
+	return ds.Credit(ctx, assetID, toID, amount)
+}
+
+// Replace overwrites the holders of the asset, it is used to restore the
+// balances saved in an asset snapshot.
+func (ds *AssetHolderDatastore) Replace(ctx context.Context, assetID int64, holders []*model.AssetHolder) error {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	_, err = tx.
+		DeleteFrom(ds.table).
+		Where("asset_id = ?", assetID).
+		ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, holder := range holders {
+		if holder.Balance <= 0 {
+			continue
+		}
+
+		_, err = tx.
+			InsertInto(ds.table).
+			Pair("asset_id", assetID).
+			Pair("account_id", holder.AccountID).
+			Pair("balance", holder.Balance).
+			Pair("updated_at", time.Now()).
+			ExecContext(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,345 @@
+package datastore
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/AlekSi/pointer"
+	"github.com/gocraft/dbr/v2"
+	"github.com/videocoin/marketplace/internal/model"
+	"github.com/videocoin/marketplace/pkg/dbrutil"
+)
+
+var (
+	ErrAuctionNotFound = errors.New("auction not found")
+)
+
+type AuctionDatastore struct {
+	conn  *dbr.Connection
+	table string
+}
+
+func NewAuctionDatastore(ctx context.Context, conn *dbr.Connection) (*AuctionDatastore, error) {
+	return &AuctionDatastore{
+		conn:  conn,
+		table: "auctions",
+	}, nil
+}
+
+func (ds *AuctionDatastore) Create(ctx context.Context, auction *model.Auction) error {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	if auction.CreatedAt == nil || auction.CreatedAt.IsZero() {
+		auction.CreatedAt = pointer.ToTime(time.Now())
+	}
+
+	if auction.Status == "" {
+		auction.Status = model.AuctionStatusOpen
+	}
+
+	cols := []string{
+		"created_at", "asset_id", "seller_id", "sell_order_id", "status", "payment_token_address",
+		"start_price", "reserve_price", "min_bid_increment_bps", "extension_window", "start_at", "end_at",
+		"expire_at",
+	}
+	err = tx.
+		InsertInto(ds.table).
+		Columns(cols...).
+		Record(auction).
+		Returning("id").
+		LoadContext(ctx, auction)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (ds *AuctionDatastore) GetByID(ctx context.Context, id int64) (*model.Auction, error) {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return nil, err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	auction := new(model.Auction)
+	err = tx.
+		Select("*").
+		From(ds.table).
+		Where("id = ?", id).
+		LoadOneContext(ctx, auction)
+	if err != nil {
+		if err == dbr.ErrNotFound {
+			return nil, ErrAuctionNotFound
+		}
+		return nil, err
+	}
+
+	return auction, nil
+}
+
+// GetLatestByAssetID returns the most recent auction of the asset whatever its status is.
+func (ds *AuctionDatastore) GetLatestByAssetID(ctx context.Context, assetID int64) (*model.Auction, error) {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return nil, err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	auction := new(model.Auction)
+	err = tx.
+		Select("*").
+		From(ds.table).
+		Where("asset_id = ?", assetID).
+		OrderDesc("id").
+		Limit(1).
+		LoadOneContext(ctx, auction)
+	if err != nil {
+		if err == dbr.ErrNotFound {
+			return nil, ErrAuctionNotFound
+		}
+		return nil, err
+	}
+
+	return auction, nil
+}
+
+// GetOpenByAssetID locks the open auction of the asset for the rest of the
+// transaction, so concurrent bids are validated one after another.
+func (ds *AuctionDatastore) GetOpenByAssetID(ctx context.Context, assetID int64) (*model.Auction, error) {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return nil, err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	auction := new(model.Auction)
+	err = tx.
+		Select("*").
+		From(ds.table).
+		Where("asset_id = ? AND status = ?", assetID, model.AuctionStatusOpen).
+		OrderDesc("id").
+		Limit(1).
+		Suffix("FOR UPDATE").
+		LoadOneContext(ctx, auction)
+	if err != nil {
+		if err == dbr.ErrNotFound {
+			return nil, ErrAuctionNotFound
+		}
+		return nil, err
+	}
+
+	return auction, nil
+}
+
+func (ds *AuctionDatastore) ListLatestByAssetIds(ctx context.Context, assetIds []int64) ([]*model.Auction, error) {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return nil, err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	auctions := []*model.Auction{}
+	if len(assetIds) == 0 {
+		return auctions, nil
+	}
+
+	_, err = tx.
+		Select("DISTINCT ON (asset_id) *").
+		From(ds.table).
+		Where("asset_id IN ?", assetIds).
+		OrderBy("asset_id").
+		OrderDesc("id").
+		LoadContext(ctx, &auctions)
+	if err != nil {
+		return nil, err
+	}
+
+	return auctions, nil
+}
+
+// ListExpired returns the open auctions whose end time has passed.
+func (ds *AuctionDatastore) ListExpired(ctx context.Context, now time.Time) ([]*model.Auction, error) {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return nil, err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	auctions := []*model.Auction{}
+	_, err = tx.
+		Select("*").
+		From(ds.table).
+		Where("status = ? AND end_at <= ?", model.AuctionStatusOpen, now).
+		OrderAsc("end_at").
+		LoadContext(ctx, &auctions)
+	if err != nil {
+		return nil, err
+	}
+
+	return auctions, nil
+}
+
+func (ds *AuctionDatastore) UpdateEndAt(ctx context.Context, auction *model.Auction, endAt time.Time) error {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	_, err = tx.
+		Update(ds.table).
+		Set("end_at", endAt).
+		Where("id = ?", auction.ID).
+		ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	auction.EndAt = pointer.ToTime(endAt)
+
+	return nil
+}
+
+func (ds *AuctionDatastore) MarkStatusAs(ctx context.Context, auction *model.Auction, status model.AuctionStatus) error {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	_, err = tx.
+		Update(ds.table).
+		Set("status", status).
+		Where("id = ?", auction.ID).
+		ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	auction.Status = status
+
+	return nil
+}
+
+// MarkStatusAsEnded closes the auction with the given winning bid.
+func (ds *AuctionDatastore) MarkStatusAsEnded(ctx context.Context, auction *model.Auction, bid *model.AuctionBid) error {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	_, err = tx.
+		Update(ds.table).
+		Set("status", model.AuctionStatusEnded).
+		Set("winning_bid_id", bid.ID).
+		Where("id = ?", auction.ID).
+		ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	auction.Status = model.AuctionStatusEnded
+	auction.WinningBidID = dbr.NewNullInt64(bid.ID)
+
+	return nil
+}
+
+func (ds *AuctionDatastore) MarkStatusAsUnsold(ctx context.Context, auction *model.Auction) error {
+	return ds.MarkStatusAs(ctx, auction, model.AuctionStatusUnsold)
+}
+
+func (ds *AuctionDatastore) MarkStatusAsSettled(ctx context.Context, auction *model.Auction) error {
+	return ds.MarkStatusAs(ctx, auction, model.AuctionStatusSettled)
+}
+
+func (ds *AuctionDatastore) MarkStatusAsCancelled(ctx context.Context, auction *model.Auction) error {
+	return ds.MarkStatusAs(ctx, auction, model.AuctionStatusCancelled)
+}
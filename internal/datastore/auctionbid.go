@@ -0,0 +1,90 @@
+package datastore
+
+import (
+	"context"
+	"time"
+
+	"github.com/AlekSi/pointer"
+	"github.com/gocraft/dbr/v2"
+	"github.com/videocoin/marketplace/internal/model"
+	"github.com/videocoin/marketplace/pkg/dbrutil"
+)
+
+type AuctionBidDatastore struct {
+	conn  *dbr.Connection
+	table string
+}
+
+func NewAuctionBidDatastore(ctx context.Context, conn *dbr.Connection) (*AuctionBidDatastore, error) {
+	return &AuctionBidDatastore{
+		conn:  conn,
+		table: "auction_bids",
+	}, nil
+}
+
+func (ds *AuctionBidDatastore) Create(ctx context.Context, bid *model.AuctionBid) error {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	if bid.CreatedAt == nil || bid.CreatedAt.IsZero() {
+		bid.CreatedAt = pointer.ToTime(time.Now())
+	}
+
+	cols := []string{"created_at", "auction_id", "order_id", "bidder_id", "amount"}
+	err = tx.
+		InsertInto(ds.table).
+		Columns(cols...).
+		Record(bid).
+		Returning("id").
+		LoadContext(ctx, bid)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ListByAuctionID returns the auction bids, highest first. Bids with the same
+// amount are ordered by time, the earliest wins.
+func (ds *AuctionBidDatastore) ListByAuctionID(ctx context.Context, auctionID int64) ([]*model.AuctionBid, error) {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return nil, err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	bids := []*model.AuctionBid{}
+	_, err = tx.
+		Select("*").
+		From(ds.table).
+		Where("auction_id = ?", auctionID).
+		OrderDesc("amount").
+		OrderAsc("created_at").
+		LoadContext(ctx, &bids)
+	if err != nil {
+		return nil, err
+	}
+
+	return bids, nil
+}
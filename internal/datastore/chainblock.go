@@ -0,0 +1,172 @@
+package datastore
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gocraft/dbr/v2"
+	"github.com/videocoin/marketplace/internal/model"
+	"github.com/videocoin/marketplace/pkg/dbrutil"
+)
+
+var (
+	ErrChainBlockNotFound = errors.New("chain block not found")
+)
+
+type ChainBlockDatastore struct {
+	conn  *dbr.Connection
+	table string
+}
+
+func NewChainBlockDatastore(ctx context.Context, conn *dbr.Connection) (*ChainBlockDatastore, error) {
+	return &ChainBlockDatastore{
+		conn:  conn,
+		table: "chain_blocks",
+	}, nil
+}
+
+func (ds *ChainBlockDatastore) Save(ctx context.Context, block *model.ChainBlock) error {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	query := `INSERT INTO chain_blocks (chain_id, height, hash, parent_hash) VALUES (?, ?, ?, ?)
+		ON CONFLICT (chain_id, height) DO UPDATE SET hash = EXCLUDED.hash, parent_hash = EXCLUDED.parent_hash`
+	_, err = tx.
+		InsertBySql(query, block.ChainID, block.Height, block.Hash, block.ParentHash).
+		ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (ds *ChainBlockDatastore) Get(ctx context.Context, chainID string, height uint64) (*model.ChainBlock, error) {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return nil, err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	block := new(model.ChainBlock)
+	err = tx.
+		Select("*").
+		From(ds.table).
+		Where("chain_id = ? AND height = ?", chainID, height).
+		LoadOneContext(ctx, block)
+	if err != nil {
+		if err == dbr.ErrNotFound {
+			return nil, ErrChainBlockNotFound
+		}
+		return nil, err
+	}
+
+	return block, nil
+}
+
+// ListBelow returns the known blocks at or below the given height, highest first.
+func (ds *ChainBlockDatastore) ListBelow(ctx context.Context, chainID string, height uint64) ([]*model.ChainBlock, error) {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return nil, err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	blocks := []*model.ChainBlock{}
+	_, err = tx.
+		Select("*").
+		From(ds.table).
+		Where("chain_id = ? AND height <= ?", chainID, height).
+		OrderDesc("height").
+		LoadContext(ctx, &blocks)
+	if err != nil {
+		return nil, err
+	}
+
+	return blocks, nil
+}
+
+func (ds *ChainBlockDatastore) DeleteAbove(ctx context.Context, chainID string, height uint64) error {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	_, err = tx.
+		DeleteFrom(ds.table).
+		Where("chain_id = ? AND height > ?", chainID, height).
+		ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Prune removes blocks below the given height, they are too deep to be reorganized.
+func (ds *ChainBlockDatastore) Prune(ctx context.Context, chainID string, height uint64) error {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	_, err = tx.
+		DeleteFrom(ds.table).
+		Where("chain_id = ? AND height < ?", chainID, height).
+		ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,143 @@
+package datastore
+
+import (
+	"context"
+	"time"
+
+	"github.com/AlekSi/pointer"
+	"github.com/gocraft/dbr/v2"
+	"github.com/videocoin/marketplace/internal/model"
+	"github.com/videocoin/marketplace/pkg/dbrutil"
+)
+
+type ChainBlockChangeDatastore struct {
+	conn  *dbr.Connection
+	table string
+}
+
+func NewChainBlockChangeDatastore(ctx context.Context, conn *dbr.Connection) (*ChainBlockChangeDatastore, error) {
+	return &ChainBlockChangeDatastore{
+		conn:  conn,
+		table: "chain_block_changes",
+	}, nil
+}
+
+func (ds *ChainBlockChangeDatastore) Create(ctx context.Context, change *model.ChainBlockChange) error {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	if change.CreatedAt == nil || change.CreatedAt.IsZero() {
+		change.CreatedAt = pointer.ToTime(time.Now())
+	}
+
+	cols := []string{"created_at", "chain_id", "height", "block_hash", "order_id", "order_status", "order_filled_quantity", "asset"}
+	err = tx.
+		InsertInto(ds.table).
+		Columns(cols...).
+		Record(change).
+		Returning("id").
+		LoadContext(ctx, change)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ListAbove returns the changes applied above the given height, latest first,
+// which is the order they have to be reverted in.
+func (ds *ChainBlockChangeDatastore) ListAbove(ctx context.Context, chainID string, height uint64) ([]*model.ChainBlockChange, error) {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return nil, err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	changes := []*model.ChainBlockChange{}
+	_, err = tx.
+		Select("*").
+		From(ds.table).
+		Where("chain_id = ? AND height > ?", chainID, height).
+		OrderDesc("id").
+		LoadContext(ctx, &changes)
+	if err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+func (ds *ChainBlockChangeDatastore) Delete(ctx context.Context, change *model.ChainBlockChange) error {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	_, err = tx.
+		DeleteFrom(ds.table).
+		Where("id = ?", change.ID).
+		ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (ds *ChainBlockChangeDatastore) Prune(ctx context.Context, chainID string, height uint64) error {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	_, err = tx.
+		DeleteFrom(ds.table).
+		Where("chain_id = ? AND height < ?", chainID, height).
+		ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,250 @@
+package datastore
+
+import (
+	"context"
+	"time"
+
+	"github.com/AlekSi/pointer"
+	"github.com/gocraft/dbr/v2"
+	"github.com/videocoin/marketplace/internal/model"
+	"github.com/videocoin/marketplace/pkg/dbrutil"
+)
+
+type ChainEventDatastore struct {
+	conn  *dbr.Connection
+	table string
+}
+
+func NewChainEventDatastore(ctx context.Context, conn *dbr.Connection) (*ChainEventDatastore, error) {
+	return &ChainEventDatastore{
+		conn:  conn,
+		table: "chain_events",
+	}, nil
+}
+
+// Save stores the event unless it has already been seen, the same log is
+// read again when a block range is replayed.
+func (ds *ChainEventDatastore) Save(ctx context.Context, event *model.ChainEvent) error {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	if event.CreatedAt == nil || event.CreatedAt.IsZero() {
+		event.CreatedAt = pointer.ToTime(time.Now())
+	}
+	if event.Status == "" {
+		event.Status = model.ChainEventStatusPending
+	}
+
+	query := `INSERT INTO chain_events (
+			created_at, updated_at, chain_id, height, block_hash, tx_hash,
+			log_index, type, payload, status
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (chain_id, tx_hash, log_index) DO NOTHING`
+	_, err = tx.InsertBySql(
+		query,
+		event.CreatedAt,
+		event.CreatedAt,
+		event.ChainID,
+		event.Height,
+		event.BlockHash,
+		event.TxHash,
+		event.LogIndex,
+		event.Type,
+		event.Payload,
+		event.Status,
+	).ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ListPending returns the events waiting to be processed in the order they
+// were emitted on chain.
+func (ds *ChainEventDatastore) ListPending(ctx context.Context, chainID string, limit uint64) ([]*model.ChainEvent, error) {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return nil, err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	events := []*model.ChainEvent{}
+	_, err = tx.
+		Select("*").
+		From(ds.table).
+		Where("chain_id = ? AND status = ?", chainID, model.ChainEventStatusPending).
+		OrderAsc("height").
+		OrderAsc("log_index").
+		Limit(limit).
+		LoadContext(ctx, &events)
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func (ds *ChainEventDatastore) MarkStatusAsProcessed(ctx context.Context, event *model.ChainEvent) error {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	now := time.Now()
+
+	_, err = tx.
+		Update(ds.table).
+		Set("status", model.ChainEventStatusProcessed).
+		Set("attempts", event.Attempts+1).
+		Set("last_error", nil).
+		Set("processed_at", now).
+		Set("updated_at", now).
+		Where("id = ?", event.ID).
+		ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	event.Status = model.ChainEventStatusProcessed
+	event.Attempts++
+	event.LastError = dbr.NullString{}
+	event.ProcessedAt = pointer.ToTime(now)
+
+	return nil
+}
+
+// MarkStatusAsFailed keeps the event pending for another attempt or marks it
+// as failed once maxAttempts have been used.
+func (ds *ChainEventDatastore) MarkStatusAsFailed(ctx context.Context, event *model.ChainEvent, eventErr error, maxAttempts int) error {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	status := model.ChainEventStatusPending
+	if event.Attempts+1 >= maxAttempts {
+		status = model.ChainEventStatusFailed
+	}
+
+	_, err = tx.
+		Update(ds.table).
+		Set("status", status).
+		Set("attempts", event.Attempts+1).
+		Set("last_error", eventErr.Error()).
+		Set("updated_at", time.Now()).
+		Where("id = ?", event.ID).
+		ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	event.Status = status
+	event.Attempts++
+	event.LastError = dbr.NewNullString(eventErr.Error())
+
+	return nil
+}
+
+// Reset makes the events of the block range pending again, so they are
+// processed once more.
+func (ds *ChainEventDatastore) Reset(ctx context.Context, chainID string, from, to uint64) (int64, error) {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return 0, err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	res, err := tx.
+		Update(ds.table).
+		Set("status", model.ChainEventStatusPending).
+		Set("attempts", 0).
+		Set("last_error", nil).
+		Set("processed_at", nil).
+		Set("updated_at", time.Now()).
+		Where("chain_id = ? AND height >= ? AND height <= ?", chainID, from, to).
+		ExecContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
+
+// DeleteAbove removes the events of orphaned blocks.
+func (ds *ChainEventDatastore) DeleteAbove(ctx context.Context, chainID string, height uint64) error {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	_, err = tx.
+		DeleteFrom(ds.table).
+		Where("chain_id = ? AND height > ?", chainID, height).
+		ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
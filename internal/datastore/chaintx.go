@@ -0,0 +1,309 @@
+package datastore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AlekSi/pointer"
+	"github.com/gocraft/dbr/v2"
+	"github.com/videocoin/marketplace/internal/model"
+	"github.com/videocoin/marketplace/pkg/dbrutil"
+)
+
+var (
+	ErrChainTxNotFound = errors.New("chain tx not found")
+)
+
+type ChainTxDatastore struct {
+	conn  *dbr.Connection
+	table string
+}
+
+func NewChainTxDatastore(ctx context.Context, conn *dbr.Connection) (*ChainTxDatastore, error) {
+	return &ChainTxDatastore{
+		conn:  conn,
+		table: "chain_txs",
+	}, nil
+}
+
+func (ds *ChainTxDatastore) Create(ctx context.Context, chainTx *model.ChainTx) error {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	if chainTx.CreatedAt == nil || chainTx.CreatedAt.IsZero() {
+		chainTx.CreatedAt = pointer.ToTime(time.Now())
+	}
+	if chainTx.Status == "" {
+		chainTx.Status = model.ChainTxStatusPending
+	}
+
+	cols := []string{
+		"created_at", "network", "from_address", "to_address", "nonce", "kind", "asset_id", "asset_ids",
+		"token_uri", "data", "gas_limit", "gas_price", "tx_hash", "hashes", "status", "sent_at",
+	}
+	err = tx.
+		InsertInto(ds.table).
+		Columns(cols...).
+		Record(chainTx).
+		Returning("id").
+		LoadContext(ctx, chainTx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetLatestByAssetID returns the latest transaction of the kind sent for
+// the asset which hasn't failed, including the ones minting it together
+// with other assets.
+func (ds *ChainTxDatastore) GetLatestByAssetID(ctx context.Context, kind model.ChainTxKind, assetID int64) (*model.ChainTx, error) {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return nil, err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	chainTx := new(model.ChainTx)
+	err = tx.
+		Select("*").
+		From(ds.table).
+		Where(
+			"kind = ? AND (asset_id = ? OR asset_ids @> ?::jsonb) AND status != ?",
+			kind, assetID, fmt.Sprintf("[%d]", assetID), model.ChainTxStatusFailed,
+		).
+		OrderDesc("id").
+		Limit(1).
+		LoadOneContext(ctx, chainTx)
+	if err != nil {
+		if err == dbr.ErrNotFound {
+			return nil, ErrChainTxNotFound
+		}
+		return nil, err
+	}
+
+	return chainTx, nil
+}
+
+func (ds *ChainTxDatastore) CountPendingByAssetID(ctx context.Context, kind model.ChainTxKind, assetID int64) (int64, error) {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return 0, err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	var count int64
+	err = tx.
+		Select("COUNT(id)").
+		From(ds.table).
+		Where("kind = ? AND asset_id = ? AND status = ?", kind, assetID, model.ChainTxStatusPending).
+		LoadOneContext(ctx, &count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// ListPending returns the transactions of the sender waiting for a receipt
+// in nonce order.
+func (ds *ChainTxDatastore) ListPending(ctx context.Context, network string, from string) ([]*model.ChainTx, error) {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return nil, err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	chainTxs := []*model.ChainTx{}
+	_, err = tx.
+		Select("*").
+		From(ds.table).
+		Where("network = ? AND from_address = ? AND status = ?", network, from, model.ChainTxStatusPending).
+		OrderAsc("nonce").
+		LoadContext(ctx, &chainTxs)
+	if err != nil {
+		return nil, err
+	}
+
+	return chainTxs, nil
+}
+
+// GetMaxNonce returns the highest nonce used by the sender, the nonces of
+// failed transactions are free again.
+func (ds *ChainTxDatastore) GetMaxNonce(ctx context.Context, network string, from string) (dbr.NullInt64, error) {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return dbr.NullInt64{}, err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	var nonce dbr.NullInt64
+	err = tx.
+		Select("MAX(nonce)").
+		From(ds.table).
+		Where("network = ? AND from_address = ? AND status != ?", network, from, model.ChainTxStatusFailed).
+		LoadOneContext(ctx, &nonce)
+	if err != nil {
+		return dbr.NullInt64{}, err
+	}
+
+	return nonce, nil
+}
+
+// UpdateSent saves the latest transaction sent with the nonce.
+func (ds *ChainTxDatastore) UpdateSent(ctx context.Context, chainTx *model.ChainTx) error {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	_, err = tx.
+		Update(ds.table).
+		Set("tx_hash", chainTx.TxHash).
+		Set("hashes", chainTx.Hashes).
+		Set("gas_price", chainTx.GasPrice).
+		Set("bumps", chainTx.Bumps).
+		Set("sent_at", chainTx.SentAt).
+		Set("updated_at", time.Now()).
+		Where("id = ?", chainTx.ID).
+		ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (ds *ChainTxDatastore) MarkStatusAsConfirmed(ctx context.Context, chainTx *model.ChainTx, hash string, blockNumber uint64) error {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	now := time.Now()
+
+	_, err = tx.
+		Update(ds.table).
+		Set("status", model.ChainTxStatusConfirmed).
+		Set("tx_hash", hash).
+		Set("block_number", blockNumber).
+		Set("last_error", nil).
+		Set("confirmed_at", now).
+		Set("updated_at", now).
+		Where("id = ?", chainTx.ID).
+		ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	chainTx.Status = model.ChainTxStatusConfirmed
+	chainTx.TxHash = hash
+	chainTx.BlockNumber = dbr.NewNullInt64(int64(blockNumber))
+	chainTx.LastError = dbr.NullString{}
+	chainTx.ConfirmedAt = pointer.ToTime(now)
+
+	return nil
+}
+
+func (ds *ChainTxDatastore) MarkStatusAsFailed(ctx context.Context, chainTx *model.ChainTx, txErr error) error {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	_, err = tx.
+		Update(ds.table).
+		Set("status", model.ChainTxStatusFailed).
+		Set("last_error", txErr.Error()).
+		Set("updated_at", time.Now()).
+		Where("id = ?", chainTx.ID).
+		ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	chainTx.Status = model.ChainTxStatusFailed
+	chainTx.LastError = dbr.NewNullString(txErr.Error())
+
+	return nil
+}
@@ -14,13 +14,24 @@ import (
 type Datastore struct {
 	conn *dbr.Connection
 
-	Accounts  *AccountDatastore
-	Assets    *AssetDatastore
-	Media     *MediaDatastore
-	Tokens    *TokenDatastore
-	Orders    *OrderDatastore
-	ChainMeta *ChainMetaDatastore
-	Activity  *ActivityDatastore
+	Accounts          *AccountDatastore
+	Assets            *AssetDatastore
+	AssetHolders      *AssetHolderDatastore
+	AssetBatches      *AssetBatchDatastore
+	AssetKeyEnvelopes *AssetKeyEnvelopeDatastore
+	Media             *MediaDatastore
+	Tokens            *TokenDatastore
+	Orders            *OrderDatastore
+	ChainMeta         *ChainMetaDatastore
+	ChainBlocks       *ChainBlockDatastore
+	ChainBlockChanges *ChainBlockChangeDatastore
+	ChainEvents       *ChainEventDatastore
+	ChainTxs          *ChainTxDatastore
+	Activity          *ActivityDatastore
+	Auctions          *AuctionDatastore
+	AuctionBids       *AuctionBidDatastore
+	Ledger            *LedgerDatastore
+	Jobs              *JobDatastore
 }
 
 func NewDatastore(ctx context.Context, uri string) (*Datastore, error) {
@@ -54,6 +65,27 @@ func NewDatastore(ctx context.Context, uri string) (*Datastore, error) {
 
 	ds.Assets = assetsDs
 
+	assetHoldersDs, err := NewAssetHolderDatastore(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	ds.AssetHolders = assetHoldersDs
+
+	assetBatchesDs, err := NewAssetBatchDatastore(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	ds.AssetBatches = assetBatchesDs
+
+	assetKeyEnvelopesDs, err := NewAssetKeyEnvelopeDatastore(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	ds.AssetKeyEnvelopes = assetKeyEnvelopesDs
+
 	mediaDs, err := NewMediaDatastore(ctx, conn)
 	if err != nil {
 		return nil, err
@@ -82,6 +114,34 @@ func NewDatastore(ctx context.Context, uri string) (*Datastore, error) {
 
 	ds.ChainMeta = chainMetaDs
 
+	chainBlocksDs, err := NewChainBlockDatastore(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	ds.ChainBlocks = chainBlocksDs
+
+	chainBlockChangesDs, err := NewChainBlockChangeDatastore(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	ds.ChainBlockChanges = chainBlockChangesDs
+
+	chainEventsDs, err := NewChainEventDatastore(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	ds.ChainEvents = chainEventsDs
+
+	chainTxsDs, err := NewChainTxDatastore(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	ds.ChainTxs = chainTxsDs
+
 	activityDs, err := NewActivityDatastore(ctx, conn)
 	if err != nil {
 		return nil, err
@@ -89,9 +149,59 @@ func NewDatastore(ctx context.Context, uri string) (*Datastore, error) {
 
 	ds.Activity = activityDs
 
+	auctionsDs, err := NewAuctionDatastore(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	ds.Auctions = auctionsDs
+
+	auctionBidsDs, err := NewAuctionBidDatastore(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	ds.AuctionBids = auctionBidsDs
+
+	ledgerDs, err := NewLedgerDatastore(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	ds.Ledger = ledgerDs
+
+	jobsDs, err := NewJobDatastore(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	ds.Jobs = jobsDs
+
 	return ds, nil
 }
 
+// InTx runs fn in a single database transaction, datastore calls made with
+// the passed context join it. A transaction already in ctx is reused.
+func (ds *Datastore) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := dbrutil.DbTxFromContext(ctx); ok {
+		return fn(ctx)
+	}
+
+	sess := ds.conn.NewSession(nil)
+	tx, err := sess.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	err = fn(dbrutil.NewContextWithDbTx(ctx, tx))
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
 func (ds *Datastore) GetAssetsList(ctx context.Context, fltr *AssetsFilter, opts *LimitOpts) ([]*model.Asset, error) {
 	accounts, err := ds.Accounts.List(ctx, nil, nil)
 	if err != nil {
@@ -105,6 +215,18 @@ func (ds *Datastore) GetAssetsList(ctx context.Context, fltr *AssetsFilter, opts
 
 	JoinAccountsToAsset(ctx, assets, accounts)
 
+	assetIds := make([]int64, 0, len(assets))
+	for _, asset := range assets {
+		assetIds = append(assetIds, asset.ID)
+	}
+
+	auctions, err := ds.Auctions.ListLatestByAssetIds(ctx, assetIds)
+	if err != nil {
+		return nil, err
+	}
+
+	JoinAuctionsToAsset(ctx, assets, auctions)
+
 	return assets, nil
 }
 
@@ -144,6 +266,16 @@ func JoinAccountsToAsset(ctx context.Context, assets []*model.Asset, accounts []
 	}
 }
 
+func JoinAuctionsToAsset(ctx context.Context, assets []*model.Asset, auctions []*model.Auction) {
+	byAssetID := map[int64]*model.Auction{}
+	for _, item := range auctions {
+		byAssetID[item.AssetID] = item
+	}
+	for _, asset := range assets {
+		asset.Auction = byAssetID[asset.ID]
+	}
+}
+
 func JoinAccountsToOrder(ctx context.Context, orders []*model.Order, accounts []*model.Account) {
 	byID := map[int64]*model.Account{}
 	for _, item := range accounts {
@@ -286,4 +418,62 @@ func (ds *Datastore) JoinOrderToActivity(ctx context.Context, activity []*model.
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+func (ds *Datastore) JoinAssetToLedger(ctx context.Context, entries []*model.LedgerEntry) error {
+	ids := make([]int64, 0, len(entries))
+	for _, entry := range entries {
+		ids = append(ids, entry.AssetID)
+	}
+
+	byID, err := ds.getAssetsByIds(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		entry.Asset = byID[entry.AssetID]
+	}
+
+	return nil
+}
+
+func (ds *Datastore) JoinAssetToEarnings(ctx context.Context, earnings []*model.Earnings) error {
+	ids := make([]int64, 0, len(earnings))
+	for _, item := range earnings {
+		ids = append(ids, item.AssetID)
+	}
+
+	byID, err := ds.getAssetsByIds(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range earnings {
+		item.Asset = byID[item.AssetID]
+	}
+
+	return nil
+}
+
+func (ds *Datastore) getAssetsByIds(ctx context.Context, ids []int64) (map[int64]*model.Asset, error) {
+	byID := map[int64]*model.Asset{}
+	if len(ids) == 0 {
+		return byID, nil
+	}
+
+	assets, err := ds.GetAssetsList(ctx, &AssetsFilter{Ids: ids}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ds.JoinMediaToAssets(ctx, assets)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, asset := range assets {
+		byID[asset.ID] = asset
+	}
+
+	return byID, nil
+}
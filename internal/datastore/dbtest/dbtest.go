@@ -0,0 +1,95 @@
+// Package dbtest connects tests to the database in TEST_DBURI, which has to
+// be migrated with `make db-up`. Tests using it are skipped when it is
+// not set.
+package dbtest
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/videocoin/marketplace/internal/datastore"
+)
+
+// lockID serializes the tests of the packages go test runs in parallel, they
+// share the database.
+const lockID = 7130
+
+// Open empties the tables of the test database and returns a datastore
+// connected to it. The database is locked for the test until it ends.
+func Open(t *testing.T) *datastore.Datastore {
+	t.Helper()
+
+	uri := os.Getenv("TEST_DBURI")
+	if uri == "" {
+		t.Skip("TEST_DBURI is not set")
+	}
+
+	ctx := context.Background()
+
+	db, err := sql.Open("postgres", uri)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", lockID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		_, _ = conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", lockID)
+		_ = conn.Close()
+		_ = db.Close()
+	})
+
+	err = truncate(ctx, conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ds, err := datastore.NewDatastore(ctx, uri)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return ds
+}
+
+func truncate(ctx context.Context, conn *sql.Conn) error {
+	rows, err := conn.QueryContext(
+		ctx,
+		"SELECT tablename FROM pg_tables WHERE schemaname = current_schema() AND tablename <> 'goose_db_version'",
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	tables := make([]string, 0)
+	for rows.Next() {
+		var table string
+		if err := rows.Scan(&table); err != nil {
+			return err
+		}
+		tables = append(tables, fmt.Sprintf("%q", table))
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if len(tables) == 0 {
+		return nil
+	}
+
+	_, err = conn.ExecContext(ctx, "TRUNCATE "+strings.Join(tables, ", ")+" RESTART IDENTITY CASCADE")
+	return err
+}
@@ -13,6 +13,8 @@ type AssetsFilter struct {
 	OnSale      *bool
 	Sold        *bool
 	Minted      *bool
+	Network     *string
+	BatchID     *int64
 	Sort        *SortOption
 }
 
@@ -22,9 +24,10 @@ type AccountsFilter struct {
 }
 
 type TokensFilter struct {
-	Symbol  *string
-	Address *string
-	Sort    *SortOption
+	Symbol    *string
+	Address   *string
+	Addresses []string
+	Sort      *SortOption
 }
 
 type OrderFilter struct {
@@ -37,6 +40,7 @@ type OrderFilter struct {
 	MakerID              *int64
 	TakerID              *int64
 	IsArchive            *bool
+	Network              *string
 	Sort                 *SortOption
 }
 
@@ -0,0 +1,394 @@
+package datastore
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/AlekSi/pointer"
+	"github.com/gocraft/dbr/v2"
+	"github.com/videocoin/marketplace/internal/model"
+	"github.com/videocoin/marketplace/pkg/dbrutil"
+)
+
+var (
+	ErrJobNotFound     = errors.New("job not found")
+	ErrJobLeaseExpired = errors.New("job lease expired on the last attempt")
+)
+
+type JobDatastore struct {
+	conn  *dbr.Connection
+	table string
+}
+
+func NewJobDatastore(ctx context.Context, conn *dbr.Connection) (*JobDatastore, error) {
+	return &JobDatastore{
+		conn:  conn,
+		table: "jobs",
+	}, nil
+}
+
+func (ds *JobDatastore) Create(ctx context.Context, job *model.Job) error {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	if job.ID == "" {
+		job.ID = model.GenJobID()
+	}
+
+	now := time.Now()
+	if job.CreatedAt == nil || job.CreatedAt.IsZero() {
+		job.CreatedAt = pointer.ToTime(now)
+	}
+	job.UpdatedAt = pointer.ToTime(now)
+
+	if job.RunAt == nil || job.RunAt.IsZero() {
+		job.RunAt = pointer.ToTime(now)
+	}
+
+	if job.Status == "" {
+		job.Status = model.JobStatusPending
+	}
+
+	if job.MaxAttempts == 0 {
+		job.MaxAttempts = model.DefaultJobMaxAttempts
+	}
+
+	cols := []string{
+		"id", "created_at", "updated_at", "created_by_id", "type", "status",
+		"payload", "checkpoints", "max_attempts", "run_at",
+	}
+	err = tx.
+		InsertInto(ds.table).
+		Columns(cols...).
+		Record(job).
+		Returning("id").
+		LoadContext(ctx, job)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (ds *JobDatastore) GetByID(ctx context.Context, id string) (*model.Job, error) {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return nil, err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	job := new(model.Job)
+	err = tx.
+		Select("*").
+		From(ds.table).
+		Where("id = ?", id).
+		LoadOneContext(ctx, job)
+	if err != nil {
+		if err == dbr.ErrNotFound {
+			return nil, ErrJobNotFound
+		}
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// Lease picks up the next job which is due to run or whose lease has expired
+// and locks it for the worker until the lease ends. Expired jobs without
+// attempts left are not picked up, see BuryExpired.
+func (ds *JobDatastore) Lease(ctx context.Context, workerID string, types []model.JobType, lease time.Duration) (*model.Job, error) {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return nil, err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	if len(types) == 0 {
+		return nil, ErrJobNotFound
+	}
+
+	now := time.Now()
+	query := `UPDATE jobs SET status = ?, locked_by = ?, locked_until = ?, attempts = attempts + 1, updated_at = ?
+		WHERE id = (
+			SELECT id FROM jobs
+			WHERE type IN ? AND ((status = ? AND run_at <= ?) OR (status = ? AND locked_until < ? AND attempts < max_attempts))
+			ORDER BY run_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`
+
+	job := new(model.Job)
+	err = tx.
+		SelectBySql(
+			query,
+			model.JobStatusRunning, workerID, now.Add(lease), now,
+			types, model.JobStatusPending, now, model.JobStatusRunning, now,
+		).
+		LoadOneContext(ctx, job)
+	if err != nil {
+		if err == dbr.ErrNotFound {
+			return nil, ErrJobNotFound
+		}
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// BuryExpired moves the jobs whose lease has expired on their last attempt
+// to the dead-letter state and returns them. Their worker died without
+// marking them as failed.
+func (ds *JobDatastore) BuryExpired(ctx context.Context, types []model.JobType) ([]*model.Job, error) {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return nil, err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	jobs := make([]*model.Job, 0)
+	if len(types) == 0 {
+		return jobs, nil
+	}
+
+	now := time.Now()
+	query := `UPDATE jobs SET status = ?, locked_by = NULL, locked_until = NULL, last_error = ?, updated_at = ?
+		WHERE type IN ? AND status = ? AND locked_until < ? AND attempts >= max_attempts
+		RETURNING *`
+
+	_, err = tx.
+		SelectBySql(
+			query,
+			model.JobStatusDead, ErrJobLeaseExpired.Error(), now,
+			types, model.JobStatusRunning, now,
+		).
+		LoadContext(ctx, &jobs)
+	if err != nil {
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+func (ds *JobDatastore) ExtendLease(ctx context.Context, job *model.Job, lease time.Duration) error {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	lockedUntil := time.Now().Add(lease)
+	_, err = tx.
+		Update(ds.table).
+		Set("locked_until", lockedUntil).
+		Set("updated_at", time.Now()).
+		Where("id = ? AND locked_by = ?", job.ID, job.LockedBy.String).
+		ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	job.LockedUntil = pointer.ToTime(lockedUntil)
+
+	return nil
+}
+
+func (ds *JobDatastore) SaveCheckpoint(ctx context.Context, job *model.Job, step string) error {
+	if job.IsCheckpointPassed(step) {
+		return nil
+	}
+
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	checkpoints := append(model.JobCheckpoints{}, job.Checkpoints...)
+	checkpoints = append(checkpoints, step)
+
+	_, err = tx.
+		Update(ds.table).
+		Set("checkpoints", checkpoints).
+		Set("updated_at", time.Now()).
+		Where("id = ?", job.ID).
+		ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	job.Checkpoints = checkpoints
+
+	return nil
+}
+
+func (ds *JobDatastore) MarkStatusAsSucceeded(ctx context.Context, job *model.Job) error {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	_, err = tx.
+		Update(ds.table).
+		Set("status", model.JobStatusSucceeded).
+		Set("locked_by", nil).
+		Set("locked_until", nil).
+		Set("last_error", nil).
+		Set("updated_at", time.Now()).
+		Where("id = ?", job.ID).
+		ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	job.Status = model.JobStatusSucceeded
+	job.LockedBy = dbr.NullString{}
+	job.LockedUntil = nil
+	job.LastError = dbr.NullString{}
+
+	return nil
+}
+
+// MarkStatusAsFailed schedules the job for another attempt with backoff or
+// moves it to the dead-letter state once all attempts have been used.
+func (ds *JobDatastore) MarkStatusAsFailed(ctx context.Context, job *model.Job, jobErr error) error {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	status := model.JobStatusPending
+	if job.IsLastAttempt() {
+		status = model.JobStatusDead
+	}
+	runAt := time.Now().Add(job.Backoff())
+
+	_, err = tx.
+		Update(ds.table).
+		Set("status", status).
+		Set("run_at", runAt).
+		Set("locked_by", nil).
+		Set("locked_until", nil).
+		Set("last_error", jobErr.Error()).
+		Set("updated_at", time.Now()).
+		Where("id = ?", job.ID).
+		ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	job.Status = status
+	job.RunAt = pointer.ToTime(runAt)
+	job.LockedBy = dbr.NullString{}
+	job.LockedUntil = nil
+	job.LastError = dbr.NewNullString(jobErr.Error())
+
+	return nil
+}
+
+func (ds *JobDatastore) CountUnfinished(ctx context.Context) (int64, error) {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return 0, err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	count := int64(0)
+	err = tx.
+		Select("COUNT(id)").
+		From(ds.table).
+		Where("status IN ?", []model.JobStatus{model.JobStatusPending, model.JobStatusRunning}).
+		LoadOneContext(ctx, &count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
@@ -0,0 +1,274 @@
+package datastore
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/AlekSi/pointer"
+	"github.com/gocraft/dbr/v2"
+	"github.com/videocoin/marketplace/internal/model"
+	"github.com/videocoin/marketplace/pkg/dbrutil"
+)
+
+var (
+	ErrAssetKeyEnvelopeNotFound = errors.New("asset key envelope not found")
+)
+
+type AssetKeyEnvelopeDatastore struct {
+	conn  *dbr.Connection
+	table string
+}
+
+func NewAssetKeyEnvelopeDatastore(ctx context.Context, conn *dbr.Connection) (*AssetKeyEnvelopeDatastore, error) {
+	return &AssetKeyEnvelopeDatastore{
+		conn:  conn,
+		table: "asset_key_envelopes",
+	}, nil
+}
+
+// Put stores the envelope, replacing the one the account already has with
+// the same role.
+func (ds *AssetKeyEnvelopeDatastore) Put(ctx context.Context, envelope *model.AssetKeyEnvelope) error {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	if envelope.CreatedAt == nil || envelope.CreatedAt.IsZero() {
+		envelope.CreatedAt = pointer.ToTime(time.Now())
+	}
+
+	query := `INSERT INTO asset_key_envelopes (asset_id, account_id, role, envelope, granted_by_id, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (asset_id, account_id, role) DO UPDATE
+		SET envelope = EXCLUDED.envelope, granted_by_id = EXCLUDED.granted_by_id, created_at = EXCLUDED.created_at`
+	_, err = tx.InsertBySql(
+		query,
+		envelope.AssetID,
+		envelope.AccountID,
+		envelope.Role,
+		envelope.Envelope,
+		envelope.GrantedByID,
+		envelope.CreatedAt,
+	).ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (ds *AssetKeyEnvelopeDatastore) Get(ctx context.Context, assetID, accountID int64, role model.KeyEnvelopeRole) (*model.AssetKeyEnvelope, error) {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return nil, err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	envelope := new(model.AssetKeyEnvelope)
+	err = tx.
+		Select("*").
+		From(ds.table).
+		Where("asset_id = ? AND account_id = ? AND role = ?", assetID, accountID, role).
+		LoadOneContext(ctx, envelope)
+	if err != nil {
+		if err == dbr.ErrNotFound {
+			return nil, ErrAssetKeyEnvelopeNotFound
+		}
+		return nil, err
+	}
+
+	return envelope, nil
+}
+
+func (ds *AssetKeyEnvelopeDatastore) ListByAssetID(ctx context.Context, assetID int64) ([]*model.AssetKeyEnvelope, error) {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return nil, err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	envelopes := make([]*model.AssetKeyEnvelope, 0)
+	_, err = tx.
+		Select("*").
+		From(ds.table).
+		Where("asset_id = ?", assetID).
+		OrderAsc("created_at").
+		LoadContext(ctx, &envelopes)
+	if err != nil {
+		return nil, err
+	}
+
+	return envelopes, nil
+}
+
+// CountGrantedBy returns the number of delegates the account has authorized
+// for the asset.
+func (ds *AssetKeyEnvelopeDatastore) CountGrantedBy(ctx context.Context, assetID, grantedByID int64) (int64, error) {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return 0, err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	var count int64
+	err = tx.
+		Select("COUNT(*)").
+		From(ds.table).
+		Where("asset_id = ? AND role = ? AND granted_by_id = ?", assetID, model.KeyEnvelopeRoleDelegate, grantedByID).
+		LoadOneContext(ctx, &count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// DeleteGrantedBy revokes the delegates the account has authorized for the
+// asset, it returns how many there were.
+func (ds *AssetKeyEnvelopeDatastore) DeleteGrantedBy(ctx context.Context, assetID, grantedByID int64) (int64, error) {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return 0, err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	res, err := tx.
+		DeleteFrom(ds.table).
+		Where("asset_id = ? AND role = ? AND granted_by_id = ?", assetID, model.KeyEnvelopeRoleDelegate, grantedByID).
+		ExecContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
+
+// Delete revokes the envelope, it reports whether there was one.
+func (ds *AssetKeyEnvelopeDatastore) Delete(ctx context.Context, assetID, accountID int64, role model.KeyEnvelopeRole) (bool, error) {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return false, err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	res, err := tx.
+		DeleteFrom(ds.table).
+		Where("asset_id = ? AND account_id = ? AND role = ?", assetID, accountID, role).
+		ExecContext(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+// Replace overwrites the envelopes of the asset, it is used once the
+// content key has changed and to restore an asset snapshot.
+func (ds *AssetKeyEnvelopeDatastore) Replace(ctx context.Context, assetID int64, envelopes []*model.AssetKeyEnvelope) error {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	_, err = tx.
+		DeleteFrom(ds.table).
+		Where("asset_id = ?", assetID).
+		ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, envelope := range envelopes {
+		createdAt := envelope.CreatedAt
+		if createdAt == nil {
+			createdAt = pointer.ToTime(time.Now())
+		}
+
+		_, err = tx.
+			InsertInto(ds.table).
+			Pair("asset_id", assetID).
+			Pair("account_id", envelope.AccountID).
+			Pair("role", envelope.Role).
+			Pair("envelope", envelope.Envelope).
+			Pair("granted_by_id", envelope.GrantedByID).
+			Pair("created_at", createdAt).
+			ExecContext(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
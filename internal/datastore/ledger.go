@@ -0,0 +1,310 @@
+package datastore
+
+import (
+	"context"
+	"time"
+
+	"github.com/AlekSi/pointer"
+	"github.com/gocraft/dbr/v2"
+	"github.com/videocoin/marketplace/internal/model"
+	"github.com/videocoin/marketplace/pkg/dbrutil"
+)
+
+type LedgerDatastore struct {
+	conn  *dbr.Connection
+	table string
+}
+
+type LedgerFilter struct {
+	// AccountID matches the entries of sales the account earned on, as the
+	// seller or as the creator of the asset.
+	AccountID *int64
+	AssetID   *int64
+	Sort      *SortOption
+}
+
+func NewLedgerDatastore(ctx context.Context, conn *dbr.Connection) (*LedgerDatastore, error) {
+	return &LedgerDatastore{
+		conn:  conn,
+		table: "ledger_entries",
+	}, nil
+}
+
+// Create records the entry unless the sale has already been recorded for
+// the same log, events can be seen more than once.
+func (ds *LedgerDatastore) Create(ctx context.Context, entry *model.LedgerEntry) error {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	if entry.CreatedAt == nil || entry.CreatedAt.IsZero() {
+		entry.CreatedAt = pointer.ToTime(time.Now())
+	}
+
+	query := `INSERT INTO ledger_entries (
+			created_at, asset_id, order_id, seller_id, buyer_id, creator_id,
+			payment_token_address, quantity, price, royalty_bps, royalty,
+			platform_fee, protocol_fee, seller_proceeds, block_hash, tx_hash, log_index
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (tx_hash, log_index) DO NOTHING`
+	_, err = tx.InsertBySql(
+		query,
+		entry.CreatedAt,
+		entry.AssetID,
+		entry.OrderID,
+		entry.SellerID,
+		entry.BuyerID,
+		entry.CreatorID,
+		entry.PaymentTokenAddress,
+		entry.Quantity,
+		entry.Price,
+		entry.RoyaltyBps,
+		entry.Royalty,
+		entry.PlatformFee,
+		entry.ProtocolFee,
+		entry.SellerProceeds,
+		entry.BlockHash,
+		entry.TxHash,
+		entry.LogIndex,
+	).ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (ds *LedgerDatastore) List(ctx context.Context, fltr *LedgerFilter, limit *LimitOpts) ([]*model.LedgerEntry, error) {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return nil, err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	entries := make([]*model.LedgerEntry, 0)
+
+	selectStmt := tx.Select("*").From(ds.table)
+	applyLedgerFilter(selectStmt, fltr)
+	if fltr != nil && fltr.Sort != nil && fltr.Sort.Field != "" {
+		selectStmt = selectStmt.OrderDir(fltr.Sort.Field, fltr.Sort.IsAsc)
+	}
+
+	if limit != nil {
+		if limit.Offset != nil {
+			selectStmt = selectStmt.Offset(*limit.Offset)
+		}
+		if limit.Limit != nil && *limit.Limit != 0 {
+			selectStmt = selectStmt.Limit(*limit.Limit)
+		}
+	}
+
+	_, err = selectStmt.LoadContext(ctx, &entries)
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (ds *LedgerDatastore) Count(ctx context.Context, fltr *LedgerFilter) (int64, error) {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return 0, err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	count := int64(0)
+
+	selectStmt := tx.Select("COUNT(id)").From(ds.table)
+	applyLedgerFilter(selectStmt, fltr)
+
+	err = selectStmt.LoadOneContext(ctx, &count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// ListEarnings sums up the royalties and the sale proceeds of the account
+// per asset and payment token, the most recently sold assets first.
+func (ds *LedgerDatastore) ListEarnings(ctx context.Context, accountID int64, limit *LimitOpts) ([]*model.Earnings, error) {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return nil, err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	earnings := make([]*model.Earnings, 0)
+
+	offset, count := uint64(0), DefaultLimit
+	if limit != nil {
+		if limit.Offset != nil {
+			offset = *limit.Offset
+		}
+		if limit.Limit != nil && *limit.Limit != 0 {
+			count = *limit.Limit
+		}
+	}
+
+	query := `SELECT
+			asset_id,
+			payment_token_address,
+			COUNT(id) AS sales,
+			COALESCE(SUM(royalty) FILTER (WHERE creator_id = ?), 0) AS royalties,
+			COALESCE(SUM(seller_proceeds) FILTER (WHERE seller_id = ?), 0) AS proceeds
+		FROM ledger_entries
+		WHERE seller_id = ? OR creator_id = ?
+		GROUP BY asset_id, payment_token_address
+		ORDER BY MAX(created_at) DESC
+		LIMIT ? OFFSET ?`
+	selectStmt := tx.SelectBySql(query, accountID, accountID, accountID, accountID, count, offset)
+
+	_, err = selectStmt.LoadContext(ctx, &earnings)
+	if err != nil {
+		return nil, err
+	}
+
+	return earnings, nil
+}
+
+func (ds *LedgerDatastore) CountEarnings(ctx context.Context, accountID int64) (int64, error) {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return 0, err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	count := int64(0)
+
+	query := `SELECT COUNT(*) FROM (
+			SELECT asset_id FROM ledger_entries
+			WHERE seller_id = ? OR creator_id = ?
+			GROUP BY asset_id, payment_token_address
+		) AS earnings`
+	err = tx.SelectBySql(query, accountID, accountID).LoadOneContext(ctx, &count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// HasFill reports whether the sale of the log has already been recorded.
+func (ds *LedgerDatastore) HasFill(ctx context.Context, txHash string, logIndex uint) (bool, error) {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return false, err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	count := int64(0)
+	err = tx.
+		Select("COUNT(id)").
+		From(ds.table).
+		Where("tx_hash = ? AND log_index = ?", txHash, logIndex).
+		LoadOneContext(ctx, &count)
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// DeleteByBlock removes the entries an order got in an orphaned block.
+func (ds *LedgerDatastore) DeleteByBlock(ctx context.Context, orderID int64, blockHash string) error {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	_, err = tx.
+		DeleteFrom(ds.table).
+		Where("order_id = ? AND block_hash = ?", orderID, blockHash).
+		ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func applyLedgerFilter(stmt *dbr.SelectStmt, fltr *LedgerFilter) {
+	if fltr == nil {
+		return
+	}
+
+	if fltr.AccountID != nil {
+		stmt.Where("seller_id = ? OR creator_id = ?", *fltr.AccountID, *fltr.AccountID)
+	}
+
+	if fltr.AssetID != nil {
+		stmt.Where("asset_id = ?", *fltr.AssetID)
+	}
+}
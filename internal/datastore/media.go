@@ -16,13 +16,21 @@ var (
 )
 
 type MediaUpdatedFields struct {
-	CID          *string
-	ThumbnailCID *string
-	EncryptedCID *string
-	EncryptedKey *string
-	Status       *string
-	AssetID      *int64
-	Featured     *bool
+	CID                *string
+	SourceCID          *string
+	ThumbnailCID       *string
+	EncryptedCID       *string
+	EncryptedKey       *string
+	EncryptedHLSKey    *string
+	EncryptedHLSAESKey *string
+	FileEncryption     *string
+	PreviewKey         *string
+	PreviewCID         *string
+	Status             *string
+	AssetID            *int64
+	Featured           *bool
+	JobID              *string
+	Renditions         model.MediaRenditions
 }
 
 type MediaDatastore struct {
@@ -63,8 +71,8 @@ func (ds *MediaDatastore) Create(ctx context.Context, media *model.Media) error
 
 	cols := []string{
 		"id", "name", "created_at", "created_by_id", "content_type", "media_type", "status",
-		"featured", "cache_root_key", "root_key", "key", "thumbnail_key", "encrypted_key",
-		"duration", "size",
+		"featured", "cache_root_key", "root_key", "key", "thumbnail_key", "encrypted_key", "preview_key",
+		"duration", "size", "source_content_type", "source_key", "cid", "source_cid",
 	}
 	err = tx.
 		InsertInto(ds.table).
@@ -192,6 +200,11 @@ func (ds *MediaDatastore) Update(ctx context.Context, media *model.Media, fields
 		media.CID = dbr.NewNullString(*fields.CID)
 	}
 
+	if fields.SourceCID != nil {
+		stmt.Set("source_cid", *fields.SourceCID)
+		media.SourceCID = dbr.NewNullString(*fields.SourceCID)
+	}
+
 	if fields.ThumbnailCID != nil {
 		stmt.Set("thumbnail_cid", *fields.ThumbnailCID)
 		media.ThumbnailCID = dbr.NewNullString(*fields.ThumbnailCID)
@@ -207,6 +220,36 @@ func (ds *MediaDatastore) Update(ctx context.Context, media *model.Media, fields
 		media.EncryptedKey = *fields.EncryptedKey
 	}
 
+	if fields.FileEncryption != nil {
+		stmt.Set("file_encryption", *fields.FileEncryption)
+		media.FileEncryption = *fields.FileEncryption
+	}
+
+	if fields.EncryptedHLSKey != nil {
+		stmt.Set("encrypted_hls_key", *fields.EncryptedHLSKey)
+		media.EncryptedHLSKey = *fields.EncryptedHLSKey
+	}
+
+	if fields.EncryptedHLSAESKey != nil {
+		stmt.Set("encrypted_hls_aes_key", *fields.EncryptedHLSAESKey)
+		media.EncryptedHLSAESKey = *fields.EncryptedHLSAESKey
+	}
+
+	if fields.Renditions != nil {
+		stmt.Set("renditions", fields.Renditions)
+		media.Renditions = fields.Renditions
+	}
+
+	if fields.PreviewKey != nil {
+		stmt.Set("preview_key", *fields.PreviewKey)
+		media.PreviewKey = *fields.PreviewKey
+	}
+
+	if fields.PreviewCID != nil {
+		stmt.Set("preview_cid", *fields.PreviewCID)
+		media.PreviewCID = dbr.NewNullString(*fields.PreviewCID)
+	}
+
 	if fields.Status != nil {
 		stmt.Set("status", *fields.Status)
 		media.Status = model.MediaStatus(*fields.Status)
@@ -222,6 +265,11 @@ func (ds *MediaDatastore) Update(ctx context.Context, media *model.Media, fields
 		media.Featured = *fields.Featured
 	}
 
+	if fields.JobID != nil {
+		stmt.Set("job_id", *fields.JobID)
+		media.JobID = dbr.NewNullString(*fields.JobID)
+	}
+
 	_, err = stmt.Where("id = ?", media.ID).ExecContext(ctx)
 	if err != nil {
 		return err
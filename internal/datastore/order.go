@@ -7,6 +7,8 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/gocraft/dbr/v2"
 	"github.com/videocoin/marketplace/internal/model"
+	"github.com/videocoin/marketplace/internal/network"
+	"github.com/videocoin/marketplace/internal/wyvern"
 	"github.com/videocoin/marketplace/pkg/dbrutil"
 	"github.com/videocoin/marketplace/pkg/ethutil"
 	"strconv"
@@ -54,10 +56,17 @@ func (ds *OrderDatastore) Create(ctx context.Context, order *model.Order) error
 	order.SaleKind = order.WyvernOrder.SaleKind
 	order.PaymentTokenAddress = strings.ToLower(order.WyvernOrder.PaymentToken)
 	order.CreatedDate = pointer.ToTime(time.Now())
+	if order.Quantity <= 0 {
+		order.Quantity = 1
+	}
+	if order.Network == "" {
+		order.Network = network.DefaultName
+	}
 
 	cols := []string{
 		"created_by_id", "hash", "sign_hash", "asset_contract_address", "token_id", "side", "sale_kind",
 		"payment_token_address", "maker_id", "taker_id", "created_date", "wyvern_order",
+		"quantity", "network",
 	}
 	err = tx.
 		InsertInto(ds.table).
@@ -251,6 +260,36 @@ func (ds *OrderDatastore) MarkStatusAsProcessed(ctx context.Context, order *mode
 	return ds.MarkStatusAs(ctx, order, model.OrderStatusProcessed)
 }
 
+func (ds *OrderDatastore) UpdateFilledQuantity(ctx context.Context, order *model.Order, filledQuantity int64) error {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	_, err = tx.
+		Update(ds.table).
+		Set("filled_quantity", filledQuantity).
+		Where("id = ?", order.ID).
+		ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	order.FilledQuantity = filledQuantity
+
+	return nil
+}
+
 func applyOrderFilters(stmt *dbr.SelectStmt, fltr *OrderFilter, applySort bool) {
 	if fltr == nil {
 		return
@@ -265,6 +304,9 @@ func applyOrderFilters(stmt *dbr.SelectStmt, fltr *OrderFilter, applySort bool)
 	if fltr.PaymentTokenAddress != nil {
 		stmt = stmt.Where("payment_token_address = ?", *fltr.PaymentTokenAddress)
 	}
+	if fltr.Network != nil {
+		stmt = stmt.Where("network = ?", *fltr.Network)
+	}
 	if fltr.AssetContractAddress != nil {
 		stmt = stmt.Where("asset_contract_address = ?", *fltr.AssetContractAddress)
 	}
@@ -317,4 +359,130 @@ func (ds *OrderDatastore) ArchiveByTokenID(ctx context.Context, tokenID int64) e
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+func (ds *OrderDatastore) ArchiveByIds(ctx context.Context, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	_, err = tx.
+		Update(ds.table).
+		Set("is_archive", true).
+		Where("id IN ?", ids).
+		ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (ds *OrderDatastore) UnarchiveByIds(ctx context.Context, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	_, err = tx.
+		Update(ds.table).
+		Set("is_archive", false).
+		Where("id IN ?", ids).
+		ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (ds *OrderDatastore) UpdateWyvernOrder(ctx context.Context, order *model.Order, wyvernOrder *wyvern.Order) error {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	_, err = tx.
+		Update(ds.table).
+		Set("side", wyvernOrder.Side).
+		Set("sale_kind", wyvernOrder.SaleKind).
+		Set("payment_token_address", strings.ToLower(wyvernOrder.PaymentToken)).
+		Set("wyvern_order", wyvernOrder).
+		Where("id = ?", order.ID).
+		ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	order.Side = wyvernOrder.Side
+	order.SaleKind = wyvernOrder.SaleKind
+	order.PaymentTokenAddress = strings.ToLower(wyvernOrder.PaymentToken)
+	order.WyvernOrder = wyvernOrder
+
+	return nil
+}
+
+func (ds *OrderDatastore) Delete(ctx context.Context, order *model.Order) error {
+	var err error
+	tx, ok := dbrutil.DbTxFromContext(ctx)
+	if !ok {
+		sess := ds.conn.NewSession(nil)
+		tx, err = sess.Begin()
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			err = tx.Commit()
+			tx.RollbackUnlessCommitted()
+		}()
+	}
+
+	_, err = tx.
+		DeleteFrom(ds.table).
+		Where("id = ?", order.ID).
+		ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
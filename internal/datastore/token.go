@@ -50,6 +50,9 @@ func (ds *TokenDatastore) List(ctx context.Context, fltr *TokensFilter, limit *L
 		if fltr.Address != nil {
 			selectStmt = selectStmt.Where("address = ?", *fltr.Address)
 		}
+		if fltr.Addresses != nil {
+			selectStmt = selectStmt.Where("address IN ?", fltr.Addresses)
+		}
 	}
 
 	if limit != nil {
@@ -92,6 +95,9 @@ func (ds *TokenDatastore) Count(ctx context.Context, fltr *TokensFilter) (int64,
 		if fltr.Symbol != nil {
 			selectStmt = selectStmt.Where("symbol = ?", *fltr.Symbol)
 		}
+		if fltr.Addresses != nil {
+			selectStmt = selectStmt.Where("address IN ?", fltr.Addresses)
+		}
 	}
 
 	err = selectStmt.LoadOneContext(ctx, &count)
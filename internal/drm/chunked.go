@@ -0,0 +1,314 @@
+package drm
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"io"
+)
+
+// Files that cannot be packaged for playback are encrypted chunk by chunk
+// with AES-GCM. The file starts with a header:
+//
+//	magic "MPCE" | version (1 byte) | chunk size (uint32 BE) | nonce prefix (7 bytes)
+//
+// followed by the chunks, every chunk but the last holds ChunkSize bytes of
+// plaintext and is followed by its tag. The nonce of a chunk is the prefix,
+// its index (uint32 BE) and a byte set to 1 for the last chunk only, so
+// chunks cannot be reordered and the file cannot be truncated. The header
+// is the additional data of every chunk.
+//
+// A byte range is decrypted from the chunks that hold it, see CipherRange.
+const (
+	// FileEncryptionOpenSSL is the base64 AES-CBC blob files used to be
+	// encrypted to, it has to be decoded whole before it can be read
+	FileEncryptionOpenSSL   = "aes-128-cbc-base64"
+	FileEncryptionChunkedV1 = "aes-gcm-chunked-v1"
+
+	CurrentFileEncryption = FileEncryptionChunkedV1
+
+	DefaultChunkSize = 64 * 1024
+
+	chunkedVersion1     = 1
+	chunkedMagic        = "MPCE"
+	chunkedNoncePrefix  = 7
+	ChunkedHeaderSize   = len(chunkedMagic) + 1 + 4 + chunkedNoncePrefix
+	ChunkedTagSize      = 16
+	maxChunkSize        = 16 * 1024 * 1024
+	chunkedLastChunkTag = 1
+)
+
+var (
+	ErrInvalidChunkedHeader = errors.New("invalid chunked encryption header")
+	ErrChunkedTruncated     = errors.New("chunked encryption stream is truncated")
+)
+
+// ChunkedHeader is the header of a file encrypted with the chunked format.
+type ChunkedHeader struct {
+	Version     byte
+	ChunkSize   int
+	NoncePrefix [chunkedNoncePrefix]byte
+}
+
+func newChunkedHeader(chunkSize int) (*ChunkedHeader, error) {
+	h := &ChunkedHeader{
+		Version:   chunkedVersion1,
+		ChunkSize: chunkSize,
+	}
+	if _, err := rand.Read(h.NoncePrefix[:]); err != nil {
+		return nil, err
+	}
+
+	return h, nil
+}
+
+func (h *ChunkedHeader) Bytes() []byte {
+	b := make([]byte, 0, ChunkedHeaderSize)
+	b = append(b, chunkedMagic...)
+	b = append(b, h.Version)
+	b = append(b, 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(b[len(chunkedMagic)+1:], uint32(h.ChunkSize))
+	b = append(b, h.NoncePrefix[:]...)
+	return b
+}
+
+// ParseChunkedHeader parses the header at the start of an encrypted file.
+func ParseChunkedHeader(b []byte) (*ChunkedHeader, error) {
+	if len(b) < ChunkedHeaderSize || string(b[:len(chunkedMagic)]) != chunkedMagic {
+		return nil, ErrInvalidChunkedHeader
+	}
+
+	h := &ChunkedHeader{
+		Version:   b[len(chunkedMagic)],
+		ChunkSize: int(binary.BigEndian.Uint32(b[len(chunkedMagic)+1:])),
+	}
+	if h.Version != chunkedVersion1 || h.ChunkSize <= 0 || h.ChunkSize > maxChunkSize {
+		return nil, ErrInvalidChunkedHeader
+	}
+	copy(h.NoncePrefix[:], b[len(chunkedMagic)+5:ChunkedHeaderSize])
+
+	return h, nil
+}
+
+func (h *ChunkedHeader) nonce(index uint32, last bool) []byte {
+	nonce := make([]byte, 12)
+	copy(nonce, h.NoncePrefix[:])
+	binary.BigEndian.PutUint32(nonce[chunkedNoncePrefix:], index)
+	if last {
+		nonce[11] = chunkedLastChunkTag
+	}
+	return nonce
+}
+
+// ChunkCount returns the number of chunks of an encrypted file of the size.
+func (h *ChunkedHeader) ChunkCount(cipherSize int64) int64 {
+	sealed := int64(h.ChunkSize + ChunkedTagSize)
+	body := cipherSize - int64(ChunkedHeaderSize)
+	if body <= 0 {
+		return 0
+	}
+
+	return (body + sealed - 1) / sealed
+}
+
+// CipherRange returns the first chunk holding the plaintext range [offset,
+// offset+length) and the range of the encrypted file its chunks are stored
+// in, end is past the end of the file when the range reaches the last chunk.
+func (h *ChunkedHeader) CipherRange(offset, length int64) (firstChunk int64, start int64, end int64) {
+	sealed := int64(h.ChunkSize + ChunkedTagSize)
+	firstChunk = offset / int64(h.ChunkSize)
+	lastChunk := (offset + length - 1) / int64(h.ChunkSize)
+	if length <= 0 {
+		lastChunk = firstChunk
+	}
+
+	start = int64(ChunkedHeaderSize) + firstChunk*sealed
+	end = int64(ChunkedHeaderSize) + (lastChunk+1)*sealed
+
+	return firstChunk, start, end
+}
+
+func newGCM(meta *Metadata) (cipher.AEAD, error) {
+	key, err := hex.DecodeString(meta.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// DecryptChunk opens a single chunk, last must be set for the final chunk of
+// the file.
+func DecryptChunk(meta *Metadata, h *ChunkedHeader, index int64, last bool, sealed []byte) ([]byte, error) {
+	aead, err := newGCM(meta)
+	if err != nil {
+		return nil, err
+	}
+
+	return aead.Open(nil, h.nonce(uint32(index), last), sealed, h.Bytes())
+}
+
+type chunkedEncryptReader struct {
+	src    *bufio.Reader
+	aead   cipher.AEAD
+	header *ChunkedHeader
+	ad     []byte
+	plain  []byte
+	index  uint32
+	out    bytes.Buffer
+	done   bool
+}
+
+// NewChunkedEncryptReader encrypts src as it is read, a chunk at a time.
+func NewChunkedEncryptReader(src io.Reader, meta *Metadata) (io.Reader, error) {
+	aead, err := newGCM(meta)
+	if err != nil {
+		return nil, err
+	}
+
+	header, err := newChunkedHeader(DefaultChunkSize)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &chunkedEncryptReader{
+		src:    bufio.NewReaderSize(src, DefaultChunkSize),
+		aead:   aead,
+		header: header,
+		ad:     header.Bytes(),
+		plain:  make([]byte, DefaultChunkSize),
+	}
+	r.out.Write(r.ad)
+
+	return r, nil
+}
+
+func (r *chunkedEncryptReader) Read(p []byte) (int, error) {
+	for r.out.Len() == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+
+		if err := r.sealChunk(); err != nil {
+			return 0, err
+		}
+	}
+
+	return r.out.Read(p)
+}
+
+func (r *chunkedEncryptReader) sealChunk() error {
+	n, err := io.ReadFull(r.src, r.plain)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return err
+	}
+
+	// the chunk is the last one when nothing follows it
+	last := err != nil
+	if !last {
+		if _, perr := r.src.Peek(1); perr == io.EOF {
+			last = true
+		} else if perr != nil {
+			return perr
+		}
+	}
+
+	sealed := r.aead.Seal(nil, r.header.nonce(r.index, last), r.plain[:n], r.ad)
+	r.out.Write(sealed)
+	r.index++
+	r.done = last
+
+	return nil
+}
+
+type chunkedDecryptReader struct {
+	src    *bufio.Reader
+	aead   cipher.AEAD
+	header *ChunkedHeader
+	ad     []byte
+	sealed []byte
+	index  uint32
+	out    bytes.Buffer
+	done   bool
+}
+
+// NewChunkedDecryptReader decrypts a whole file encrypted with the chunked
+// format.
+func NewChunkedDecryptReader(src io.Reader, meta *Metadata) (io.Reader, error) {
+	aead, err := newGCM(meta)
+	if err != nil {
+		return nil, err
+	}
+
+	ad := make([]byte, ChunkedHeaderSize)
+	if _, err := io.ReadFull(src, ad); err != nil {
+		return nil, ErrInvalidChunkedHeader
+	}
+
+	header, err := ParseChunkedHeader(ad)
+	if err != nil {
+		return nil, err
+	}
+
+	return &chunkedDecryptReader{
+		src:    bufio.NewReaderSize(src, header.ChunkSize+ChunkedTagSize),
+		aead:   aead,
+		header: header,
+		ad:     ad,
+		sealed: make([]byte, header.ChunkSize+ChunkedTagSize),
+	}, nil
+}
+
+func (r *chunkedDecryptReader) Read(p []byte) (int, error) {
+	for r.out.Len() == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+
+		if err := r.openChunk(); err != nil {
+			return 0, err
+		}
+	}
+
+	return r.out.Read(p)
+}
+
+func (r *chunkedDecryptReader) openChunk() error {
+	n, err := io.ReadFull(r.src, r.sealed)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return err
+	}
+	if n < ChunkedTagSize {
+		return ErrChunkedTruncated
+	}
+
+	last := err != nil
+	if !last {
+		if _, perr := r.src.Peek(1); perr == io.EOF {
+			last = true
+		} else if perr != nil {
+			return perr
+		}
+	}
+
+	plain, err := r.aead.Open(nil, r.header.nonce(r.index, last), r.sealed[:n], r.ad)
+	if err != nil {
+		return err
+	}
+
+	r.out.Write(plain)
+	r.index++
+	r.done = last
+
+	return nil
+}
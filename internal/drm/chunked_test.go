@@ -0,0 +1,211 @@
+package drm
+
+import (
+	"bytes"
+	"crypto/rand"
+	"io/ioutil"
+	"testing"
+)
+
+func encryptChunked(t *testing.T, meta *Metadata, plain []byte) []byte {
+	t.Helper()
+
+	r, err := NewChunkedEncryptReader(bytes.NewReader(plain), meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return sealed
+}
+
+func decryptChunked(meta *Metadata, sealed []byte) ([]byte, error) {
+	r, err := NewChunkedDecryptReader(bytes.NewReader(sealed), meta)
+	if err != nil {
+		return nil, err
+	}
+
+	return ioutil.ReadAll(r)
+}
+
+func randomPlain(t *testing.T, size int) []byte {
+	t.Helper()
+
+	plain := make([]byte, size)
+	if _, err := rand.Read(plain); err != nil {
+		t.Fatal(err)
+	}
+
+	return plain
+}
+
+func TestChunkedRoundTrip(t *testing.T) {
+	meta := NewMetadata()
+
+	sizes := []int{0, 1, DefaultChunkSize - 1, DefaultChunkSize, DefaultChunkSize + 1, 3*DefaultChunkSize + 100}
+	for _, size := range sizes {
+		plain := randomPlain(t, size)
+		sealed := encryptChunked(t, meta, plain)
+
+		h, err := ParseChunkedHeader(sealed)
+		if err != nil {
+			t.Fatalf("size %d: %s", size, err)
+		}
+
+		chunks := int64((size + DefaultChunkSize - 1) / DefaultChunkSize)
+		if chunks == 0 {
+			chunks = 1
+		}
+		if got := h.ChunkCount(int64(len(sealed))); got != chunks {
+			t.Errorf("size %d: chunk count = %d, want %d", size, got, chunks)
+		}
+		if want := ChunkedHeaderSize + int(chunks)*ChunkedTagSize + size; len(sealed) != want {
+			t.Errorf("size %d: encrypted size = %d, want %d", size, len(sealed), want)
+		}
+
+		got, err := decryptChunked(meta, sealed)
+		if err != nil {
+			t.Fatalf("size %d: %s", size, err)
+		}
+		if !bytes.Equal(got, plain) {
+			t.Errorf("size %d: decrypted content differs", size)
+		}
+	}
+}
+
+func TestChunkedDetectsTruncation(t *testing.T) {
+	meta := NewMetadata()
+	sealed := encryptChunked(t, meta, randomPlain(t, 3*DefaultChunkSize+100))
+	sealedChunk := DefaultChunkSize + ChunkedTagSize
+
+	cuts := map[string]int{
+		"last chunk dropped":     ChunkedHeaderSize + 3*sealedChunk,
+		"cut at a chunk end":     ChunkedHeaderSize + sealedChunk,
+		"cut within a chunk":     ChunkedHeaderSize + sealedChunk + 100,
+		"cut within the tag":     len(sealed) - 1,
+		"header only":            ChunkedHeaderSize,
+		"shorter than a tag":     ChunkedHeaderSize + ChunkedTagSize - 1,
+		"shorter than a header":  ChunkedHeaderSize - 1,
+		"cut before the last 10": len(sealed) - 10,
+	}
+	for name, cut := range cuts {
+		_, err := decryptChunked(meta, sealed[:cut])
+		if err == nil {
+			t.Errorf("%s: truncated file has been decrypted", name)
+		}
+	}
+}
+
+func TestChunkedDetectsTampering(t *testing.T) {
+	meta := NewMetadata()
+	sealed := encryptChunked(t, meta, randomPlain(t, 2*DefaultChunkSize+100))
+	sealedChunk := DefaultChunkSize + ChunkedTagSize
+
+	flip := func(offset int) []byte {
+		tampered := append([]byte{}, sealed...)
+		tampered[offset] ^= 1
+		return tampered
+	}
+
+	tests := map[string][]byte{
+		// the chunk size is part of the header every chunk is bound to
+		"nonce prefix":   flip(ChunkedHeaderSize - 1),
+		"first chunk":    flip(ChunkedHeaderSize + 10),
+		"first tag":      flip(ChunkedHeaderSize + sealedChunk - 1),
+		"last chunk":     flip(len(sealed) - ChunkedTagSize - 1),
+		"swapped chunks": swapChunks(sealed, sealedChunk),
+	}
+	for name, tampered := range tests {
+		_, err := decryptChunked(meta, tampered)
+		if err == nil {
+			t.Errorf("%s: tampered file has been decrypted", name)
+		}
+	}
+
+	other := NewMetadata()
+	if _, err := decryptChunked(other, sealed); err == nil {
+		t.Error("file has been decrypted with another key")
+	}
+}
+
+func swapChunks(sealed []byte, sealedChunk int) []byte {
+	swapped := append([]byte{}, sealed...)
+	first := swapped[ChunkedHeaderSize : ChunkedHeaderSize+sealedChunk]
+	second := swapped[ChunkedHeaderSize+sealedChunk : ChunkedHeaderSize+2*sealedChunk]
+	tmp := append([]byte{}, first...)
+	copy(first, second)
+	copy(second, tmp)
+	return swapped
+}
+
+func TestChunkedRangeDecryption(t *testing.T) {
+	meta := NewMetadata()
+	plain := randomPlain(t, 3*DefaultChunkSize+100)
+	sealed := encryptChunked(t, meta, plain)
+
+	h, err := ParseChunkedHeader(sealed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chunkCount := h.ChunkCount(int64(len(sealed)))
+	sealedChunk := int64(h.ChunkSize + ChunkedTagSize)
+
+	ranges := []struct {
+		name           string
+		offset, length int64
+	}{
+		{"first byte", 0, 1},
+		{"within a chunk", 100, 1000},
+		{"across chunks", int64(DefaultChunkSize) - 10, 20},
+		{"whole chunk", int64(DefaultChunkSize), int64(DefaultChunkSize)},
+		{"into the last chunk", 2*int64(DefaultChunkSize) + 5, int64(DefaultChunkSize) + 50},
+		{"last byte", int64(len(plain)) - 1, 1},
+	}
+
+	for _, tt := range ranges {
+		firstChunk, start, end := h.CipherRange(tt.offset, tt.length)
+		if end > int64(len(sealed)) {
+			end = int64(len(sealed))
+		}
+
+		var got []byte
+		index := firstChunk
+		for pos := start; pos < end; pos += sealedChunk {
+			chunkEnd := pos + sealedChunk
+			if chunkEnd > end {
+				chunkEnd = end
+			}
+
+			chunk, err := DecryptChunk(meta, h, index, index == chunkCount-1, sealed[pos:chunkEnd])
+			if err != nil {
+				t.Fatalf("%s: chunk %d: %s", tt.name, index, err)
+			}
+			got = append(got, chunk...)
+			index++
+		}
+
+		skip := tt.offset - firstChunk*int64(h.ChunkSize)
+		if skip+tt.length > int64(len(got)) {
+			t.Fatalf("%s: decrypted %d bytes from offset %d, want %d", tt.name, len(got), skip, tt.length)
+		}
+		if !bytes.Equal(got[skip:skip+tt.length], plain[tt.offset:tt.offset+tt.length]) {
+			t.Errorf("%s: decrypted range differs", tt.name)
+		}
+	}
+
+	// a chunk opened as the last one while it isn't fails, so a range can't
+	// pass a truncated file off as complete
+	_, start, _ := h.CipherRange(0, 1)
+	_, err = DecryptChunk(meta, h, 0, true, sealed[start:start+sealedChunk])
+	if err == nil {
+		t.Error("first chunk has been opened as the last one")
+	}
+
+	_, err = DecryptChunk(meta, h, 1, false, sealed[start:start+sealedChunk])
+	if err == nil {
+		t.Error("chunk has been opened at another index")
+	}
+}
@@ -0,0 +1,77 @@
+package drm
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+)
+
+// ClearKeySessionTemporary is the only session type licenses are issued
+// for, keys are requested again for every playback.
+const ClearKeySessionTemporary = "temporary"
+
+var (
+	ErrInvalidKID = errors.New("invalid kid")
+	ErrInvalidKey = errors.New("invalid key")
+)
+
+// ClearKeyRequest is the license request a Clear Key player sends, KIDs are
+// base64url encoded without padding.
+type ClearKeyRequest struct {
+	KIDs []string `json:"kids"`
+	Type string   `json:"type"`
+}
+
+// ClearKey is a content key in the JSON Web Key format of Clear Key.
+type ClearKey struct {
+	Kty string `json:"kty"`
+	K   string `json:"k"`
+	KID string `json:"kid"`
+}
+
+// ClearKeyLicense is the license of the Clear Key format, players pass it
+// to the Clear Key CDM of the browser as it is.
+type ClearKeyLicense struct {
+	Keys []*ClearKey `json:"keys"`
+	Type string      `json:"type"`
+}
+
+// DecodeKID converts a Clear Key kid to the hex form the kid is stored
+// with.
+func DecodeKID(kid string) (string, error) {
+	b, err := base64.RawURLEncoding.DecodeString(kid)
+	if err != nil || len(b) != 16 {
+		return "", ErrInvalidKID
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+// EncodeKID converts a hex kid to its Clear Key form.
+func EncodeKID(kid string) (string, error) {
+	b, err := hex.DecodeString(kid)
+	if err != nil || len(b) != 16 {
+		return "", ErrInvalidKID
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// NewClearKey returns the content key of the metadata.
+func NewClearKey(meta *Metadata) (*ClearKey, error) {
+	kid, err := EncodeKID(meta.KID)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := hex.DecodeString(meta.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ClearKey{
+		Kty: "oct",
+		K:   base64.RawURLEncoding.EncodeToString(key),
+		KID: kid,
+	}, nil
+}
@@ -0,0 +1,57 @@
+package drm
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// TestClearKeyLicense checks the license against the format of the Clear
+// Key CDM, keys and kids are base64url encoded without padding.
+func TestClearKeyLicense(t *testing.T) {
+	meta := &Metadata{
+		KID: "00112233445566778899aabbccddeeff",
+		Key: "fffefdfcfbfaf9f8f7f6f5f4f3f2f1f0",
+	}
+
+	key, err := NewClearKey(meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	license := &ClearKeyLicense{Keys: []*ClearKey{key}, Type: ClearKeySessionTemporary}
+
+	b, err := json.Marshal(license)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"keys":[{"kty":"oct","k":"__79_Pv6-fj39vX08_Lx8A","kid":"ABEiM0RVZneImaq7zN3u_w"}],"type":"temporary"}`
+	if string(b) != want {
+		t.Errorf("license = %s, want %s", b, want)
+	}
+}
+
+func TestClearKeyRequest(t *testing.T) {
+	req := new(ClearKeyRequest)
+	err := json.Unmarshal([]byte(`{"kids":["ABEiM0RVZneImaq7zN3u_w"],"type":"temporary"}`), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(req.KIDs) != 1 {
+		t.Fatalf("request has %d kids, want 1", len(req.KIDs))
+	}
+
+	kid, err := DecodeKID(req.KIDs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kid != "00112233445566778899aabbccddeeff" {
+		t.Errorf("kid = %s", kid)
+	}
+
+	for _, kid := range []string{"", "not base64", "ABEiM0RVZneImaq7zN3u_w=="} {
+		if _, err := DecodeKID(kid); err != ErrInvalidKID {
+			t.Errorf("kid %q returned %v, want %v", kid, err, ErrInvalidKID)
+		}
+	}
+}
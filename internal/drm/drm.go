@@ -59,28 +59,51 @@ func GenerateEncryptionKey() string {
 	return s
 }
 
-func GenerateDRMKey(pubKeyBase64 string) (string, *Metadata, error) {
-	meta := &Metadata{
+func NewMetadata() *Metadata {
+	return &Metadata{
 		FirstIV: GenerateFirstIV(),
 		Key:     GenerateEncryptionKey(),
 		KID:     GenerateKID(),
 	}
+}
 
-	message, err := json.Marshal(meta)
+// GenerateDRMKey generates a content key and seals it to the public key.
+func GenerateDRMKey(pubKeyBase64 string) (string, *Metadata, error) {
+	meta := NewMetadata()
+
+	drmKey, err := SealKey(meta, pubKeyBase64)
 	if err != nil {
 		return "", nil, err
 	}
 
+	return drmKey, meta, nil
+}
+
+// SealKey seals the content key to the X25519 public key of a recipient.
+// Every recipient of the same key gets an envelope of its own.
+func SealKey(meta *Metadata, pubKeyBase64 string) (string, error) {
+	message, err := json.Marshal(meta)
+	if err != nil {
+		return "", err
+	}
+
+	return seal(message, pubKeyBase64)
+}
+
+// seal encrypts the message to the public key in the
+// x25519-xsalsa20-poly1305 format wallets decrypt, the envelope is returned
+// as hex encoded JSON.
+func seal(message []byte, pubKeyBase64 string) (string, error) {
 	keyPair, err := tweetnacl.CryptoBoxKeyPair()
 	if err != nil {
-		return "", nil, err
+		return "", err
 	}
 
 	ephemPublicKey64 := base64.StdEncoding.EncodeToString(keyPair.PublicKey)
 
 	pubKey, err := base64.StdEncoding.DecodeString(pubKeyBase64)
 	if err != nil {
-		return "", nil, err
+		return "", err
 	}
 
 	nonce := NewNonce()
@@ -88,7 +111,7 @@ func GenerateDRMKey(pubKeyBase64 string) (string, *Metadata, error) {
 
 	cipher, err := tweetnacl.CryptoBox(message, nonce, pubKey, keyPair.SecretKey)
 	if err != nil {
-		return "", nil, err
+		return "", err
 	}
 
 	cipher64 := base64.StdEncoding.EncodeToString(cipher)
@@ -102,10 +125,10 @@ func GenerateDRMKey(pubKeyBase64 string) (string, *Metadata, error) {
 
 	drmKeyJSON, err := json.Marshal(drmKey)
 	if err != nil {
-		return "", nil, err
+		return "", err
 	}
 
-	return common.Bytes2Hex(drmKeyJSON), meta, nil
+	return common.Bytes2Hex(drmKeyJSON), nil
 }
 
 func GenerateDrmXml(meta *Metadata) string {
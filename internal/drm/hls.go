@@ -0,0 +1,135 @@
+package drm
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// The cbcs HLS packaging is protected with Clear Key, like the dash one. It
+// plays in players which parse the playlists themselves and hand the key
+// over to the Clear Key CDM of the browser through EME, Shaka Player or
+// hls.js with EME enabled, on Chromium based browsers and Firefox.
+//
+// The cbcs scheme is used so the same segments can be decrypted by
+// FairPlay once it is supported, the key is packaged with a constant IV and
+// the 1:9 pattern of SAMPLE-AES.
+//
+// Safari and iOS play the AES-128 packaging instead. Its playlists are
+// served by the license endpoint to the holders, which points their key
+// uri at the key endpoint, see RewriteHLSPlaylist.
+const drmCBCSXMLTemplate = `
+<?xml version="1.0" encoding="UTF-8"?>
+<GPACDRM type="CENC AES-CBC-PATTERN">
+  <DRMInfo type="pssh" version="1">
+    <BS ID128="1077efecc0b24d02ace33c1e52e2fb4b"/>
+    <BS bits="32" value="1"/>
+    <BS ID128="%s"/>
+  </DRMInfo>
+  <CrypTrack IV_size="0" constant_IV_size="16" constant_IV="%s" isEncrypted="1" crypt_byte_block="1" skip_byte_block="9" saiSavedBox="senc">
+    <key KID="%s" value="%s"/>
+  </CrypTrack>
+</GPACDRM>`
+
+// GenerateCBCSDrmXml returns the GPAC drm file of the cbcs packaging. The
+// constant IV is stored in the init segments so a fresh one is used, it
+// must not be derived from the first IV of the cenc packaging under the same
+// key.
+func GenerateCBCSDrmXml(meta *Metadata) (string, error) {
+	constantIV, err := randomHex(16)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf(drmCBCSXMLTemplate, meta.KID, constantIV, meta.KID, meta.Key), nil
+}
+
+// HLSAESKeyURI is the key uri of the AES-128 playlists, it is relative to
+// the playlists the license endpoint serves.
+const HLSAESKeyURI = "key"
+
+// HLSKeyTag returns the Clear Key key tag of the media playlists, the key
+// itself is requested from the Clear Key license endpoint by its kid.
+// Native HLS playback ignores the tag, see above for the players it works
+// in.
+func HLSKeyTag(meta *Metadata) (string, error) {
+	kid, err := hex.DecodeString(meta.KID)
+	if err != nil || len(kid) != 16 {
+		return "", ErrInvalidKID
+	}
+
+	return fmt.Sprintf(
+		`#EXT-X-KEY:METHOD=SAMPLE-AES,URI="data:text/plain;base64,%s",KEYFORMAT="org.w3.clearkey",KEYFORMATVERSIONS="1"`,
+		base64.StdEncoding.EncodeToString(kid),
+	), nil
+}
+
+// HLSAESKey returns the content key the AES-128 segments are encrypted
+// with, the key endpoint hands it out as it is.
+func HLSAESKey(meta *Metadata) ([]byte, error) {
+	key, err := hex.DecodeString(meta.Key)
+	if err != nil || len(key) != 16 {
+		return nil, ErrInvalidKey
+	}
+
+	return key, nil
+}
+
+// HLSAESKeyInfo returns the key info file ffmpeg encrypts the segments of a
+// rendition with: the key uri, the path of the key file and a fresh IV, so
+// the renditions encrypted under the same key don't share IVs.
+func HLSAESKeyInfo(keyPath string) (string, error) {
+	iv, err := randomHex(16)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s\n%s\n%s\n", HLSAESKeyURI, keyPath, iv), nil
+}
+
+// RewriteHLSPlaylist prepares a stored AES-128 playlist to be served by the
+// license endpoint. Native players can't authenticate the requests of the
+// key and the media playlists, so the query of the playlist request, which
+// carries the token, is passed on to their relative uris. Segments are
+// public and are pointed at where they are stored.
+func RewriteHLSPlaylist(playlist string, query string, segmentURL func(name string) string) string {
+	withQuery := func(uri string) string {
+		if query == "" {
+			return uri
+		}
+		return uri + "?" + query
+	}
+
+	lines := strings.Split(playlist, "\n")
+	for idx, line := range lines {
+		line = strings.TrimRight(line, "\r")
+
+		switch {
+		case line == "":
+		case strings.HasPrefix(line, "#EXT-X-KEY:"):
+			start := strings.Index(line, `URI="`)
+			if start < 0 {
+				break
+			}
+			start += len(`URI="`)
+			end := strings.Index(line[start:], `"`)
+			if end < 0 {
+				break
+			}
+			uri := line[start : start+end]
+			if !strings.Contains(uri, ":") {
+				line = line[:start] + withQuery(uri) + line[start+end:]
+			}
+		case strings.HasPrefix(line, "#"):
+		case strings.HasSuffix(line, ".m3u8"):
+			line = withQuery(line)
+		default:
+			line = segmentURL(line)
+		}
+
+		lines[idx] = line
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,95 @@
+package drm
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHLSAESKeyInfo(t *testing.T) {
+	info, err := HLSAESKeyInfo("/tmp/media/hls_aes.key")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(info, "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("key info has %d lines, want 3", len(lines))
+	}
+	if lines[0] != HLSAESKeyURI || lines[1] != "/tmp/media/hls_aes.key" {
+		t.Errorf("key info = %q", info)
+	}
+	if len(lines[2]) != 32 {
+		t.Errorf("iv = %q, want 16 hex bytes", lines[2])
+	}
+
+	other, err := HLSAESKeyInfo("/tmp/media/hls_aes.key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other == info {
+		t.Error("renditions share an iv")
+	}
+}
+
+func TestHLSAESKey(t *testing.T) {
+	key, err := HLSAESKey(&Metadata{Key: "fffefdfcfbfaf9f8f7f6f5f4f3f2f1f0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(key) != 16 || key[0] != 0xff || key[15] != 0xf0 {
+		t.Errorf("key = %x", key)
+	}
+
+	if _, err := HLSAESKey(&Metadata{Key: "fffe"}); err != ErrInvalidKey {
+		t.Errorf("short key returned %v, want %v", err, ErrInvalidKey)
+	}
+}
+
+func TestRewriteHLSPlaylist(t *testing.T) {
+	segmentURL := func(name string) string {
+		return "https://cache.example.com/media/" + name
+	}
+
+	master := "#EXTM3U\n#EXT-X-VERSION:3\n#EXT-X-STREAM-INF:BANDWIDTH=800000\naes_0.m3u8\n"
+	want := "#EXTM3U\n#EXT-X-VERSION:3\n#EXT-X-STREAM-INF:BANDWIDTH=800000\naes_0.m3u8?token=jwt\n"
+	if got := RewriteHLSPlaylist(master, "token=jwt", segmentURL); got != want {
+		t.Errorf("master playlist = %q, want %q", got, want)
+	}
+
+	media := strings.Join([]string{
+		"#EXTM3U",
+		"#EXT-X-VERSION:3",
+		"#EXT-X-TARGETDURATION:6",
+		"#EXT-X-PLAYLIST-TYPE:VOD",
+		`#EXT-X-KEY:METHOD=AES-128,URI="key",IV=0x000102030405060708090a0b0c0d0e0f`,
+		"#EXTINF:6.000000,",
+		"aes_0_000.ts",
+		"#EXT-X-ENDLIST",
+		"",
+	}, "\r\n")
+	want = strings.Join([]string{
+		"#EXTM3U",
+		"#EXT-X-VERSION:3",
+		"#EXT-X-TARGETDURATION:6",
+		"#EXT-X-PLAYLIST-TYPE:VOD",
+		`#EXT-X-KEY:METHOD=AES-128,URI="key?token=jwt",IV=0x000102030405060708090a0b0c0d0e0f`,
+		"#EXTINF:6.000000,",
+		"https://cache.example.com/media/aes_0_000.ts",
+		"#EXT-X-ENDLIST",
+		"",
+	}, "\n")
+	if got := RewriteHLSPlaylist(media, "token=jwt", segmentURL); got != want {
+		t.Errorf("media playlist = %q, want %q", got, want)
+	}
+
+	// absolute key uris are left alone
+	clearKey := `#EXT-X-KEY:METHOD=SAMPLE-AES,URI="data:text/plain;base64,AA==",KEYFORMAT="org.w3.clearkey"`
+	if got := RewriteHLSPlaylist(clearKey, "token=jwt", segmentURL); got != clearKey {
+		t.Errorf("clear key tag = %q", got)
+	}
+
+	// requests authorized with a header carry no query
+	if got := RewriteHLSPlaylist(master, "", segmentURL); got != master {
+		t.Errorf("master playlist without query = %q", got)
+	}
+}
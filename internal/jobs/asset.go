@@ -0,0 +1,277 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/videocoin/marketplace/internal/datastore"
+	"github.com/videocoin/marketplace/internal/drm"
+	"github.com/videocoin/marketplace/internal/model"
+	"github.com/videocoin/marketplace/pkg/ethutil"
+)
+
+const (
+	checkpointAssetEncrypted = "encrypted"
+	checkpointAssetToken     = "token"
+	checkpointAssetMinted    = "minted"
+	checkpointAssetVoucher   = "voucher"
+)
+
+type assetProcessHandler struct {
+	pool *Pool
+}
+
+func checkpointMediaEncrypted(media *model.Media) string {
+	return fmt.Sprintf("encrypted:%s", media.ID)
+}
+
+func checkpointMediaPreview(media *model.Media) string {
+	return fmt.Sprintf("preview:%s", media.ID)
+}
+
+func (h *assetProcessHandler) Handle(ctx context.Context, job *model.Job) error {
+	payload := new(model.AssetProcessJobPayload)
+	err := job.UnmarshalPayload(payload)
+	if err != nil {
+		return err
+	}
+
+	asset, err := h.pool.ds.Assets.GetByID(ctx, payload.AssetID)
+	if err != nil {
+		return err
+	}
+
+	account, err := h.pool.ds.Accounts.GetByID(ctx, asset.CreatedByID)
+	if err != nil {
+		return err
+	}
+	asset.CreatedBy = account
+
+	owner, err := h.pool.ds.Accounts.GetByID(ctx, asset.OwnerID)
+	if err != nil {
+		return err
+	}
+	asset.Owner = owner
+
+	mediaItems, err := h.pool.ds.Media.ListByAssetID(ctx, asset.ID)
+	if err != nil {
+		return err
+	}
+	for _, media := range mediaItems {
+		media.CreatedBy = asset.CreatedBy
+	}
+	asset.Media = mediaItems
+
+	logger := h.pool.logger.
+		WithField("job_id", job.ID).
+		WithField("asset_id", asset.ID)
+
+	if asset.Locked && !job.IsCheckpointPassed(checkpointAssetEncrypted) {
+		drmMeta := new(drm.Metadata)
+		err = json.Unmarshal([]byte(asset.DRMMeta), drmMeta)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal drm meta: %s", err)
+		}
+
+		for _, media := range mediaItems {
+			if media.Featured || job.IsCheckpointPassed(checkpointMediaEncrypted(media)) {
+				continue
+			}
+
+			logger.WithField("media_id", media.ID).Info("encrypting media")
+
+			err = h.pool.mp.EncryptMedia(ctx, media, drmMeta)
+			if err != nil {
+				return fmt.Errorf("failed to encrypt media #%s: %s", media.ID, err)
+			}
+
+			err = h.pool.ds.Jobs.SaveCheckpoint(ctx, job, checkpointMediaEncrypted(media))
+			if err != nil {
+				return err
+			}
+		}
+
+		err = h.pool.ds.Jobs.SaveCheckpoint(ctx, job, checkpointAssetEncrypted)
+		if err != nil {
+			return err
+		}
+	}
+
+	if asset.Locked {
+		for _, media := range mediaItems {
+			if !media.CanPreview() || job.IsCheckpointPassed(checkpointMediaPreview(media)) {
+				continue
+			}
+
+			logger.WithField("media_id", media.ID).Info("generating preview")
+
+			err = h.pool.mp.GeneratePreview(ctx, media)
+			if err != nil {
+				return fmt.Errorf("failed to generate preview of media #%s: %s", media.ID, err)
+			}
+
+			err = h.pool.ds.Jobs.SaveCheckpoint(ctx, job, checkpointMediaPreview(media))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if !job.IsCheckpointPassed(checkpointAssetToken) {
+		err = h.pool.publishToken(ctx, asset)
+		if err != nil {
+			return err
+		}
+
+		err = h.pool.ds.Jobs.SaveCheckpoint(ctx, job, checkpointAssetToken)
+		if err != nil {
+			return err
+		}
+	}
+
+	// the tokens of a batch are minted together once all its assets have
+	// been processed, the asset is marked as ready by the batch mint job
+	if asset.BatchID.Valid && !asset.LazyMint {
+		return h.pool.queueBatchMint(ctx, asset.BatchID.Int64)
+	}
+
+	if asset.LazyMint {
+		if !job.IsCheckpointPassed(checkpointAssetVoucher) {
+			err = h.signVoucher(ctx, asset)
+			if err != nil {
+				return err
+			}
+
+			err = h.pool.ds.Jobs.SaveCheckpoint(ctx, job, checkpointAssetVoucher)
+			if err != nil {
+				return err
+			}
+		}
+
+		return h.pool.ds.Assets.MarkStatusAsReady(ctx, asset)
+	}
+
+	if !job.IsCheckpointPassed(checkpointAssetMinted) && !asset.MintTxID.Valid {
+		tokenURI := asset.GetTokenUrl()
+		if tokenURI == nil {
+			return errors.New("failed to get asset token uri")
+		}
+
+		logger.
+			WithField("token_uri", *tokenURI).
+			WithField("schema", asset.Schema).
+			WithField("supply", asset.Supply).
+			WithField("network", asset.Network).
+			Info("minting")
+
+		m, err := h.pool.minters.Get(asset.Network)
+		if err != nil {
+			return err
+		}
+
+		// the mint tx id and the chain token uri are set by the minter once
+		// the transaction is mined
+		var mintTx *model.ChainTx
+		if asset.IsEdition() {
+			mintTx, err = m.Mint1155(
+				ctx,
+				common.HexToAddress(asset.CreatedBy.Address),
+				big.NewInt(asset.ID),
+				asset.Supply,
+			)
+		} else {
+			mintTx, err = m.Mint(
+				ctx,
+				common.HexToAddress(asset.CreatedBy.Address),
+				big.NewInt(asset.ID),
+				*tokenURI,
+			)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to mint: %s", err)
+		}
+
+		if mintTx != nil {
+			logger.WithField("tx_hash", mintTx.TxHash).Info("mint transaction has been sent")
+		}
+
+		err = h.pool.ds.Jobs.SaveCheckpoint(ctx, job, checkpointAssetMinted)
+		if err != nil {
+			return err
+		}
+	}
+
+	return h.pool.ds.Assets.MarkStatusAsReady(ctx, asset)
+}
+
+// signVoucher lists a lazily minted asset with a voucher, which lets the
+// token be minted once an order is placed for it instead of now.
+func (h *assetProcessHandler) signVoucher(ctx context.Context, asset *model.Asset) error {
+	tokenURI := asset.GetTokenUrl()
+	if tokenURI == nil {
+		return errors.New("failed to get asset token uri")
+	}
+
+	m, err := h.pool.minters.Get(asset.Network)
+	if err != nil {
+		return err
+	}
+
+	voucher := &model.MintVoucher{
+		TokenID:    strconv.FormatInt(asset.ID, 10),
+		URI:        *tokenURI,
+		Creator:    asset.CreatedBy.Address,
+		Price:      ethutil.EtherToWei(asset.Price).String(),
+		RoyaltyBps: asset.RoyaltyBps(),
+	}
+
+	err = m.SignVoucher(voucher)
+	if err != nil {
+		return fmt.Errorf("failed to sign mint voucher: %s", err)
+	}
+
+	h.pool.logger.
+		WithField("asset_id", asset.ID).
+		WithField("token_uri", *tokenURI).
+		WithField("network", asset.Network).
+		Info("mint voucher has been signed")
+
+	err = h.pool.ds.Assets.Update(ctx, asset, datastore.AssetUpdatedFields{
+		MintVoucher: voucher,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update mint voucher: %s", err)
+	}
+
+	return nil
+}
+
+func (h *assetProcessHandler) Bury(ctx context.Context, job *model.Job) error {
+	payload := new(model.AssetProcessJobPayload)
+	err := job.UnmarshalPayload(payload)
+	if err != nil {
+		return err
+	}
+
+	asset, err := h.pool.ds.Assets.GetByID(ctx, payload.AssetID)
+	if err != nil {
+		return err
+	}
+
+	err = h.pool.ds.Assets.MarkStatusAsFailed(ctx, asset)
+	if err != nil {
+		return err
+	}
+
+	// the rest of the batch is minted without the failed asset
+	if asset.BatchID.Valid {
+		return h.pool.queueBatchMint(ctx, asset.BatchID.Int64)
+	}
+
+	return nil
+}
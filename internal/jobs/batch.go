@@ -0,0 +1,219 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/videocoin/marketplace/internal/datastore"
+	"github.com/videocoin/marketplace/internal/minter"
+	"github.com/videocoin/marketplace/internal/model"
+)
+
+// assetBatchMintHandler mints the tokens of a batch once all its assets
+// have been processed. The minter packs them into as few transactions as
+// the contracts allow and skips the tokens already sent, so a retry
+// doesn't mint them twice.
+type assetBatchMintHandler struct {
+	pool *Pool
+}
+
+// isBatchItemProcessed reports whether the asset no longer holds back the
+// mint of its batch. Lazily minted assets aren't minted with it.
+func isBatchItemProcessed(asset *model.Asset) bool {
+	return asset.LazyMint || asset.Status == model.AssetStatusFailed || asset.TokenCID.Valid
+}
+
+// queueBatchMint creates the mint job of the batch once none of its assets
+// is being processed anymore.
+func (p *Pool) queueBatchMint(ctx context.Context, batchID int64) error {
+	batch, err := p.ds.AssetBatches.GetByID(ctx, batchID)
+	if err != nil {
+		return err
+	}
+
+	if batch.MintJobID.Valid {
+		return nil
+	}
+
+	assets, err := p.ds.Assets.List(ctx, &datastore.AssetsFilter{BatchID: &batch.ID}, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, asset := range assets {
+		if !isBatchItemProcessed(asset) {
+			return nil
+		}
+	}
+
+	job, err := model.NewAssetBatchMintJob(batch)
+	if err != nil {
+		return err
+	}
+
+	return p.ds.InTx(ctx, func(ctx context.Context) error {
+		ok, err := p.ds.AssetBatches.SetMintJobID(ctx, batch, job.ID)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+
+		err = p.ds.Jobs.Create(ctx, job)
+		if err != nil {
+			return err
+		}
+
+		p.logger.
+			WithField("batch_id", batch.ID).
+			WithField("job_id", job.ID).
+			Info("batch mint job has been queued")
+
+		return nil
+	})
+}
+
+func (h *assetBatchMintHandler) Handle(ctx context.Context, job *model.Job) error {
+	payload := new(model.AssetBatchMintJobPayload)
+	err := job.UnmarshalPayload(payload)
+	if err != nil {
+		return err
+	}
+
+	batch, err := h.pool.ds.AssetBatches.GetByID(ctx, payload.BatchID)
+	if err != nil {
+		return err
+	}
+
+	account, err := h.pool.ds.Accounts.GetByID(ctx, batch.CreatedByID)
+	if err != nil {
+		return err
+	}
+
+	m, err := h.pool.minters.Get(batch.Network)
+	if err != nil {
+		return err
+	}
+
+	assets, err := h.pool.ds.Assets.List(ctx, &datastore.AssetsFilter{BatchID: &batch.ID}, nil)
+	if err != nil {
+		return err
+	}
+
+	logger := h.pool.logger.
+		WithField("job_id", job.ID).
+		WithField("batch_id", batch.ID).
+		WithField("network", batch.Network)
+
+	minting := make([]*model.Asset, 0, len(assets))
+	tokens := make([]*minter.MintItem, 0, len(assets))
+	editions := make([]*minter.MintItem, 0, len(assets))
+	for _, asset := range assets {
+		if asset.LazyMint || asset.Status != model.AssetStatusProcessing {
+			continue
+		}
+
+		if asset.IsEdition() {
+			editions = append(editions, &minter.MintItem{
+				ID:     big.NewInt(asset.ID),
+				Amount: asset.Supply,
+			})
+			minting = append(minting, asset)
+			continue
+		}
+
+		mediaItems, err := h.pool.ds.Media.ListByAssetID(ctx, asset.ID)
+		if err != nil {
+			return err
+		}
+		asset.Media = mediaItems
+
+		tokenURI := asset.GetTokenUrl()
+		if tokenURI == nil {
+			return fmt.Errorf("failed to get asset #%d token uri", asset.ID)
+		}
+
+		tokens = append(tokens, &minter.MintItem{
+			ID:  big.NewInt(asset.ID),
+			URI: *tokenURI,
+		})
+		minting = append(minting, asset)
+	}
+
+	if len(minting) == 0 {
+		logger.Info("batch has nothing to mint")
+		return nil
+	}
+
+	logger.
+		WithField("tokens", len(tokens)).
+		WithField("editions", len(editions)).
+		Info("minting batch")
+
+	to := common.HexToAddress(account.Address)
+
+	// the mint tx ids and the chain token uris are set by the minter once
+	// the transactions are mined
+	chainTxs := make([]*model.ChainTx, 0)
+	if len(tokens) > 0 {
+		sent, err := m.MintBatch(ctx, to, tokens)
+		if err != nil {
+			return fmt.Errorf("failed to mint batch: %s", err)
+		}
+		chainTxs = append(chainTxs, sent...)
+	}
+
+	if len(editions) > 0 {
+		sent, err := m.MintBatch1155(ctx, to, editions)
+		if err != nil {
+			return fmt.Errorf("failed to mint batch editions: %s", err)
+		}
+		chainTxs = append(chainTxs, sent...)
+	}
+
+	for _, chainTx := range chainTxs {
+		logger.
+			WithField("asset_ids", chainTx.Assets()).
+			WithField("tx_hash", chainTx.TxHash).
+			Info("mint transaction has been sent")
+	}
+
+	for _, asset := range minting {
+		err = h.pool.ds.Assets.MarkStatusAsReady(ctx, asset)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Bury marks the assets of the batch which couldn't be minted as failed.
+func (h *assetBatchMintHandler) Bury(ctx context.Context, job *model.Job) error {
+	payload := new(model.AssetBatchMintJobPayload)
+	err := job.UnmarshalPayload(payload)
+	if err != nil {
+		return err
+	}
+
+	assets, err := h.pool.ds.Assets.List(ctx, &datastore.AssetsFilter{BatchID: &payload.BatchID}, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, asset := range assets {
+		if asset.LazyMint || asset.Status != model.AssetStatusProcessing {
+			continue
+		}
+
+		err = h.pool.ds.Assets.MarkStatusAsFailed(ctx, asset)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,162 @@
+package jobs
+
+import (
+	"context"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/AlekSi/pointer"
+	"github.com/videocoin/marketplace/internal/datastore"
+	"github.com/videocoin/marketplace/internal/model"
+)
+
+const (
+	checkpointMediaNormalized = "normalized"
+	checkpointMediaThumbnail  = "thumbnail"
+)
+
+type mediaUploadHandler struct {
+	pool *Pool
+}
+
+func (h *mediaUploadHandler) Handle(ctx context.Context, job *model.Job) error {
+	payload := new(model.MediaUploadJobPayload)
+	err := job.UnmarshalPayload(payload)
+	if err != nil {
+		return err
+	}
+
+	media, err := h.pool.ds.Media.GetByID(ctx, payload.MediaID)
+	if err != nil {
+		return err
+	}
+
+	tmpFolder := filepath.Join("/tmp", job.ID)
+	err = os.MkdirAll(tmpFolder, 0777)
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpFolder)
+
+	meta := payload.AssetMeta()
+	meta.LocalDest = filepath.Join(tmpFolder, path.Base(meta.DestKey))
+	meta.LocalThumbDest = filepath.Join(tmpFolder, "thumb.jpg")
+	meta.LocalThumbBluredDest = filepath.Join(tmpFolder, "b_thumb.jpg")
+
+	logger := h.pool.logger.
+		WithField("job_id", job.ID).
+		WithField("media_id", media.ID).
+		WithField("key", meta.DestKey)
+
+	// the original upload is kept next to the mezzanine the media is played
+	// from
+	if meta.DestSourceKey != "" && !job.IsCheckpointPassed(checkpointMediaNormalized) {
+		meta.LocalSourceDest = filepath.Join(tmpFolder, "source"+path.Ext(meta.DestSourceKey))
+
+		logger.WithField("source_key", meta.DestSourceKey).Info("downloading source")
+
+		err = h.download(meta.DestSourceKey, meta.LocalSourceDest)
+		if err != nil {
+			return err
+		}
+
+		logger.
+			WithField("source_content_type", meta.SourceContentType).
+			Info("normalizing video")
+
+		err = h.pool.mp.NormalizeVideo(ctx, meta.LocalSourceDest, meta.LocalDest)
+		if err != nil {
+			return err
+		}
+
+		logger.Info("uploading mezzanine to storage")
+
+		f, err := os.Open(meta.LocalDest)
+		if err != nil {
+			return err
+		}
+
+		cid, err := h.pool.storage.PushPath(meta.DestKey, f, payload.Featured)
+		_ = f.Close()
+		if err != nil {
+			return err
+		}
+
+		logger.WithField("cid", cid).Info("mezzanine has been uploaded to storage")
+
+		err = h.pool.ds.Media.Update(ctx, media, datastore.MediaUpdatedFields{
+			CID: pointer.ToString(cid),
+		})
+		if err != nil {
+			return err
+		}
+
+		err = h.pool.ds.Jobs.SaveCheckpoint(ctx, job, checkpointMediaNormalized)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !job.IsCheckpointPassed(checkpointMediaThumbnail) {
+		if media.IsVideo() || media.GetMediaType() == model.MediaTypeImage {
+			if _, err := os.Stat(meta.LocalDest); os.IsNotExist(err) {
+				logger.Info("downloading media")
+
+				err = h.download(meta.DestKey, meta.LocalDest)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		logger.Info("generating thumbnail")
+
+		err = h.pool.mp.GenerateThumbnail(ctx, media, meta)
+		if err != nil {
+			return err
+		}
+
+		err = h.pool.ds.Jobs.SaveCheckpoint(ctx, job, checkpointMediaThumbnail)
+		if err != nil {
+			return err
+		}
+	}
+
+	return h.pool.ds.Media.Update(ctx, media, datastore.MediaUpdatedFields{
+		Status: pointer.ToString(string(model.MediaStatusReady)),
+	})
+}
+
+func (h *mediaUploadHandler) download(key, dest string) error {
+	r, err := h.pool.storage.ObjReader(key)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	f, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(f, r)
+	return err
+}
+
+func (h *mediaUploadHandler) Bury(ctx context.Context, job *model.Job) error {
+	payload := new(model.MediaUploadJobPayload)
+	err := job.UnmarshalPayload(payload)
+	if err != nil {
+		return err
+	}
+
+	media, err := h.pool.ds.Media.GetByID(ctx, payload.MediaID)
+	if err != nil {
+		return err
+	}
+
+	return h.pool.ds.Media.MarkStatusAsFailed(ctx, media)
+}
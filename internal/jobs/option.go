@@ -0,0 +1,71 @@
+package jobs
+
+import (
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/videocoin/marketplace/internal/datastore"
+	"github.com/videocoin/marketplace/internal/mediaprocessor"
+	"github.com/videocoin/marketplace/internal/minter"
+	"github.com/videocoin/marketplace/internal/storage"
+)
+
+type Option func(*Pool) error
+
+func WithLogger(logger *logrus.Entry) Option {
+	return func(p *Pool) error {
+		p.logger = logger
+		return nil
+	}
+}
+
+func WithDatastore(ds *datastore.Datastore) Option {
+	return func(p *Pool) error {
+		p.ds = ds
+		return nil
+	}
+}
+
+func WithMediaProcessor(mp *mediaprocessor.MediaProcessor) Option {
+	return func(p *Pool) error {
+		p.mp = mp
+		return nil
+	}
+}
+
+func WithStorage(s *storage.Storage) Option {
+	return func(p *Pool) error {
+		p.storage = s
+		return nil
+	}
+}
+
+// WithMinters sets the minter of every network, assets are minted on the
+// network they are tagged with.
+func WithMinters(minters minter.Minters) Option {
+	return func(p *Pool) error {
+		p.minters = minters
+		return nil
+	}
+}
+
+func WithWorkers(workers int) Option {
+	return func(p *Pool) error {
+		p.workers = workers
+		return nil
+	}
+}
+
+func WithLease(lease time.Duration) Option {
+	return func(p *Pool) error {
+		p.lease = lease
+		return nil
+	}
+}
+
+func WithPollInterval(interval time.Duration) Option {
+	return func(p *Pool) error {
+		p.pollInterval = interval
+		return nil
+	}
+}
@@ -0,0 +1,259 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/logrus/ctxlogrus"
+	"github.com/sirupsen/logrus"
+	"github.com/videocoin/marketplace/internal/datastore"
+	"github.com/videocoin/marketplace/internal/mediaprocessor"
+	"github.com/videocoin/marketplace/internal/minter"
+	"github.com/videocoin/marketplace/internal/model"
+	"github.com/videocoin/marketplace/internal/storage"
+	"github.com/videocoin/marketplace/pkg/random"
+)
+
+// Handler runs a single job type. Handle must be idempotent: a job is
+// retried from its last checkpoint after failures and process restarts.
+type Handler interface {
+	Handle(ctx context.Context, job *model.Job) error
+	// Bury is called once the job has been moved to the dead-letter state.
+	Bury(ctx context.Context, job *model.Job) error
+}
+
+type Pool struct {
+	logger       *logrus.Entry
+	ds           *datastore.Datastore
+	mp           *mediaprocessor.MediaProcessor
+	storage      *storage.Storage
+	minters      minter.Minters
+	workerID     string
+	workers      int
+	lease        time.Duration
+	pollInterval time.Duration
+	buryInterval time.Duration
+	handlers     map[model.JobType]Handler
+	stop         chan struct{}
+}
+
+func NewPool(ctx context.Context, opts ...Option) (*Pool, error) {
+	p := &Pool{
+		logger:       ctxlogrus.Extract(ctx).WithField("system", "jobs"),
+		workers:      4,
+		lease:        5 * time.Minute,
+		pollInterval: 2 * time.Second,
+		buryInterval: time.Minute,
+		handlers:     map[model.JobType]Handler{},
+		stop:         make(chan struct{}),
+	}
+
+	for _, o := range opts {
+		if err := o(p); err != nil {
+			return nil, err
+		}
+	}
+
+	hostname, _ := os.Hostname()
+	p.workerID = fmt.Sprintf("%s-%d-%s", hostname, os.Getpid(), random.RandomString(6))
+
+	p.Register(model.JobTypeMediaUpload, &mediaUploadHandler{pool: p})
+	p.Register(model.JobTypeAssetProcess, &assetProcessHandler{pool: p})
+	p.Register(model.JobTypeTokenURISync, &tokenURISyncHandler{pool: p})
+	p.Register(model.JobTypeAssetRedeem, &assetRedeemHandler{pool: p})
+	p.Register(model.JobTypeAssetBatchMint, &assetBatchMintHandler{pool: p})
+	p.Register(model.JobTypeAssetTransfer, &assetTransferHandler{pool: p})
+
+	return p, nil
+}
+
+func (p *Pool) Register(jobType model.JobType, handler Handler) {
+	p.handlers[jobType] = handler
+}
+
+func (p *Pool) types() []model.JobType {
+	types := make([]model.JobType, 0, len(p.handlers))
+	for jobType := range p.handlers {
+		types = append(types, jobType)
+	}
+	return types
+}
+
+func (p *Pool) Start(errCh chan error) {
+	p.logger.
+		WithField("worker_id", p.workerID).
+		WithField("workers", p.workers).
+		Info("starting job workers")
+
+	count, err := p.ds.Jobs.CountUnfinished(context.Background())
+	if err != nil {
+		p.logger.WithError(err).Error("failed to count unfinished jobs")
+	} else if count > 0 {
+		p.logger.WithField("count", count).Info("resuming unfinished jobs")
+	}
+
+	for i := 0; i < p.workers; i++ {
+		go p.work()
+	}
+	go p.bury()
+}
+
+// Stop stops picking up new jobs. Jobs which are still running are not
+// waited for: their leases expire and they are resumed from the last
+// checkpoint on the next start.
+func (p *Pool) Stop() error {
+	p.logger.Info("stopping job workers")
+	close(p.stop)
+	return nil
+}
+
+func (p *Pool) work() {
+	t := time.NewTicker(p.pollInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-p.stop:
+			return
+		default:
+		}
+
+		if p.runNext() {
+			continue
+		}
+
+		select {
+		case <-p.stop:
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// bury checks the expired leases on its own ticker, a single query per
+// pool is enough whatever the number of workers.
+func (p *Pool) bury() {
+	t := time.NewTicker(p.buryInterval)
+	defer t.Stop()
+
+	for {
+		p.buryExpired(context.Background())
+
+		select {
+		case <-p.stop:
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// buryExpired dead-letters the jobs whose worker died on their last
+// attempt, Lease leaves them alone.
+func (p *Pool) buryExpired(ctx context.Context) {
+	jobs, err := p.ds.Jobs.BuryExpired(ctx, p.types())
+	if err != nil {
+		p.logger.WithError(err).Error("failed to bury expired jobs")
+		return
+	}
+
+	for _, job := range jobs {
+		logger := p.logger.
+			WithField("job_id", job.ID).
+			WithField("job_type", job.Type).
+			WithField("attempt", job.Attempts)
+		logger.Warning("job lease has expired on the last attempt, job has been moved to dead-letter")
+
+		err = p.handlers[job.Type].Bury(ctx, job)
+		if err != nil {
+			logger.WithError(err).Error("failed to bury job")
+		}
+	}
+}
+
+func (p *Pool) runNext() bool {
+	ctx := context.Background()
+
+	job, err := p.ds.Jobs.Lease(ctx, p.workerID, p.types(), p.lease)
+	if err != nil {
+		if err != datastore.ErrJobNotFound {
+			p.logger.WithError(err).Error("failed to lease job")
+		}
+		return false
+	}
+
+	logger := p.logger.
+		WithField("job_id", job.ID).
+		WithField("job_type", job.Type).
+		WithField("attempt", job.Attempts)
+	logger.Info("running job")
+
+	handler := p.handlers[job.Type]
+
+	done := make(chan struct{})
+	go p.heartbeat(job, done)
+	err = p.handle(ctx, handler, job)
+	close(done)
+
+	if err == nil {
+		err = p.ds.Jobs.MarkStatusAsSucceeded(ctx, job)
+		if err != nil {
+			logger.WithError(err).Error("failed to mark job as succeeded")
+			return true
+		}
+
+		logger.Info("job has been completed")
+		return true
+	}
+
+	logger.WithError(err).Error("job failed")
+
+	markErr := p.ds.Jobs.MarkStatusAsFailed(ctx, job, err)
+	if markErr != nil {
+		logger.WithError(markErr).Error("failed to mark job as failed")
+		return true
+	}
+
+	if job.Status == model.JobStatusDead {
+		logger.Warning("job has been moved to dead-letter")
+		err = handler.Bury(ctx, job)
+		if err != nil {
+			logger.WithError(err).Error("failed to bury job")
+		}
+	} else {
+		logger.WithField("run_at", job.RunAt).Info("job has been rescheduled")
+	}
+
+	return true
+}
+
+func (p *Pool) handle(ctx context.Context, handler Handler, job *model.Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+
+	return handler.Handle(ctx, job)
+}
+
+func (p *Pool) heartbeat(job *model.Job, done chan struct{}) {
+	t := time.NewTicker(p.lease / 2)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-t.C:
+			err := p.ds.Jobs.ExtendLease(context.Background(), job, p.lease)
+			if err != nil {
+				p.logger.
+					WithField("job_id", job.ID).
+					WithError(err).
+					Error("failed to extend job lease")
+			}
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/videocoin/marketplace/internal/model"
+)
+
+// assetRedeemHandler mints a lazily minted token with its voucher to its
+// creator once an order has been placed for the asset, the exchange can't
+// match an order of a token which doesn't exist yet. The minter returns the
+// transaction already sent for the token, so a retry or the job of another
+// order doesn't mint it twice.
+type assetRedeemHandler struct {
+	pool *Pool
+}
+
+func (h *assetRedeemHandler) Handle(ctx context.Context, job *model.Job) error {
+	payload := new(model.AssetRedeemJobPayload)
+	err := job.UnmarshalPayload(payload)
+	if err != nil {
+		return err
+	}
+
+	asset, err := h.pool.ds.Assets.GetByID(ctx, payload.AssetID)
+	if err != nil {
+		return err
+	}
+
+	logger := h.pool.logger.
+		WithField("job_id", job.ID).
+		WithField("asset_id", asset.ID)
+
+	if !asset.IsUnminted() {
+		logger.Info("asset has already been minted")
+		return nil
+	}
+
+	if asset.MintVoucher == nil {
+		return errors.New("asset has no mint voucher")
+	}
+
+	if asset.MintVoucher.TokenID != strconv.FormatInt(asset.ID, 10) {
+		return errors.New("mint voucher is for another token")
+	}
+
+	m, err := h.pool.minters.Get(asset.Network)
+	if err != nil {
+		return err
+	}
+
+	logger.
+		WithField("token_uri", asset.MintVoucher.URI).
+		WithField("creator", asset.MintVoucher.Creator).
+		WithField("network", asset.Network).
+		Info("redeeming mint voucher")
+
+	chainTx, err := m.Redeem(ctx, asset.MintVoucher)
+	if err != nil {
+		return fmt.Errorf("failed to redeem mint voucher: %s", err)
+	}
+
+	logger.WithField("tx_hash", chainTx.TxHash).Info("mint transaction has been sent")
+
+	return nil
+}
+
+// Bury leaves the asset unminted, its voucher is redeemed again at the next
+// order.
+func (h *assetRedeemHandler) Bury(ctx context.Context, job *model.Job) error {
+	return nil
+}
@@ -0,0 +1,94 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/videocoin/marketplace/internal/datastore"
+	"github.com/videocoin/marketplace/internal/model"
+)
+
+// tokenURISyncHandler points the on-chain token uri at the current metadata
+// of the asset, which is published again whenever the asset changes hands.
+// The chain is checked first and the minter returns the update already
+// pending, so a retry doesn't send it twice.
+type tokenURISyncHandler struct {
+	pool *Pool
+}
+
+func (h *tokenURISyncHandler) Handle(ctx context.Context, job *model.Job) error {
+	payload := new(model.TokenURISyncJobPayload)
+	err := job.UnmarshalPayload(payload)
+	if err != nil {
+		return err
+	}
+
+	asset, err := h.pool.ds.Assets.GetByID(ctx, payload.AssetID)
+	if err != nil {
+		return err
+	}
+
+	logger := h.pool.logger.
+		WithField("job_id", job.ID).
+		WithField("asset_id", asset.ID)
+
+	// editions share the uri of the contract, unminted assets get the
+	// current uri when they are minted
+	if asset.IsEdition() || !asset.MintTxID.Valid {
+		return nil
+	}
+
+	mediaItems, err := h.pool.ds.Media.ListByAssetID(ctx, asset.ID)
+	if err != nil {
+		return err
+	}
+	asset.Media = mediaItems
+
+	tokenURI := asset.GetTokenUrl()
+	if tokenURI == nil {
+		return errors.New("failed to get asset token uri")
+	}
+
+	logger = logger.WithField("token_uri", *tokenURI)
+
+	m, err := h.pool.minters.Get(asset.Network)
+	if err != nil {
+		return err
+	}
+
+	tokenID := big.NewInt(asset.ID)
+	chainTokenURI, err := m.TokenURI(ctx, tokenID)
+	if err != nil {
+		return fmt.Errorf("failed to get chain token uri: %s", err)
+	}
+
+	if chainTokenURI != *tokenURI {
+		// the chain token uri is set by the minter once the update is mined
+		chainTx, err := m.UpdateTokenURI(ctx, tokenID, *tokenURI)
+		if err != nil {
+			return fmt.Errorf("failed to update chain token uri: %s", err)
+		}
+
+		logger.WithField("tx_hash", chainTx.TxHash).Info("token uri update has been sent")
+
+		return nil
+	}
+
+	err = h.pool.ds.Assets.Update(ctx, asset, datastore.AssetUpdatedFields{
+		ChainTokenURI: tokenURI,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update chain token uri: %s", err)
+	}
+
+	logger.Info("chain token uri is synced")
+
+	return nil
+}
+
+// Bury leaves the asset out of sync, which the asset response exposes.
+func (h *tokenURISyncHandler) Bury(ctx context.Context, job *model.Job) error {
+	return nil
+}
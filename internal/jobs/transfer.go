@@ -0,0 +1,173 @@
+package jobs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+
+	"github.com/AlekSi/pointer"
+	"github.com/videocoin/marketplace/internal/datastore"
+	"github.com/videocoin/marketplace/internal/drm"
+	"github.com/videocoin/marketplace/internal/model"
+	"github.com/videocoin/marketplace/internal/token"
+)
+
+const checkpointTransferSynced = "synced"
+
+// assetTransferHandler does the storage work of an asset which changed
+// hands. The order book applies the transfer to the database along with
+// the match and queues this job in the same transaction, so a rolled back
+// match leaves nothing behind in storage.
+type assetTransferHandler struct {
+	pool *Pool
+}
+
+func (h *assetTransferHandler) Handle(ctx context.Context, job *model.Job) error {
+	payload := new(model.AssetTransferJobPayload)
+	err := job.UnmarshalPayload(payload)
+	if err != nil {
+		return err
+	}
+
+	asset, err := h.pool.ds.Assets.GetByID(ctx, payload.AssetID)
+	if err != nil {
+		return err
+	}
+
+	logger := h.pool.logger.
+		WithField("job_id", job.ID).
+		WithField("asset_id", asset.ID).
+		WithField("drm_kid", payload.DRMKID)
+
+	// the transfer has been reverted or the asset has changed hands again,
+	// the job queued along with the newer key publishes it
+	if asset.DRMKID.String != payload.DRMKID {
+		logger.Info("asset content key has changed, skipping transfer")
+		return nil
+	}
+
+	account, err := h.pool.ds.Accounts.GetByID(ctx, asset.CreatedByID)
+	if err != nil {
+		return err
+	}
+	asset.CreatedBy = account
+
+	mediaItems, err := h.pool.ds.Media.ListByAssetID(ctx, asset.ID)
+	if err != nil {
+		return err
+	}
+	asset.Media = mediaItems
+
+	if payload.Reencrypt {
+		drmMeta := new(drm.Metadata)
+		err = json.Unmarshal([]byte(asset.DRMMeta), drmMeta)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal drm meta: %s", err)
+		}
+
+		for _, media := range mediaItems {
+			if media.Featured || job.IsCheckpointPassed(checkpointMediaEncrypted(media)) {
+				continue
+			}
+
+			logger.WithField("media_id", media.ID).Info("encrypting media")
+
+			assetMeta := model.NewAssetMeta(path.Base(media.GetUrl(false)), media.ContentType)
+			media.EncryptedKey = assetMeta.DestEncKey
+			err = h.pool.mp.EncryptMedia(ctx, media, drmMeta)
+			if err != nil {
+				return fmt.Errorf("failed to encrypt media #%s: %s", media.ID, err)
+			}
+
+			err = h.pool.ds.Media.Update(ctx, media, datastore.MediaUpdatedFields{
+				EncryptedKey: pointer.ToString(media.EncryptedKey),
+			})
+			if err != nil {
+				return fmt.Errorf("failed to update media encrypted key #%s: %s", media.ID, err)
+			}
+
+			err = h.pool.ds.Jobs.SaveCheckpoint(ctx, job, checkpointMediaEncrypted(media))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if !job.IsCheckpointPassed(checkpointAssetToken) {
+		err = h.pool.publishToken(ctx, asset)
+		if err != nil {
+			return err
+		}
+
+		err = h.pool.ds.Jobs.SaveCheckpoint(ctx, job, checkpointAssetToken)
+		if err != nil {
+			return err
+		}
+	}
+
+	// the metadata of the asset now carries the drm key of its owner
+	if !asset.IsEdition() && !job.IsCheckpointPassed(checkpointTransferSynced) {
+		syncJob, err := model.NewTokenURISyncJob(asset)
+		if err != nil {
+			return err
+		}
+
+		err = h.pool.ds.InTx(ctx, func(ctx context.Context) error {
+			err := h.pool.ds.Jobs.Create(ctx, syncJob)
+			if err != nil {
+				return fmt.Errorf("failed to schedule token uri sync: %s", err)
+			}
+
+			return h.pool.ds.Jobs.SaveCheckpoint(ctx, job, checkpointTransferSynced)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if asset.Status == model.AssetStatusTransferring {
+		err = h.pool.ds.Assets.MarkStatusAsTransfered(ctx, asset)
+		if err != nil {
+			return fmt.Errorf("failed to mark asset as transferred: %s", err)
+		}
+	}
+
+	logger.Info("asset transfer has been published")
+
+	return nil
+}
+
+// Bury leaves the asset transferring, which the asset response exposes.
+func (h *assetTransferHandler) Bury(ctx context.Context, job *model.Job) error {
+	return nil
+}
+
+// publishToken uploads the token metadata of the asset and points the asset
+// at it.
+func (p *Pool) publishToken(ctx context.Context, asset *model.Asset) error {
+	tokenJSON, _ := token.ToTokenJSON(asset)
+	tokenCID, err := p.storage.PushPath(
+		fmt.Sprintf("%d.json", asset.ID),
+		bytes.NewBuffer(tokenJSON),
+		true,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to upload token json to storage: %s", err)
+	}
+
+	p.logger.
+		WithField("asset_id", asset.ID).
+		WithField("token_cid", tokenCID).
+		Info("updating token url")
+
+	err = p.ds.Assets.Update(ctx, asset, datastore.AssetUpdatedFields{
+		TokenCID: pointer.ToString(tokenCID),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update asset token cid: %s", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,24 @@
+package listener
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Backend is the part of the ethereum client the listener relies on. Both
+// ethclient.Client and backends.SimulatedBackend implement it, so the listener
+// can be run against a simulated chain.
+type Backend interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error)
+}
+
+// SubscriptionBackend is implemented by clients connected over websocket or
+// ipc, the node pushes new heads and logs to them.
+type SubscriptionBackend interface {
+	SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error)
+	SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error)
+}
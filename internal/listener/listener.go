@@ -4,38 +4,72 @@ import (
 	"context"
 	"crypto/md5"
 	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"github.com/videocoin/marketplace/internal/model"
-	"github.com/videocoin/marketplace/internal/wyvern"
+	"math/big"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/gocraft/dbr/v2"
+	"github.com/videocoin/marketplace/internal/model"
+	"github.com/videocoin/marketplace/internal/wyvern"
+
 	"github.com/grpc-ecosystem/go-grpc-middleware/logging/logrus/ctxlogrus"
 	"github.com/sirupsen/logrus"
 	"github.com/videocoin/marketplace/internal/datastore"
 	"github.com/videocoin/marketplace/internal/orderbook"
 )
 
+const (
+	// blocksHistory is how many blocks below the last processed height are
+	// kept for reorg detection.
+	blocksHistory = 1024
+	// eventsBatch is how many stored events are processed per poll.
+	eventsBatch = 100
+)
+
+var (
+	ErrReorgTooDeep      = errors.New("reorg is deeper than the known blocks history")
+	ErrInvalidBlockRange = errors.New("invalid block range")
+)
+
 type ExchangeListener struct {
-	logger    *logrus.Entry
-	ds        *datastore.Datastore
-	orderbook *orderbook.OrderBook
-	url       string
-	ca        string
-	logStep   uint64
-	scanFrom  uint64
-	cli       *ethclient.Client
-	re        *EventReader
-	chainID   string
-	t         *time.Ticker
+	logger        *logrus.Entry
+	ds            *datastore.Datastore
+	orderbook     *orderbook.OrderBook
+	url           string
+	ca            string
+	nftCA         string
+	nft1155CA     string
+	network       string
+	logStep       uint64
+	scanFrom      uint64
+	confirmations uint64
+	cli           Backend
+	re            *EventReader
+	maxAttempts   int
+	chainID       string
+	pollInterval  time.Duration
+	t             *time.Ticker
+	sub           *subscription
+	done          chan struct{}
+	mu            sync.Mutex
 }
 
 func NewExchangeListener(ctx context.Context, opts ...ExchangeListenerOption) (*ExchangeListener, error) {
 	l := &ExchangeListener{
-		logger:   ctxlogrus.Extract(ctx).WithField("system", "exchange-listener"),
-		logStep:  1000,
-		scanFrom: 0,
-		t:        time.NewTicker(time.Second * 5),
+		logger:       ctxlogrus.Extract(ctx).WithField("system", "exchange-listener"),
+		logStep:      1000,
+		scanFrom:     0,
+		maxAttempts:  model.DefaultChainEventMaxAttempts,
+		pollInterval: time.Second * 5,
+		done:         make(chan struct{}),
 	}
 
 	for _, o := range opts {
@@ -44,22 +78,30 @@ func NewExchangeListener(ctx context.Context, opts ...ExchangeListenerOption) (*
 		}
 	}
 
+	l.t = time.NewTicker(l.pollInterval)
+
 	chainIDHash := md5.Sum([]byte(fmt.Sprintf("%s#%s", l.url, l.ca)))
 	l.chainID = hex.EncodeToString(chainIDHash[:])
 
-	cli, err := ethclient.Dial(l.url)
-	if err != nil {
-		return nil, err
-	}
+	if l.cli == nil {
+		cli, err := ethclient.Dial(l.url)
+		if err != nil {
+			return nil, err
+		}
 
-	l.cli = cli
+		l.cli = cli
+	}
 
 	erLogger := l.logger.WithField("system", "event-reader")
-	re, err := NewEventReader(cli, l.ca, erLogger)
+	re, err := NewEventReader(l.cli, l.ca, erLogger)
 	if err != nil {
 		return nil, err
 	}
 
+	if l.nftCA != "" {
+		re.WatchTransfers(l.nftCA)
+	}
+
 	l.re = re
 
 	_, err = l.ds.ChainMeta.GetLastHeight(ctx, l.chainID)
@@ -73,6 +115,12 @@ func NewExchangeListener(ctx context.Context, opts ...ExchangeListenerOption) (*
 	return l, nil
 }
 
+// ChainID identifies the chain and exchange contract the listener stores
+// its blocks and events for.
+func (listener *ExchangeListener) ChainID() string {
+	return listener.chainID
+}
+
 func (listener *ExchangeListener) headNumber(ctx context.Context) (uint64, error) {
 	header, err := listener.cli.HeaderByNumber(ctx, nil)
 	if err != nil {
@@ -92,6 +140,12 @@ func (listener *ExchangeListener) waitEvents(ctx context.Context) error {
 		return err
 	}
 
+	// Only blocks with enough confirmations are processed.
+	if number < listener.confirmations {
+		return nil
+	}
+	number -= listener.confirmations
+
 	var start uint64
 	if knownHeight < listener.scanFrom {
 		start = listener.scanFrom
@@ -100,7 +154,7 @@ func (listener *ExchangeListener) waitEvents(ctx context.Context) error {
 	}
 
 	if start > number {
-		start = number
+		return nil
 	}
 
 	end := start + listener.logStep
@@ -108,143 +162,680 @@ func (listener *ExchangeListener) waitEvents(ctx context.Context) error {
 		end = number
 	}
 
+	startHeader, err := listener.cli.HeaderByNumber(ctx, new(big.Int).SetUint64(start))
+	if err != nil {
+		return err
+	}
+
+	reorged, err := listener.checkParent(ctx, startHeader)
+	if err != nil {
+		return err
+	}
+	if reorged {
+		return nil
+	}
+
 	listener.logger.
 		WithField("block_start", start).
 		WithField("block_end", end).
 		Info("scanning blocks")
 
-	events, err := listener.re.GetEvents(ctx, start, end)
+	events, err := listener.fetchEvents(ctx, start, end)
 	if err != nil {
 		return err
 	}
 
-	err = listener.processEvents(events)
+	endHeader := startHeader
+	if end != start {
+		endHeader, err = listener.cli.HeaderByNumber(ctx, new(big.Int).SetUint64(end))
+		if err != nil {
+			return err
+		}
+	}
+
+	// the events are stored along with the new height, so none of them is
+	// lost when processing fails
+	return listener.ds.InTx(ctx, func(ctx context.Context) error {
+		err := listener.saveEvents(ctx, events)
+		if err != nil {
+			return err
+		}
+
+		for _, header := range []*types.Header{startHeader, endHeader} {
+			err = listener.ds.ChainBlocks.Save(ctx, &model.ChainBlock{
+				ChainID:    listener.chainID,
+				Height:     header.Number.Uint64(),
+				Hash:       header.Hash().Hex(),
+				ParentHash: header.ParentHash.Hex(),
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		err = listener.ds.ChainMeta.SaveLastHeight(ctx, listener.chainID, end)
+		if err != nil {
+			return err
+		}
+
+		if end > blocksHistory {
+			err = listener.ds.ChainBlocks.Prune(ctx, listener.chainID, end-blocksHistory)
+			if err != nil {
+				return err
+			}
+
+			err = listener.ds.ChainBlockChanges.Prune(ctx, listener.chainID, end-blocksHistory)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// fetchEvents decodes the logs pushed by the node when the subscription
+// covers the block range, and reads them from the node otherwise.
+func (listener *ExchangeListener) fetchEvents(ctx context.Context, start, end uint64) ([]*OrderEvent, error) {
+	if listener.sub != nil && listener.sub.covers(start, end) {
+		return listener.re.Decode(listener.sub.take(start, end))
+	}
+
+	return listener.re.GetEvents(ctx, start, end)
+}
+
+func (listener *ExchangeListener) saveEvents(ctx context.Context, events []*OrderEvent) error {
+	for _, event := range events {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+
+		err = listener.ds.ChainEvents.Save(ctx, &model.ChainEvent{
+			ChainID:   listener.chainID,
+			Height:    event.BlockNumber,
+			BlockHash: event.BlockHash.Hex(),
+			TxHash:    event.TxHash.Hex(),
+			LogIndex:  event.LogIndex,
+			Type:      event.TypeName(),
+			Payload:   string(payload),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkParent makes sure the block about to be scanned builds on the last
+// processed one. On a parent hash mismatch the changes made for the orphaned
+// blocks are reverted and the scan resumes from the fork point.
+func (listener *ExchangeListener) checkParent(ctx context.Context, header *types.Header) (bool, error) {
+	height := header.Number.Uint64()
+	if height == 0 {
+		return false, nil
+	}
+
+	parent, err := listener.ds.ChainBlocks.Get(ctx, listener.chainID, height-1)
 	if err != nil {
-		listener.logger.WithError(err).Error("failed to process events")
-		//return err
+		if err == datastore.ErrChainBlockNotFound {
+			return false, nil
+		}
+		return false, err
 	}
 
-	err = listener.ds.ChainMeta.SaveLastHeight(ctx, listener.chainID, end)
+	if parent.Hash == header.ParentHash.Hex() {
+		return false, nil
+	}
+
+	listener.logger.
+		WithField("height", height).
+		WithField("parent_hash", header.ParentHash.Hex()).
+		WithField("known_parent_hash", parent.Hash).
+		Warning("chain reorg detected")
+
+	forkHeight, err := listener.findFork(ctx, height-1)
+	if err != nil {
+		return false, err
+	}
+
+	err = listener.rollback(ctx, forkHeight)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// findFork returns the highest known block which is still part of the canonical chain.
+func (listener *ExchangeListener) findFork(ctx context.Context, height uint64) (uint64, error) {
+	blocks, err := listener.ds.ChainBlocks.ListBelow(ctx, listener.chainID, height)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, block := range blocks {
+		header, err := listener.cli.HeaderByNumber(ctx, new(big.Int).SetUint64(block.Height))
+		if err != nil {
+			return 0, err
+		}
+
+		if header.Hash().Hex() == block.Hash {
+			return block.Height, nil
+		}
+	}
+
+	return 0, ErrReorgTooDeep
+}
+
+// rollback reverts the changes made for the blocks above the fork point and
+// forgets the blocks and their events, all or nothing: a failure leaves the
+// orphaned blocks to be rolled back on the next poll.
+func (listener *ExchangeListener) rollback(ctx context.Context, forkHeight uint64) error {
+	logger := listener.logger.WithField("fork_height", forkHeight)
+	logger.Warning("rolling back orphaned blocks")
+
+	count := 0
+	err := listener.ds.InTx(ctx, func(ctx context.Context) error {
+		changes, err := listener.ds.ChainBlockChanges.ListAbove(ctx, listener.chainID, forkHeight)
+		if err != nil {
+			return err
+		}
+
+		for _, change := range changes {
+			if listener.orderbook != nil {
+				err = listener.orderbook.Revert(ctx, change)
+				if err != nil {
+					return err
+				}
+			}
+
+			err = listener.ds.ChainBlockChanges.Delete(ctx, change)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = listener.ds.ChainEvents.DeleteAbove(ctx, listener.chainID, forkHeight)
+		if err != nil {
+			return err
+		}
+
+		err = listener.ds.ChainBlocks.DeleteAbove(ctx, listener.chainID, forkHeight)
+		if err != nil {
+			return err
+		}
+
+		err = listener.ds.ChainMeta.SaveLastHeight(ctx, listener.chainID, forkHeight)
+		if err != nil {
+			return err
+		}
+
+		count = len(changes)
+		return nil
+	})
 	if err != nil {
 		return err
 	}
 
+	logger.WithField("changes", count).Info("orphaned blocks have been rolled back")
+
 	return nil
 }
 
-func (listener *ExchangeListener) processEvents(events []*OrderEvent) error {
-	listener.logger.Debugf("%+v\n", events)
-
+// processPending applies the stored events which have not been processed
+// yet, in the order they were emitted. Each event is applied, recorded for
+// rollback and marked as processed in one transaction, so a crash never
+// leaves an event applied but pending. A failed event stops the batch, so
+// the events after it are not applied out of order, until it has used all
+// of its attempts.
+func (listener *ExchangeListener) processPending(ctx context.Context) error {
 	if listener.orderbook == nil {
 		return nil
 	}
 
-	for _, event := range events {
-		ctx := context.Background()
+	events, err := listener.ds.ChainEvents.ListPending(ctx, listener.chainID, eventsBatch)
+	if err != nil {
+		return err
+	}
 
-		var order *model.Order
-		var orderHashErr error
-		orderSignHashFound := false
-		hashes := []string{event.Hash.String(), event.SellHash.String(), event.BuyHash.String()}
+	for _, chainEvent := range events {
+		logger := listener.logger.
+			WithField("chain_event_id", chainEvent.ID).
+			WithField("type", chainEvent.Type).
+			WithField("tx_hash", chainEvent.TxHash).
+			WithField("log_index", chainEvent.LogIndex)
+
+		event := new(OrderEvent)
+		err = json.Unmarshal([]byte(chainEvent.Payload), event)
+		if err == nil {
+			err = listener.ds.InTx(ctx, func(ctx context.Context) error {
+				err := listener.processEvent(ctx, event)
+				if err != nil {
+					return err
+				}
+
+				return listener.ds.ChainEvents.MarkStatusAsProcessed(ctx, chainEvent)
+			})
+		}
+		if err != nil {
+			logger.WithError(err).Error("failed to process event")
 
-		for _, orderHash := range hashes {
-			if orderHash == wyvern.NullAddress {
-				continue
+			markErr := listener.ds.ChainEvents.MarkStatusAsFailed(ctx, chainEvent, err, listener.maxAttempts)
+			if markErr != nil {
+				return markErr
 			}
 
-			order, orderHashErr = listener.orderbook.GetBySignHash(ctx, orderHash)
-			if orderHashErr == nil {
-				orderSignHashFound = true
-				break
+			if chainEvent.Status == model.ChainEventStatusPending {
+				return nil
 			}
 
-			listener.logger.
-				WithField("order_hash", orderHash).
-				WithError(orderHashErr).
-				Error("failed to get order by hash")
+			logger.Warning("event has used all attempts")
+			continue
 		}
+	}
 
-		if !orderSignHashFound {
-			listener.logger.
-				WithField("order_hashes", hashes).
-				Error("failed to get order by hash")
+	return nil
+}
+
+// Replay reads the logs of the block range from the chain again and makes
+// their events pending, they are processed on the next poll. Blocks which
+// haven't been scanned yet are left to the regular scan. It returns the
+// number of events to be processed again.
+func (listener *ExchangeListener) Replay(ctx context.Context, from, to uint64) (int64, error) {
+	listener.mu.Lock()
+	defer listener.mu.Unlock()
+
+	knownHeight, err := listener.ds.ChainMeta.GetLastHeight(ctx, listener.chainID)
+	if err != nil {
+		return 0, err
+	}
+
+	if to > knownHeight {
+		to = knownHeight
+	}
+	if from > to {
+		return 0, ErrInvalidBlockRange
+	}
+
+	listener.logger.
+		WithField("block_start", from).
+		WithField("block_end", to).
+		Info("replaying blocks")
+
+	count := int64(0)
+	for start := from; start <= to; {
+		end := start + listener.logStep
+		if end > to {
+			end = to
+		}
+
+		events, err := listener.re.GetEvents(ctx, start, end)
+		if err != nil {
+			return 0, err
+		}
+
+		err = listener.ds.InTx(ctx, func(ctx context.Context) error {
+			err := listener.saveEvents(ctx, events)
+			if err != nil {
+				return err
+			}
+
+			n, err := listener.ds.ChainEvents.Reset(ctx, listener.chainID, start, end)
+			if err != nil {
+				return err
+			}
+			count += n
+
+			return nil
+		})
+		if err != nil {
+			return 0, err
+		}
+
+		if end == to {
+			break
+		}
+		start = end + 1
+	}
+
+	return count, nil
+}
+
+func (listener *ExchangeListener) processEvent(ctx context.Context, event *OrderEvent) error {
+	if event.Type == TokenTransferred {
+		return listener.processTransfer(ctx, event)
+	}
+
+	var order *model.Order
+	var orderHashErr error
+	orderSignHashFound := false
+	hashes := []string{event.Hash.String(), event.SellHash.String(), event.BuyHash.String()}
+
+	for _, orderHash := range hashes {
+		if orderHash == wyvern.NullAddress {
 			continue
 		}
 
-		switch event.Type {
-		case OrderApproved:
-			{
-				listener.logger.
-					WithField("hash", event.Hash.String()).
-					WithField("event", "OrderApproved").
-					Info("event received")
-				return listener.orderbook.Approve(ctx, order)
-			}
-		case OrderCancelled:
-			{
-				listener.logger.
-					WithField("hash", event.Hash.String()).
-					WithField("event", "OrderCancelled").
-					Info("event received")
-				return listener.orderbook.Cancel(ctx, order)
-			}
-		case OrdersMatched:
-			{
-				logger := listener.logger.
-					WithField("hash", event.Hash.String()).
-					WithField("sell_hash", event.SellHash.String()).
-					WithField("buy_hash", event.BuyHash.String()).
-					WithField("maker", event.Maker.String()).
-					WithField("taker", event.Taker.String()).
-					WithField("maker", event.Maker.String()).
-					WithField("event", "OrdersMatched")
-
-				logger.Info("event received")
-
-				var newOwner *model.Account
-
-				if order.Side == wyvern.Sell {
-					taker, err := listener.ds.Accounts.GetByAddress(ctx, event.Taker.String())
-					if err != nil {
-						logger.WithError(err).Error("failed to get new owner")
-						return err
-					}
-					newOwner = taker
-				}
+		order, orderHashErr = listener.orderbook.GetBySignHash(ctx, orderHash)
+		if orderHashErr == nil {
+			orderSignHashFound = true
+			break
+		}
 
-				if order.Side == wyvern.Buy {
-					maker, err := listener.ds.Accounts.GetByAddress(ctx, event.Maker.String())
-					if err != nil {
-						logger.WithError(err).Error("failed to get new owner")
-						return err
-					}
-					newOwner = maker
-				}
+		listener.logger.
+			WithField("order_hash", orderHash).
+			WithError(orderHashErr).
+			Error("failed to get order by hash")
+	}
+
+	if !orderSignHashFound {
+		if event.Type == OrderApproved && event.Order != nil {
+			return listener.indexApprovedOrder(ctx, event)
+		}
+
+		listener.logger.
+			WithField("order_hashes", hashes).
+			Error("failed to get order by hash")
+		return nil
+	}
+
+	change, err := listener.orderbook.Snapshot(ctx, order)
+	if err != nil {
+		return err
+	}
+
+	err = listener.applyEvent(ctx, event, order)
+	if err != nil {
+		return err
+	}
+
+	change.ChainID = listener.chainID
+	change.Height = event.BlockNumber
+	change.BlockHash = event.BlockHash.Hex()
+
+	return listener.ds.ChainBlockChanges.Create(ctx, change)
+}
+
+// indexApprovedOrder stores an order approved directly on the exchange
+// contract. An empty order status in the change means the order did not
+// exist before the block and is removed on rollback. Orders which can never
+// be indexed are skipped, any other error is returned so the event is
+// retried.
+func (listener *ExchangeListener) indexApprovedOrder(ctx context.Context, event *OrderEvent) error {
+	logger := listener.logger.
+		WithField("hash", event.Hash.String()).
+		WithField("target", event.Order.Target).
+		WithField("event", "OrderApproved")
+
+	logger.Info("event received")
+
+	schema, err := event.Order.Schema()
+	if err != nil {
+		logger.WithError(err).Warning("unknown order calldata")
+		return nil
+	}
+
+	target := listener.nftCA
+	if schema == wyvern.SchemaERC1155 {
+		target = listener.nft1155CA
+	}
+	if target == "" || !strings.EqualFold(event.Order.Target, target) {
+		logger.Warning("order targets another contract")
+		return nil
+	}
+
+	order, err := listener.orderbook.Index(ctx, listener.network, event.Hash, event.Order)
+	if err != nil {
+		switch {
+		case err == orderbook.ErrApprovedOrderHashMismatch,
+			err == orderbook.ErrApprovedOrderForeignNetwork,
+			err == orderbook.ErrApprovedOrderForeignTarget,
+			err == datastore.ErrAssetNotFound,
+			errors.Is(err, wyvern.ErrInvalidOrderField):
+			logger.WithError(err).Warning("approved order has been skipped")
+			return nil
+		}
 
-				return listener.orderbook.Process(ctx, order, newOwner)
+		logger.WithError(err).Error("failed to index approved order")
+		return err
+	}
+
+	return listener.ds.ChainBlockChanges.Create(ctx, &model.ChainBlockChange{
+		ChainID:   listener.chainID,
+		Height:    event.BlockNumber,
+		BlockHash: event.BlockHash.Hex(),
+		OrderID:   dbr.NewNullInt64(order.ID),
+	})
+}
+
+// processTransfer moves an asset to the recipient of a transfer made outside
+// of the exchange.
+func (listener *ExchangeListener) processTransfer(ctx context.Context, event *OrderEvent) error {
+	logger := listener.logger.
+		WithField("token_id", event.TokenID.String()).
+		WithField("from", event.From.String()).
+		WithField("to", event.To.String()).
+		WithField("tx_hash", event.TxHash.String()).
+		WithField("event", "Transfer")
+
+	logger.Info("event received")
+
+	if !event.TokenID.IsInt64() {
+		logger.Warning("unknown token")
+		return nil
+	}
+
+	if event.To == (common.Address{}) {
+		logger.Warning("token has been burned")
+		return nil
+	}
+
+	asset, err := listener.ds.Assets.GetByID(ctx, event.TokenID.Int64())
+	if err != nil {
+		if err == datastore.ErrAssetNotFound {
+			logger.Warning("unknown token")
+			return nil
+		}
+		return err
+	}
+
+	if listener.network != "" && asset.Network != listener.network {
+		logger.
+			WithField("network", asset.Network).
+			Warning("token has been minted on another network")
+		return nil
+	}
+
+	change, err := listener.orderbook.SnapshotAsset(ctx, asset)
+	if err != nil {
+		return err
+	}
+
+	err = listener.orderbook.ProcessTransfer(ctx, asset, event.To.String())
+	if err != nil {
+		logger.WithError(err).Error("failed to process transfer")
+		return err
+	}
+
+	change.ChainID = listener.chainID
+	change.Height = event.BlockNumber
+	change.BlockHash = event.BlockHash.Hex()
+
+	return listener.ds.ChainBlockChanges.Create(ctx, change)
+}
+
+func (listener *ExchangeListener) applyEvent(ctx context.Context, event *OrderEvent, order *model.Order) error {
+	switch event.Type {
+	case OrderApproved:
+		{
+			listener.logger.
+				WithField("hash", event.Hash.String()).
+				WithField("event", "OrderApproved").
+				Info("event received")
+			if event.Order != nil {
+				return listener.orderbook.Reconcile(ctx, order, event.Order)
 			}
+			return listener.orderbook.Approve(ctx, order)
+		}
+	case OrderCancelled:
+		{
+			listener.logger.
+				WithField("hash", event.Hash.String()).
+				WithField("event", "OrderCancelled").
+				Info("event received")
+			return listener.orderbook.Cancel(ctx, order)
+		}
+	case OrdersMatched:
+		{
+			logger := listener.logger.
+				WithField("hash", event.Hash.String()).
+				WithField("sell_hash", event.SellHash.String()).
+				WithField("buy_hash", event.BuyHash.String()).
+				WithField("maker", event.Maker.String()).
+				WithField("taker", event.Taker.String()).
+				WithField("maker", event.Maker.String()).
+				WithField("event", "OrdersMatched")
+
+			logger.Info("event received")
+
+			buyerAddress, sellerAddress := event.Taker.String(), event.Maker.String()
+			counterpartHash := event.BuyHash.String()
+			if order.Side == wyvern.Buy {
+				buyerAddress, sellerAddress = event.Maker.String(), event.Taker.String()
+				counterpartHash = event.SellHash.String()
+			}
+
+			buyer, err := listener.ds.Accounts.GetByAddress(ctx, buyerAddress)
+			if err != nil {
+				logger.WithError(err).Error("failed to get new owner")
+				return err
+			}
+
+			seller, err := listener.ds.Accounts.GetByAddress(ctx, sellerAddress)
+			if err != nil && err != datastore.ErrAccountNotFound {
+				logger.WithError(err).Error("failed to get previous owner")
+				return err
+			}
+
+			fill := &orderbook.Fill{
+				Buyer:     buyer,
+				Seller:    seller,
+				Quantity:  order.RemainingQuantity(),
+				Price:     event.Price,
+				BlockHash: event.BlockHash.Hex(),
+				TxHash:    event.TxHash.Hex(),
+				LogIndex:  event.LogIndex,
+			}
+
+			counterpart, err := listener.orderbook.GetBySignHash(ctx, counterpartHash)
+			if err == nil {
+				fill.Counterpart = counterpart
+				remaining := counterpart.RemainingQuantity()
+				if remaining > 0 && remaining < fill.Quantity {
+					fill.Quantity = remaining
+				}
+			}
+
+			return listener.orderbook.Process(ctx, order, fill)
 		}
 	}
 
 	return nil
 }
 
+// Start processes new blocks as the node pushes their heads when it
+// supports subscriptions, and polls on every tick otherwise. Whenever the
+// subscription drops, polling takes over until it is restored, so the
+// blocks in between are read with ranged queries.
 func (listener *ExchangeListener) Start(errCh chan error) {
 	listener.logger.Info("starting exchange listener")
 
-	for range listener.t.C {
-		listener.logger.Info("getting chain events")
+	ctx := context.Background()
+	subscriptions := true
+
+	for {
+		if subscriptions && listener.sub == nil {
+			sub, err := listener.subscribe(ctx)
+			if err == rpc.ErrNotificationsUnsupported {
+				listener.logger.Info("subscriptions are not supported, polling for events")
+				subscriptions = false
+			} else if err != nil {
+				listener.logger.WithError(err).Warning("failed to subscribe, polling for events")
+			} else {
+				listener.logger.WithField("since", sub.since).Info("subscribed to chain events")
+				listener.sub = sub
+			}
+		}
 
-		err := listener.waitEvents(context.Background())
+		var (
+			tick    <-chan time.Time
+			heads   <-chan *types.Header
+			logs    <-chan types.Log
+			headErr <-chan error
+			logErr  <-chan error
+		)
+		if listener.sub == nil {
+			tick = listener.t.C
+		} else {
+			heads = listener.sub.heads
+			logs = listener.sub.logs
+			headErr = listener.sub.headSub.Err()
+			logErr = listener.sub.logSub.Err()
+		}
+
+		select {
+		case <-listener.done:
+			if listener.sub != nil {
+				listener.sub.unsubscribe()
+				listener.sub = nil
+			}
+			return
+		case log := <-logs:
+			listener.sub.add(log)
+			continue
+		case head := <-heads:
+			listener.sub.head = head.Number.Uint64()
+		case <-tick:
+		case err := <-headErr:
+			listener.dropSubscription(err)
+		case err := <-logErr:
+			listener.dropSubscription(err)
+		}
+
+		listener.logger.Debug("getting chain events")
+
+		err := listener.Poll(ctx)
 		if err != nil {
 			listener.logger.WithError(err).Error("failed to process events")
-			continue
 		}
 	}
 }
 
+func (listener *ExchangeListener) dropSubscription(err error) {
+	listener.logger.WithError(err).Warning("subscription has been dropped, polling for events")
+	listener.sub.unsubscribe()
+	listener.sub = nil
+}
+
+// Poll processes the confirmed blocks which have not been seen yet. Start
+// calls it on every tick, a simulated chain can be synced with it on demand.
+func (listener *ExchangeListener) Poll(ctx context.Context) error {
+	listener.mu.Lock()
+	defer listener.mu.Unlock()
+
+	err := listener.waitEvents(ctx)
+	if err != nil {
+		return err
+	}
+
+	return listener.processPending(ctx)
+}
+
 func (listener *ExchangeListener) Stop() error {
 	listener.logger.Info("stopping exchange listener")
 	listener.t.Stop()
+	close(listener.done)
 	return nil
 }
@@ -0,0 +1,136 @@
+package listener_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/videocoin/marketplace/internal/datastore"
+	"github.com/videocoin/marketplace/internal/datastore/dbtest"
+	"github.com/videocoin/marketplace/internal/listener"
+	"github.com/videocoin/marketplace/internal/simchain"
+)
+
+func TestReorgRollsBackOrphanedBlocks(t *testing.T) {
+	ds := dbtest.Open(t)
+	ctx := context.Background()
+
+	owner, key, err := simchain.NewTransactor()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	backend, err := simchain.NewSimulatedBackend(core.GenesisAlloc{
+		owner.From: {Balance: new(big.Int).Lsh(big.NewInt(1), 100)},
+	}, 30000000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer backend.Close()
+
+	chain, err := simchain.Deploy(backend, owner, &simchain.DeployConfig{Name: "Test", Symbol: "TST"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	forkHeader, err := backend.HeaderByNumber(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sell := chain.SellOrder(owner.From, big.NewInt(1), big.NewInt(1e18))
+	if err := simchain.SignOrder(sell, key); err != nil {
+		t.Fatal(err)
+	}
+	approveTx, err := chain.ApproveOrder(owner, sell)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := chain.Listener(ctx, listener.WithDatastore(ds))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := l.Poll(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	orphanHeight := forkHeader.Number.Uint64() + 1
+	events, err := ds.ChainEvents.ListPending(ctx, l.ChainID(), 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) == 0 {
+		t.Fatal("the approval has not been stored")
+	}
+	for _, event := range events {
+		if event.Height != orphanHeight {
+			t.Fatalf("event stored at height %d, want %d", event.Height, orphanHeight)
+		}
+	}
+
+	// the fork outgrows the block of the approval
+	if err := backend.Fork(forkHeader.Hash()); err != nil {
+		t.Fatal(err)
+	}
+	backend.Commit()
+	backend.Commit()
+
+	if _, err := backend.TransactionReceipt(ctx, approveTx.Hash()); err == nil {
+		t.Fatal("the approval is still part of the canonical chain")
+	}
+
+	// the first poll detects the reorg and rolls back
+	if err := l.Poll(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	height, err := ds.ChainMeta.GetLastHeight(ctx, l.ChainID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if height >= orphanHeight {
+		t.Fatalf("last height = %d after the rollback, want below %d", height, orphanHeight)
+	}
+
+	events, err = ds.ChainEvents.ListPending(ctx, l.ChainID(), 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 0 {
+		t.Fatalf("%d events of orphaned blocks are left", len(events))
+	}
+
+	_, err = ds.ChainBlocks.Get(ctx, l.ChainID(), orphanHeight)
+	if err != datastore.ErrChainBlockNotFound {
+		t.Fatalf("orphaned block is still known: %v", err)
+	}
+
+	// the next one scans the new canonical blocks
+	if err := l.Poll(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	head, err := backend.HeaderByNumber(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	block, err := ds.ChainBlocks.Get(ctx, l.ChainID(), head.Number.Uint64())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if block.Hash != head.Hash().Hex() {
+		t.Errorf("head block hash = %s, want %s", block.Hash, head.Hash().Hex())
+	}
+
+	events, err = ds.ChainEvents.ListPending(ctx, l.ChainID(), 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 0 {
+		t.Errorf("%d events stored for the fork, which has none", len(events))
+	}
+}
@@ -0,0 +1,353 @@
+package listener_test
+
+import (
+	"context"
+	"encoding/base64"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AlekSi/pointer"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/gocraft/dbr/v2"
+	"github.com/twystd/tweetnacl-go/tweetnacl"
+	"github.com/videocoin/marketplace/internal/datastore"
+	"github.com/videocoin/marketplace/internal/datastore/dbtest"
+	"github.com/videocoin/marketplace/internal/listener"
+	"github.com/videocoin/marketplace/internal/minter"
+	"github.com/videocoin/marketplace/internal/model"
+	"github.com/videocoin/marketplace/internal/orderbook"
+	"github.com/videocoin/marketplace/internal/simchain"
+	"github.com/videocoin/marketplace/internal/wyvern"
+)
+
+// match is an order of the seller posted the way the api stores it and
+// matched on the exchange by the buyer.
+type match struct {
+	backend *simchain.SimulatedBackend
+	chain   *simchain.Chain
+	seller  *model.Account
+	buyer   *model.Account
+	asset   *model.Asset
+	order   *model.Order
+	matchTx *types.Transaction
+}
+
+// newMatch mints a token, posts a signed sell order of it and matches the
+// order on the exchange.
+func newMatch(ctx context.Context, t *testing.T, ds *datastore.Datastore) *match {
+	owner, _, err := simchain.NewTransactor()
+	if err != nil {
+		t.Fatal(err)
+	}
+	seller, sellerKey, err := simchain.NewTransactor()
+	if err != nil {
+		t.Fatal(err)
+	}
+	buyer, _, err := simchain.NewTransactor()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	funds := new(big.Int).Lsh(big.NewInt(1), 100)
+	backend, err := simchain.NewSimulatedBackend(core.GenesisAlloc{
+		owner.From:  {Balance: funds},
+		seller.From: {Balance: funds},
+		buyer.From:  {Balance: funds},
+	}, 30000000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(backend.Close)
+
+	chain, err := simchain.Deploy(backend, owner, &simchain.DeployConfig{Name: "Test", Symbol: "TST"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := &match{
+		backend: backend,
+		chain:   chain,
+		seller:  newAccount(ctx, t, ds, seller.From),
+		buyer:   newAccount(ctx, t, ds, buyer.From),
+	}
+
+	m.asset = &model.Asset{
+		CreatedByID:     m.seller.ID,
+		OwnerID:         m.seller.ID,
+		Status:          model.AssetStatusReady,
+		Name:            dbr.NewNullString("Test"),
+		ContractAddress: dbr.NewNullString(strings.ToLower(chain.NFT721Addr.Hex())),
+		OnSale:          true,
+		Price:           1,
+	}
+	if err := ds.Assets.Create(ctx, m.asset); err != nil {
+		t.Fatal(err)
+	}
+	err = ds.AssetHolders.Replace(ctx, m.asset.ID, []*model.AssetHolder{
+		{AssetID: m.asset.ID, AccountID: m.seller.ID, Balance: 1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tokenID := big.NewInt(m.asset.ID)
+	mnt, err := chain.Minter(minter.WithDatastore(ds))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mnt.Mint(ctx, seller.From, tokenID, "ipfs://token"); err != nil {
+		t.Fatal(err)
+	}
+	if err := chain.ApproveProxy(seller); err != nil {
+		t.Fatal(err)
+	}
+
+	price := big.NewInt(1e18)
+	sell := chain.SellOrder(seller.From, tokenID, price)
+	if err := simchain.SignOrder(sell, sellerKey); err != nil {
+		t.Fatal(err)
+	}
+	sell.Metadata = &wyvern.ExchangeMetadata{
+		Asset: &wyvern.WyvernNFTAsset{
+			ID:       tokenID.String(),
+			Address:  strings.ToLower(chain.NFT721Addr.Hex()),
+			Quantity: "1",
+		},
+		Schema: wyvern.SchemaERC721,
+	}
+
+	m.order = &model.Order{
+		CreatedByID: m.seller.ID,
+		MakerID:     &m.seller.ID,
+		Hash:        sell.Hash,
+		WyvernOrder: sell,
+		Network:     m.asset.Network,
+	}
+	if err := ds.Orders.Create(ctx, m.order); err != nil {
+		t.Fatal(err)
+	}
+
+	buy, err := chain.BuyOrder(sell, buyer.From)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.matchTx, err = chain.AtomicMatch(buyer, buy, sell)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tokenOwner, err := chain.NFT721.OwnerOf(&bind.CallOpts{}, tokenID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tokenOwner != buyer.From {
+		t.Fatalf("token owner = %s, want the buyer", tokenOwner.Hex())
+	}
+
+	return m
+}
+
+func newMatchListener(ctx context.Context, t *testing.T, ds *datastore.Datastore, chain *simchain.Chain) *listener.ExchangeListener {
+	book, err := orderbook.NewOderBook(ctx, orderbook.WithDatastore(ds))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := chain.Listener(ctx, listener.WithDatastore(ds), listener.WithOrderbook(book))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return l
+}
+
+// leaseTransferJob returns the payload of the next asset transfer job.
+func leaseTransferJob(ctx context.Context, t *testing.T, ds *datastore.Datastore) *model.AssetTransferJobPayload {
+	job, err := ds.Jobs.Lease(ctx, "test", []model.JobType{model.JobTypeAssetTransfer}, time.Minute)
+	if err != nil {
+		t.Fatalf("asset transfer job: %s", err)
+	}
+
+	payload := new(model.AssetTransferJobPayload)
+	if err := job.UnmarshalPayload(payload); err != nil {
+		t.Fatal(err)
+	}
+
+	return payload
+}
+
+// TestMatchedOrderTransfersAsset checks that the listener hands a match
+// over to the order book, which transfers the asset and queues its
+// re-encryption.
+func TestMatchedOrderTransfersAsset(t *testing.T) {
+	ds := dbtest.Open(t)
+	ctx := context.Background()
+
+	m := newMatch(ctx, t, ds)
+
+	l := newMatchListener(ctx, t, ds, m.chain)
+	if err := l.Poll(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	order, err := ds.Orders.GetByHash(ctx, m.order.Hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !order.IsProcessed() {
+		t.Errorf("order status = %s, want processed", order.Status)
+	}
+
+	asset, err := ds.Assets.GetByID(ctx, m.asset.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if asset.OwnerID != m.buyer.ID {
+		t.Errorf("asset owner = %d, want the buyer %d", asset.OwnerID, m.buyer.ID)
+	}
+	if asset.OnSale {
+		t.Error("sold asset is still on sale")
+	}
+	if asset.Status != model.AssetStatusTransferring {
+		t.Errorf("asset status = %s, want transferring until the media is published", asset.Status)
+	}
+
+	balance, err := ds.AssetHolders.GetBalance(ctx, asset.ID, m.buyer.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if balance != 1 {
+		t.Errorf("buyer balance = %d, want 1", balance)
+	}
+
+	payload := leaseTransferJob(ctx, t, ds)
+	if payload.AssetID != asset.ID || payload.DRMKID != asset.DRMKID.String || !payload.Reencrypt {
+		t.Errorf("asset transfer job = %+v, want the re-encryption of asset %d with kid %s", payload, asset.ID, asset.DRMKID.String)
+	}
+
+	events, err := ds.ChainEvents.ListPending(ctx, l.ChainID(), 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 0 {
+		t.Errorf("%d events are left pending", len(events))
+	}
+}
+
+// TestReorgRevertsMatchedOrder applies a match, orphans its block and
+// checks that the rollback gives the asset back to the seller.
+func TestReorgRevertsMatchedOrder(t *testing.T) {
+	ds := dbtest.Open(t)
+	ctx := context.Background()
+
+	m := newMatch(ctx, t, ds)
+	backend := m.backend
+
+	receipt, err := backend.TransactionReceipt(ctx, m.matchTx.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	forkHeader, err := backend.HeaderByNumber(ctx, new(big.Int).Sub(receipt.BlockNumber, big.NewInt(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l := newMatchListener(ctx, t, ds, m.chain)
+	if err := l.Poll(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	asset, err := ds.Assets.GetByID(ctx, m.asset.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if asset.OwnerID != m.buyer.ID {
+		t.Fatalf("asset owner = %d before the reorg, want the buyer %d", asset.OwnerID, m.buyer.ID)
+	}
+	leaseTransferJob(ctx, t, ds)
+
+	// the fork outgrows the block of the match
+	if err := backend.Fork(forkHeader.Hash()); err != nil {
+		t.Fatal(err)
+	}
+	backend.Commit()
+	backend.Commit()
+
+	if _, err := backend.TransactionReceipt(ctx, m.matchTx.Hash()); err == nil {
+		t.Fatal("the match is still part of the canonical chain")
+	}
+
+	if err := l.Poll(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	order, err := ds.Orders.GetByHash(ctx, m.order.Hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order.IsProcessed() {
+		t.Error("order of the orphaned match is still processed")
+	}
+
+	asset, err = ds.Assets.GetByID(ctx, m.asset.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if asset.OwnerID != m.seller.ID {
+		t.Errorf("asset owner = %d after the reorg, want the seller %d", asset.OwnerID, m.seller.ID)
+	}
+	if !asset.OnSale {
+		t.Error("asset is not on sale again")
+	}
+	if asset.Status != m.asset.Status {
+		t.Errorf("asset status = %s, want %s", asset.Status, m.asset.Status)
+	}
+	if asset.DRMKID.String != m.asset.DRMKID.String {
+		t.Errorf("asset kid = %s, want %s", asset.DRMKID.String, m.asset.DRMKID.String)
+	}
+
+	holders, err := ds.AssetHolders.ListByAssetID(ctx, asset.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(holders) != 1 || holders[0].AccountID != m.seller.ID || holders[0].Balance != 1 {
+		t.Errorf("asset holders = %+v, want the seller alone", holders)
+	}
+
+	entries, err := ds.Ledger.Count(ctx, &datastore.LedgerFilter{AssetID: pointer.ToInt64(asset.ID)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entries != 0 {
+		t.Errorf("%d ledger entries of the orphaned match are left", entries)
+	}
+
+	// the metadata is published again for the seller, whose media is
+	// still stored
+	payload := leaseTransferJob(ctx, t, ds)
+	if payload.AssetID != asset.ID || payload.DRMKID != asset.DRMKID.String || payload.Reencrypt {
+		t.Errorf("asset transfer job = %+v, want the metadata of asset %d published again", payload, asset.ID)
+	}
+}
+
+func newAccount(ctx context.Context, t *testing.T, ds *datastore.Datastore, address common.Address) *model.Account {
+	keyPair, err := tweetnacl.CryptoBoxKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	account := &model.Account{
+		Address:             strings.ToLower(address.Hex()),
+		EncryptionPublicKey: dbr.NewNullString(base64.StdEncoding.EncodeToString(keyPair.PublicKey)),
+	}
+	if err := ds.Accounts.Create(ctx, account); err != nil {
+		t.Fatal(err)
+	}
+
+	return account
+}
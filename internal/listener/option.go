@@ -1,6 +1,8 @@
 package listener
 
 import (
+	"time"
+
 	"github.com/sirupsen/logrus"
 	"github.com/videocoin/marketplace/internal/datastore"
 	"github.com/videocoin/marketplace/internal/orderbook"
@@ -43,6 +45,33 @@ func WithContractAddress(ca string) ExchangeListenerOption {
 	}
 }
 
+// WithNFTContractAddress enables tracking of the token transfers made
+// outside of the exchange.
+func WithNFTContractAddress(ca string) ExchangeListenerOption {
+	return func(l *ExchangeListener) error {
+		l.nftCA = ca
+		return nil
+	}
+}
+
+// WithNFT1155ContractAddress sets the ERC1155 contract the orders approved
+// directly on the exchange may target.
+func WithNFT1155ContractAddress(ca string) ExchangeListenerOption {
+	return func(l *ExchangeListener) error {
+		l.nft1155CA = ca
+		return nil
+	}
+}
+
+// WithNetwork sets the network the listener watches, transfers of tokens
+// minted on other networks are ignored.
+func WithNetwork(name string) ExchangeListenerOption {
+	return func(l *ExchangeListener) error {
+		l.network = name
+		return nil
+	}
+}
+
 func WithLogStep(step uint64) ExchangeListenerOption {
 	return func(l *ExchangeListener) error {
 		l.logStep = step
@@ -50,9 +79,41 @@ func WithLogStep(step uint64) ExchangeListenerOption {
 	}
 }
 
+// WithPollInterval sets how often the node is polled when it doesn't push
+// new heads.
+func WithPollInterval(interval time.Duration) ExchangeListenerOption {
+	return func(l *ExchangeListener) error {
+		l.pollInterval = interval
+		return nil
+	}
+}
+
 func WithScanFrom(scanFrom uint64) ExchangeListenerOption {
 	return func(l *ExchangeListener) error {
 		l.scanFrom = scanFrom
 		return nil
 	}
 }
+
+func WithConfirmations(confirmations uint64) ExchangeListenerOption {
+	return func(l *ExchangeListener) error {
+		l.confirmations = confirmations
+		return nil
+	}
+}
+
+// WithMaxEventAttempts sets how many times a stored event is processed
+// before it is marked as failed.
+func WithMaxEventAttempts(attempts int) ExchangeListenerOption {
+	return func(l *ExchangeListener) error {
+		l.maxAttempts = attempts
+		return nil
+	}
+}
+
+func WithBackend(backend Backend) ExchangeListenerOption {
+	return func(l *ExchangeListener) error {
+		l.cli = backend
+		return nil
+	}
+}
@@ -9,18 +9,20 @@ import (
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/videocoin/marketplace/internal/wyvern"
 )
 
 type EventReader struct {
 	ca     []common.Address
-	cli    *ethclient.Client
+	nft    *common.Address
+	cli    Backend
 	pa     *Parser
 	logger *logrus.Entry
 }
 
-func NewEventReader(cli *ethclient.Client, contractAddress string, logger *logrus.Entry) (*EventReader, error) {
+func NewEventReader(cli Backend, contractAddress string, logger *logrus.Entry) (*EventReader, error) {
 	ca := common.HexToAddress(contractAddress)
 
 	pa := NewParser()
@@ -36,68 +38,114 @@ func NewEventReader(cli *ethclient.Client, contractAddress string, logger *logru
 	}, nil
 }
 
+// WatchTransfers makes the reader also return the transfers of the ERC721
+// contract which were not made by the exchange.
+func (reader *EventReader) WatchTransfers(nftContractAddress string) {
+	nft := common.HexToAddress(nftContractAddress)
+	reader.nft = &nft
+	reader.ca = append(reader.ca, nft)
+}
+
 func (reader *EventReader) GetEvents(ctx context.Context, start, end uint64) ([]*OrderEvent, error) {
 	reader.logger.Debugf("getting events from %d to %d blocks", start, end)
 
-	logs, err := reader.cli.FilterLogs(ctx, ethereum.FilterQuery{
-		Addresses: reader.ca,
-		FromBlock: big.NewInt(int64(start)),
-		ToBlock:   big.NewInt(int64(end)),
-		Topics:    [][]common.Hash{{orderApprovedPartOne, orderApprovedPartTwo, orderCanceled, ordersMatched}},
-	})
+	query := reader.query()
+	query.FromBlock = new(big.Int).SetUint64(start)
+	query.ToBlock = new(big.Int).SetUint64(end)
+
+	logs, err := reader.cli.FilterLogs(ctx, query)
 	if err != nil {
 		return nil, err
 	}
 
 	reader.logger.Debugf("logs length = %d", len(logs))
 
+	return reader.Decode(logs)
+}
+
+// query matches the logs of the watched contracts, without a block range.
+func (reader *EventReader) query() ethereum.FilterQuery {
+	return ethereum.FilterQuery{
+		Addresses: reader.ca,
+		Topics:    [][]common.Hash{{orderApprovedPartOne, orderApprovedPartTwo, orderCanceled, ordersMatched, tokenTransfer}},
+	}
+}
+
+// Decode turns the logs of whole blocks into events. Logs are only emitted
+// by successful transactions, and the other logs of a transaction are looked
+// up among the given ones, so no receipt has to be fetched.
+func (reader *EventReader) Decode(logs []types.Log) ([]*OrderEvent, error) {
+	txLogs := make(map[common.Hash][]*types.Log)
+	for i := range logs {
+		txLogs[logs[i].TxHash] = append(txLogs[logs[i].TxHash], &logs[i])
+	}
+
 	events := make([]*OrderEvent, 0, len(logs))
 	for i := range logs {
 		reader.logger.Debugf("read block number = %d", logs[i].BlockNumber)
 
-		var event *OrderEvent
+		if logs[i].Removed || len(logs[i].Topics) == 0 {
+			continue
+		}
+
 		var eventType int
 		switch logs[i].Topics[0] {
 		case orderApprovedPartOne:
 			eventType = OrderApproved
 		case orderApprovedPartTwo:
-			eventType = OrderApproved
+			// joined with the first part
+			continue
 		case orderCanceled:
 			eventType = OrderCancelled
 		case ordersMatched:
 			eventType = OrdersMatched
+		case tokenTransfer:
+			// ERC20 transfers share the topic
+			if reader.nft == nil || logs[i].Address != *reader.nft {
+				continue
+			}
+			eventType = TokenTransferred
 		default:
 			continue
 		}
-		event, err = reader.unpackEvent(ctx, eventType, logs[i].TxHash)
+
+		event, err := reader.unpackEvent(eventType, &logs[i], txLogs[logs[i].TxHash])
 		if err != nil {
 			return nil, err
 		}
 
+		if event == nil {
+			continue
+		}
+
+		event.BlockNumber = logs[i].BlockNumber
+		event.BlockHash = logs[i].BlockHash
+		event.TxHash = logs[i].TxHash
+		event.LogIndex = logs[i].Index
+
 		events = append(events, event)
 	}
 	return events, nil
 }
 
-func (reader *EventReader) unpackEvent(ctx context.Context, eventType int, txHash common.Hash) (*OrderEvent, error) {
-	receipt, err := reader.cli.TransactionReceipt(ctx, txHash)
-	if err != nil {
-		return nil, err
-	}
-	if receipt.Status == types.ReceiptStatusFailed {
-		return nil, nil
-	}
+func (reader *EventReader) unpackEvent(eventType int, log *types.Log, txLogs []*types.Log) (*OrderEvent, error) {
 	switch eventType {
 	case OrderApproved:
-		one := reader.matchTypeEvent(orderApprovedPartOne, receipt.Logs)
-		two := reader.matchTypeEvent(orderApprovedPartTwo, receipt.Logs)
-		return reader.toOrderAprovedEvent(one, two)
+		two := reader.matchApprovedPartTwo(log, txLogs)
+		if two == nil {
+			return nil, fmt.Errorf("missing second part of approved order %s", log.Topics[1].Hex())
+		}
+		return reader.toOrderAprovedEvent(log, two)
 	case OrderCancelled:
-		log := reader.matchTypeEvent(orderCanceled, receipt.Logs)
 		return reader.toOrderCanceledEvent(log)
 	case OrdersMatched:
-		log := reader.matchTypeEvent(ordersMatched, receipt.Logs)
 		return reader.toOrdersMatchedEvent(log)
+	case TokenTransferred:
+		// transfers made by a match are applied with the matched orders
+		if reader.matchTypeEvent(ordersMatched, txLogs) != nil {
+			return nil, nil
+		}
+		return reader.toTokenTransferredEvent(log)
 	}
 	return nil, nil
 }
@@ -113,6 +161,27 @@ func (reader *EventReader) toOrdersMatchedEvent(log *types.Log) (*OrderEvent, er
 		BuyHash:  event.BuyHash,
 		Maker:    event.Maker,
 		Taker:    event.Taker,
+		Price:    event.Price,
+	}, nil
+}
+
+// toTokenTransferredEvent reads the transfer from the topics, all of its
+// arguments are indexed. Mints are skipped, the minter records them.
+func (reader *EventReader) toTokenTransferredEvent(log *types.Log) (*OrderEvent, error) {
+	if len(log.Topics) != 4 {
+		return nil, fmt.Errorf("unexpected token transfer topics count %d", len(log.Topics))
+	}
+
+	from := common.BytesToAddress(log.Topics[1].Bytes())
+	if from == (common.Address{}) {
+		return nil, nil
+	}
+
+	return &OrderEvent{
+		Type:    TokenTransferred,
+		From:    from,
+		To:      common.BytesToAddress(log.Topics[2].Bytes()),
+		TokenID: log.Topics[3].Big(),
 	}, nil
 }
 
@@ -139,13 +208,68 @@ func (reader *EventReader) toOrderAprovedEvent(partOne, partTwo *types.Log) (*Or
 	if event1.Hash != event2.Hash {
 		return nil, fmt.Errorf("aproved order1 hash and aproved order2 hash doesn't match; buy=%s; sell=%s", event1.Hash.Hex(), event2.Hash.Hex())
 	}
-	return nil, nil
+
+	order := &wyvern.Order{
+		Exchange:           toAddress(event1.Exchange),
+		Maker:              &wyvern.Account{Address: toAddress(event1.Maker)},
+		Taker:              &wyvern.Account{Address: toAddress(event1.Taker)},
+		MakerRelayerFee:    event1.MakerRelayerFee.String(),
+		TakerRelayerFee:    event1.TakerRelayerFee.String(),
+		MakerProtocolFee:   event1.MakerProtocolFee.String(),
+		TakerProtocolFee:   event1.TakerProtocolFee.String(),
+		FeeRecipient:       &wyvern.Account{Address: toAddress(event1.FeeRecipient)},
+		FeeMethod:          wyvern.FeeMethod(event1.FeeMethod),
+		Side:               wyvern.OrderSide(event1.Side),
+		SaleKind:           wyvern.SaleKind(event1.SaleKind),
+		Target:             toAddress(event1.Target),
+		HowToCall:          wyvern.HowToCall(event2.HowToCall),
+		Calldata:           hexutil.Encode(event2.Calldata),
+		ReplacementPattern: hexutil.Encode(event2.ReplacementPattern),
+		StaticTarget:       toAddress(event2.StaticTarget),
+		StaticExtradata:    hexutil.Encode(event2.StaticExtradata),
+		PaymentToken:       toAddress(event2.PaymentToken),
+		BasePrice:          event2.BasePrice.String(),
+		Extra:              event2.Extra.String(),
+		ListingTime:        event2.ListingTime.String(),
+		ExpirationTime:     event2.ExpirationTime.String(),
+		Salt:               event2.Salt.String(),
+	}
+
+	hash, err := order.OrderHash()
+	if err != nil {
+		return nil, err
+	}
+	order.Hash = strings.ToLower(hash.Hex())
+
+	return &OrderEvent{
+		Type:  OrderApproved,
+		Hash:  event1.Hash,
+		Maker: event1.Maker,
+		Taker: event1.Taker,
+		Order: order,
+	}, nil
+}
+
+func (reader *EventReader) matchApprovedPartTwo(partOne *types.Log, logs []*types.Log) *types.Log {
+	if len(partOne.Topics) < 2 {
+		return nil
+	}
+
+	for _, log := range logs {
+		if len(log.Topics) < 2 {
+			continue
+		}
+		if log.Topics[0] == orderApprovedPartTwo && log.Topics[1] == partOne.Topics[1] {
+			return log
+		}
+	}
+	return nil
 }
 
 func (reader *EventReader) matchTypeEvent(topic common.Hash, logs []*types.Log) *types.Log {
 	for _, log := range logs {
 		if len(log.Topics) == 0 {
-			// TODO: log
+			continue
 		}
 		if log.Topics[0] == topic {
 			return log
@@ -153,3 +277,7 @@ func (reader *EventReader) matchTypeEvent(topic common.Hash, logs []*types.Log)
 	}
 	return nil
 }
+
+func toAddress(addr common.Address) string {
+	return strings.ToLower(addr.Hex())
+}
@@ -0,0 +1,114 @@
+package listener
+
+import (
+	"context"
+	"sort"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// subscription keeps the logs pushed by the node until their blocks have
+// enough confirmations. Blocks from since on are fully covered by it, the
+// ones before are read with ranged polling. It is owned by the Start loop.
+type subscription struct {
+	heads   chan *types.Header
+	logs    chan types.Log
+	headSub ethereum.Subscription
+	logSub  ethereum.Subscription
+	since   uint64
+	head    uint64
+	pending map[uint64][]types.Log
+}
+
+func (listener *ExchangeListener) subscribe(ctx context.Context) (*subscription, error) {
+	backend, ok := listener.cli.(SubscriptionBackend)
+	if !ok {
+		return nil, rpc.ErrNotificationsUnsupported
+	}
+
+	sub := &subscription{
+		heads:   make(chan *types.Header, 16),
+		logs:    make(chan types.Log, 256),
+		pending: make(map[uint64][]types.Log),
+	}
+
+	var err error
+	sub.logSub, err = backend.SubscribeFilterLogs(ctx, listener.re.query(), sub.logs)
+	if err != nil {
+		return nil, err
+	}
+
+	sub.headSub, err = backend.SubscribeNewHead(ctx, sub.heads)
+	if err != nil {
+		sub.logSub.Unsubscribe()
+		return nil, err
+	}
+
+	// the head is read once the logs are subscribed to, so the blocks after
+	// it can't have been mined before
+	head, err := listener.headNumber(ctx)
+	if err != nil {
+		sub.unsubscribe()
+		return nil, err
+	}
+
+	sub.since = head + 1
+	sub.head = head
+
+	return sub, nil
+}
+
+func (sub *subscription) unsubscribe() {
+	sub.headSub.Unsubscribe()
+	sub.logSub.Unsubscribe()
+}
+
+// add buffers the log, or drops it when the node reports that its block
+// has been reorged out.
+func (sub *subscription) add(log types.Log) {
+	if !log.Removed {
+		sub.pending[log.BlockNumber] = append(sub.pending[log.BlockNumber], log)
+		return
+	}
+
+	logs := sub.pending[log.BlockNumber]
+	for i := range logs {
+		if logs[i].BlockHash == log.BlockHash && logs[i].Index == log.Index {
+			sub.pending[log.BlockNumber] = append(logs[:i], logs[i+1:]...)
+			break
+		}
+	}
+}
+
+// covers reports whether all the logs of the block range have been pushed.
+// Blocks at the latest head are left to polling, the node may still be
+// sending their logs.
+func (sub *subscription) covers(start, end uint64) bool {
+	return start >= sub.since && end < sub.head
+}
+
+// take returns the buffered logs of the block range in chain order and
+// forgets the logs up to its end.
+func (sub *subscription) take(start, end uint64) []types.Log {
+	logs := make([]types.Log, 0)
+	for number, blockLogs := range sub.pending {
+		if number > end {
+			continue
+		}
+		if number >= start {
+			logs = append(logs, blockLogs...)
+		}
+		delete(sub.pending, number)
+	}
+
+	sort.Slice(logs, func(i, j int) bool {
+		if logs[i].BlockNumber != logs[j].BlockNumber {
+			return logs[i].BlockNumber < logs[j].BlockNumber
+		}
+		return logs[i].Index < logs[j].Index
+	})
+
+	return logs
+}
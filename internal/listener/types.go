@@ -5,6 +5,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/videocoin/marketplace/internal/wyvern"
 )
 
 var (
@@ -12,14 +13,24 @@ var (
 	orderApprovedPartTwo = crypto.Keccak256Hash([]byte("OrderApprovedPartTwo(bytes32,uint8,bytes,bytes,address,bytes,address,uint256,uint256,uint256,uint256,uint256,bool)"))
 	orderCanceled        = crypto.Keccak256Hash([]byte("OrderCancelled(bytes32)"))
 	ordersMatched        = crypto.Keccak256Hash([]byte("OrdersMatched(bytes32,bytes32,address,address,uint256,bytes32)"))
+	tokenTransfer        = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
 )
 
 const (
 	OrderApproved  = iota
 	OrderCancelled = iota
 	OrdersMatched  = iota
+	// TokenTransferred is an ERC721 transfer made outside of the exchange.
+	TokenTransferred = iota
 )
 
+var eventTypeNames = map[int]string{
+	OrderApproved:    "OrderApproved",
+	OrderCancelled:   "OrderCancelled",
+	OrdersMatched:    "OrdersMatched",
+	TokenTransferred: "Transfer",
+}
+
 type OrderEvent struct {
 	Type     int
 	Hash     common.Hash
@@ -27,6 +38,24 @@ type OrderEvent struct {
 	BuyHash  common.Hash
 	Maker    common.Address
 	Taker    common.Address
+	Price    *big.Int
+
+	// From, To and TokenID describe a token transfer.
+	From    common.Address
+	To      common.Address
+	TokenID *big.Int
+
+	// Order is the full order joined from the OrderApproved event parts.
+	Order *wyvern.Order
+
+	BlockNumber uint64
+	BlockHash   common.Hash
+	TxHash      common.Hash
+	LogIndex    uint
+}
+
+func (e *OrderEvent) TypeName() string {
+	return eventTypeNames[e.Type]
 }
 
 type ordersMatchedEvent struct {
@@ -48,3 +48,35 @@ func ffmpegTranscodeAudioToM4A(inputPath, outputPath string) error {
 
 	return nil
 }
+
+// ffmpegHlsAESExec packages a rendition as MPEG-TS HLS with segments
+// encrypted with AES-128 under the key of the key info file.
+func ffmpegHlsAESExec(videoPath, audioPath, keyInfoPath, segmentPath, outputPath string) (string, error) {
+	cmdArgs := []string{"-hide_banner", "-loglevel", "info", "-y"}
+
+	if videoPath != "" {
+		cmdArgs = append(cmdArgs, "-i", videoPath)
+	}
+	if audioPath != "" {
+		cmdArgs = append(cmdArgs, "-i", audioPath)
+	}
+	if videoPath != "" && audioPath != "" {
+		cmdArgs = append(cmdArgs, "-map", "0:v", "-map", "1:a")
+	}
+
+	cmdArgs = append(
+		cmdArgs,
+		"-c", "copy", "-f", "hls", "-hls_time", "6", "-hls_playlist_type", "vod",
+		"-hls_segment_type", "mpegts", "-hls_key_info_file", keyInfoPath,
+		"-hls_segment_filename", segmentPath, outputPath,
+	)
+
+	cmd := exec.CommandContext(context.Background(), "ffmpeg", cmdArgs...)
+	out, err := cmd.CombinedOutput()
+	outStr := string(out)
+	if err != nil {
+		return "", fmt.Errorf("%s: %s", err.Error(), outStr)
+	}
+
+	return outStr, nil
+}
@@ -0,0 +1,277 @@
+package mediaprocessor
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/videocoin/marketplace/internal/drm"
+)
+
+const (
+	hlsMasterPlaylist    = "encrypted.m3u8"
+	hlsAESMasterPlaylist = "encrypted_aes.m3u8"
+)
+
+// packageHLS encrypts the tracks with cbcs under the key of the dash
+// packaging and writes the HLS playlists and segments to a folder of their
+// own, along with the AES-128 packaging of packageHLSAES.
+func (mp *MediaProcessor) packageHLS(logger *logrus.Entry, tmpFolder string, drmMeta *drm.Metadata, videoPaths []string, audioPath string) (string, error) {
+	hlsFolder := filepath.Join(tmpFolder, "hls")
+	err := os.MkdirAll(hlsFolder, 0777)
+	if err != nil {
+		return "", err
+	}
+
+	drmXmlPath := filepath.Join(tmpFolder, "drm_cbcs.xml")
+	videoEncPaths := make([]string, 0, len(videoPaths))
+	for _, videoPath := range videoPaths {
+		videoEncPaths = append(videoEncPaths, cbcsPath(videoPath))
+	}
+	audioEncPath := ""
+	if audioPath != "" {
+		audioEncPath = cbcsPath(audioPath)
+	}
+
+	defer func() {
+		_ = os.Remove(drmXmlPath)
+		_ = os.Remove(audioEncPath)
+		for _, p := range videoEncPaths {
+			_ = os.Remove(p)
+		}
+	}()
+
+	logger.WithField("drm_xml_path", drmXmlPath).Info("generating cbcs drm xml")
+
+	drmXml, err := drm.GenerateCBCSDrmXml(drmMeta)
+	if err != nil {
+		return "", err
+	}
+
+	err = ioutil.WriteFile(drmXmlPath, []byte(drmXml), 0644)
+	if err != nil {
+		return "", err
+	}
+
+	for idx, videoPath := range videoPaths {
+		logger.
+			WithField("video_path", videoPath).
+			WithField("video_enc_path", videoEncPaths[idx]).
+			Info("encrypting video with cbcs")
+		out, err := mp4boxCryptExec(drmXmlPath, videoPath, videoEncPaths[idx])
+		if err != nil {
+			return "", err
+		}
+
+		logger.Debugf("mp4box crypt video out: %s", out)
+	}
+
+	if audioPath != "" {
+		logger.
+			WithField("audio_path", audioPath).
+			WithField("audio_enc_path", audioEncPath).
+			Info("encrypting audio with cbcs")
+		out, err := mp4boxCryptExec(drmXmlPath, audioPath, audioEncPath)
+		if err != nil {
+			return "", err
+		}
+
+		logger.Debugf("mp4box crypt audio out: %s", out)
+	}
+
+	outputPath := filepath.Join(hlsFolder, hlsMasterPlaylist)
+
+	logger.WithField("output_m3u8_path", outputPath).Info("generating hls")
+
+	out, err := mp4boxHlsExec(videoEncPaths, audioEncPath, outputPath)
+	if err != nil {
+		return "", err
+	}
+
+	logger.Debugf("mp4box hls out: %s", out)
+
+	err = addHLSKeyTags(hlsFolder, drmMeta)
+	if err != nil {
+		return "", err
+	}
+
+	err = mp.packageHLSAES(logger, tmpFolder, hlsFolder, drmMeta, videoPaths, audioPath)
+	if err != nil {
+		return "", err
+	}
+
+	return hlsFolder, nil
+}
+
+// packageHLSAES packages the tracks as MPEG-TS HLS with segments encrypted
+// with AES-128 under the key of the dash packaging, the encryption Safari
+// and iOS play natively. Every rendition is muxed with the audio track into
+// a media playlist of its own, the master playlist lists them by their peak
+// bit rate.
+func (mp *MediaProcessor) packageHLSAES(logger *logrus.Entry, tmpFolder, hlsFolder string, drmMeta *drm.Metadata, videoPaths []string, audioPath string) error {
+	key, err := drm.HLSAESKey(drmMeta)
+	if err != nil {
+		return err
+	}
+
+	keyPath := filepath.Join(tmpFolder, "hls_aes.key")
+	err = ioutil.WriteFile(keyPath, key, 0600)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(keyPath)
+
+	// audio only media has a single rendition
+	if len(videoPaths) == 0 {
+		videoPaths = []string{""}
+	}
+
+	master := []string{"#EXTM3U", "#EXT-X-VERSION:3"}
+	for idx, videoPath := range videoPaths {
+		name := fmt.Sprintf("aes_%d", idx)
+
+		keyInfo, err := drm.HLSAESKeyInfo(keyPath)
+		if err != nil {
+			return err
+		}
+
+		keyInfoPath := filepath.Join(tmpFolder, name+".keyinfo")
+		err = ioutil.WriteFile(keyInfoPath, []byte(keyInfo), 0600)
+		if err != nil {
+			return err
+		}
+		defer os.Remove(keyInfoPath)
+
+		playlistPath := filepath.Join(hlsFolder, name+".m3u8")
+
+		logger.
+			WithField("video_path", videoPath).
+			WithField("audio_path", audioPath).
+			WithField("output_m3u8_path", playlistPath).
+			Info("generating aes-128 hls")
+
+		out, err := ffmpegHlsAESExec(videoPath, audioPath, keyInfoPath, filepath.Join(hlsFolder, name+"_%03d.ts"), playlistPath)
+		if err != nil {
+			return err
+		}
+
+		logger.Debugf("ffmpeg hls out: %s", out)
+
+		bandwidth, err := hlsBandwidth(playlistPath)
+		if err != nil {
+			return err
+		}
+
+		master = append(master, fmt.Sprintf("#EXT-X-STREAM-INF:BANDWIDTH=%d", bandwidth), name+".m3u8")
+	}
+
+	masterPath := filepath.Join(hlsFolder, hlsAESMasterPlaylist)
+	return ioutil.WriteFile(masterPath, []byte(strings.Join(master, "\n")+"\n"), 0644)
+}
+
+// hlsBandwidth returns the peak bit rate of the segments of a media
+// playlist, the BANDWIDTH of its variant.
+func hlsBandwidth(playlistPath string) (int64, error) {
+	f, err := os.Open(playlistPath)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	var (
+		peak     float64
+		duration float64
+	)
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if strings.HasPrefix(line, "#EXTINF:") {
+			value := strings.SplitN(strings.TrimPrefix(line, "#EXTINF:"), ",", 2)[0]
+			duration, err = strconv.ParseFloat(value, 64)
+			if err != nil {
+				return 0, err
+			}
+			continue
+		}
+
+		if line == "" || strings.HasPrefix(line, "#") || duration <= 0 {
+			continue
+		}
+
+		fi, err := os.Stat(filepath.Join(filepath.Dir(playlistPath), line))
+		if err != nil {
+			return 0, err
+		}
+
+		peak = math.Max(peak, float64(fi.Size()*8)/duration)
+		duration = 0
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+
+	return int64(math.Ceil(peak)), nil
+}
+
+// cbcsPath returns the path of the cbcs encrypted copy of a track.
+func cbcsPath(trackPath string) string {
+	ext := filepath.Ext(trackPath)
+	return strings.TrimSuffix(trackPath, ext) + "_cbcs" + ext
+}
+
+// addHLSKeyTags signals the encryption in the media playlists MP4Box left
+// without a key tag.
+func addHLSKeyTags(hlsFolder string, drmMeta *drm.Metadata) error {
+	keyTag, err := drm.HLSKeyTag(drmMeta)
+	if err != nil {
+		return err
+	}
+
+	files, err := ioutil.ReadDir(hlsFolder)
+	if err != nil {
+		return err
+	}
+
+	for _, fi := range files {
+		if fi.IsDir() || filepath.Ext(fi.Name()) != ".m3u8" || fi.Name() == hlsMasterPlaylist {
+			continue
+		}
+
+		playlistPath := filepath.Join(hlsFolder, fi.Name())
+		data, err := ioutil.ReadFile(playlistPath)
+		if err != nil {
+			return err
+		}
+
+		playlist := string(data)
+		if strings.Contains(playlist, "#EXT-X-KEY") {
+			continue
+		}
+
+		lines := strings.Split(playlist, "\n")
+		tagged := make([]string, 0, len(lines)+1)
+		inserted := false
+		for _, line := range lines {
+			if !inserted && (strings.HasPrefix(line, "#EXT-X-MAP") || strings.HasPrefix(line, "#EXTINF")) {
+				tagged = append(tagged, keyTag)
+				inserted = true
+			}
+			tagged = append(tagged, line)
+		}
+
+		err = ioutil.WriteFile(playlistPath, []byte(strings.Join(tagged, "\n")), 0644)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
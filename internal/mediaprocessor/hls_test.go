@@ -0,0 +1,51 @@
+package mediaprocessor
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestHLSBandwidth(t *testing.T) {
+	dir := t.TempDir()
+
+	segments := map[string]int{
+		"aes_0_000.ts": 600000,
+		"aes_0_001.ts": 900000,
+		"aes_0_002.ts": 100000,
+	}
+	for name, size := range segments {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), make([]byte, size), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	playlist := strings.Join([]string{
+		"#EXTM3U",
+		"#EXT-X-VERSION:3",
+		"#EXT-X-TARGETDURATION:6",
+		`#EXT-X-KEY:METHOD=AES-128,URI="key",IV=0x000102030405060708090a0b0c0d0e0f`,
+		"#EXTINF:6.000000,",
+		"aes_0_000.ts",
+		"#EXTINF:6.000000,",
+		"aes_0_001.ts",
+		"#EXTINF:0.500000,",
+		"aes_0_002.ts",
+		"#EXT-X-ENDLIST",
+	}, "\n")
+	playlistPath := filepath.Join(dir, "aes_0.m3u8")
+	if err := ioutil.WriteFile(playlistPath, []byte(playlist), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	bandwidth, err := hlsBandwidth(playlistPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the short last segment has the peak bit rate
+	if bandwidth != 1600000 {
+		t.Errorf("bandwidth = %d, want 1600000", bandwidth)
+	}
+}
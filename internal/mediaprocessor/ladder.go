@@ -0,0 +1,107 @@
+package mediaprocessor
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/videocoin/marketplace/internal/model"
+)
+
+// renditionKeyframeInterval is the number of seconds between the forced key
+// frames of the renditions, it keeps their segments aligned.
+const renditionKeyframeInterval = 2
+
+// ParseRenditionLadder parses a comma separated list of height:kbps[:codec]
+// steps, the codec is h264 by default.
+func ParseRenditionLadder(s string) (model.MediaRenditions, error) {
+	ladder := make(model.MediaRenditions, 0)
+	for _, step := range strings.Split(s, ",") {
+		step = strings.TrimSpace(step)
+		if step == "" {
+			continue
+		}
+
+		parts := strings.Split(step, ":")
+		if len(parts) < 2 || len(parts) > 3 {
+			return nil, fmt.Errorf("invalid rendition %q", step)
+		}
+
+		height, err := strconv.Atoi(parts[0])
+		if err != nil || height <= 0 || height%2 != 0 {
+			return nil, fmt.Errorf("invalid rendition height %q", parts[0])
+		}
+
+		bitrate, err := strconv.Atoi(strings.TrimSuffix(parts[1], "k"))
+		if err != nil || bitrate <= 0 {
+			return nil, fmt.Errorf("invalid rendition bitrate %q", parts[1])
+		}
+
+		codec := model.RenditionCodecH264
+		if len(parts) == 3 {
+			codec = strings.ToLower(parts[2])
+		}
+		if codec != model.RenditionCodecH264 && codec != model.RenditionCodecHEVC {
+			return nil, fmt.Errorf("unsupported rendition codec %q", codec)
+		}
+
+		ladder = append(ladder, model.NewMediaRendition(height, bitrate, codec))
+	}
+
+	return ladder, nil
+}
+
+// renditionsFor returns the steps of the ladder that do not upscale the
+// source, a source smaller than every step gets the lowest one at its own
+// height.
+func (mp *MediaProcessor) renditionsFor(sourceHeight int) model.MediaRenditions {
+	renditions := make(model.MediaRenditions, 0, len(mp.renditions))
+	var lowest *model.MediaRendition
+	for _, r := range mp.renditions {
+		if lowest == nil || r.Height < lowest.Height {
+			lowest = r
+		}
+		if sourceHeight > 0 && r.Height > sourceHeight {
+			continue
+		}
+
+		renditions = append(renditions, model.NewMediaRendition(r.Height, r.Bitrate, r.Codec))
+	}
+
+	if len(renditions) == 0 && lowest != nil {
+		height := sourceHeight - sourceHeight%2
+		renditions = append(renditions, model.NewMediaRendition(height, lowest.Bitrate, lowest.Codec))
+	}
+
+	return renditions
+}
+
+func ffmpegTranscodeRendition(ctx context.Context, inputPath, outputPath string, r *model.MediaRendition) error {
+	cmdArgs := []string{
+		"-hide_banner", "-loglevel", "info", "-y", "-i", inputPath,
+		"-map", "0:v:0", "-an", "-vf", fmt.Sprintf("scale=-2:%d", r.Height),
+		"-b:v", fmt.Sprintf("%dk", r.Bitrate),
+		"-maxrate", fmt.Sprintf("%dk", r.Bitrate*107/100),
+		"-bufsize", fmt.Sprintf("%dk", r.Bitrate*2),
+		"-force_key_frames", fmt.Sprintf("expr:gte(t,n_forced*%d)", renditionKeyframeInterval),
+		"-pix_fmt", "yuv420p", "-preset", "veryfast",
+	}
+
+	if r.Codec == model.RenditionCodecHEVC {
+		cmdArgs = append(cmdArgs, "-c:v", "libx265", "-x265-params", "scenecut=0", "-tag:v", "hvc1")
+	} else {
+		cmdArgs = append(cmdArgs, "-c:v", "libx264", "-profile:v", "high", "-sc_threshold", "0")
+	}
+
+	cmdArgs = append(cmdArgs, outputPath)
+
+	cmd := exec.CommandContext(ctx, "ffmpeg", cmdArgs...)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s: %s", err.Error(), string(out))
+	}
+
+	return nil
+}
@@ -20,7 +20,7 @@ func mp4boxCryptExec(drmXmlPath, inputPath, outputPath string) (string, error) {
 	return outStr, nil
 }
 
-func mp4boxDashExec(inputVideoPath, inputAudioPath, outputPath string) (string, error) {
+func mp4boxDashExec(inputVideoPaths []string, inputAudioPath, outputPath string) (string, error) {
 	ctx := context.Background()
 
 	cmdArgs := []string{
@@ -34,9 +34,35 @@ func mp4boxDashExec(inputVideoPath, inputAudioPath, outputPath string) (string,
 		cmdArgs = append(cmdArgs, inputAudioPath)
 	}
 
-	cmdArgs = append(cmdArgs, inputVideoPath)
+	cmdArgs = append(cmdArgs, inputVideoPaths...)
 
-	fmt.Println(cmdArgs)
+	cmd := exec.CommandContext(ctx, "MP4Box", cmdArgs...)
+	out, err := cmd.CombinedOutput()
+	outStr := string(out)
+	if err != nil {
+		return "", fmt.Errorf("%s: %s", err.Error(), outStr)
+	}
+
+	return outStr, nil
+}
+
+// mp4boxHlsExec packages already encrypted tracks as fMP4 HLS, the master
+// playlist, the media playlists and the segments are written next to
+// outputPath.
+func mp4boxHlsExec(inputVideoPaths []string, inputAudioPath, outputPath string) (string, error) {
+	ctx := context.Background()
+
+	cmdArgs := []string{
+		"-dash", "6000", "-frag", "6000", "-rap", "-bs-switching", "no",
+		"-profile", "live", "-segment-name", "%s_", "-url-template",
+		"-out", outputPath,
+	}
+
+	if inputAudioPath != "" {
+		cmdArgs = append(cmdArgs, inputAudioPath)
+	}
+
+	cmdArgs = append(cmdArgs, inputVideoPaths...)
 
 	cmd := exec.CommandContext(ctx, "MP4Box", cmdArgs...)
 	out, err := cmd.CombinedOutput()
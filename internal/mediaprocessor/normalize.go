@@ -0,0 +1,65 @@
+package mediaprocessor
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+
+	"github.com/videocoin/marketplace/internal/model"
+	"gopkg.in/vansante/go-ffprobe.v2"
+)
+
+// NormalizeVideo transcodes video that cannot be streamed to an H.264/AAC
+// MP4 mezzanine, streams that already are are copied.
+func (mp *MediaProcessor) NormalizeVideo(ctx context.Context, inputPath, outputPath string) error {
+	logger := mp.logger.
+		WithField("input_path", inputPath).
+		WithField("output_path", outputPath)
+
+	probe, err := ffprobe.ProbeURL(ctx, inputPath)
+	if err != nil {
+		return err
+	}
+
+	video := probe.FirstVideoStream()
+	if video == nil {
+		return fmt.Errorf("no video stream in %s", inputPath)
+	}
+
+	cmdArgs := []string{
+		"-hide_banner", "-loglevel", "info", "-y", "-i", inputPath,
+		"-map", "0:v:0", "-map", "0:a:0?",
+	}
+
+	if model.IsStreamableVideo(video) {
+		cmdArgs = append(cmdArgs, "-c:v", "copy")
+	} else {
+		cmdArgs = append(cmdArgs,
+			"-c:v", "libx264", "-preset", "veryfast", "-crf", "18",
+			"-profile:v", "high", "-pix_fmt", "yuv420p",
+		)
+	}
+
+	audio := probe.FirstAudioStream()
+	if audio != nil && model.IsStreamableAudio(audio) {
+		cmdArgs = append(cmdArgs, "-c:a", "copy")
+	} else {
+		cmdArgs = append(cmdArgs, "-c:a", "aac", "-b:a", "192k")
+	}
+
+	cmdArgs = append(cmdArgs, "-movflags", "+faststart", outputPath)
+
+	logger.
+		WithField("video_codec", video.CodecName).
+		Info("transcoding video to mp4")
+
+	cmd := exec.CommandContext(ctx, "ffmpeg", cmdArgs...)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s: %s", err.Error(), string(out))
+	}
+
+	logger.Debug(string(out))
+
+	return nil
+}
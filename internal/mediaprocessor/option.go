@@ -1,8 +1,11 @@
 package mediaprocessor
 
 import (
+	"time"
+
 	"github.com/sirupsen/logrus"
 	"github.com/videocoin/marketplace/internal/datastore"
+	"github.com/videocoin/marketplace/internal/model"
 	"github.com/videocoin/marketplace/internal/storage"
 )
 
@@ -28,3 +31,36 @@ func WithStorage(s *storage.Storage) Option {
 		return nil
 	}
 }
+
+func WithPreviewDuration(d time.Duration) Option {
+	return func(mc *MediaProcessor) error {
+		mc.previewDuration = d
+		return nil
+	}
+}
+
+func WithPreviewStart(d time.Duration) Option {
+	return func(mc *MediaProcessor) error {
+		mc.previewStart = d
+		return nil
+	}
+}
+
+// WithPreviewWatermark sets the text drawn over video previews, the font file
+// is optional and falls back to the ffmpeg default.
+func WithPreviewWatermark(text, fontFile string) Option {
+	return func(mc *MediaProcessor) error {
+		mc.previewWatermark = text
+		mc.previewWatermarkFont = fontFile
+		return nil
+	}
+}
+
+// WithRenditionLadder sets the renditions encrypted video is transcoded to,
+// the original rendition is packaged as is when the ladder is empty.
+func WithRenditionLadder(ladder model.MediaRenditions) Option {
+	return func(mc *MediaProcessor) error {
+		mc.renditions = ladder
+		return nil
+	}
+}
@@ -0,0 +1,194 @@
+package mediaprocessor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/AlekSi/pointer"
+	"github.com/videocoin/marketplace/internal/datastore"
+	"github.com/videocoin/marketplace/internal/model"
+)
+
+const (
+	DefaultPreviewDuration = 15 * time.Second
+
+	previewFadeOut = 2 * time.Second
+)
+
+// GeneratePreview cuts a short unencrypted teaser from the original video or
+// audio of locked media and publishes it next to the media.
+func (mp *MediaProcessor) GeneratePreview(ctx context.Context, media *model.Media) error {
+	if !media.CanPreview() {
+		return nil
+	}
+
+	logger := mp.logger.WithField("media_id", media.ID)
+
+	previewKey := media.PreviewKey
+	if previewKey == "" {
+		// media uploaded before previews existed
+		previewKey = path.Join(path.Dir(media.Key), "preview.mp4")
+		if media.IsAudio() {
+			previewKey = path.Join(path.Dir(media.Key), "preview.m4a")
+		}
+	}
+
+	inputPath := genTempFilepath("", path.Ext(media.Key))
+	outputPath := genTempFilepath("preview_", path.Ext(previewKey))
+	defer func() {
+		_ = os.Remove(inputPath)
+		_ = os.Remove(outputPath)
+	}()
+
+	logger.
+		WithField("input_uri", media.GetOriginalUrl()).
+		WithField("input_path", inputPath).
+		Info("downloading input url")
+
+	ir, err := mp.storage.ObjReader(media.Key)
+	if err != nil {
+		return err
+	}
+	defer ir.Close()
+
+	err = downloadFile(media.GetOriginalUrl(), inputPath, ir)
+	if err != nil {
+		return err
+	}
+
+	start, duration := mp.previewWindow(media)
+
+	logger.
+		WithField("start", start).
+		WithField("duration", duration).
+		WithField("output_path", outputPath).
+		Info("cutting preview")
+
+	if media.IsVideo() {
+		err = mp.ffmpegCutVideoPreview(ctx, inputPath, outputPath, start, duration)
+	} else {
+		err = ffmpegCutAudioPreview(ctx, inputPath, outputPath, start, duration)
+	}
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(outputPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	cid, err := mp.storage.PushPath(previewKey, f, true)
+	if err != nil {
+		return err
+	}
+
+	err = mp.ds.Media.Update(ctx, media, datastore.MediaUpdatedFields{
+		PreviewKey: pointer.ToString(previewKey),
+		PreviewCID: pointer.ToString(cid),
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.WithField("preview_cid", cid).Info("preview has been generated")
+
+	return nil
+}
+
+// previewWindow returns the start and the duration of the preview, the
+// configured start is ignored when it is past the end of the media.
+func (mp *MediaProcessor) previewWindow(media *model.Media) (time.Duration, time.Duration) {
+	start := mp.previewStart
+	duration := mp.previewDuration
+	if duration <= 0 {
+		duration = DefaultPreviewDuration
+	}
+
+	if media.Duration > 0 {
+		total := time.Duration(media.Duration) * time.Second
+		if start >= total {
+			start = 0
+		}
+		if start+duration > total {
+			duration = total - start
+		}
+	}
+
+	return start, duration
+}
+
+func (mp *MediaProcessor) ffmpegCutVideoPreview(ctx context.Context, inputPath, outputPath string, start, duration time.Duration) error {
+	filters := []string{"scale='min(1280,iw)':-2"}
+	if mp.previewWatermark != "" {
+		drawtext := fmt.Sprintf(
+			"drawtext=text=%s:expansion=none:fontcolor=white@0.6:fontsize=h/18:x=w-tw-h/36:y=h-th-h/36",
+			escapeFilterValue(mp.previewWatermark),
+		)
+		if mp.previewWatermarkFont != "" {
+			drawtext += fmt.Sprintf(":fontfile=%s", escapeFilterValue(mp.previewWatermarkFont))
+		}
+		filters = append(filters, drawtext)
+	}
+
+	cmdArgs := []string{
+		"-hide_banner", "-loglevel", "info", "-y",
+		"-ss", formatSeconds(start), "-i", inputPath, "-t", formatSeconds(duration),
+		"-map", "0:v:0", "-map", "0:a:0?",
+		"-vf", strings.Join(filters, ","),
+		"-c:v", "libx264", "-preset", "veryfast", "-crf", "28", "-pix_fmt", "yuv420p",
+		"-c:a", "aac", "-b:a", "128k",
+		"-movflags", "+faststart", outputPath,
+	}
+
+	cmd := exec.CommandContext(ctx, "ffmpeg", cmdArgs...)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s: %s", err.Error(), string(out))
+	}
+
+	return nil
+}
+
+func ffmpegCutAudioPreview(ctx context.Context, inputPath, outputPath string, start, duration time.Duration) error {
+	cmdArgs := []string{
+		"-hide_banner", "-loglevel", "info", "-y",
+		"-ss", formatSeconds(start), "-i", inputPath, "-t", formatSeconds(duration),
+		"-vn", "-c:a", "aac", "-b:a", "128k",
+	}
+	if duration > previewFadeOut {
+		cmdArgs = append(cmdArgs, "-af", fmt.Sprintf(
+			"afade=t=out:st=%s:d=%s",
+			formatSeconds(duration-previewFadeOut),
+			formatSeconds(previewFadeOut),
+		))
+	}
+	cmdArgs = append(cmdArgs, "-movflags", "+faststart", outputPath)
+
+	cmd := exec.CommandContext(ctx, "ffmpeg", cmdArgs...)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s: %s", err.Error(), string(out))
+	}
+
+	return nil
+}
+
+func formatSeconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
+
+// escapeFilterValue escapes a filter option value for both the option and
+// the filtergraph levels of ffmpeg.
+func escapeFilterValue(s string) string {
+	opt := strings.NewReplacer(`\`, `\\`, `'`, `\'`, `:`, `\:`).Replace(s)
+	return strings.NewReplacer(
+		`\`, `\\`, `'`, `\'`, `[`, `\[`, `]`, `\]`, `,`, `\,`, `;`, `\;`,
+	).Replace(opt)
+}
@@ -16,18 +16,29 @@ import (
 	"io/ioutil"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
 type MediaProcessor struct {
 	logger  *logrus.Entry
 	ds      *datastore.Datastore
 	storage *storage.Storage
+
+	previewDuration      time.Duration
+	previewStart         time.Duration
+	previewWatermark     string
+	previewWatermarkFont string
+
+	renditions model.MediaRenditions
 }
 
 func NewMediaProcessor(ctx context.Context, opts ...Option) (*MediaProcessor, error) {
-	mp := new(MediaProcessor)
+	mp := &MediaProcessor{
+		previewDuration: DefaultPreviewDuration,
+	}
 	for _, o := range opts {
 		if err := o(mp); err != nil {
 			return nil, err
@@ -131,31 +142,37 @@ func (mp *MediaProcessor) GenerateThumbnail(ctx context.Context, media *model.Me
 	return nil
 }
 
-func (mp *MediaProcessor) EncryptVideo(inputURI string, drmMeta *drm.Metadata, key string) (string, error) {
+func (mp *MediaProcessor) EncryptVideo(ctx context.Context, media *model.Media, drmMeta *drm.Metadata) (string, string, error) {
 	tmpFolder := filepath.Join("/tmp", random.RandomString(16))
-	err := os.MkdirAll(tmpFolder, 0777)
+	dashFolder := filepath.Join(tmpFolder, "dash")
+	err := os.MkdirAll(dashFolder, 0777)
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
 
-	logger := mp.logger.WithField("tmp_folder", tmpFolder)
+	logger := mp.logger.
+		WithField("media_id", media.ID).
+		WithField("tmp_folder", tmpFolder)
 
+	inputURI := media.GetOriginalUrl()
 	ext := filepath.Ext(inputURI)
 	inputPath := filepath.Join(tmpFolder, fmt.Sprintf("original%s", ext))
-	videoPath := filepath.Join(tmpFolder, fmt.Sprintf("_video%s", ext))
 	audioPath := filepath.Join(tmpFolder, "_video.m4a")
-	videoEncPath := filepath.Join(tmpFolder, fmt.Sprintf("video%s", ext))
 	audioEncPath := filepath.Join(tmpFolder, "audio.m4a")
-	outputMPDPath := filepath.Join(tmpFolder, "encrypted.mpd")
+	outputMPDPath := filepath.Join(dashFolder, "encrypted.mpd")
 	drmXmlPath := filepath.Join(tmpFolder, "drm.xml")
 
+	videoPaths := make([]string, 0)
+	videoEncPaths := make([]string, 0)
+
 	defer func() {
 		_ = os.Remove(inputPath)
-		_ = os.Remove(videoPath)
 		_ = os.Remove(audioPath)
-		_ = os.Remove(videoEncPath)
 		_ = os.Remove(audioEncPath)
 		_ = os.Remove(drmXmlPath)
+		for _, p := range append(videoPaths, videoEncPaths...) {
+			_ = os.Remove(p)
+		}
 	}()
 
 	logger.
@@ -163,55 +180,93 @@ func (mp *MediaProcessor) EncryptVideo(inputURI string, drmMeta *drm.Metadata, k
 		WithField("output_path", inputPath).
 		Info("downloading input url")
 
-	ir, err := mp.storage.ObjReader(key)
+	ir, err := mp.storage.ObjReader(media.Key)
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
+	defer ir.Close()
 
 	err = downloadFile(inputURI, inputPath, ir)
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
 
 	logger.Info("splitting a/v")
-	probe, err := ffprobe.ProbeURL(context.Background(), inputPath)
+	probe, err := ffprobe.ProbeURL(ctx, inputPath)
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
 
 	audioStream := probe.FirstAudioStream()
 	if audioStream != nil {
-		err = ffmpegExtractVideo(inputPath, videoPath)
-		if err != nil {
-			return "", err
-		}
 		err = ffmpegExtractAudio(inputPath, audioStream.Index, audioPath)
 		if err != nil {
-			return "", err
+			return "", "", err
 		}
 	} else {
 		audioPath = ""
-		videoPath = inputPath
 	}
 
 	logger.WithField("drm_xml_path", drmXmlPath).Info("generating drm xml")
 
 	err = ioutil.WriteFile(drmXmlPath, []byte(drm.GenerateDrmXml(drmMeta)), 0644)
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
 
-	logger.
-		WithField("drm_xml_path", drmXmlPath).
-		WithField("video_path", videoPath).
-		WithField("video_enc_path", videoEncPath).
-		Info("encrypting video")
-	out, err := mp4boxCryptExec(drmXmlPath, videoPath, videoEncPath)
-	if err != nil {
-		return "", err
-	}
+	if len(mp.renditions) == 0 {
+		// without a ladder the original rendition is packaged as is
+		videoPath := inputPath
+		if audioStream != nil {
+			videoPath = filepath.Join(tmpFolder, fmt.Sprintf("_video%s", ext))
+			err = ffmpegExtractVideo(inputPath, videoPath)
+			if err != nil {
+				return "", "", err
+			}
+		}
+		videoEncPath := filepath.Join(tmpFolder, fmt.Sprintf("video%s", ext))
 
-	logger.Debugf("mp4box crypt video out: %s", out)
+		videoPaths = append(videoPaths, videoPath)
+		videoEncPaths = append(videoEncPaths, videoEncPath)
+
+		logger.
+			WithField("drm_xml_path", drmXmlPath).
+			WithField("video_path", videoPath).
+			WithField("video_enc_path", videoEncPath).
+			Info("encrypting video")
+		out, err := mp4boxCryptExec(drmXmlPath, videoPath, videoEncPath)
+		if err != nil {
+			return "", "", err
+		}
+
+		logger.Debugf("mp4box crypt video out: %s", out)
+	} else {
+		sourceHeight := 0
+		if videoStream := probe.FirstVideoStream(); videoStream != nil {
+			sourceHeight = videoStream.Height
+		}
+
+		renditions := mp.renditionsFor(sourceHeight)
+		err = mp.ds.Media.Update(ctx, media, datastore.MediaUpdatedFields{
+			Renditions: renditions,
+		})
+		if err != nil {
+			return "", "", err
+		}
+
+		for _, r := range renditions {
+			videoPath := filepath.Join(tmpFolder, fmt.Sprintf("_video_%s.mp4", r.Name))
+			videoEncPath := filepath.Join(tmpFolder, fmt.Sprintf("video_%s.mp4", r.Name))
+
+			videoPaths = append(videoPaths, videoPath)
+			videoEncPaths = append(videoEncPaths, videoEncPath)
+
+			err = mp.encryptRendition(ctx, logger, media, renditions, r, inputPath, videoPath, videoEncPath, drmXmlPath)
+			if err != nil {
+				return "", "", fmt.Errorf("rendition %s: %s", r.Name, err)
+			}
+		}
+	}
 
 	if audioPath != "" {
 		logger.
@@ -219,9 +274,9 @@ func (mp *MediaProcessor) EncryptVideo(inputURI string, drmMeta *drm.Metadata, k
 			WithField("audio_path", audioPath).
 			WithField("audio_enc_path", audioEncPath).
 			Info("encrypting audio")
-		out, err = mp4boxCryptExec(drmXmlPath, audioPath, audioEncPath)
+		out, err := mp4boxCryptExec(drmXmlPath, audioPath, audioEncPath)
 		if err != nil {
-			return "", err
+			return "", "", err
 		}
 
 		logger.Debugf("mp4box crypt audio out: %s", out)
@@ -230,25 +285,88 @@ func (mp *MediaProcessor) EncryptVideo(inputURI string, drmMeta *drm.Metadata, k
 	}
 
 	logger.
-		WithField("video_enc_path", videoEncPath).
+		WithField("video_enc_paths", videoEncPaths).
 		WithField("audio_enc_path", audioEncPath).
 		WithField("output_mpd_path", outputMPDPath).
 		Info("generating dash")
-	out, err = mp4boxDashExec(videoEncPath, audioEncPath, outputMPDPath)
+	out, err := mp4boxDashExec(videoEncPaths, audioEncPath, outputMPDPath)
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
 
 	logger.Debugf("mp4box dash out: %s", out)
 
-	return outputMPDPath, nil
+	hlsFolder, err := mp.packageHLS(logger, tmpFolder, drmMeta, videoPaths, audioPath)
+	if err != nil {
+		return "", "", err
+	}
+
+	return outputMPDPath, hlsFolder, nil
 }
 
-func (mp *MediaProcessor) EncryptAudio(inputURI string, drmMeta *drm.Metadata, key string) (string, error) {
+// encryptRendition transcodes a step of the ladder and encrypts it under
+// the key of the media, the progress of the step is saved with the media.
+func (mp *MediaProcessor) encryptRendition(
+	ctx context.Context,
+	logger *logrus.Entry,
+	media *model.Media,
+	renditions model.MediaRenditions,
+	r *model.MediaRendition,
+	inputPath, videoPath, videoEncPath, drmXmlPath string,
+) error {
+	logger = logger.
+		WithField("rendition", r.Name).
+		WithField("bitrate", r.Bitrate).
+		WithField("codec", r.Codec)
+
+	setStatus := func(status model.RenditionStatus) error {
+		r.Status = status
+		return mp.ds.Media.Update(ctx, media, datastore.MediaUpdatedFields{
+			Renditions: renditions,
+		})
+	}
+
+	fail := func(err error) error {
+		if serr := setStatus(model.RenditionStatusFailed); serr != nil {
+			logger.WithError(serr).Error("failed to save rendition status")
+		}
+		return err
+	}
+
+	err := setStatus(model.RenditionStatusTranscoding)
+	if err != nil {
+		return err
+	}
+
+	logger.WithField("video_path", videoPath).Info("transcoding rendition")
+
+	err = ffmpegTranscodeRendition(ctx, inputPath, videoPath, r)
+	if err != nil {
+		return fail(err)
+	}
+
+	err = setStatus(model.RenditionStatusEncrypting)
+	if err != nil {
+		return err
+	}
+
+	logger.WithField("video_enc_path", videoEncPath).Info("encrypting rendition")
+
+	out, err := mp4boxCryptExec(drmXmlPath, videoPath, videoEncPath)
+	if err != nil {
+		return fail(err)
+	}
+
+	logger.Debugf("mp4box crypt rendition out: %s", out)
+
+	return setStatus(model.RenditionStatusReady)
+}
+
+func (mp *MediaProcessor) EncryptAudio(inputURI string, drmMeta *drm.Metadata, key string) (string, string, error) {
 	tmpFolder := filepath.Join("/tmp", random.RandomString(16))
 	err := os.MkdirAll(tmpFolder, 0777)
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
 
 	logger := mp.logger.WithField("tmp_folder", tmpFolder)
@@ -274,12 +392,13 @@ func (mp *MediaProcessor) EncryptAudio(inputURI string, drmMeta *drm.Metadata, k
 
 	ir, err := mp.storage.ObjReader(key)
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
+	defer ir.Close()
 
 	err = downloadFile(inputURI, inputPath, ir)
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
 
 	logger.
@@ -287,14 +406,14 @@ func (mp *MediaProcessor) EncryptAudio(inputURI string, drmMeta *drm.Metadata, k
 		Info("transcoding audio to m4a")
 	err = ffmpegTranscodeAudioToM4A(inputPath, inputM4APath)
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
 
 	logger.WithField("drm_xml_path", drmXmlPath).Info("generating drm xml")
 
 	err = ioutil.WriteFile(drmXmlPath, []byte(drm.GenerateDrmXml(drmMeta)), 0644)
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
 
 	logger.
@@ -304,7 +423,7 @@ func (mp *MediaProcessor) EncryptAudio(inputURI string, drmMeta *drm.Metadata, k
 		Info("encrypting")
 	out, err := mp4boxCryptExec(drmXmlPath, inputM4APath, outputEncPath)
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
 
 	logger.Debugf("mp4box crypt out: %s", out)
@@ -313,51 +432,43 @@ func (mp *MediaProcessor) EncryptAudio(inputURI string, drmMeta *drm.Metadata, k
 		WithField("input_path", outputEncPath).
 		WithField("output_mpd_path", outputMPDPath).
 		Info("generating dash")
-	out, err = mp4boxDashExec(outputEncPath, "", outputMPDPath)
+	out, err = mp4boxDashExec([]string{outputEncPath}, "", outputMPDPath)
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
 
 	logger.Debugf("mp4box dash out: %s", out)
 
-	return outputMPDPath, nil
-}
-
-func (mp *MediaProcessor) EncryptFile(inputURI string, drmMeta *drm.Metadata, key string) (string, error) {
-	logger := mp.logger.WithField("input_uri", inputURI)
+	hlsFolder, err := mp.packageHLS(logger, tmpFolder, drmMeta, nil, inputM4APath)
+	if err != nil {
+		return "", "", err
+	}
 
-	inputPath := genTempFilepath("", filepath.Ext(inputURI))
-	outputPath := genTempFilepath("", filepath.Ext(inputURI))
+	return outputMPDPath, hlsFolder, nil
+}
 
-	logger.Info("downloading input url")
+// EncryptFile encrypts the original of the media with the chunked format
+// while it is read from storage and pushes it to its encrypted key.
+func (mp *MediaProcessor) EncryptFile(media *model.Media, drmMeta *drm.Metadata) (string, error) {
+	logger := mp.logger.
+		WithField("media_id", media.ID).
+		WithField("key", media.Key).
+		WithField("encrypted_key", media.EncryptedKey)
 
-	ir, err := mp.storage.ObjReader(key)
+	ir, err := mp.storage.ObjReader(media.Key)
 	if err != nil {
 		return "", err
 	}
+	defer ir.Close()
 
-	err = downloadFile(inputURI, inputPath, ir)
+	er, err := drm.NewChunkedEncryptReader(ir, drmMeta)
 	if err != nil {
 		return "", err
 	}
 
-	logger = logger.WithField("input_path", outputPath)
-
-	ctx := context.Background()
-	cmdArgs := []string{
-		"enc", "-e", "-aes-128-cbc", "-in", inputPath, "-out", outputPath, "-K",
-		drmMeta.Key, "-iv", drmMeta.FirstIV, "-e", "-A", "-base64",
-	}
-
-	logger.Debugf("openssl %s", strings.Join(cmdArgs, " "))
-
-	cmd := exec.CommandContext(ctx, "openssl", cmdArgs...)
-	out, err := cmd.CombinedOutput()
-	if err != nil {
-		return "", fmt.Errorf("%s: %s", err.Error(), string(out))
-	}
+	logger.Info("uploading encrypted file")
 
-	return outputPath, nil
+	return mp.storage.PushPath(media.EncryptedKey, er, true)
 }
 
 func (mp *MediaProcessor) EncryptMedia(ctx context.Context, media *model.Media, drmMeta *drm.Metadata) error {
@@ -366,21 +477,14 @@ func (mp *MediaProcessor) EncryptMedia(ctx context.Context, media *model.Media,
 	if media.IsApplication() || media.IsImage() {
 		logger.Info("encrypting file")
 
-		outputPath, err := mp.EncryptFile(media.GetOriginalUrl(), drmMeta, media.Key)
-		if err != nil {
-			return err
-		}
-		defer func() { _ = os.Remove(outputPath) }()
-
-		logger.Info("uploading encrypted file")
-
-		cid, err := mp.storage.Upload(outputPath, media.EncryptedKey)
+		cid, err := mp.EncryptFile(media, drmMeta)
 		if err != nil {
 			return err
 		}
 
 		err = mp.ds.Media.Update(ctx, media, datastore.MediaUpdatedFields{
-			EncryptedCID: pointer.ToString(cid),
+			EncryptedCID:   pointer.ToString(cid),
+			FileEncryption: pointer.ToString(drm.CurrentFileEncryption),
 		})
 		if err != nil {
 			return err
@@ -394,37 +498,32 @@ func (mp *MediaProcessor) EncryptMedia(ctx context.Context, media *model.Media,
 	}
 
 	if media.IsVideo() {
-		outputPath, err := mp.EncryptVideo(media.GetOriginalUrl(), drmMeta, media.Key)
+		outputPath, hlsFolder, err := mp.EncryptVideo(ctx, media, drmMeta)
 		if err != nil {
 			return err
 		}
 
-		videoSegmentKey := strings.Replace(media.EncryptedKey, "encrypted.mpd", "videoinit.mp4", -1)
-		audioSegmentKey := strings.Replace(media.EncryptedKey, "encrypted.mpd", "audioinit.mp4", -1)
-		videoSegmentPath := strings.Replace(outputPath, "encrypted.mpd", "videoinit.mp4", -1)
-		audioSegmentPath := strings.Replace(outputPath, "encrypted.mpd", "audioinit.mp4", -1)
-
+		dashFolder := filepath.Dir(outputPath)
 		defer func() {
-			_ = os.Remove(outputPath)
-			_ = os.Remove(videoSegmentPath)
-			_ = os.Remove(audioSegmentPath)
+			_ = os.RemoveAll(dashFolder)
+			_ = os.RemoveAll(hlsFolder)
 		}()
 
-		outputPaths := []string{
-			outputPath,
-			videoSegmentPath,
-		}
-		to := []string{
-			media.EncryptedKey,
-			videoSegmentKey,
+		// every rendition of the ladder has segments of its own, the whole
+		// packaging folders are uploaded
+		outputPaths, to, err := folderUploads(dashFolder, media.EncryptedKey)
+		if err != nil {
+			return err
 		}
 
-		if _, err := os.Stat(audioSegmentPath); err == nil {
-			outputPaths = append(outputPaths, audioSegmentPath)
-			to = append(to, audioSegmentKey)
+		hlsPaths, hlsKeys, err := folderUploads(hlsFolder, media.EncryptedKey)
+		if err != nil {
+			return err
 		}
+		outputPaths = append(outputPaths, hlsPaths...)
+		to = append(to, hlsKeys...)
 
-		logger.Info("uploading dash manifest and segments")
+		logger.Info("uploading dash and hls manifests and segments")
 
 		cid, err := mp.storage.MultiUpload(outputPaths, to)
 		if err != nil {
@@ -432,7 +531,9 @@ func (mp *MediaProcessor) EncryptMedia(ctx context.Context, media *model.Media,
 		}
 
 		err = mp.ds.Media.Update(ctx, media, datastore.MediaUpdatedFields{
-			EncryptedCID: pointer.ToString(cid),
+			EncryptedCID:       pointer.ToString(cid),
+			EncryptedHLSKey:    pointer.ToString(path.Join(path.Dir(media.EncryptedKey), hlsMasterPlaylist)),
+			EncryptedHLSAESKey: pointer.ToString(path.Join(path.Dir(media.EncryptedKey), hlsAESMasterPlaylist)),
 		})
 		if err != nil {
 			return err
@@ -446,7 +547,7 @@ func (mp *MediaProcessor) EncryptMedia(ctx context.Context, media *model.Media,
 	}
 
 	if media.IsAudio() {
-		outputPath, err := mp.EncryptAudio(media.GetOriginalUrl(), drmMeta, media.Key)
+		outputPath, hlsFolder, err := mp.EncryptAudio(media.GetOriginalUrl(), drmMeta, media.Key)
 		if err != nil {
 			return err
 		}
@@ -457,6 +558,7 @@ func (mp *MediaProcessor) EncryptMedia(ctx context.Context, media *model.Media,
 		defer func() {
 			_ = os.Remove(outputPath)
 			_ = os.Remove(segmentPath)
+			_ = os.RemoveAll(hlsFolder)
 		}()
 
 		outputPaths := []string{
@@ -468,7 +570,14 @@ func (mp *MediaProcessor) EncryptMedia(ctx context.Context, media *model.Media,
 			segmentKey,
 		}
 
-		logger.Info("uploading dash manifest and segments")
+		hlsPaths, hlsKeys, err := folderUploads(hlsFolder, media.EncryptedKey)
+		if err != nil {
+			return err
+		}
+		outputPaths = append(outputPaths, hlsPaths...)
+		to = append(to, hlsKeys...)
+
+		logger.Info("uploading dash and hls manifests and segments")
 
 		cid, err := mp.storage.MultiUpload(outputPaths, to)
 		if err != nil {
@@ -476,7 +585,9 @@ func (mp *MediaProcessor) EncryptMedia(ctx context.Context, media *model.Media,
 		}
 
 		err = mp.ds.Media.Update(ctx, media, datastore.MediaUpdatedFields{
-			EncryptedCID: pointer.ToString(cid),
+			EncryptedCID:       pointer.ToString(cid),
+			EncryptedHLSKey:    pointer.ToString(path.Join(path.Dir(media.EncryptedKey), hlsMasterPlaylist)),
+			EncryptedHLSAESKey: pointer.ToString(path.Join(path.Dir(media.EncryptedKey), hlsAESMasterPlaylist)),
 		})
 		if err != nil {
 			return err
@@ -1,13 +1,14 @@
 package mediaprocessor
 
 import (
-	"cloud.google.com/go/storage"
 	"encoding/hex"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"math/rand"
 	"net/http"
 	"os"
+	"path"
 	"path/filepath"
 )
 
@@ -17,28 +18,26 @@ func genTempFilepath(prefix, suffix string) string {
 	return filepath.Join(os.TempDir(), prefix+hex.EncodeToString(randBytes)+suffix)
 }
 
-func downloadFile(url string, filepath string, ir *storage.Reader) (err error) {
+func downloadFile(url string, filepath string, ir io.Reader) (err error) {
 	out, err := os.Create(filepath)
 	if err != nil  {
 		return err
 	}
 	defer out.Close()
 
-	resp, err := http.Get(url)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
+	src := ir
+	if src == nil {
+		resp, err := http.Get(url)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("bad status: %s", resp.Status)
-	}
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("bad status: %s", resp.Status)
+		}
 
-	var src io.Reader
-	if ir == nil {
 		src = resp.Body
-	} else {
-		src = ir
 	}
 
 	_, err = io.Copy(out, src)
@@ -48,3 +47,25 @@ func downloadFile(url string, filepath string, ir *storage.Reader) (err error) {
 
 	return nil
 }
+
+// folderUploads lists the files of a packaging folder with the keys they
+// are stored with, next to the encrypted manifest.
+func folderUploads(folder, encryptedKey string) ([]string, []string, error) {
+	files, err := ioutil.ReadDir(folder)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	paths := make([]string, 0, len(files))
+	keys := make([]string, 0, len(files))
+	for _, fi := range files {
+		if fi.IsDir() {
+			continue
+		}
+
+		paths = append(paths, filepath.Join(folder, fi.Name()))
+		keys = append(keys, path.Join(path.Dir(encryptedKey), fi.Name()))
+	}
+
+	return paths, keys, nil
+}
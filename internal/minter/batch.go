@@ -0,0 +1,361 @@
+package minter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/videocoin/marketplace/internal/datastore"
+	"github.com/videocoin/marketplace/internal/model"
+)
+
+// DefaultMaxBatchSize keeps a packed mint well below the block gas limit.
+const DefaultMaxBatchSize = 20
+
+// The methods tokens are minted with:
+//
+//	mint:      a transaction per token
+//	mintBatch: the tokens of a chunk with a single call
+//	multicall: the mint calls of a chunk with a single transaction
+//
+// The dev contracts have all of them and mintBatch is used by default,
+// contracts deployed before batch minting are set to mint with WithBatchMint
+// and WithBatchMint1155.
+const (
+	methodMint      = "mint"
+	methodMintBatch = "mintBatch"
+	methodMulticall = "multicall"
+)
+
+var ErrUnknownBatchMethod = errors.New("unknown batch mint method")
+
+// MintItem is a token minted with MintBatch or MintBatch1155. URI is only
+// used for ERC721 tokens, Amount for editions.
+type MintItem struct {
+	ID     *big.Int
+	URI    string
+	Amount int64
+}
+
+// batchMethod checks that tokens can be minted with the method of the
+// contract.
+func batchMethod(contractABI abi.ABI, method string) (string, error) {
+	switch method {
+	case methodMint, methodMintBatch, methodMulticall:
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnknownBatchMethod, method)
+	}
+
+	if _, ok := contractABI.Methods[method]; !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnknownBatchMethod, method)
+	}
+
+	return method, nil
+}
+
+// SupportsBatchMint reports whether several ERC721 tokens can be minted
+// with a single transaction.
+func (m *Minter) SupportsBatchMint() bool {
+	return m.batchMethod != methodMint
+}
+
+// SupportsBatchMint1155 reports whether several editions can be minted with
+// a single transaction.
+func (m *Minter) SupportsBatchMint1155() bool {
+	return m.contract1155 != nil && m.batchMethod1155 != methodMint
+}
+
+// MintBatch mints the tokens to the given address with as few transactions
+// as the contract allows, falling back to a transaction per token. Tokens
+// which have already been minted or are being minted are skipped, so a
+// failed batch can be retried.
+func (m *Minter) MintBatch(ctx context.Context, to common.Address, items []*MintItem) ([]*model.ChainTx, error) {
+	if !m.SupportsBatchMint() {
+		chainTxs := make([]*model.ChainTx, 0, len(items))
+		for _, item := range items {
+			chainTx, err := m.Mint(ctx, to, item.ID, item.URI)
+			if err != nil {
+				return nil, err
+			}
+			chainTxs = append(chainTxs, chainTx)
+		}
+
+		return chainTxs, nil
+	}
+
+	items, err := m.unsentItems(ctx, model.ChainTxKindMint, items)
+	if err != nil {
+		return nil, err
+	}
+
+	chainTxs := make([]*model.ChainTx, 0)
+	for _, chunk := range m.chunkItems(items) {
+		data, err := m.packBatch(to, chunk)
+		if err != nil {
+			return nil, err
+		}
+
+		chainTx, err := m.send(ctx, newBatchChainTx(model.ChainTxKindMint, chunk), m.ca, data)
+		if err != nil {
+			return nil, err
+		}
+		chainTxs = append(chainTxs, chainTx)
+	}
+
+	return chainTxs, nil
+}
+
+// MintBatch1155 mints the editions to the given address with as few
+// transactions as the contract allows, falling back to Mint1155. Only
+// tokens none of which editions have been minted yet are packed, the
+// others are completed with Mint1155.
+func (m *Minter) MintBatch1155(ctx context.Context, to common.Address, items []*MintItem) ([]*model.ChainTx, error) {
+	if m.contract1155 == nil {
+		return nil, ErrERC1155NotConfigured
+	}
+
+	chainTxs := make([]*model.ChainTx, 0)
+	if !m.SupportsBatchMint1155() {
+		for _, item := range items {
+			chainTx, err := m.Mint1155(ctx, to, item.ID, item.Amount)
+			if err != nil {
+				return nil, err
+			}
+			if chainTx != nil {
+				chainTxs = append(chainTxs, chainTx)
+			}
+		}
+
+		return chainTxs, nil
+	}
+
+	items, err := m.unsentItems(ctx, model.ChainTxKindMintEdition, items)
+	if err != nil {
+		return nil, err
+	}
+
+	packed := make([]*MintItem, 0, len(items))
+	for _, item := range items {
+		balance, err := m.contract1155.BalanceOf(m.getCallOpts(ctx), to, item.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		if balance.Sign() == 0 {
+			packed = append(packed, item)
+			continue
+		}
+
+		chainTx, err := m.Mint1155(ctx, to, item.ID, item.Amount)
+		if err != nil {
+			return nil, err
+		}
+		if chainTx != nil {
+			chainTxs = append(chainTxs, chainTx)
+		}
+	}
+
+	for _, chunk := range m.chunkItems(packed) {
+		data, err := m.packBatch1155(to, chunk)
+		if err != nil {
+			return nil, err
+		}
+
+		chainTx, err := m.send(ctx, newBatchChainTx(model.ChainTxKindMintEdition, chunk), m.ca1155, data)
+		if err != nil {
+			return nil, err
+		}
+		chainTxs = append(chainTxs, chainTx)
+	}
+
+	return chainTxs, nil
+}
+
+// packBatch returns the call data minting the ERC721 tokens of the chunk.
+func (m *Minter) packBatch(to common.Address, chunk []*MintItem) ([]byte, error) {
+	if m.batchMethod == methodMintBatch {
+		ids := make([]*big.Int, 0, len(chunk))
+		uris := make([]string, 0, len(chunk))
+		for _, item := range chunk {
+			ids = append(ids, item.ID)
+			uris = append(uris, item.URI)
+		}
+		return m.abi721.Pack(methodMintBatch, to, ids, uris)
+	}
+
+	calls := make([][]byte, 0, len(chunk))
+	for _, item := range chunk {
+		call, err := m.abi721.Pack(methodMint, to, item.ID, item.URI)
+		if err != nil {
+			return nil, err
+		}
+		calls = append(calls, call)
+	}
+
+	return m.abi721.Pack(methodMulticall, calls)
+}
+
+// packBatch1155 returns the call data minting the editions of the chunk.
+func (m *Minter) packBatch1155(to common.Address, chunk []*MintItem) ([]byte, error) {
+	if m.batchMethod1155 == methodMintBatch {
+		ids := make([]*big.Int, 0, len(chunk))
+		amounts := make([]*big.Int, 0, len(chunk))
+		for _, item := range chunk {
+			ids = append(ids, item.ID)
+			amounts = append(amounts, big.NewInt(item.Amount))
+		}
+		return m.abi1155.Pack(methodMintBatch, to, ids, amounts, []byte{})
+	}
+
+	// the contract mints a single edition per call
+	calls := make([][]byte, 0, len(chunk))
+	for _, item := range chunk {
+		call, err := m.abi1155.Pack(methodMint, to, item.ID)
+		if err != nil {
+			return nil, err
+		}
+		for i := int64(0); i < item.Amount; i++ {
+			calls = append(calls, call)
+		}
+	}
+
+	return m.abi1155.Pack(methodMulticall, calls)
+}
+
+func newBatchChainTx(kind model.ChainTxKind, items []*MintItem) *model.ChainTx {
+	ids := make(model.ChainTxAssetIDs, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ID.Int64())
+	}
+
+	return &model.ChainTx{
+		Kind:     kind,
+		AssetID:  ids[0],
+		AssetIDs: ids,
+	}
+}
+
+// unsentItems drops the items a transaction of the kind has already been
+// sent for.
+func (m *Minter) unsentItems(ctx context.Context, kind model.ChainTxKind, items []*MintItem) ([]*MintItem, error) {
+	if m.ds == nil {
+		return items, nil
+	}
+
+	unsent := make([]*MintItem, 0, len(items))
+	for _, item := range items {
+		_, err := m.ds.ChainTxs.GetLatestByAssetID(ctx, kind, item.ID.Int64())
+		if err == nil {
+			continue
+		}
+		if err != datastore.ErrChainTxNotFound {
+			return nil, err
+		}
+		unsent = append(unsent, item)
+	}
+
+	return unsent, nil
+}
+
+func (m *Minter) chunkItems(items []*MintItem) [][]*MintItem {
+	size := m.maxBatchSize
+	if size <= 0 {
+		size = DefaultMaxBatchSize
+	}
+
+	chunks := make([][]*MintItem, 0, len(items)/size+1)
+	for len(items) > 0 {
+		n := size
+		if len(items) < n {
+			n = len(items)
+		}
+		chunks = append(chunks, items[:n])
+		items = items[n:]
+	}
+
+	return chunks
+}
+
+// tokenURIs returns the token uris a mint transaction sets by asset id.
+// The uris of a packed mint are read back from its call data.
+func (m *Minter) tokenURIs(chainTx *model.ChainTx) (map[int64]string, error) {
+	uris := map[int64]string{}
+	if chainTx.TokenURI.Valid {
+		uris[chainTx.AssetID] = chainTx.TokenURI.String
+	}
+
+	if chainTx.Kind != model.ChainTxKindMint || len(chainTx.AssetIDs) == 0 {
+		return uris, nil
+	}
+
+	data, err := hexutil.Decode(chainTx.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	method, args, err := unpackCall(m.abi721, data)
+	if err != nil {
+		return nil, err
+	}
+
+	switch method.Name {
+	case methodMintBatch:
+		ids, ok := args[1].([]*big.Int)
+		if !ok {
+			return nil, errors.New("unexpected mintBatch ids")
+		}
+		batchURIs, ok := args[2].([]string)
+		if !ok || len(batchURIs) != len(ids) {
+			return nil, errors.New("unexpected mintBatch uris")
+		}
+		for i, id := range ids {
+			uris[id.Int64()] = batchURIs[i]
+		}
+	case methodMulticall:
+		calls, ok := args[0].([][]byte)
+		if !ok {
+			return nil, errors.New("unexpected multicall data")
+		}
+		for _, call := range calls {
+			_, callArgs, err := unpackCall(m.abi721, call)
+			if err != nil {
+				return nil, err
+			}
+			id, ok := callArgs[1].(*big.Int)
+			if !ok {
+				return nil, errors.New("unexpected mint id")
+			}
+			uri, ok := callArgs[2].(string)
+			if !ok {
+				return nil, errors.New("unexpected mint uri")
+			}
+			uris[id.Int64()] = uri
+		}
+	default:
+		return nil, fmt.Errorf("unexpected mint method %s", method.Name)
+	}
+
+	return uris, nil
+}
+
+func unpackCall(contractABI abi.ABI, data []byte) (*abi.Method, []interface{}, error) {
+	if len(data) < 4 {
+		return nil, nil, errors.New("call data is too short")
+	}
+
+	method, err := contractABI.MethodById(data[:4])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	args, err := method.Inputs.Unpack(data[4:])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return method, args, nil
+}
@@ -0,0 +1,99 @@
+package minter_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/videocoin/marketplace/internal/minter"
+	"github.com/videocoin/marketplace/internal/simchain"
+)
+
+func TestMintBatchOnChain(t *testing.T) {
+	ctx := context.Background()
+
+	for _, method := range []string{"mintBatch", "multicall"} {
+		_, chain := newTxChain(t)
+		to, _, err := simchain.NewTransactor()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		m, err := chain.Minter(minter.WithBatchMint(method), minter.WithMaxBatchSize(2))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		items := []*minter.MintItem{
+			{ID: big.NewInt(3), URI: "ipfs://3"},
+			{ID: big.NewInt(5), URI: "ipfs://5"},
+			{ID: big.NewInt(8), URI: "ipfs://8"},
+		}
+		chainTxs, err := m.MintBatch(ctx, to.From, items)
+		if err != nil {
+			t.Fatalf("%s: %s", method, err)
+		}
+		if len(chainTxs) != 2 {
+			t.Errorf("%s: minted with %d transactions, want 2", method, len(chainTxs))
+		}
+
+		opts := &bind.CallOpts{Context: ctx}
+		for _, item := range items {
+			owner, err := chain.NFT721.OwnerOf(opts, item.ID)
+			if err != nil {
+				t.Fatalf("%s: token %s: %s", method, item.ID, err)
+			}
+			if owner != to.From {
+				t.Errorf("%s: token %s owner = %s, want %s", method, item.ID, owner.Hex(), to.From.Hex())
+			}
+
+			uri, err := chain.NFT721.TokenURI(opts, item.ID)
+			if err != nil {
+				t.Fatalf("%s: token %s: %s", method, item.ID, err)
+			}
+			if uri != item.URI {
+				t.Errorf("%s: token %s uri = %q, want %q", method, item.ID, uri, item.URI)
+			}
+		}
+	}
+}
+
+func TestMintBatch1155OnChain(t *testing.T) {
+	ctx := context.Background()
+
+	for _, method := range []string{"mintBatch", "multicall"} {
+		_, chain := newTxChain(t)
+		to, _, err := simchain.NewTransactor()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		m, err := chain.Minter(minter.WithBatchMint1155(method))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		items := []*minter.MintItem{
+			{ID: big.NewInt(3), Amount: 2},
+			{ID: big.NewInt(5), Amount: 1},
+		}
+		chainTxs, err := m.MintBatch1155(ctx, to.From, items)
+		if err != nil {
+			t.Fatalf("%s: %s", method, err)
+		}
+		if len(chainTxs) != 1 {
+			t.Errorf("%s: minted with %d transactions, want 1", method, len(chainTxs))
+		}
+
+		for _, item := range items {
+			balance, err := chain.NFT1155.BalanceOf(&bind.CallOpts{Context: ctx}, to.From, item.ID)
+			if err != nil {
+				t.Fatalf("%s: token %s: %s", method, item.ID, err)
+			}
+			if balance.Int64() != item.Amount {
+				t.Errorf("%s: token %s balance = %s, want %d", method, item.ID, balance, item.Amount)
+			}
+		}
+	}
+}
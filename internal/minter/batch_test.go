@@ -0,0 +1,150 @@
+package minter
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/videocoin/marketplace/internal/contracts/dev/nft"
+	"github.com/videocoin/marketplace/internal/model"
+)
+
+func newTestBatchMinter(t *testing.T, opts ...Option) *Minter {
+	abi721, err := abi.JSON(strings.NewReader(nft.NFT721ABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	abi1155, err := abi.JSON(strings.NewReader(nft.NFT1155ABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := &Minter{
+		abi721:          abi721,
+		abi1155:         abi1155,
+		contract1155:    &nft.NFT1155{},
+		batchMethod:     methodMintBatch,
+		batchMethod1155: methodMintBatch,
+	}
+	for _, o := range opts {
+		if err := o(m); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return m
+}
+
+func TestBatchMintMethod(t *testing.T) {
+	m := newTestBatchMinter(t)
+	if !m.SupportsBatchMint() || !m.SupportsBatchMint1155() {
+		t.Fatal("the compiled-in contracts do not support batch mints")
+	}
+
+	m = newTestBatchMinter(t, WithBatchMint(methodMint), WithBatchMint1155(methodMint))
+	if m.SupportsBatchMint() || m.SupportsBatchMint1155() {
+		t.Error("tokens are batched with the mint method")
+	}
+
+	err := WithBatchMint("mintAll")(m)
+	if !errors.Is(err, ErrUnknownBatchMethod) {
+		t.Errorf("unknown batch method returned %v, want %v", err, ErrUnknownBatchMethod)
+	}
+	err = WithBatchMint1155("updateTokenURI")(m)
+	if !errors.Is(err, ErrUnknownBatchMethod) {
+		t.Errorf("method of another kind returned %v, want %v", err, ErrUnknownBatchMethod)
+	}
+}
+
+func TestBatchMintPackDecode(t *testing.T) {
+	to := common.HexToAddress("0x00000000000000000000000000000000000000b2")
+	items := []*MintItem{
+		{ID: big.NewInt(3), URI: "ipfs://3"},
+		{ID: big.NewInt(5), URI: "ipfs://5"},
+	}
+
+	for _, method := range []string{methodMintBatch, methodMulticall} {
+		m := newTestBatchMinter(t, WithBatchMint(method))
+
+		data, err := m.packBatch(to, items)
+		if err != nil {
+			t.Fatalf("%s: %s", method, err)
+		}
+
+		chainTx := newBatchChainTx(model.ChainTxKindMint, items)
+		chainTx.Data = hexutil.Encode(data)
+
+		uris, err := m.tokenURIs(chainTx)
+		if err != nil {
+			t.Fatalf("%s: %s", method, err)
+		}
+		if len(uris) != len(items) {
+			t.Errorf("%s: decoded %d uris, want %d", method, len(uris), len(items))
+		}
+		for _, item := range items {
+			if got := uris[item.ID.Int64()]; got != item.URI {
+				t.Errorf("%s: token %s uri = %q, want %q", method, item.ID, got, item.URI)
+			}
+		}
+	}
+}
+
+func TestBatchMint1155PackDecode(t *testing.T) {
+	to := common.HexToAddress("0x00000000000000000000000000000000000000b2")
+	items := []*MintItem{
+		{ID: big.NewInt(3), Amount: 2},
+		{ID: big.NewInt(5), Amount: 1},
+	}
+
+	m := newTestBatchMinter(t, WithBatchMint1155(methodMintBatch))
+	data, err := m.packBatch1155(to, items)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	method, args, err := unpackCall(m.abi1155, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if method.Name != methodMintBatch {
+		t.Fatalf("method = %s", method.Name)
+	}
+	if args[0].(common.Address) != to {
+		t.Errorf("to = %s", args[0])
+	}
+	ids, amounts := args[1].([]*big.Int), args[2].([]*big.Int)
+	for i, item := range items {
+		if ids[i].Cmp(item.ID) != 0 || amounts[i].Int64() != item.Amount {
+			t.Errorf("item %d = %s x %s, want %s x %d", i, ids[i], amounts[i], item.ID, item.Amount)
+		}
+	}
+
+	// multicall repeats the single edition mint
+	m = newTestBatchMinter(t, WithBatchMint1155(methodMulticall))
+	data, err = m.packBatch1155(to, items)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, args, err = unpackCall(m.abi1155, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	calls := args[0].([][]byte)
+	if len(calls) != 3 {
+		t.Fatalf("multicall has %d calls, want 3", len(calls))
+	}
+	for i, want := range []int64{3, 3, 5} {
+		_, callArgs, err := unpackCall(m.abi1155, calls[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id := callArgs[1].(*big.Int); id.Int64() != want {
+			t.Errorf("call %d mints token %s, want %d", i, id, want)
+		}
+	}
+}
@@ -0,0 +1,10 @@
+package minter
+
+import "context"
+
+// ConfirmPending runs a single pass of the transaction manager.
+func (m *Minter) ConfirmPending(ctx context.Context) error {
+	return m.confirmPending(ctx)
+}
+
+var BumpGasPrice = bumpGasPrice
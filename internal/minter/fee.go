@@ -0,0 +1,82 @@
+package minter
+
+import (
+	"context"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	feeHistoryBlocks     = 10
+	feeHistoryPercentile = 50
+	// baseFeeHeadroomPercent keeps a transaction includable while the base
+	// fee rises for a couple of blocks.
+	baseFeeHeadroomPercent = 25
+)
+
+// FeeEstimator suggests the gas price of new transactions.
+type FeeEstimator interface {
+	SuggestGasPrice(ctx context.Context) (*big.Int, error)
+}
+
+// FeeHistoryEstimator prices legacy transactions from the base fee of the
+// next block and the priority fees recently paid, as reported by
+// eth_feeHistory. The vendored go-ethereum can't sign dynamic fee
+// transactions, so the minter sends gas price transactions which pay the
+// base fee and tip the rest. Nodes without eth_feeHistory fall back to the
+// gas price they suggest.
+type FeeHistoryEstimator struct {
+	rpc      *rpc.Client
+	fallback FeeEstimator
+}
+
+func NewFeeHistoryEstimator(rpcCli *rpc.Client, fallback FeeEstimator) *FeeHistoryEstimator {
+	return &FeeHistoryEstimator{
+		rpc:      rpcCli,
+		fallback: fallback,
+	}
+}
+
+type feeHistory struct {
+	BaseFee []*hexutil.Big   `json:"baseFeePerGas"`
+	Reward  [][]*hexutil.Big `json:"reward"`
+}
+
+func (e *FeeHistoryEstimator) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	history := new(feeHistory)
+	err := e.rpc.CallContext(
+		ctx,
+		history,
+		"eth_feeHistory",
+		hexutil.Uint64(feeHistoryBlocks),
+		"latest",
+		[]float64{feeHistoryPercentile},
+	)
+	if err != nil || len(history.BaseFee) == 0 {
+		return e.fallback.SuggestGasPrice(ctx)
+	}
+
+	// the last base fee is the one of the next block
+	baseFee := history.BaseFee[len(history.BaseFee)-1].ToInt()
+
+	tips := make([]*big.Int, 0, len(history.Reward))
+	for _, reward := range history.Reward {
+		if len(reward) > 0 {
+			tips = append(tips, reward[0].ToInt())
+		}
+	}
+
+	tip := big.NewInt(0)
+	if len(tips) > 0 {
+		sort.Slice(tips, func(i, j int) bool { return tips[i].Cmp(tips[j]) < 0 })
+		tip = tips[len(tips)/2]
+	}
+
+	gasPrice := new(big.Int).Mul(baseFee, big.NewInt(100+baseFeeHeadroomPercent))
+	gasPrice.Div(gasPrice, big.NewInt(100))
+
+	return gasPrice.Add(gasPrice, tip), nil
+}
@@ -2,133 +2,289 @@ package minter
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"errors"
 	"fmt"
 	"math/big"
+	"strings"
 	"sync"
+	"time"
 
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/accounts/keystore"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/gocraft/dbr/v2"
+	"github.com/sirupsen/logrus"
 	"github.com/videocoin/marketplace/internal/contracts/dev/nft"
+	"github.com/videocoin/marketplace/internal/datastore"
+	"github.com/videocoin/marketplace/internal/model"
+	"github.com/videocoin/marketplace/internal/network"
 )
 
 const (
-	MintingGasLimit uint64 = 300000
-	ZeroAddress     string = "0000000000000000000000000000000000000000"
+	ZeroAddress string = "0000000000000000000000000000000000000000"
 )
 
+var (
+	ErrERC1155NotConfigured = errors.New("erc1155 contract is not configured")
+	ErrUnknownNetwork       = errors.New("no minter for network")
+)
+
+// Backend is the part of the ethereum client the minter relies on. Both
+// ethclient.Client and backends.SimulatedBackend implement it.
+type Backend interface {
+	bind.ContractBackend
+	bind.DeployBackend
+}
+
+// Minter sends the mint and token uri transactions of a network. Nonces are
+// allocated locally, so transactions don't wait for each other to be mined.
+// With a datastore the transactions are persisted and confirmed in the
+// background by Start, stuck ones are replaced with a higher gas price.
+// Without one every transaction is waited for.
 type Minter struct {
-	ca       common.Address
-	cli      *ethclient.Client
-	contract *nft.NFT721
-	opts     bind.TransactOpts
-	mtx      sync.Mutex
+	logger          *logrus.Entry
+	ds              *datastore.Datastore
+	network         string
+	ca              common.Address
+	ca1155          common.Address
+	cli             Backend
+	fees            FeeEstimator
+	contract        *nft.NFT721
+	contract1155    *nft.NFT1155
+	abi721          abi.ABI
+	abi1155         abi.ABI
+	opts            bind.TransactOpts
+	voucherKey      *ecdsa.PrivateKey
+	chainID         *big.Int
+	nonce           *uint64
+	confirmInterval time.Duration
+	stuckAfter      time.Duration
+	feeBumpPercent  int64
+	maxGasPrice     *big.Int
+	maxBatchSize    int
+	batchMethod     string
+	batchMethod1155 string
+	done            chan struct{}
+	mtx             sync.Mutex
 }
 
-func NewMinter(url string, chainId uint64, contractAddress string, contractKey string, contractKeyPass string) (*Minter, error) {
-	cli, err := ethclient.Dial(url)
+func NewMinter(url string, chainId uint64, contractAddress string, erc1155ContractAddress string, contractKey string, contractKeyPass string, options ...Option) (*Minter, error) {
+	key, err := keystore.DecryptKey([]byte(contractKey), contractKeyPass)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt a key %s: %v", contractKey, err)
+	}
+
+	opts, err := bind.NewKeyedTransactorWithChainID(key.PrivateKey, big.NewInt(int64(chainId)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tx signer: %v", err)
+	}
+
+	rpcCli, err := rpc.Dial(url)
 	if err != nil {
 		return nil, err
 	}
+	cli := ethclient.NewClient(rpcCli)
+
+	options = append([]Option{WithFeeEstimator(NewFeeHistoryEstimator(rpcCli, cli))}, options...)
 
+	m, err := NewMinterWithBackend(cli, opts, contractAddress, erc1155ContractAddress, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	m.EnableLazyMint(key.PrivateKey, big.NewInt(int64(chainId)))
+
+	return m, nil
+}
+
+// NewMinterWithBackend creates a minter on top of an already connected
+// backend, transactions are signed with the given transactor.
+func NewMinterWithBackend(cli Backend, opts *bind.TransactOpts, contractAddress string, erc1155ContractAddress string, options ...Option) (*Minter, error) {
 	ca := common.HexToAddress(contractAddress)
 	contract, err := nft.NewNFT721(ca, cli)
 	if err != nil {
 		return nil, err
 	}
 
-	key, err := keystore.DecryptKey([]byte(contractKey), contractKeyPass)
+	abi721, err := abi.JSON(strings.NewReader(nft.NFT721ABI))
 	if err != nil {
-		return nil, fmt.Errorf("failed to decrypt a key %s: %v", contractKey, err)
+		return nil, err
 	}
 
-	opts, err := bind.NewKeyedTransactorWithChainID(key.PrivateKey, big.NewInt(int64(chainId)))
+	abi1155, err := abi.JSON(strings.NewReader(nft.NFT1155ABI))
 	if err != nil {
-		return nil, fmt.Errorf("failed to create tx signer: %v", err)
+		return nil, err
+	}
+
+	var ca1155 common.Address
+	var contract1155 *nft.NFT1155
+	if erc1155ContractAddress != "" {
+		ca1155 = common.HexToAddress(erc1155ContractAddress)
+		contract1155, err = nft.NewNFT1155(ca1155, cli)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	m := &Minter{
+		logger:          logrus.NewEntry(logrus.StandardLogger()).WithField("system", "minter"),
+		network:         network.DefaultName,
+		ca:              ca,
+		ca1155:          ca1155,
+		cli:             cli,
+		fees:            cli,
+		contract:        contract,
+		contract1155:    contract1155,
+		abi721:          abi721,
+		abi1155:         abi1155,
+		opts:            *opts,
+		confirmInterval: DefaultConfirmInterval,
+		stuckAfter:      DefaultStuckAfter,
+		feeBumpPercent:  DefaultFeeBumpPercent,
+		maxBatchSize:    DefaultMaxBatchSize,
+		batchMethod:     methodMintBatch,
+		batchMethod1155: methodMintBatch,
+		done:            make(chan struct{}),
+	}
+
+	for _, o := range options {
+		if err := o(m); err != nil {
+			return nil, err
+		}
 	}
 
-	return &Minter{
-		ca:       ca,
-		cli:      cli,
-		contract: contract,
-		opts:     *opts,
-	}, nil
+	return m, nil
 }
 
 func (m *Minter) ContractAddress() common.Address {
 	return m.ca
 }
 
-func (m *Minter) Mint(ctx context.Context, to common.Address, id *big.Int, uri string) (*types.Transaction, error) {
-	m.mtx.Lock()
-	defer m.mtx.Unlock()
-
-	// opts := m.getCallOpts(ctx)
-	// _, err := m.contract.OwnerOf(opts, id)
-	// if err == nil {
-	// 	return nil, fmt.Errorf("token with ID %s already exists", id.String())
-	// }
-	// if err.Error() != "execution reverted: ERC721: owner query for nonexistent token" {
-	// 	return nil, err
-	// }
-
-	txOpts := m.getTxOpts(ctx)
-	tx, err := m.contract.Mint(txOpts, to, id, uri)
+func (m *Minter) ERC1155ContractAddress() common.Address {
+	return m.ca1155
+}
+
+func (m *Minter) SupportsERC1155() bool {
+	return m.contract1155 != nil
+}
+
+// Mint1155 mints amount editions of the token to the given address. The
+// NFT1155 contract mints a single unit per call, so editions which are
+// already held by the address or being minted are not minted again when a
+// failed run is retried. The returned transaction is nil if all editions
+// have already been minted.
+func (m *Minter) Mint1155(ctx context.Context, to common.Address, id *big.Int, amount int64) (*model.ChainTx, error) {
+	if m.contract1155 == nil {
+		return nil, ErrERC1155NotConfigured
+	}
+
+	balance, err := m.contract1155.BalanceOf(m.getCallOpts(ctx), to, id)
 	if err != nil {
 		return nil, err
 	}
 
-	return tx, m.waitMined(ctx, tx)
-}
+	minted := balance.Int64()
+	if m.ds != nil {
+		pending, err := m.ds.ChainTxs.CountPendingByAssetID(ctx, model.ChainTxKindMintEdition, id.Int64())
+		if err != nil {
+			return nil, err
+		}
+		minted += pending
+	}
 
-func (m *Minter) UpdateTokenURI(ctx context.Context, id *big.Int, uri string) (*types.Transaction, error) {
-	m.mtx.Lock()
-	defer m.mtx.Unlock()
-
-	// opts := m.getCallOpts(ctx)
-	// _, err := m.contract.OwnerOf(opts, id)
-	// if err == nil {
-	// 	return nil, fmt.Errorf("token with ID %s already exists", id.String())
-	// }
-	// if err.Error() != "execution reverted: ERC721: owner query for nonexistent token" {
-	// 	return nil, err
-	// }
-
-	txOpts := m.getTxOpts(ctx)
-	tx, err := m.contract.UpdateTokenURI(txOpts, id, uri)
+	data, err := m.abi1155.Pack("mint", to, id)
 	if err != nil {
 		return nil, err
 	}
 
-	return tx, m.waitMined(ctx, tx)
+	var chainTx *model.ChainTx
+	for i := minted; i < amount; i++ {
+		chainTx, err = m.send(ctx, &model.ChainTx{
+			Kind:    model.ChainTxKindMintEdition,
+			AssetID: id.Int64(),
+		}, m.ca1155, data)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return chainTx, nil
 }
 
-func (m *Minter) getCallOpts(ctx context.Context) *bind.CallOpts {
-	return &bind.CallOpts{
-		Context: ctx,
+// Mint sends the mint transaction of the token, or returns the one already
+// sent for it.
+func (m *Minter) Mint(ctx context.Context, to common.Address, id *big.Int, uri string) (*model.ChainTx, error) {
+	if m.ds != nil {
+		chainTx, err := m.ds.ChainTxs.GetLatestByAssetID(ctx, model.ChainTxKindMint, id.Int64())
+		if err == nil {
+			return chainTx, nil
+		}
+		if err != datastore.ErrChainTxNotFound {
+			return nil, err
+		}
 	}
-}
 
-func (m *Minter) getTxOpts(ctx context.Context) *bind.TransactOpts {
-	return &bind.TransactOpts{
-		GasLimit: MintingGasLimit,
-		Context:  ctx,
-		From:     m.opts.From,
-		Signer:   m.opts.Signer,
+	data, err := m.abi721.Pack("mint", to, id, uri)
+	if err != nil {
+		return nil, err
 	}
+
+	return m.send(ctx, &model.ChainTx{
+		Kind:     model.ChainTxKindMint,
+		AssetID:  id.Int64(),
+		TokenURI: dbr.NewNullString(uri),
+	}, m.ca, data)
 }
 
-func (m *Minter) waitMined(ctx context.Context, tx *types.Transaction) error {
-	receipt, err := bind.WaitMined(ctx, m.cli, tx)
+// UpdateTokenURI sends the token uri update, or returns the pending one
+// setting the same uri.
+func (m *Minter) UpdateTokenURI(ctx context.Context, id *big.Int, uri string) (*model.ChainTx, error) {
+	if m.ds != nil {
+		chainTx, err := m.ds.ChainTxs.GetLatestByAssetID(ctx, model.ChainTxKindTokenURI, id.Int64())
+		if err == nil && chainTx.IsPending() && chainTx.TokenURI.String == uri {
+			return chainTx, nil
+		}
+		if err != nil && err != datastore.ErrChainTxNotFound {
+			return nil, err
+		}
+	}
+
+	data, err := m.abi721.Pack("updateTokenURI", id, uri)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if receipt.Status != types.ReceiptStatusSuccessful {
-		return fmt.Errorf("transaction %s failed", tx.Hash().String())
+	return m.send(ctx, &model.ChainTx{
+		Kind:     model.ChainTxKindTokenURI,
+		AssetID:  id.Int64(),
+		TokenURI: dbr.NewNullString(uri),
+	}, m.ca, data)
+}
+
+// OwnerOf returns the current owner of the token.
+func (m *Minter) OwnerOf(ctx context.Context, id *big.Int) (common.Address, error) {
+	return m.contract.OwnerOf(m.getCallOpts(ctx), id)
+}
+
+// BalanceOf1155 returns how many editions of the token the address holds.
+func (m *Minter) BalanceOf1155(ctx context.Context, owner common.Address, id *big.Int) (*big.Int, error) {
+	if m.contract1155 == nil {
+		return nil, ErrERC1155NotConfigured
 	}
 
-	return nil
+	return m.contract1155.BalanceOf(m.getCallOpts(ctx), owner, id)
+}
+
+func (m *Minter) TokenURI(ctx context.Context, id *big.Int) (string, error) {
+	return m.contract.TokenURI(m.getCallOpts(ctx), id)
+}
+
+func (m *Minter) getCallOpts(ctx context.Context) *bind.CallOpts {
+	return &bind.CallOpts{
+		Context: ctx,
+	}
 }
@@ -0,0 +1,12 @@
+package minter
+
+// Minters holds the minter of every network by network name.
+type Minters map[string]*Minter
+
+func (ms Minters) Get(network string) (*Minter, error) {
+	m, ok := ms[network]
+	if !ok {
+		return nil, ErrUnknownNetwork
+	}
+	return m, nil
+}
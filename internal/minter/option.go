@@ -0,0 +1,111 @@
+package minter
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/videocoin/marketplace/internal/datastore"
+)
+
+type Option func(m *Minter) error
+
+func WithLogger(logger *logrus.Entry) Option {
+	return func(m *Minter) error {
+		m.logger = logger
+		return nil
+	}
+}
+
+// WithDatastore persists the sent transactions, which are then confirmed in
+// the background instead of being waited for.
+func WithDatastore(ds *datastore.Datastore) Option {
+	return func(m *Minter) error {
+		m.ds = ds
+		return nil
+	}
+}
+
+func WithNetwork(name string) Option {
+	return func(m *Minter) error {
+		m.network = name
+		return nil
+	}
+}
+
+func WithFeeEstimator(fees FeeEstimator) Option {
+	return func(m *Minter) error {
+		m.fees = fees
+		return nil
+	}
+}
+
+func WithConfirmInterval(interval time.Duration) Option {
+	return func(m *Minter) error {
+		m.confirmInterval = interval
+		return nil
+	}
+}
+
+// WithStuckAfter sets how long a transaction may stay unmined before it is
+// replaced with a higher gas price.
+func WithStuckAfter(d time.Duration) Option {
+	return func(m *Minter) error {
+		m.stuckAfter = d
+		return nil
+	}
+}
+
+// WithFeeBumpPercent sets how much the gas price of a replacement is
+// raised, nodes reject replacements below MinFeeBumpPercent.
+func WithFeeBumpPercent(percent int64) Option {
+	return func(m *Minter) error {
+		if percent < MinFeeBumpPercent {
+			return ErrFeeBumpTooLow
+		}
+		m.feeBumpPercent = percent
+		return nil
+	}
+}
+
+// WithMaxGasPrice caps the gas price of replacements, nil leaves it
+// uncapped.
+func WithMaxGasPrice(price *big.Int) Option {
+	return func(m *Minter) error {
+		m.maxGasPrice = price
+		return nil
+	}
+}
+
+// WithMaxBatchSize sets how many tokens are packed into a single mint
+// transaction when the contract has a batch method.
+func WithMaxBatchSize(size int) Option {
+	return func(m *Minter) error {
+		m.maxBatchSize = size
+		return nil
+	}
+}
+
+// WithBatchMint sets the method, mint, mintBatch or multicall, ERC721
+// tokens are minted with.
+func WithBatchMint(method string) Option {
+	return func(m *Minter) (err error) {
+		if method == "" {
+			return nil
+		}
+		m.batchMethod, err = batchMethod(m.abi721, method)
+		return err
+	}
+}
+
+// WithBatchMint1155 sets the method, mint, mintBatch or multicall, editions
+// are minted with.
+func WithBatchMint1155(method string) Option {
+	return func(m *Minter) (err error) {
+		if method == "" {
+			return nil
+		}
+		m.batchMethod1155, err = batchMethod(m.abi1155, method)
+		return err
+	}
+}
@@ -0,0 +1,469 @@
+package minter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/AlekSi/pointer"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/videocoin/marketplace/internal/datastore"
+	"github.com/videocoin/marketplace/internal/model"
+	"github.com/videocoin/marketplace/pkg/ethutil"
+)
+
+const (
+	DefaultConfirmInterval = 15 * time.Second
+	DefaultStuckAfter      = 3 * time.Minute
+	// DefaultFeeBumpPercent is above the 10% nodes require to accept a
+	// replacement transaction.
+	DefaultFeeBumpPercent = 15
+	// MinFeeBumpPercent is the price bump of the go-ethereum tx pool, a
+	// replacement paying less is rejected as underpriced.
+	MinFeeBumpPercent = 10
+
+	// gasLimitHeadroomPercent is added to the estimated gas, the estimate
+	// is made against the latest state which can change until the
+	// transaction is mined.
+	gasLimitHeadroomPercent = 20
+)
+
+var (
+	ErrTxReverted    = errors.New("transaction reverted")
+	ErrFeeBumpTooLow = errors.New("fee bump is below the replacement price bump of nodes")
+)
+
+// Start confirms the pending transactions of the minter until it is
+// stopped. Transactions are only tracked with a datastore.
+func (m *Minter) Start(errCh chan error) {
+	if m.ds == nil {
+		return
+	}
+
+	m.logger.
+		WithField("network", m.network).
+		WithField("from", m.opts.From.Hex()).
+		Info("starting minter tx manager")
+
+	t := time.NewTicker(m.confirmInterval)
+	defer t.Stop()
+
+	for {
+		err := m.confirmPending(context.Background())
+		if err != nil {
+			m.logger.WithError(err).Error("failed to confirm pending transactions")
+		}
+
+		select {
+		case <-m.done:
+			return
+		case <-t.C:
+		}
+	}
+}
+
+func (m *Minter) Stop() error {
+	close(m.done)
+	return nil
+}
+
+// send signs the transaction with the next nonce of the minter. It is
+// persisted before it is broadcast, so it is confirmed even if the process
+// stops right after.
+func (m *Minter) send(ctx context.Context, chainTx *model.ChainTx, to common.Address, data []byte) (*model.ChainTx, error) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	gasLimit, err := m.estimateGas(ctx, to, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to estimate gas: %s", err)
+	}
+
+	gasPrice, err := m.fees.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to suggest gas price: %s", err)
+	}
+
+	nonce, err := m.nextNonce(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nonce: %s", err)
+	}
+
+	tx, err := m.opts.Signer(m.opts.From, types.NewTransaction(nonce, to, big.NewInt(0), gasLimit, gasPrice, data))
+	if err != nil {
+		return nil, err
+	}
+
+	chainTx.Network = m.network
+	chainTx.FromAddress = strings.ToLower(m.opts.From.Hex())
+	chainTx.ToAddress = strings.ToLower(to.Hex())
+	chainTx.Nonce = nonce
+	chainTx.Data = hexutil.Encode(data)
+	chainTx.GasLimit = gasLimit
+	chainTx.GasPrice = gasPrice.String()
+	chainTx.TxHash = tx.Hash().Hex()
+	chainTx.Hashes = model.ChainTxHashes{tx.Hash().Hex()}
+	chainTx.SentAt = pointer.ToTime(time.Now())
+
+	logger := m.logger.
+		WithField("network", m.network).
+		WithField("kind", chainTx.Kind).
+		WithField("asset_id", chainTx.AssetID).
+		WithField("nonce", nonce).
+		WithField("gas_limit", gasLimit).
+		WithField("gas_price", gasPrice.String()).
+		WithField("tx_hash", chainTx.TxHash)
+
+	if m.ds == nil {
+		err = m.cli.SendTransaction(ctx, tx)
+		if err != nil {
+			m.nonce = nil
+			return nil, err
+		}
+		m.nonce = pointer.ToUint64(nonce + 1)
+
+		logger.Info("transaction has been sent")
+
+		err = m.waitMined(ctx, tx)
+		if err != nil {
+			return nil, err
+		}
+		chainTx.Status = model.ChainTxStatusConfirmed
+
+		return chainTx, nil
+	}
+
+	err = m.ds.ChainTxs.Create(ctx, chainTx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save transaction: %s", err)
+	}
+
+	err = m.cli.SendTransaction(ctx, tx)
+	if err != nil {
+		// the nonce is allocated again from the node
+		m.nonce = nil
+
+		markErr := m.ds.ChainTxs.MarkStatusAsFailed(ctx, chainTx, err)
+		if markErr != nil {
+			logger.WithError(markErr).Error("failed to mark transaction as failed")
+		}
+
+		return nil, err
+	}
+	m.nonce = pointer.ToUint64(nonce + 1)
+
+	logger.Info("transaction has been sent")
+
+	return chainTx, nil
+}
+
+// nextNonce returns the locally allocated nonce. It is read again from the
+// node after a failure, persisted pending transactions the node may have
+// dropped keep their nonces.
+func (m *Minter) nextNonce(ctx context.Context) (uint64, error) {
+	if m.nonce != nil {
+		return *m.nonce, nil
+	}
+
+	nonce, err := m.cli.PendingNonceAt(ctx, m.opts.From)
+	if err != nil {
+		return 0, err
+	}
+
+	if m.ds != nil {
+		maxNonce, err := m.ds.ChainTxs.GetMaxNonce(ctx, m.network, strings.ToLower(m.opts.From.Hex()))
+		if err != nil {
+			return 0, err
+		}
+		if maxNonce.Valid && uint64(maxNonce.Int64) >= nonce {
+			nonce = uint64(maxNonce.Int64) + 1
+		}
+	}
+
+	m.nonce = pointer.ToUint64(nonce)
+
+	return nonce, nil
+}
+
+func (m *Minter) estimateGas(ctx context.Context, to common.Address, data []byte) (uint64, error) {
+	gas, err := m.cli.EstimateGas(ctx, ethereum.CallMsg{
+		From: m.opts.From,
+		To:   &to,
+		Data: data,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return gas * (100 + gasLimitHeadroomPercent) / 100, nil
+}
+
+func (m *Minter) confirmPending(ctx context.Context) error {
+	chainTxs, err := m.ds.ChainTxs.ListPending(ctx, m.network, strings.ToLower(m.opts.From.Hex()))
+	if err != nil {
+		return err
+	}
+
+	for _, chainTx := range chainTxs {
+		err = m.confirm(ctx, chainTx)
+		if err != nil {
+			m.logger.
+				WithField("network", m.network).
+				WithField("nonce", chainTx.Nonce).
+				WithField("tx_hash", chainTx.TxHash).
+				WithError(err).
+				Error("failed to confirm transaction")
+		}
+	}
+
+	return nil
+}
+
+// confirm looks for the receipt of any transaction sent with the nonce and
+// replaces the transaction when it has been pending for too long.
+func (m *Minter) confirm(ctx context.Context, chainTx *model.ChainTx) error {
+	for i := len(chainTx.Hashes) - 1; i >= 0; i-- {
+		hash := common.HexToHash(chainTx.Hashes[i])
+		receipt, err := m.cli.TransactionReceipt(ctx, hash)
+		if err != nil {
+			if err == ethereum.NotFound {
+				continue
+			}
+			return err
+		}
+
+		if receipt.Status != types.ReceiptStatusSuccessful {
+			err = m.ds.ChainTxs.MarkStatusAsFailed(ctx, chainTx, fmt.Errorf("%w: %s", ErrTxReverted, hash.Hex()))
+			if err != nil {
+				return err
+			}
+
+			return m.onFailed(ctx, chainTx)
+		}
+
+		err = m.ds.ChainTxs.MarkStatusAsConfirmed(ctx, chainTx, hash.Hex(), receipt.BlockNumber.Uint64())
+		if err != nil {
+			return err
+		}
+
+		return m.onConfirmed(ctx, chainTx)
+	}
+
+	if chainTx.SentAt != nil && time.Since(*chainTx.SentAt) < m.stuckAfter {
+		return nil
+	}
+
+	return m.bump(ctx, chainTx)
+}
+
+// bump replaces the transaction with one paying a higher gas price. It is
+// also sent again when the node has dropped it.
+func (m *Minter) bump(ctx context.Context, chainTx *model.ChainTx) error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	logger := m.logger.
+		WithField("network", m.network).
+		WithField("nonce", chainTx.Nonce).
+		WithField("tx_hash", chainTx.TxHash)
+
+	gasPrice, err := ethutil.ParseBigInt(chainTx.GasPrice)
+	if err != nil {
+		return err
+	}
+
+	if m.maxGasPrice != nil && gasPrice.Cmp(m.maxGasPrice) >= 0 {
+		logger.Warning("transaction is stuck at the max gas price")
+		return nil
+	}
+
+	bumped := bumpGasPrice(gasPrice, m.feeBumpPercent)
+
+	suggested, err := m.fees.SuggestGasPrice(ctx)
+	if err == nil && suggested.Cmp(bumped) > 0 {
+		bumped = suggested
+	}
+
+	if m.maxGasPrice != nil && bumped.Cmp(m.maxGasPrice) > 0 {
+		bumped = new(big.Int).Set(m.maxGasPrice)
+	}
+
+	data, err := hexutil.Decode(chainTx.Data)
+	if err != nil {
+		return err
+	}
+
+	tx := types.NewTransaction(
+		chainTx.Nonce,
+		common.HexToAddress(chainTx.ToAddress),
+		big.NewInt(0),
+		chainTx.GasLimit,
+		bumped,
+		data,
+	)
+	tx, err = m.opts.Signer(m.opts.From, tx)
+	if err != nil {
+		return err
+	}
+
+	err = m.cli.SendTransaction(ctx, tx)
+	if err != nil {
+		return fmt.Errorf("failed to send replacement: %s", err)
+	}
+
+	chainTx.TxHash = tx.Hash().Hex()
+	chainTx.Hashes = append(chainTx.Hashes, chainTx.TxHash)
+	chainTx.GasPrice = bumped.String()
+	chainTx.Bumps++
+	chainTx.SentAt = pointer.ToTime(time.Now())
+
+	err = m.ds.ChainTxs.UpdateSent(ctx, chainTx)
+	if err != nil {
+		return err
+	}
+
+	logger.
+		WithField("replacement_tx_hash", chainTx.TxHash).
+		WithField("gas_price", chainTx.GasPrice).
+		Info("stuck transaction has been replaced")
+
+	return nil
+}
+
+// bumpGasPrice raises the gas price by the percentage, rounded up so that a
+// low price still gets above the price bump nodes require.
+func bumpGasPrice(gasPrice *big.Int, percent int64) *big.Int {
+	bumped := new(big.Int).Mul(gasPrice, big.NewInt(100+percent))
+	bumped.Add(bumped, big.NewInt(99))
+	bumped.Div(bumped, big.NewInt(100))
+
+	if bumped.Cmp(gasPrice) <= 0 {
+		bumped.Add(gasPrice, big.NewInt(1))
+	}
+
+	return bumped
+}
+
+// onConfirmed points the assets at the mined transaction.
+func (m *Minter) onConfirmed(ctx context.Context, chainTx *model.ChainTx) error {
+	uris, err := m.tokenURIs(chainTx)
+	if err != nil {
+		return fmt.Errorf("failed to read token uris: %s", err)
+	}
+
+	for _, assetID := range chainTx.Assets() {
+		err = m.onAssetConfirmed(ctx, chainTx, assetID, uris[assetID])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *Minter) onAssetConfirmed(ctx context.Context, chainTx *model.ChainTx, assetID int64, tokenURI string) error {
+	asset, err := m.ds.Assets.GetByID(ctx, assetID)
+	if err != nil {
+		return err
+	}
+
+	fields := datastore.AssetUpdatedFields{}
+	switch chainTx.Kind {
+	case model.ChainTxKindMint, model.ChainTxKindMintEdition:
+		fields.MintTxID = pointer.ToString(chainTx.TxHash)
+	case model.ChainTxKindTokenURI:
+		fields.TokenURITxID = pointer.ToString(chainTx.TxHash)
+	}
+	if tokenURI != "" {
+		fields.ChainTokenURI = pointer.ToString(tokenURI)
+	}
+
+	err = m.ds.Assets.Update(ctx, asset, fields)
+	if err != nil {
+		return err
+	}
+
+	m.logger.
+		WithField("network", m.network).
+		WithField("kind", chainTx.Kind).
+		WithField("asset_id", asset.ID).
+		WithField("tx_hash", chainTx.TxHash).
+		Info("transaction has been confirmed")
+
+	if chainTx.Kind != model.ChainTxKindMint {
+		return nil
+	}
+
+	mediaItems, err := m.ds.Media.ListByAssetID(ctx, asset.ID)
+	if err != nil {
+		return err
+	}
+	asset.Media = mediaItems
+
+	// the metadata has been published again if the asset was sold before
+	// the token got minted
+	if asset.IsTokenURISynced() {
+		return nil
+	}
+
+	job, err := model.NewTokenURISyncJob(asset)
+	if err != nil {
+		return err
+	}
+
+	return m.ds.Jobs.Create(ctx, job)
+}
+
+// onFailed marks the assets which couldn't be minted as failed. Lazily
+// minted assets stay unminted, their voucher is redeemed again at the next
+// order.
+func (m *Minter) onFailed(ctx context.Context, chainTx *model.ChainTx) error {
+	m.logger.
+		WithField("network", m.network).
+		WithField("kind", chainTx.Kind).
+		WithField("asset_id", chainTx.AssetID).
+		WithField("tx_hash", chainTx.TxHash).
+		Error("transaction has failed")
+
+	if chainTx.Kind == model.ChainTxKindTokenURI {
+		return nil
+	}
+
+	for _, assetID := range chainTx.Assets() {
+		asset, err := m.ds.Assets.GetByID(ctx, assetID)
+		if err != nil {
+			return err
+		}
+
+		if asset.LazyMint {
+			continue
+		}
+
+		err = m.ds.Assets.MarkStatusAsFailed(ctx, asset)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *Minter) waitMined(ctx context.Context, tx *types.Transaction) error {
+	receipt, err := bind.WaitMined(ctx, m.cli, tx)
+	if err != nil {
+		return err
+	}
+
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return fmt.Errorf("%w: %s", ErrTxReverted, tx.Hash().String())
+	}
+
+	return nil
+}
@@ -0,0 +1,343 @@
+package minter_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/gocraft/dbr/v2"
+	"github.com/videocoin/marketplace/internal/datastore"
+	"github.com/videocoin/marketplace/internal/datastore/dbtest"
+	"github.com/videocoin/marketplace/internal/minter"
+	"github.com/videocoin/marketplace/internal/model"
+	"github.com/videocoin/marketplace/internal/simchain"
+)
+
+// newTxChain deploys the contracts on a backend which only mines pending
+// transactions on Commit, so sent transactions stay pending.
+func newTxChain(t *testing.T) (*simchain.SimulatedBackend, *simchain.Chain) {
+	owner, _, err := simchain.NewTransactor()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	backend, err := simchain.NewSimulatedBackend(core.GenesisAlloc{
+		owner.From: {Balance: new(big.Int).Lsh(big.NewInt(1), 100)},
+	}, 30000000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(backend.Close)
+
+	chain, err := simchain.Deploy(backend, owner, &simchain.DeployConfig{Name: "Test", Symbol: "TST"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return backend, chain
+}
+
+func newTxMinter(t *testing.T, backend minter.Backend, chain *simchain.Chain, opts ...minter.Option) *minter.Minter {
+	m, err := minter.NewMinterWithBackend(backend, chain.Owner, chain.NFT721Addr.Hex(), "", opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return m
+}
+
+// flakyBackend fails the next send, after broadcasting the transaction if
+// broadcast is set, the way a node timing out on an accepted transaction
+// does.
+type flakyBackend struct {
+	minter.Backend
+	sendErr   error
+	broadcast bool
+}
+
+func (b *flakyBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	if b.sendErr == nil {
+		return b.Backend.SendTransaction(ctx, tx)
+	}
+
+	err := b.sendErr
+	b.sendErr = nil
+	if b.broadcast {
+		if sendErr := b.Backend.SendTransaction(ctx, tx); sendErr != nil {
+			return sendErr
+		}
+	}
+
+	return err
+}
+
+// staleBackend is a node which has not seen the pending transactions of
+// the minter.
+type staleBackend struct {
+	minter.Backend
+	nonce uint64
+}
+
+func (b *staleBackend) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return b.nonce, nil
+}
+
+func TestSendAllocatesNonceAfterFailure(t *testing.T) {
+	ctx := context.Background()
+	_, chain := newTxChain(t)
+
+	backend := &flakyBackend{Backend: chain.Backend}
+	m := newTxMinter(t, backend, chain)
+
+	first, err := m.Mint(ctx, chain.Owner.From, big.NewInt(1), "ipfs://1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the node has the transaction although the send failed, the nonce is
+	// read again from the node
+	backend.sendErr, backend.broadcast = errors.New("timeout"), true
+	if _, err := m.Mint(ctx, chain.Owner.From, big.NewInt(2), "ipfs://2"); err == nil {
+		t.Fatal("failed send returned no error")
+	}
+
+	third, err := m.Mint(ctx, chain.Owner.From, big.NewInt(3), "ipfs://3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if third.Nonce != first.Nonce+2 {
+		t.Errorf("nonce after a broadcast failure = %d, want %d", third.Nonce, first.Nonce+2)
+	}
+
+	// the transaction never reached the node, its nonce is used again
+	backend.sendErr, backend.broadcast = errors.New("connection refused"), false
+	if _, err := m.Mint(ctx, chain.Owner.From, big.NewInt(4), "ipfs://4"); err == nil {
+		t.Fatal("failed send returned no error")
+	}
+
+	fourth, err := m.Mint(ctx, chain.Owner.From, big.NewInt(4), "ipfs://4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fourth.Nonce != first.Nonce+3 {
+		t.Errorf("nonce after a rejected send = %d, want %d", fourth.Nonce, first.Nonce+3)
+	}
+
+	for _, id := range []int64{1, 2, 3, 4} {
+		owner, err := chain.NFT721.OwnerOf(&bind.CallOpts{}, big.NewInt(id))
+		if err != nil {
+			t.Errorf("token %d: %s", id, err)
+			continue
+		}
+		if owner != chain.Owner.From {
+			t.Errorf("token %d owner = %s, want %s", id, owner.Hex(), chain.Owner.From.Hex())
+		}
+	}
+}
+
+func TestFeeBumpBelowPriceBumpIsRejected(t *testing.T) {
+	_, chain := newTxChain(t)
+
+	_, err := minter.NewMinterWithBackend(
+		chain.Backend,
+		chain.Owner,
+		chain.NFT721Addr.Hex(),
+		"",
+		minter.WithFeeBumpPercent(minter.MinFeeBumpPercent-1),
+	)
+	if err != minter.ErrFeeBumpTooLow {
+		t.Errorf("fee bump of %d%% returned %v, want %v", minter.MinFeeBumpPercent-1, err, minter.ErrFeeBumpTooLow)
+	}
+}
+
+func TestBumpGasPrice(t *testing.T) {
+	tests := []struct {
+		gasPrice, percent, want int64
+	}{
+		{1, minter.MinFeeBumpPercent, 2},
+		{9, minter.MinFeeBumpPercent, 10},
+		{1000, minter.MinFeeBumpPercent, 1100},
+		{1001, minter.MinFeeBumpPercent, 1102},
+		{1000, minter.DefaultFeeBumpPercent, 1150},
+	}
+
+	for _, tt := range tests {
+		got := minter.BumpGasPrice(big.NewInt(tt.gasPrice), tt.percent)
+		if got.Cmp(big.NewInt(tt.want)) != 0 {
+			t.Errorf("bump of %d by %d%% = %s, want %d", tt.gasPrice, tt.percent, got, tt.want)
+		}
+	}
+}
+
+// TestNextNonceRecoversFromMaxNonce restarts the minter against a node
+// which has not seen its pending transaction, the nonce is allocated after
+// the persisted one.
+func TestNextNonceRecoversFromMaxNonce(t *testing.T) {
+	ds := dbtest.Open(t)
+	ctx := context.Background()
+	backend, chain := newTxChain(t)
+
+	nodeNonce, err := backend.PendingNonceAt(ctx, chain.Owner.From)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := newTxMinter(t, backend, chain, minter.WithDatastore(ds)).
+		Mint(ctx, chain.Owner.From, big.NewInt(1), "ipfs://1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Nonce != nodeNonce {
+		t.Fatalf("nonce = %d, want %d", first.Nonce, nodeNonce)
+	}
+
+	restarted := newTxMinter(t, &staleBackend{Backend: backend, nonce: nodeNonce}, chain, minter.WithDatastore(ds))
+	second, err := restarted.Mint(ctx, chain.Owner.From, big.NewInt(2), "ipfs://2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.Nonce != nodeNonce+1 {
+		t.Errorf("nonce after restart = %d, want %d", second.Nonce, nodeNonce+1)
+	}
+
+	backend.Commit()
+
+	for _, chainTx := range []*model.ChainTx{first, second} {
+		receipt, err := backend.TransactionReceipt(ctx, common.HexToHash(chainTx.TxHash))
+		if err != nil {
+			t.Errorf("nonce %d: %s", chainTx.Nonce, err)
+			continue
+		}
+		if receipt.Status != types.ReceiptStatusSuccessful {
+			t.Errorf("nonce %d: transaction has failed", chainTx.Nonce)
+		}
+	}
+}
+
+// TestStuckTransactionIsReplaced replaces a pending mint paying 1 wei, a
+// bump rounded down would pay the same and be rejected by the node, and
+// confirms the replacement once it is mined.
+func TestStuckTransactionIsReplaced(t *testing.T) {
+	ds := dbtest.Open(t)
+	ctx := context.Background()
+	backend, chain := newTxChain(t)
+
+	asset := newMintAsset(ctx, t, ds, chain)
+	m := newTxMinter(t, backend, chain,
+		minter.WithDatastore(ds),
+		minter.WithStuckAfter(0),
+		minter.WithFeeBumpPercent(minter.MinFeeBumpPercent),
+	)
+
+	sent, err := m.Mint(ctx, chain.Owner.From, big.NewInt(asset.ID), "ipfs://token")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.ConfirmPending(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	replaced, err := ds.ChainTxs.GetLatestByAssetID(ctx, model.ChainTxKindMint, asset.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replaced.Bumps != 1 || len(replaced.Hashes) != 2 || replaced.TxHash == sent.TxHash {
+		t.Fatalf("transaction has not been replaced: bumps %d, hashes %v", replaced.Bumps, replaced.Hashes)
+	}
+
+	sentPrice, _ := new(big.Int).SetString(sent.GasPrice, 10)
+	replacedPrice, _ := new(big.Int).SetString(replaced.GasPrice, 10)
+	threshold := new(big.Int).Mul(sentPrice, big.NewInt(100+simchain.PriceBump))
+	threshold.Div(threshold, big.NewInt(100))
+	if replacedPrice.Cmp(sentPrice) <= 0 || replacedPrice.Cmp(threshold) < 0 {
+		t.Errorf("replacement gas price = %s, want above %s by %d%%", replacedPrice, sentPrice, simchain.PriceBump)
+	}
+
+	backend.Commit()
+
+	if err := m.ConfirmPending(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	confirmed, err := ds.ChainTxs.GetLatestByAssetID(ctx, model.ChainTxKindMint, asset.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if confirmed.Status != model.ChainTxStatusConfirmed || confirmed.TxHash != replaced.TxHash {
+		t.Errorf("transaction is %s with %s, want confirmed with the replacement %s", confirmed.Status, confirmed.TxHash, replaced.TxHash)
+	}
+
+	asset, err = ds.Assets.GetByID(ctx, asset.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if asset.MintTxID.String != replaced.TxHash {
+		t.Errorf("asset mint tx = %s, want the replacement %s", asset.MintTxID.String, replaced.TxHash)
+	}
+}
+
+// TestReplacedHashIsConfirmed mines the original transaction after a
+// replacement has been recorded, the way a node which didn't get the
+// replacement would, and checks that it is confirmed.
+func TestReplacedHashIsConfirmed(t *testing.T) {
+	ds := dbtest.Open(t)
+	ctx := context.Background()
+	backend, chain := newTxChain(t)
+
+	asset := newMintAsset(ctx, t, ds, chain)
+	m := newTxMinter(t, backend, chain, minter.WithDatastore(ds))
+
+	sent, err := m.Mint(ctx, chain.Owner.From, big.NewInt(asset.ID), "ipfs://token")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	backend.Commit()
+
+	replacement := "0x" + strings.Repeat("ab", 32)
+	sent.TxHash = replacement
+	sent.Hashes = append(sent.Hashes, replacement)
+	sent.Bumps++
+	if err := ds.ChainTxs.UpdateSent(ctx, sent); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.ConfirmPending(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	confirmed, err := ds.ChainTxs.GetLatestByAssetID(ctx, model.ChainTxKindMint, asset.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if confirmed.Status != model.ChainTxStatusConfirmed || confirmed.TxHash != sent.Hashes[0] {
+		t.Errorf("transaction is %s with %s, want confirmed with the replaced %s", confirmed.Status, confirmed.TxHash, sent.Hashes[0])
+	}
+}
+
+func newMintAsset(ctx context.Context, t *testing.T, ds *datastore.Datastore, chain *simchain.Chain) *model.Asset {
+	account := &model.Account{Address: strings.ToLower(chain.Owner.From.Hex())}
+	if err := ds.Accounts.Create(ctx, account); err != nil {
+		t.Fatal(err)
+	}
+
+	asset := &model.Asset{
+		CreatedByID:     account.ID,
+		OwnerID:         account.ID,
+		Status:          model.AssetStatusReady,
+		Name:            dbr.NewNullString("Test"),
+		ContractAddress: dbr.NewNullString(strings.ToLower(chain.NFT721Addr.Hex())),
+	}
+	if err := ds.Assets.Create(ctx, asset); err != nil {
+		t.Fatal(err)
+	}
+
+	return asset
+}
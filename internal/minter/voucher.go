@@ -0,0 +1,151 @@
+package minter
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/videocoin/marketplace/internal/model"
+	"github.com/videocoin/marketplace/pkg/ethutil"
+)
+
+const (
+	VoucherDomainName    = "NFT721"
+	VoucherDomainVersion = "1"
+)
+
+var (
+	ErrLazyMintNotConfigured = errors.New("lazy minting is not configured")
+	ErrInvalidVoucher        = errors.New("invalid mint voucher")
+
+	eip712DomainTypeHash = crypto.Keccak256(
+		[]byte("EIP712Domain(string name,string version,uint256 chainId,address verifyingContract)"),
+	)
+	mintVoucherTypeHash = crypto.Keccak256(
+		[]byte("MintVoucher(uint256 tokenId,string uri,address creator,uint256 price,uint256 royaltyBps)"),
+	)
+)
+
+// EnableLazyMint lets the minter sign mint vouchers with the key of its
+// transactor.
+func (m *Minter) EnableLazyMint(key *ecdsa.PrivateKey, chainID *big.Int) {
+	m.voucherKey = key
+	m.chainID = chainID
+}
+
+func (m *Minter) SupportsLazyMint() bool {
+	return m.voucherKey != nil
+}
+
+// SignVoucher signs the voucher as EIP-712 typed data for the NFT721
+// contract of the minter.
+func (m *Minter) SignVoucher(voucher *model.MintVoucher) error {
+	if m.voucherKey == nil {
+		return ErrLazyMintNotConfigured
+	}
+
+	voucher.ChainID = m.chainID.Uint64()
+	voucher.ContractAddress = m.ca.Hex()
+
+	digest, err := voucherDigest(voucher)
+	if err != nil {
+		return err
+	}
+
+	sig, err := crypto.Sign(digest, m.voucherKey)
+	if err != nil {
+		return err
+	}
+	sig[crypto.RecoveryIDOffset] += 27
+
+	voucher.Signature = hexutil.Encode(sig)
+
+	return nil
+}
+
+// VerifyVoucher checks that the voucher has been signed by the minter for
+// its NFT721 contract.
+func (m *Minter) VerifyVoucher(voucher *model.MintVoucher) error {
+	if m.voucherKey == nil {
+		return ErrLazyMintNotConfigured
+	}
+
+	if voucher.ChainID != m.chainID.Uint64() || !common.IsHexAddress(voucher.ContractAddress) ||
+		common.HexToAddress(voucher.ContractAddress) != m.ca {
+		return ErrInvalidVoucher
+	}
+
+	digest, err := voucherDigest(voucher)
+	if err != nil {
+		return err
+	}
+
+	sig, err := hexutil.Decode(voucher.Signature)
+	if err != nil {
+		return ErrInvalidVoucher
+	}
+
+	signer, err := ethutil.RecoverAddress(digest, sig)
+	if err != nil || signer != crypto.PubkeyToAddress(m.voucherKey.PublicKey) {
+		return ErrInvalidVoucher
+	}
+
+	return nil
+}
+
+// Redeem mints the token of a verified voucher to its creator. The token
+// has to exist before an order for it can be matched, the exchange
+// transfers it from the seller.
+func (m *Minter) Redeem(ctx context.Context, voucher *model.MintVoucher) (*model.ChainTx, error) {
+	err := m.VerifyVoucher(voucher)
+	if err != nil {
+		return nil, err
+	}
+
+	tokenID, ok := math.ParseBig256(voucher.TokenID)
+	if !ok {
+		return nil, ErrInvalidVoucher
+	}
+
+	return m.Mint(ctx, common.HexToAddress(voucher.Creator), tokenID, voucher.URI)
+}
+
+func voucherDigest(voucher *model.MintVoucher) ([]byte, error) {
+	tokenID, ok := math.ParseBig256(voucher.TokenID)
+	if !ok {
+		return nil, ErrInvalidVoucher
+	}
+
+	price, ok := math.ParseBig256(voucher.Price)
+	if !ok {
+		return nil, ErrInvalidVoucher
+	}
+
+	if !common.IsHexAddress(voucher.Creator) || voucher.RoyaltyBps < 0 {
+		return nil, ErrInvalidVoucher
+	}
+
+	domainSeparator := crypto.Keccak256(
+		eip712DomainTypeHash,
+		crypto.Keccak256([]byte(VoucherDomainName)),
+		crypto.Keccak256([]byte(VoucherDomainVersion)),
+		math.U256Bytes(new(big.Int).SetUint64(voucher.ChainID)),
+		common.LeftPadBytes(common.HexToAddress(voucher.ContractAddress).Bytes(), 32),
+	)
+
+	structHash := crypto.Keccak256(
+		mintVoucherTypeHash,
+		math.U256Bytes(tokenID),
+		crypto.Keccak256([]byte(voucher.URI)),
+		common.LeftPadBytes(common.HexToAddress(voucher.Creator).Bytes(), 32),
+		math.U256Bytes(price),
+		math.U256Bytes(big.NewInt(voucher.RoyaltyBps)),
+	)
+
+	return crypto.Keccak256([]byte("\x19\x01"), domainSeparator, structHash), nil
+}
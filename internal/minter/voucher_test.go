@@ -0,0 +1,52 @@
+package minter
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/videocoin/marketplace/internal/model"
+)
+
+func newTestVoucher(t *testing.T) (*Minter, *model.MintVoucher) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := &Minter{ca: common.HexToAddress("0x00000000000000000000000000000000000000c1")}
+	m.EnableLazyMint(key, big.NewInt(1337))
+
+	voucher := &model.MintVoucher{
+		TokenID:    "7",
+		URI:        "ipfs://token",
+		Creator:    "0x00000000000000000000000000000000000000a1",
+		Price:      "1000",
+		RoyaltyBps: 500,
+	}
+	if err := m.SignVoucher(voucher); err != nil {
+		t.Fatal(err)
+	}
+
+	return m, voucher
+}
+
+func TestRedeemRejectsTamperedVoucher(t *testing.T) {
+	m, voucher := newTestVoucher(t)
+
+	tampered := *voucher
+	tampered.Price = "1"
+	_, err := m.Redeem(context.Background(), &tampered)
+	if err != ErrInvalidVoucher {
+		t.Errorf("redeem of a voucher with a changed price returned %v, want %v", err, ErrInvalidVoucher)
+	}
+
+	tampered = *voucher
+	tampered.ContractAddress = "0x00000000000000000000000000000000000000c2"
+	_, err = m.Redeem(context.Background(), &tampered)
+	if err != ErrInvalidVoucher {
+		t.Errorf("redeem of a voucher for another contract returned %v, want %v", err, ErrInvalidVoucher)
+	}
+}
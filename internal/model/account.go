@@ -30,6 +30,12 @@ func (u *Account) Id() int64 {
 	return u.ID
 }
 
+// IsPlaceholder reports whether the account has only been seen on chain,
+// as the recipient of a token, and hasn't been registered yet.
+func (u *Account) IsPlaceholder() bool {
+	return !u.EncryptionPublicKey.Valid || u.EncryptionPublicKey.String == ""
+}
+
 func (u *Account) GetImageURL() *string {
 	if u.ImageCID.String != "" {
 		return pointer.ToString(fmt.Sprintf(DwebIpfsGateway, u.ImageCID.String))
@@ -11,10 +11,14 @@ import (
 	"time"
 
 	"github.com/gocraft/dbr/v2"
+	"github.com/videocoin/marketplace/internal/wyvern"
 	"github.com/videocoin/marketplace/pkg/random"
 	"gopkg.in/vansante/go-ffprobe.v2"
 )
 
+// MaxRoyalty is the highest royalty percentage a creator can set.
+const MaxRoyalty = 50
+
 const (
 	DwebIpfsGateway    = "https://%s.ipfs.dweb.link"
 	IpfsGateway        = "https://%s.ipfs.dweb.link/%s"
@@ -62,6 +66,19 @@ type Asset struct {
 	ContractAddress dbr.NullString `db:"contract_address"`
 	MintTxID        dbr.NullString `db:"mint_tx_id"`
 
+	// Network is the name of the network the token is minted on.
+	Network string `db:"network"`
+
+	// LazyMint assets are listed with a signed voucher instead of being
+	// minted, the token is minted to the creator once an order is placed
+	// for the asset.
+	LazyMint    bool         `db:"lazy_mint"`
+	MintVoucher *MintVoucher `db:"mint_voucher"`
+
+	// BatchID is the batch the asset has been created with, its token is
+	// minted together with the other items of the batch.
+	BatchID dbr.NullInt64 `db:"batch_id"`
+
 	OnSale              bool            `db:"on_sale"`
 	Price               float64         `db:"price"`
 	PutOnSalePrice      dbr.NullFloat64 `db:"put_on_sale_price"`
@@ -75,24 +92,98 @@ type Asset struct {
 
 	TokenCID dbr.NullString `db:"token_cid"`
 
+	// ChainTokenURI is the token uri last confirmed on chain, TokenURITxID
+	// the latest transaction updating it.
+	ChainTokenURI dbr.NullString `db:"chain_token_uri"`
+	TokenURITxID  dbr.NullString `db:"token_uri_tx_id"`
+
 	DRMKey  string `db:"drm_key"`
 	DRMMeta string `db:"drm_meta"`
 
+	// DRMKID identifies the content key of the encrypted media, players
+	// request the key from the license endpoint with it.
+	DRMKID dbr.NullString `db:"drm_kid"`
+
 	Status AssetStatus `db:"status"`
 
 	Locked bool `db:"locked"`
 
+	// OwnerKeyMissing is set when the token has been transferred outside of
+	// the marketplace to an account without an encryption key, the media is
+	// still encrypted for the previous owner.
+	OwnerKeyMissing bool `db:"owner_key_missing"`
+
 	AuctionStartedAt *time.Time `db:"auction_started_at"`
 
-	CreatedBy *Account `db:"-"`
-	Owner     *Account `db:"-"`
-	Media     []*Media `db:"-"`
+	SaleKind       wyvern.SaleKind `db:"sale_kind"`
+	PriceExtra     float64         `db:"price_extra"`
+	ListingTime    *time.Time      `db:"listing_time"`
+	ExpirationTime *time.Time      `db:"expiration_time"`
+
+	Schema ContractSchemaType `db:"schema"`
+	Supply int64              `db:"supply"`
+
+	JobID dbr.NullString `db:"job_id"`
+
+	CreatedBy    *Account            `db:"-"`
+	Owner        *Account            `db:"-"`
+	Media        []*Media            `db:"-"`
+	Auction      *Auction            `db:"-"`
+	Holders      []*AssetHolder      `db:"-"`
+	KeyEnvelopes []*AssetKeyEnvelope `db:"-"`
+}
+
+// IsEdition reports whether the asset is minted as an ERC1155 token with
+// a supply of editions which can be held by several accounts.
+func (a *Asset) IsEdition() bool {
+	return a.Schema == ContractSchemaTypeERC1155
+}
+
+// IsMinted reports whether the mint transaction of the token has been sent.
+func (a *Asset) IsMinted() bool {
+	return a.MintTxID.Valid
+}
+
+// IsUnminted reports whether the asset is lazily minted and still waits for
+// an order to be minted.
+func (a *Asset) IsUnminted() bool {
+	return a.LazyMint && !a.MintTxID.Valid
+}
+
+// RoyaltyBps converts the royalty percentage of the asset to basis points.
+func (a *Asset) RoyaltyBps() int64 {
+	return int64(a.Royalty) * 100
 }
 
 func (a *Asset) IsAuction() bool {
 	return a.PutOnSalePrice.Valid && a.PutOnSalePrice.Float64 > 0
 }
 
+func (a *Asset) IsDutchAuction() bool {
+	return a.SaleKind == wyvern.DutchAuction && a.ListingTime != nil && a.ExpirationTime != nil &&
+		a.ExpirationTime.After(*a.ListingTime)
+}
+
+// CurrentPrice returns the live price of the asset. For a Dutch auction the
+// price declines from Price by PriceExtra over the listing period, see
+// wyvern.Order.CurrentPrice for the exact on-chain calculation.
+func (a *Asset) CurrentPrice(now time.Time) float64 {
+	if !a.IsDutchAuction() {
+		return a.Price
+	}
+
+	duration := a.ExpirationTime.Sub(*a.ListingTime)
+	elapsed := now.Sub(*a.ListingTime)
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	if elapsed > duration {
+		elapsed = duration
+	}
+
+	return a.Price - a.PriceExtra*elapsed.Seconds()/duration.Seconds()
+}
+
 func (a *Asset) StatusIsFailed() bool {
 	return a.Status == AssetStatusFailed
 }
@@ -177,6 +268,26 @@ func (a *Asset) GetIpfsEncryptedUrl() *string {
 	return pointer.ToString(media.GetIpfsEncryptedUrl())
 }
 
+// GetPreviewUrl returns the public teaser of locked media, nil if there is
+// none.
+func (a *Asset) GetPreviewUrl() *string {
+	media := a.GetFirstPrivateMedia()
+	if media == nil || !media.HasPreview() {
+		return nil
+	}
+
+	return pointer.ToString(media.GetCachedPreviewUrl())
+}
+
+func (a *Asset) GetIpfsPreviewUrl() *string {
+	media := a.GetFirstPrivateMedia()
+	if media == nil || !media.HasPreview() {
+		return nil
+	}
+
+	return pointer.ToString(media.GetIpfsPreviewUrl())
+}
+
 func (a *Asset) GetTokenUrl() *string {
 	media := a.GetFirstPrivateMedia()
 	if media == nil {
@@ -184,7 +295,9 @@ func (a *Asset) GetTokenUrl() *string {
 	}
 
 	if a.TokenCID.String != "" {
-		if media.RootKey != "" {
+		if isUrlRoot(media.RootKey) {
+			return pointer.ToString(joinUrl(media.RootKey, fmt.Sprintf("%d.json", a.ID)))
+		} else if media.RootKey != "" {
 			url := fmt.Sprintf(IpfsGateway, a.TokenCID.String, "")
 			url = strings.TrimSuffix(url, "/")
 			return pointer.ToString(url)
@@ -196,6 +309,17 @@ func (a *Asset) GetTokenUrl() *string {
 	return nil
 }
 
+// IsTokenURISynced reports whether the token uri on chain points at the
+// current metadata of the asset. Editions share the uri of the contract.
+func (a *Asset) IsTokenURISynced() bool {
+	if a.IsEdition() {
+		return true
+	}
+
+	tokenURI := a.GetTokenUrl()
+	return tokenURI != nil && a.ChainTokenURI.Valid && a.ChainTokenURI.String == *tokenURI
+}
+
 func GenAssetFolderID() string {
 	return fmt.Sprintf(
 		"%s-%s",
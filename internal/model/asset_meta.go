@@ -28,15 +28,79 @@ type AssetMeta struct {
 	DestThumbKey         string
 	DestThumbBlurredKey  string
 	DestEncKey           string
+
+	// the original upload of video normalized to a streamable mezzanine,
+	// LocalDest and DestKey are the mezzanine then
+	SourceContentType string
+	LocalSourceDest   string
+	DestSourceKey     string
 }
 
 func (m *AssetMeta) MediaType() string {
 	return strings.Split(m.ContentType, "/")[0]
 }
 
+// NeedsNormalization reports whether the upload is video that is not H.264
+// and AAC in MP4, it cannot be streamed before it is transcoded.
+func (m *AssetMeta) NeedsNormalization() bool {
+	if m.MediaType() != MediaTypeVideo || m.Probe == nil {
+		return false
+	}
+
+	video := m.Probe.FirstVideoStream()
+	if video == nil {
+		return false
+	}
+
+	if m.ContentType != "video/mp4" {
+		return true
+	}
+
+	// mov and mp4 share the demuxer of ffprobe, only the brand tells them
+	// apart
+	if m.Probe.Format != nil && m.Probe.Format.Tags != nil &&
+		strings.TrimSpace(m.Probe.Format.Tags.MajorBrand) == "qt" {
+		return true
+	}
+
+	if !IsStreamableVideo(video) {
+		return true
+	}
+
+	audio := m.Probe.FirstAudioStream()
+	return audio != nil && !IsStreamableAudio(audio)
+}
+
+// Normalize keeps the upload as the source and makes an MP4 mezzanine the
+// file the media is played from.
+func (m *AssetMeta) Normalize() {
+	m.SourceContentType = m.ContentType
+	m.LocalSourceDest = m.LocalDest
+	m.DestSourceKey = m.DestKey
+
+	m.ContentType = "video/mp4"
+	m.Name = "mezzanine.mp4"
+	m.LocalDest = strings.TrimSuffix(m.LocalDest, filepath.Ext(m.LocalDest)) + "_mezzanine.mp4"
+	m.DestKey = fmt.Sprintf("%s/%s", m.FolderID, m.Name)
+}
+
+func IsStreamableVideo(s *ffprobe.Stream) bool {
+	return s.CodecName == "h264" && (s.PixFmt == "" || s.PixFmt == "yuv420p" || s.PixFmt == "yuvj420p")
+}
+
+func IsStreamableAudio(s *ffprobe.Stream) bool {
+	return s.CodecName == "aac"
+}
+
 func NewAssetMeta(name, contentType string) *AssetMeta {
 	filename := fmt.Sprintf("original%s", filepath.Ext(name))
 	previewFilename := fmt.Sprintf("preview%s", filepath.Ext(name))
+	// previews of video and audio are transcoded, whatever the upload is
+	if strings.HasPrefix(contentType, "video/") {
+		previewFilename = "preview.mp4"
+	} else if strings.HasPrefix(contentType, "audio/") {
+		previewFilename = "preview.m4a"
+	}
 	encFilename := fmt.Sprintf("encrypted%s", filepath.Ext(name))
 	if strings.HasPrefix(contentType, "video/") ||
 		strings.HasPrefix(contentType, "audio/") {
@@ -62,7 +126,7 @@ func NewAssetMeta(name, contentType string) *AssetMeta {
 		DestThumbBlurredKey:  destThumbBlurredKey,
 		DestEncKey:           destEncKey,
 		LocalDest:            path.Join("/tmp", tmpFilename+filepath.Ext(filename)),
-		LocalPreviewDest:     path.Join("/tmp", tmpFilename+"_preview"+filepath.Ext(filename)),
+		LocalPreviewDest:     path.Join("/tmp", tmpFilename+"_preview"+filepath.Ext(previewFilename)),
 		LocalEncDest:         path.Join("/tmp", tmpFilename+"_encrypted"+filepath.Ext(filename)),
 		LocalThumbDest:       path.Join("/tmp", tmpFilename+".jpg"),
 		LocalThumbBluredDest: path.Join("/tmp", "b_"+tmpFilename+".jpg"),
@@ -0,0 +1,26 @@
+package model
+
+import (
+	"time"
+
+	"github.com/gocraft/dbr/v2"
+)
+
+// MaxAssetBatchSize is the largest number of assets created at once.
+const MaxAssetBatchSize = 100
+
+// AssetBatch is a set of assets created by a creator in a single request,
+// a drop. The tokens are minted by one job once all the assets have been
+// processed, so their mints can be packed into as few transactions as the
+// contracts allow.
+type AssetBatch struct {
+	ID          int64          `db:"id"`
+	CreatedAt   *time.Time     `db:"created_at"`
+	UpdatedAt   *time.Time     `db:"updated_at"`
+	CreatedByID int64          `db:"created_by_id"`
+	Network     string         `db:"network"`
+	Size        int            `db:"size"`
+	MintJobID   dbr.NullString `db:"mint_job_id"`
+
+	Assets []*Asset `db:"-"`
+}
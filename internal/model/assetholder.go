@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// AssetHolder is the balance an account holds of an asset. ERC721 assets
+// have a single holder with a balance of one, ERC1155 editions are spread
+// across any number of holders.
+type AssetHolder struct {
+	AssetID   int64      `db:"asset_id" json:"asset_id"`
+	AccountID int64      `db:"account_id" json:"account_id"`
+	Balance   int64      `db:"balance" json:"balance"`
+	UpdatedAt *time.Time `db:"updated_at" json:"updated_at"`
+
+	Account *Account `db:"-" json:"-"`
+}
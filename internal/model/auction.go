@@ -1,13 +1,163 @@
 package model
 
 import (
+	"errors"
+	"math/big"
 	"time"
+
+	"github.com/gocraft/dbr/v2"
+	"github.com/videocoin/marketplace/pkg/ethutil"
 )
 
 const (
-	DefaultAuctionDuration = 5 * 60
+	DefaultAuctionMinBidIncrementBps = 500
+	DefaultAuctionExtensionWindow    = 10 * 60
+)
+
+var (
+	ErrAuctionNotOpen         = errors.New("auction is not open")
+	ErrAuctionBidTooLow       = errors.New("bid is lower than the minimum bid")
+	ErrAuctionBidPaymentToken = errors.New("bid payment token doesn't match the auction")
+	ErrAuctionBidBySeller     = errors.New("seller can't bid on own auction")
+	ErrAuctionNoExpiration    = errors.New("auction sell order doesn't expire")
 )
 
-func AuctionIsOpen(startedAt *time.Time, duration int) bool {
-	return startedAt.Add(time.Second * time.Duration(duration)).After(time.Now())
+type AuctionStatus string
+
+const (
+	// AuctionStatusOpen accepts bids until EndAt.
+	AuctionStatusOpen AuctionStatus = "OPEN"
+	// AuctionStatusEnded has a winning bid which waits to be matched by the seller.
+	AuctionStatusEnded AuctionStatus = "ENDED"
+	// AuctionStatusUnsold ended without bids or with the reserve price not met.
+	AuctionStatusUnsold    AuctionStatus = "UNSOLD"
+	AuctionStatusSettled   AuctionStatus = "SETTLED"
+	AuctionStatusCancelled AuctionStatus = "CANCELLED"
+)
+
+type Auction struct {
+	ID                  int64         `db:"id"`
+	CreatedAt           *time.Time    `db:"created_at"`
+	AssetID             int64         `db:"asset_id"`
+	SellerID            int64         `db:"seller_id"`
+	SellOrderID         int64         `db:"sell_order_id"`
+	Status              AuctionStatus `db:"status"`
+	PaymentTokenAddress string        `db:"payment_token_address"`
+	StartPrice          string        `db:"start_price"`
+	ReservePrice        string        `db:"reserve_price"`
+	MinBidIncrementBps  int           `db:"min_bid_increment_bps"`
+	ExtensionWindow     int           `db:"extension_window"`
+	StartAt             *time.Time    `db:"start_at"`
+	EndAt               *time.Time    `db:"end_at"`
+	ExpireAt            *time.Time    `db:"expire_at"`
+	WinningBidID        dbr.NullInt64 `db:"winning_bid_id"`
+
+	Bids []*AuctionBid `db:"-"`
+}
+
+type AuctionBid struct {
+	ID        int64      `db:"id"`
+	CreatedAt *time.Time `db:"created_at"`
+	AuctionID int64      `db:"auction_id"`
+	OrderID   int64      `db:"order_id"`
+	BidderID  int64      `db:"bidder_id"`
+	Amount    string     `db:"amount"`
+
+	Bidder *Account `db:"-"`
+	Order  *Order   `db:"-"`
+}
+
+func (a *Auction) IsOpen(now time.Time) bool {
+	return a.Status == AuctionStatusOpen && !now.Before(*a.StartAt) && now.Before(*a.EndAt)
+}
+
+func (a *Auction) IsExpired(now time.Time) bool {
+	return a.Status == AuctionStatusOpen && !now.Before(*a.EndAt)
+}
+
+// HighestBid returns the highest bid, bids are expected to be ordered by amount.
+func (a *Auction) HighestBid() *AuctionBid {
+	if len(a.Bids) == 0 {
+		return nil
+	}
+	return a.Bids[0]
+}
+
+// MinNextBid returns the lowest amount the next bid has to have: the start
+// price for the first bid, the highest bid plus the minimum increment after.
+func (a *Auction) MinNextBid() *big.Int {
+	highest := a.HighestBid()
+	if highest == nil {
+		return bigOrZero(a.StartPrice)
+	}
+
+	amount := bigOrZero(highest.Amount)
+	increment := new(big.Int).Mul(amount, big.NewInt(int64(a.MinBidIncrementBps)))
+	increment.Div(increment, big.NewInt(10000))
+	if increment.Sign() == 0 {
+		increment = big.NewInt(1)
+	}
+
+	return amount.Add(amount, increment)
+}
+
+// ValidateBid checks the bid against the auction rules.
+func (a *Auction) ValidateBid(bidderID int64, paymentToken string, amount *big.Int, now time.Time) error {
+	if !a.IsOpen(now) {
+		return ErrAuctionNotOpen
+	}
+
+	if bidderID == a.SellerID {
+		return ErrAuctionBidBySeller
+	}
+
+	if paymentToken != a.PaymentTokenAddress {
+		return ErrAuctionBidPaymentToken
+	}
+
+	if amount.Cmp(a.MinNextBid()) < 0 {
+		return ErrAuctionBidTooLow
+	}
+
+	return nil
+}
+
+// Extend moves the end of the auction when a bid comes in within the
+// extension window, so last second bids can still be outbid. The end never
+// moves past ExpireAt, the sell order can't be matched once it expires on
+// chain.
+func (a *Auction) Extend(now time.Time) bool {
+	window := time.Duration(a.ExtensionWindow) * time.Second
+	if window <= 0 || a.EndAt.Sub(now) >= window {
+		return false
+	}
+
+	endAt := now.Add(window)
+	if a.ExpireAt != nil && endAt.After(*a.ExpireAt) {
+		endAt = *a.ExpireAt
+	}
+	if !endAt.After(*a.EndAt) {
+		return false
+	}
+
+	a.EndAt = &endAt
+	return true
+}
+
+// ReserveMet reports whether the highest bid reaches the reserve price.
+func (a *Auction) ReserveMet() bool {
+	highest := a.HighestBid()
+	if highest == nil {
+		return false
+	}
+
+	return bigOrZero(highest.Amount).Cmp(bigOrZero(a.ReservePrice)) >= 0
+}
+
+func bigOrZero(value string) *big.Int {
+	v, err := ethutil.ParseBigInt(value)
+	if err != nil {
+		return new(big.Int)
+	}
+	return v
 }
@@ -0,0 +1,71 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/videocoin/marketplace/internal/wyvern"
+)
+
+func TestAuctionExtendIsCappedByExpiration(t *testing.T) {
+	now := time.Unix(1617000000, 0)
+	endAt := now.Add(time.Minute)
+	expireAt := now.Add(5 * time.Minute)
+
+	auction := &Auction{
+		ExtensionWindow: 10 * 60,
+		EndAt:           &endAt,
+		ExpireAt:        &expireAt,
+	}
+
+	if !auction.Extend(now) {
+		t.Fatal("bid within the extension window didn't extend the auction")
+	}
+	if !auction.EndAt.Equal(expireAt) {
+		t.Errorf("end at = %s, want the sell order expiration %s", auction.EndAt, expireAt)
+	}
+
+	if auction.Extend(now.Add(time.Minute)) {
+		t.Errorf("auction has been extended past the sell order expiration to %s", auction.EndAt)
+	}
+}
+
+func TestOrderIsAuction(t *testing.T) {
+	tests := []struct {
+		name  string
+		order wyvern.Order
+		want  bool
+	}{
+		{
+			name:  "fixed price listing which expires",
+			order: wyvern.Order{Side: wyvern.Sell, SaleKind: wyvern.FixedPrice, ExpirationTime: "1617086400"},
+			want:  false,
+		},
+		{
+			name: "english auction",
+			order: wyvern.Order{
+				Side: wyvern.Sell, SaleKind: wyvern.FixedPrice, ExpirationTime: "1617086400",
+				EnglishAuctionReservePrice: "1000000000000000000",
+			},
+			want: true,
+		},
+		{
+			name: "bid",
+			order: wyvern.Order{
+				Side: wyvern.Buy, SaleKind: wyvern.FixedPrice, ExpirationTime: "1617086400",
+				EnglishAuctionReservePrice: "1000000000000000000",
+			},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			order := &Order{WyvernOrder: &tt.order}
+			if got := order.IsAuction(); got != tt.want {
+				t.Errorf("IsAuction() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/gocraft/dbr/v2"
+)
+
+type ChainBlock struct {
+	ChainID    string `db:"chain_id"`
+	Height     uint64 `db:"height"`
+	Hash       string `db:"hash"`
+	ParentHash string `db:"parent_hash"`
+}
+
+// AssetSnapshot keeps the asset fields the orderbook changes when an order is
+// matched, so they can be restored if the block is orphaned.
+type AssetSnapshot struct {
+	ID              int64               `json:"id"`
+	OwnerID         int64               `json:"owner_id"`
+	OnSale          bool                `json:"on_sale"`
+	Status          AssetStatus         `json:"status"`
+	PurchasedBid    dbr.NullFloat64     `json:"purchased_bid"`
+	TokenCID        dbr.NullString      `json:"token_cid"`
+	DRMKey          string              `json:"drm_key"`
+	DRMMeta         string              `json:"drm_meta"`
+	DRMKID          string              `json:"drm_kid"`
+	OwnerKeyMissing bool                `json:"owner_key_missing"`
+	Media           []*MediaSnapshot    `json:"media"`
+	Holders         []*AssetHolder      `json:"holders"`
+	Envelopes       []*AssetKeyEnvelope `json:"envelopes"`
+	ActiveOrderIDs  []int64             `json:"active_order_ids"`
+}
+
+type MediaSnapshot struct {
+	ID           string         `json:"id"`
+	EncryptedKey string         `json:"encrypted_key"`
+	EncryptedCID dbr.NullString `json:"encrypted_cid"`
+}
+
+func (s AssetSnapshot) Value() (driver.Value, error) {
+	b, err := json.Marshal(s)
+	return string(b), err
+}
+
+func (s *AssetSnapshot) Scan(value interface{}) error {
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+
+	return json.Unmarshal(b, &s)
+}
+
+func NewAssetSnapshot(asset *Asset) *AssetSnapshot {
+	snapshot := &AssetSnapshot{
+		ID:              asset.ID,
+		OwnerID:         asset.OwnerID,
+		OnSale:          asset.OnSale,
+		Status:          asset.Status,
+		PurchasedBid:    asset.PurchasedBid,
+		TokenCID:        asset.TokenCID,
+		DRMKey:          asset.DRMKey,
+		DRMMeta:         asset.DRMMeta,
+		DRMKID:          asset.DRMKID.String,
+		OwnerKeyMissing: asset.OwnerKeyMissing,
+		Media:           []*MediaSnapshot{},
+		Holders:         asset.Holders,
+		Envelopes:       asset.KeyEnvelopes,
+	}
+
+	for _, media := range asset.Media {
+		if media.Featured {
+			continue
+		}
+		snapshot.Media = append(snapshot.Media, &MediaSnapshot{
+			ID:           media.ID,
+			EncryptedKey: media.EncryptedKey,
+			EncryptedCID: media.EncryptedCID,
+		})
+	}
+
+	return snapshot
+}
+
+// ChainBlockChange records the state an order (and its asset) had before an
+// event from the given block was applied. Token transfers made outside of
+// the exchange are recorded without an order.
+type ChainBlockChange struct {
+	ID                  int64          `db:"id"`
+	CreatedAt           *time.Time     `db:"created_at"`
+	ChainID             string         `db:"chain_id"`
+	Height              uint64         `db:"height"`
+	BlockHash           string         `db:"block_hash"`
+	OrderID             dbr.NullInt64  `db:"order_id"`
+	OrderStatus         OrderStatus    `db:"order_status"`
+	OrderFilledQuantity int64          `db:"order_filled_quantity"`
+	Asset               *AssetSnapshot `db:"asset"`
+}
@@ -0,0 +1,43 @@
+package model
+
+import (
+	"time"
+
+	"github.com/gocraft/dbr/v2"
+)
+
+type ChainEventStatus string
+
+const (
+	ChainEventStatusPending   ChainEventStatus = "PENDING"
+	ChainEventStatusProcessed ChainEventStatus = "PROCESSED"
+	// ChainEventStatusFailed is set once all attempts have been used, the
+	// event is only processed again when it is replayed.
+	ChainEventStatusFailed ChainEventStatus = "FAILED"
+
+	DefaultChainEventMaxAttempts = 5
+)
+
+// ChainEvent is a decoded log of a watched contract, keyed by the
+// transaction and the position of the log in its block. The payload is the
+// decoded event as JSON.
+type ChainEvent struct {
+	ID          int64            `db:"id"`
+	CreatedAt   *time.Time       `db:"created_at"`
+	UpdatedAt   *time.Time       `db:"updated_at"`
+	ChainID     string           `db:"chain_id"`
+	Height      uint64           `db:"height"`
+	BlockHash   string           `db:"block_hash"`
+	TxHash      string           `db:"tx_hash"`
+	LogIndex    uint             `db:"log_index"`
+	Type        string           `db:"type"`
+	Payload     string           `db:"payload"`
+	Status      ChainEventStatus `db:"status"`
+	Attempts    int              `db:"attempts"`
+	LastError   dbr.NullString   `db:"last_error"`
+	ProcessedAt *time.Time       `db:"processed_at"`
+}
+
+func (e *ChainEvent) IsProcessed() bool {
+	return e.Status == ChainEventStatusProcessed
+}
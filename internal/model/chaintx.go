@@ -0,0 +1,106 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/gocraft/dbr/v2"
+)
+
+type ChainTxStatus string
+type ChainTxKind string
+
+const (
+	ChainTxStatusPending   ChainTxStatus = "PENDING"
+	ChainTxStatusConfirmed ChainTxStatus = "CONFIRMED"
+	ChainTxStatusFailed    ChainTxStatus = "FAILED"
+
+	ChainTxKindMint        ChainTxKind = "MINT"
+	ChainTxKindMintEdition ChainTxKind = "MINT_EDITION"
+	ChainTxKindTokenURI    ChainTxKind = "TOKEN_URI"
+)
+
+// ChainTxHashes are the hashes of all the transactions sent with the nonce
+// of a ChainTx, any of them may be mined.
+type ChainTxHashes []string
+
+func (h ChainTxHashes) Value() (driver.Value, error) {
+	if h == nil {
+		h = ChainTxHashes{}
+	}
+	b, err := json.Marshal(h)
+	return string(b), err
+}
+
+func (h *ChainTxHashes) Scan(value interface{}) error {
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+
+	return json.Unmarshal(b, &h)
+}
+
+// ChainTxAssetIDs are the assets of a transaction minting several tokens
+// at once.
+type ChainTxAssetIDs []int64
+
+func (ids ChainTxAssetIDs) Value() (driver.Value, error) {
+	if ids == nil {
+		ids = ChainTxAssetIDs{}
+	}
+	b, err := json.Marshal(ids)
+	return string(b), err
+}
+
+func (ids *ChainTxAssetIDs) Scan(value interface{}) error {
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+
+	return json.Unmarshal(b, &ids)
+}
+
+// ChainTx is a transaction sent by a minter. It keeps its nonce when it is
+// replaced with a higher gas price, TxHash is the latest replacement until
+// the transaction is confirmed and the mined one afterwards.
+type ChainTx struct {
+	ID          int64           `db:"id"`
+	CreatedAt   *time.Time      `db:"created_at"`
+	UpdatedAt   *time.Time      `db:"updated_at"`
+	Network     string          `db:"network"`
+	FromAddress string          `db:"from_address"`
+	ToAddress   string          `db:"to_address"`
+	Nonce       uint64          `db:"nonce"`
+	Kind        ChainTxKind     `db:"kind"`
+	AssetID     int64           `db:"asset_id"`
+	AssetIDs    ChainTxAssetIDs `db:"asset_ids"`
+	TokenURI    dbr.NullString  `db:"token_uri"`
+	Data        string          `db:"data"`
+	GasLimit    uint64          `db:"gas_limit"`
+	GasPrice    string          `db:"gas_price"`
+	TxHash      string          `db:"tx_hash"`
+	Hashes      ChainTxHashes   `db:"hashes"`
+	Bumps       int             `db:"bumps"`
+	Status      ChainTxStatus   `db:"status"`
+	LastError   dbr.NullString  `db:"last_error"`
+	SentAt      *time.Time      `db:"sent_at"`
+	BlockNumber dbr.NullInt64   `db:"block_number"`
+	ConfirmedAt *time.Time      `db:"confirmed_at"`
+}
+
+func (tx *ChainTx) IsPending() bool {
+	return tx.Status == ChainTxStatusPending
+}
+
+// Assets returns the ids of the assets the transaction has been sent for,
+// AssetID is the first of them.
+func (tx *ChainTx) Assets() []int64 {
+	if len(tx.AssetIDs) == 0 {
+		return []int64{tx.AssetID}
+	}
+	return tx.AssetIDs
+}
@@ -0,0 +1,30 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// isUrlRoot reports whether a storage root is a base url, as set by the
+// local and S3 storage backends, rather than a bucket key or name.
+func isUrlRoot(root string) bool {
+	return strings.HasPrefix(root, "http://") || strings.HasPrefix(root, "https://")
+}
+
+func joinUrl(root, key string) string {
+	return strings.TrimSuffix(root, "/") + "/" + strings.TrimPrefix(key, "/")
+}
+
+func rootUrl(root, key string) string {
+	if isUrlRoot(root) {
+		return joinUrl(root, key)
+	}
+	return fmt.Sprintf(TextileIpnsGateway, root, key)
+}
+
+func cachedUrl(root, key string) string {
+	if isUrlRoot(root) {
+		return joinUrl(root, key)
+	}
+	return fmt.Sprintf(CachedGateway, root, key)
+}
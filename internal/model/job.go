@@ -0,0 +1,223 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/gocraft/dbr/v2"
+	"github.com/videocoin/marketplace/pkg/uuid4"
+)
+
+type JobStatus string
+type JobType string
+
+const (
+	JobStatusPending   JobStatus = "PENDING"
+	JobStatusRunning   JobStatus = "RUNNING"
+	JobStatusSucceeded JobStatus = "SUCCEEDED"
+	JobStatusDead      JobStatus = "DEAD"
+
+	JobTypeMediaUpload    JobType = "media_upload"
+	JobTypeAssetProcess   JobType = "asset_process"
+	JobTypeTokenURISync   JobType = "token_uri_sync"
+	JobTypeAssetRedeem    JobType = "asset_redeem"
+	JobTypeAssetBatchMint JobType = "asset_batch_mint"
+	JobTypeAssetTransfer  JobType = "asset_transfer"
+
+	DefaultJobMaxAttempts = 5
+	DefaultJobBackoff     = 10 * time.Second
+	MaxJobBackoff         = 10 * time.Minute
+)
+
+type JobCheckpoints []string
+
+func (c JobCheckpoints) Value() (driver.Value, error) {
+	if c == nil {
+		c = JobCheckpoints{}
+	}
+	b, err := json.Marshal(c)
+	return string(b), err
+}
+
+func (c *JobCheckpoints) Scan(value interface{}) error {
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+
+	return json.Unmarshal(b, &c)
+}
+
+type Job struct {
+	ID          string         `db:"id"`
+	CreatedAt   *time.Time     `db:"created_at"`
+	UpdatedAt   *time.Time     `db:"updated_at"`
+	CreatedByID int64          `db:"created_by_id"`
+	Type        JobType        `db:"type"`
+	Status      JobStatus      `db:"status"`
+	Payload     string         `db:"payload"`
+	Checkpoints JobCheckpoints `db:"checkpoints"`
+	Attempts    int            `db:"attempts"`
+	MaxAttempts int            `db:"max_attempts"`
+	RunAt       *time.Time     `db:"run_at"`
+	LockedBy    dbr.NullString `db:"locked_by"`
+	LockedUntil *time.Time     `db:"locked_until"`
+	LastError   dbr.NullString `db:"last_error"`
+}
+
+// MediaUploadJobPayload points to the upload in storage, the API stores it
+// before the job is queued so any worker can pick the job up. The upload is
+// at DestSourceKey for video normalized to a mezzanine, at DestKey
+// otherwise.
+type MediaUploadJobPayload struct {
+	MediaID             string `json:"media_id"`
+	Featured            bool   `json:"featured"`
+	ContentType         string `json:"content_type"`
+	DestKey             string `json:"dest_key"`
+	DestThumbKey        string `json:"dest_thumb_key"`
+	DestThumbBlurredKey string `json:"dest_thumb_blurred_key"`
+	SourceContentType   string `json:"source_content_type,omitempty"`
+	DestSourceKey       string `json:"dest_source_key,omitempty"`
+}
+
+func (p *MediaUploadJobPayload) AssetMeta() *AssetMeta {
+	return &AssetMeta{
+		ContentType:         p.ContentType,
+		DestKey:             p.DestKey,
+		DestThumbKey:        p.DestThumbKey,
+		DestThumbBlurredKey: p.DestThumbBlurredKey,
+		SourceContentType:   p.SourceContentType,
+		DestSourceKey:       p.DestSourceKey,
+	}
+}
+
+type AssetProcessJobPayload struct {
+	AssetID int64 `json:"asset_id"`
+}
+
+type TokenURISyncJobPayload struct {
+	AssetID int64 `json:"asset_id"`
+}
+
+type AssetRedeemJobPayload struct {
+	AssetID int64 `json:"asset_id"`
+}
+
+type AssetBatchMintJobPayload struct {
+	BatchID int64 `json:"batch_id"`
+}
+
+// AssetTransferJobPayload publishes the media and the metadata of an asset
+// which changed hands, the content key is the one of DRMKID. Reencrypt is
+// false when a transfer has been reverted: the media the asset points back
+// to is still stored, only its metadata is published again.
+type AssetTransferJobPayload struct {
+	AssetID   int64  `json:"asset_id"`
+	DRMKID    string `json:"drm_kid"`
+	Reencrypt bool   `json:"reencrypt"`
+}
+
+func GenJobID() string {
+	id, _ := uuid4.New()
+	return id
+}
+
+func NewJob(createdByID int64, jobType JobType, payload interface{}) (*Job, error) {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Job{
+		ID:          GenJobID(),
+		CreatedByID: createdByID,
+		Type:        jobType,
+		Status:      JobStatusPending,
+		Payload:     string(b),
+		Checkpoints: JobCheckpoints{},
+		MaxAttempts: DefaultJobMaxAttempts,
+	}, nil
+}
+
+func NewMediaUploadJob(media *Media, meta *AssetMeta) (*Job, error) {
+	return NewJob(media.CreatedByID, JobTypeMediaUpload, &MediaUploadJobPayload{
+		MediaID:             media.ID,
+		Featured:            media.Featured,
+		ContentType:         meta.ContentType,
+		DestKey:             meta.DestKey,
+		DestThumbKey:        meta.DestThumbKey,
+		DestThumbBlurredKey: meta.DestThumbBlurredKey,
+		SourceContentType:   meta.SourceContentType,
+		DestSourceKey:       meta.DestSourceKey,
+	})
+}
+
+func NewAssetProcessJob(asset *Asset) (*Job, error) {
+	return NewJob(asset.CreatedByID, JobTypeAssetProcess, &AssetProcessJobPayload{
+		AssetID: asset.ID,
+	})
+}
+
+func NewTokenURISyncJob(asset *Asset) (*Job, error) {
+	return NewJob(asset.OwnerID, JobTypeTokenURISync, &TokenURISyncJobPayload{
+		AssetID: asset.ID,
+	})
+}
+
+func NewAssetRedeemJob(asset *Asset) (*Job, error) {
+	return NewJob(asset.CreatedByID, JobTypeAssetRedeem, &AssetRedeemJobPayload{
+		AssetID: asset.ID,
+	})
+}
+
+func NewAssetBatchMintJob(batch *AssetBatch) (*Job, error) {
+	return NewJob(batch.CreatedByID, JobTypeAssetBatchMint, &AssetBatchMintJobPayload{
+		BatchID: batch.ID,
+	})
+}
+
+func NewAssetTransferJob(asset *Asset, reencrypt bool) (*Job, error) {
+	return NewJob(asset.OwnerID, JobTypeAssetTransfer, &AssetTransferJobPayload{
+		AssetID:   asset.ID,
+		DRMKID:    asset.DRMKID.String,
+		Reencrypt: reencrypt,
+	})
+}
+
+func (j *Job) UnmarshalPayload(v interface{}) error {
+	return json.Unmarshal([]byte(j.Payload), v)
+}
+
+func (j *Job) IsCheckpointPassed(step string) bool {
+	for _, checkpoint := range j.Checkpoints {
+		if checkpoint == step {
+			return true
+		}
+	}
+	return false
+}
+
+func (j *Job) IsFinished() bool {
+	return j.Status == JobStatusSucceeded || j.Status == JobStatusDead
+}
+
+func (j *Job) IsLastAttempt() bool {
+	return j.Attempts >= j.MaxAttempts
+}
+
+// Backoff returns the delay before the next attempt, doubling it after every
+// failed attempt.
+func (j *Job) Backoff() time.Duration {
+	attempts := j.Attempts
+	if attempts < 1 {
+		attempts = 1
+	}
+	backoff := time.Duration(float64(DefaultJobBackoff) * math.Pow(2, float64(attempts-1)))
+	if backoff > MaxJobBackoff || backoff <= 0 {
+		backoff = MaxJobBackoff
+	}
+	return backoff
+}
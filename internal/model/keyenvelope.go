@@ -0,0 +1,34 @@
+package model
+
+import (
+	"time"
+
+	"github.com/gocraft/dbr/v2"
+)
+
+type KeyEnvelopeRole string
+
+const (
+	KeyEnvelopeRoleOwner    KeyEnvelopeRole = "OWNER"
+	KeyEnvelopeRoleCreator  KeyEnvelopeRole = "CREATOR"
+	KeyEnvelopeRoleDelegate KeyEnvelopeRole = "DELEGATE"
+
+	// MaxAssetDelegates is the most delegates an owner can authorize.
+	MaxAssetDelegates = 10
+)
+
+// AssetKeyEnvelope is the content key of an asset sealed to the encryption
+// key of one of its recipients: the owner, the creator once the asset has
+// been sold, and the delegates the owner authorizes. All the envelopes of
+// an asset hold the same key, recipients are added and revoked without
+// encrypting the media again.
+type AssetKeyEnvelope struct {
+	AssetID     int64           `db:"asset_id" json:"asset_id"`
+	AccountID   int64           `db:"account_id" json:"account_id"`
+	Role        KeyEnvelopeRole `db:"role" json:"role"`
+	Envelope    string          `db:"envelope" json:"envelope"`
+	GrantedByID dbr.NullInt64   `db:"granted_by_id" json:"granted_by_id"`
+	CreatedAt   *time.Time      `db:"created_at" json:"created_at"`
+
+	Account *Account `db:"-" json:"-"`
+}
@@ -0,0 +1,48 @@
+package model
+
+import (
+	"time"
+
+	"github.com/gocraft/dbr/v2"
+)
+
+// LedgerEntry records how the price of a matched sale was split. The royalty
+// and the platform fee are both collected by the fee recipient of the sell
+// order, the royalty is owed to the creator of the asset. Amounts are in wei
+// of the payment token.
+type LedgerEntry struct {
+	ID                  int64         `db:"id"`
+	CreatedAt           *time.Time    `db:"created_at"`
+	AssetID             int64         `db:"asset_id"`
+	OrderID             int64         `db:"order_id"`
+	SellerID            dbr.NullInt64 `db:"seller_id"`
+	BuyerID             dbr.NullInt64 `db:"buyer_id"`
+	CreatorID           dbr.NullInt64 `db:"creator_id"`
+	PaymentTokenAddress string        `db:"payment_token_address"`
+	Quantity            int64         `db:"quantity"`
+	Price               string        `db:"price"`
+	RoyaltyBps          int64         `db:"royalty_bps"`
+	Royalty             string        `db:"royalty"`
+	PlatformFee         string        `db:"platform_fee"`
+	ProtocolFee         string        `db:"protocol_fee"`
+	SellerProceeds      string        `db:"seller_proceeds"`
+	BlockHash           string        `db:"block_hash"`
+	TxHash              string        `db:"tx_hash"`
+	// LogIndex is the index of the matched event, entries recorded before
+	// it was tracked have none.
+	LogIndex dbr.NullInt64 `db:"log_index"`
+
+	Asset *Asset `db:"-"`
+}
+
+// Earnings sums up what an account earned on an asset, as its creator and
+// as a seller.
+type Earnings struct {
+	AssetID             int64  `db:"asset_id"`
+	PaymentTokenAddress string `db:"payment_token_address"`
+	Sales               int64  `db:"sales"`
+	Royalties           string `db:"royalties"`
+	Proceeds            string `db:"proceeds"`
+
+	Asset *Asset `db:"-"`
+}
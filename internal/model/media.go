@@ -35,17 +35,36 @@ type Media struct {
 	Featured    bool           `db:"featured"`
 	Status      MediaStatus    `db:"status"`
 
-	RootKey      string         `db:"root_key"`
-	CacheRootKey dbr.NullString `db:"cache_root_key"`
-	Key          string         `db:"key"`
-	ThumbnailKey string         `db:"thumbnail_key"`
-	EncryptedKey string         `db:"encrypted_key"`
+	RootKey         string         `db:"root_key"`
+	CacheRootKey    dbr.NullString `db:"cache_root_key"`
+	Key             string         `db:"key"`
+	ThumbnailKey    string         `db:"thumbnail_key"`
+	EncryptedKey    string         `db:"encrypted_key"`
+	EncryptedHLSKey string         `db:"encrypted_hls_key"`
+	FileEncryption  string         `db:"file_encryption"`
+	PreviewKey      string         `db:"preview_key"`
+
+	// EncryptedHLSAESKey is the master playlist of the AES-128 HLS
+	// packaging, the one Safari and iOS play. It is stored next to the
+	// other playlists and served by the license endpoint.
+	EncryptedHLSAESKey string `db:"encrypted_hls_aes_key"`
+
+	// SourceKey is the original upload of video normalized to the mezzanine
+	// at Key, it is empty when the upload is streamed as is. The upload is
+	// only kept to transcode it again, it is never served.
+	SourceContentType string         `db:"source_content_type"`
+	SourceKey         string         `db:"source_key"`
+	SourceCID         dbr.NullString `db:"source_cid"`
 
 	CID          dbr.NullString `db:"cid"`
 	ThumbnailCID dbr.NullString `db:"thumbnail_cid"`
 	EncryptedCID dbr.NullString `db:"encrypted_cid"`
+	PreviewCID   dbr.NullString `db:"preview_cid"`
 
-	AssetID dbr.NullInt64 `db:"asset_id"`
+	AssetID dbr.NullInt64  `db:"asset_id"`
+	JobID   dbr.NullString `db:"job_id"`
+
+	Renditions MediaRenditions `db:"renditions"`
 
 	CreatedBy *Account `db:"-"`
 }
@@ -81,7 +100,7 @@ func (m *Media) GetUrl(locked bool) string {
 
 	if m.RootKey != "" {
 		if m.CID.String != "" {
-			return fmt.Sprintf(TextileIpnsGateway, m.RootKey, m.Key)
+			return rootUrl(m.RootKey, m.Key)
 		}
 	} else {
 		if m.CID.String != "" {
@@ -95,7 +114,7 @@ func (m *Media) GetUrl(locked bool) string {
 func (m *Media) GetOriginalUrl() string {
 	if m.RootKey != "" {
 		if m.CID.String != "" {
-			return fmt.Sprintf(TextileIpnsGateway, m.RootKey, m.Key)
+			return rootUrl(m.RootKey, m.Key)
 		}
 	} else {
 		if m.CID.String != "" {
@@ -133,7 +152,7 @@ func (m *Media) GetThumbnailUrl(locked bool) string {
 
 	if m.RootKey != "" {
 		if m.ThumbnailCID.String != "" {
-			return fmt.Sprintf(TextileIpnsGateway, m.RootKey, m.ThumbnailKey)
+			return rootUrl(m.RootKey, m.ThumbnailKey)
 		}
 	} else {
 		if m.ThumbnailCID.String != "" {
@@ -154,7 +173,7 @@ func (m *Media) GetIpfsThumbnailUrl() string {
 func (m *Media) GetEncryptedUrl() string {
 	if m.RootKey != "" {
 		if m.EncryptedCID.String != "" {
-			return fmt.Sprintf(TextileIpnsGateway, m.RootKey, m.EncryptedKey)
+			return rootUrl(m.RootKey, m.EncryptedKey)
 		}
 	} else {
 		if m.EncryptedCID.String != "" {
@@ -175,7 +194,7 @@ func (m *Media) GetCachedUrl(locked bool) string {
 	}
 
 	if m.CacheRootKey.String != "" {
-		return fmt.Sprintf(CachedGateway, m.CacheRootKey.String, m.Key)
+		return cachedUrl(m.CacheRootKey.String, m.Key)
 	}
 
 	return ""
@@ -187,7 +206,7 @@ func (m *Media) GetCachedThumbnailUrl(locked bool) string {
 		key = strings.Replace(key, "thumb.jpg", "b_thumb.jpg", -1)
 	}
 	if m.CacheRootKey.String != "" {
-		return fmt.Sprintf(CachedGateway, m.CacheRootKey.String, key)
+		return cachedUrl(m.CacheRootKey.String, key)
 	}
 
 	return ""
@@ -195,12 +214,121 @@ func (m *Media) GetCachedThumbnailUrl(locked bool) string {
 
 func (m *Media) GetCachedEncryptedUrl() string {
 	if m.CacheRootKey.String != "" {
-		return fmt.Sprintf(CachedGateway, m.CacheRootKey.String, m.EncryptedKey)
+		return cachedUrl(m.CacheRootKey.String, m.EncryptedKey)
+	}
+
+	return ""
+}
+
+// IsNormalized reports whether the media is played from a mezzanine
+// transcoded from its original upload.
+func (m *Media) IsNormalized() bool {
+	return m.SourceKey != ""
+}
+
+// HasEncryptedHLS reports whether the media has been packaged for HLS too,
+// media encrypted before HLS was supported only has the dash manifest.
+func (m *Media) HasEncryptedHLS() bool {
+	return m.EncryptedHLSKey != "" && m.EncryptedCID.String != ""
+}
+
+func (m *Media) GetEncryptedHLSUrl() string {
+	if !m.HasEncryptedHLS() {
+		return ""
+	}
+
+	if m.RootKey != "" {
+		return rootUrl(m.RootKey, m.EncryptedHLSKey)
+	}
+
+	return fmt.Sprintf(IpfsGateway, m.EncryptedCID.String, filepath.Base(m.EncryptedHLSKey))
+}
+
+func (m *Media) GetIpfsEncryptedHLSUrl() string {
+	if !m.HasEncryptedHLS() {
+		return ""
+	}
+
+	return fmt.Sprintf("ipfs://%s/%s", m.EncryptedCID.String, filepath.Base(m.EncryptedHLSKey))
+}
+
+func (m *Media) GetCachedEncryptedHLSUrl() string {
+	if !m.HasEncryptedHLS() {
+		return ""
+	}
+
+	if m.CacheRootKey.String != "" {
+		return cachedUrl(m.CacheRootKey.String, m.EncryptedHLSKey)
 	}
 
 	return ""
 }
 
+// HasEncryptedHLSAES reports whether the media has been packaged for
+// native HLS playback with AES-128 too.
+func (m *Media) HasEncryptedHLSAES() bool {
+	return m.EncryptedHLSAESKey != "" && m.EncryptedCID.String != ""
+}
+
+// GetEncryptedHLSFileUrl returns the url of a file of the HLS packagings,
+// the cached copy is preferred.
+func (m *Media) GetEncryptedHLSFileUrl(name string) string {
+	key := filepath.Join(filepath.Dir(m.EncryptedKey), name)
+
+	if m.CacheRootKey.String != "" {
+		return cachedUrl(m.CacheRootKey.String, key)
+	}
+
+	if m.RootKey != "" {
+		return rootUrl(m.RootKey, key)
+	}
+
+	return fmt.Sprintf(IpfsGateway, m.EncryptedCID.String, name)
+}
+
+// HasPreview reports whether a public teaser has been cut from the locked
+// media.
+func (m *Media) HasPreview() bool {
+	return m.PreviewCID.String != ""
+}
+
+// CanPreview reports whether a teaser can be cut from the media.
+func (m *Media) CanPreview() bool {
+	return !m.Featured && (m.IsVideo() || m.IsAudio())
+}
+
+func (m *Media) GetPreviewUrl() string {
+	if !m.HasPreview() {
+		return ""
+	}
+
+	if m.RootKey != "" {
+		return rootUrl(m.RootKey, m.PreviewKey)
+	}
+
+	return fmt.Sprintf(IpfsGateway, m.PreviewCID.String, filepath.Base(m.PreviewKey))
+}
+
+func (m *Media) GetIpfsPreviewUrl() string {
+	if !m.HasPreview() {
+		return ""
+	}
+
+	return fmt.Sprintf("ipfs://%s/%s", m.PreviewCID.String, filepath.Base(m.PreviewKey))
+}
+
+func (m *Media) GetCachedPreviewUrl() string {
+	if !m.HasPreview() {
+		return ""
+	}
+
+	if m.CacheRootKey.String != "" {
+		return cachedUrl(m.CacheRootKey.String, m.PreviewKey)
+	}
+
+	return m.GetPreviewUrl()
+}
+
 func (m *Media) IsVideo() bool {
 	return m.MediaType == MediaTypeVideo
 }
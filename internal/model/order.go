@@ -31,6 +31,23 @@ type Order struct {
 	TakerID              *int64           `db:"taker_id"`
 	CreatedDate          *time.Time       `db:"created_date"`
 	WyvernOrder          *wyvern.Order    `db:"wyvern_order"`
+	Quantity             int64            `db:"quantity"`
+	FilledQuantity       int64            `db:"filled_quantity"`
+	Network              string           `db:"network"`
+}
+
+// RemainingQuantity returns the number of editions which are still left to
+// be filled by the order.
+func (o *Order) RemainingQuantity() int64 {
+	remaining := o.Quantity - o.FilledQuantity
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+func (o *Order) IsFilled() bool {
+	return o.RemainingQuantity() == 0
 }
 
 func (o *Order) IsProcessed() bool {
@@ -45,6 +62,17 @@ func (o *Order) IsProcessing() bool {
 	return o.Status == OrderStatusProcessing
 }
 
+// IsAuction reports whether the order is an English auction listing: a fixed
+// price sell order with a reserve price, the base price being the starting
+// bid. Fixed price listings which merely expire are not auctions.
 func (o *Order) IsAuction() bool {
-	return o.WyvernOrder != nil && len(o.WyvernOrder.ExpirationTime) > 1
+	if o.WyvernOrder == nil {
+		return false
+	}
+
+	if o.WyvernOrder.Side != wyvern.Sell || o.WyvernOrder.SaleKind != wyvern.FixedPrice {
+		return false
+	}
+
+	return o.WyvernOrder.IsEnglishAuction()
 }
@@ -0,0 +1,60 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+type RenditionStatus string
+
+const (
+	RenditionStatusPending     RenditionStatus = "PENDING"
+	RenditionStatusTranscoding RenditionStatus = "TRANSCODING"
+	RenditionStatusEncrypting  RenditionStatus = "ENCRYPTING"
+	RenditionStatusReady       RenditionStatus = "READY"
+	RenditionStatusFailed      RenditionStatus = "FAILED"
+
+	RenditionCodecH264 = "h264"
+	RenditionCodecHEVC = "hevc"
+)
+
+// MediaRendition is a step of the bitrate ladder encrypted video is
+// packaged with, the bitrate is in kbps.
+type MediaRendition struct {
+	Name    string          `json:"name"`
+	Height  int             `json:"height"`
+	Bitrate int             `json:"bitrate"`
+	Codec   string          `json:"codec"`
+	Status  RenditionStatus `json:"status"`
+}
+
+func NewMediaRendition(height, bitrate int, codec string) *MediaRendition {
+	return &MediaRendition{
+		Name:    fmt.Sprintf("%dp", height),
+		Height:  height,
+		Bitrate: bitrate,
+		Codec:   codec,
+		Status:  RenditionStatusPending,
+	}
+}
+
+type MediaRenditions []*MediaRendition
+
+func (r MediaRenditions) Value() (driver.Value, error) {
+	if r == nil {
+		r = MediaRenditions{}
+	}
+	b, err := json.Marshal(r)
+	return string(b), err
+}
+
+func (r *MediaRenditions) Scan(value interface{}) error {
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+
+	return json.Unmarshal(b, &r)
+}
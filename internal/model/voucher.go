@@ -0,0 +1,35 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+)
+
+// MintVoucher authorizes minting a lazily minted token to its creator.
+// It is signed as EIP-712 typed data by the minter of the network the
+// asset belongs to, Price is in wei.
+type MintVoucher struct {
+	ChainID         uint64 `json:"chain_id"`
+	ContractAddress string `json:"contract_address"`
+	TokenID         string `json:"token_id"`
+	URI             string `json:"uri"`
+	Creator         string `json:"creator"`
+	Price           string `json:"price"`
+	RoyaltyBps      int64  `json:"royalty_bps"`
+	Signature       string `json:"signature"`
+}
+
+func (v MintVoucher) Value() (driver.Value, error) {
+	b, err := json.Marshal(v)
+	return string(b), err
+}
+
+func (v *MintVoucher) Scan(value interface{}) error {
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+
+	return json.Unmarshal(b, &v)
+}
@@ -0,0 +1,122 @@
+package network
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// DefaultName is the network existing assets and orders are tagged with.
+const DefaultName = "default"
+
+var (
+	ErrUnknownNetwork = errors.New("unknown network")
+	ErrNoNetworks     = errors.New("no networks configured")
+)
+
+// Network is a chain the marketplace mints on and listens to, along with
+// the contracts deployed on it.
+type Network struct {
+	Name                    string   `json:"name"`
+	ChainID                 uint64   `json:"chain_id"`
+	RPCURL                  string   `json:"rpc_url"`
+	ERC721ContractAddress   string   `json:"erc721_contract_address"`
+	ERC1155ContractAddress  string   `json:"erc1155_contract_address"`
+	ExchangeContractAddress string   `json:"exchange_contract_address"`
+	PaymentTokens           []string `json:"payment_tokens"`
+	KeyFile                 string   `json:"key_file"`
+	KeyPass                 string   `json:"key_pass"`
+	ScanFrom                uint64   `json:"scan_from"`
+	Confirmations           uint64   `json:"confirmations"`
+}
+
+// IsExchange reports whether the address is the exchange of the network.
+// Any exchange is accepted when none is configured.
+func (n *Network) IsExchange(address string) bool {
+	return n.ExchangeContractAddress == "" || strings.EqualFold(n.ExchangeContractAddress, address)
+}
+
+// AcceptsPaymentToken reports whether orders may be paid with the token.
+// All tokens are accepted when the network doesn't list any.
+func (n *Network) AcceptsPaymentToken(address string) bool {
+	if len(n.PaymentTokens) == 0 {
+		return true
+	}
+
+	for _, token := range n.PaymentTokens {
+		if strings.EqualFold(token, address) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Registry holds the configured networks, the first one is the default.
+type Registry struct {
+	networks []*Network
+	byName   map[string]*Network
+}
+
+func NewRegistry(networks []*Network) (*Registry, error) {
+	if len(networks) == 0 {
+		return nil, ErrNoNetworks
+	}
+
+	r := &Registry{
+		networks: networks,
+		byName:   make(map[string]*Network, len(networks)),
+	}
+
+	for _, n := range networks {
+		if n.Name == "" {
+			return nil, errors.New("network name is required")
+		}
+		if _, ok := r.byName[n.Name]; ok {
+			return nil, fmt.Errorf("duplicate network %s", n.Name)
+		}
+		r.byName[n.Name] = n
+	}
+
+	return r, nil
+}
+
+// LoadFile reads the networks from a JSON file holding a list of them.
+func LoadFile(path string) ([]*Network, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	networks := make([]*Network, 0)
+	err = json.Unmarshal(b, &networks)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse networks file: %s", err)
+	}
+
+	return networks, nil
+}
+
+func (r *Registry) Default() *Network {
+	return r.networks[0]
+}
+
+func (r *Registry) List() []*Network {
+	return r.networks
+}
+
+// Get returns the network by name, the default network for an empty name.
+func (r *Registry) Get(name string) (*Network, error) {
+	if name == "" {
+		return r.Default(), nil
+	}
+
+	n, ok := r.byName[name]
+	if !ok {
+		return nil, ErrUnknownNetwork
+	}
+
+	return n, nil
+}
@@ -0,0 +1,156 @@
+package orderbook
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/AlekSi/pointer"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/videocoin/marketplace/internal/datastore"
+	"github.com/videocoin/marketplace/internal/model"
+	"github.com/videocoin/marketplace/internal/wyvern"
+)
+
+var (
+	ErrApprovedOrderHashMismatch   = errors.New("approved order hash mismatch")
+	ErrApprovedOrderForeignNetwork = errors.New("approved order token has been minted on another network")
+	ErrApprovedOrderForeignTarget  = errors.New("approved order targets another contract")
+)
+
+// Index inserts an order approved directly on the exchange contract, which
+// was never posted through the api. The token has to be minted on the
+// network, or be lazily minted, in which case it is minted for the order.
+// The maker gets a placeholder account if it hasn't signed up yet.
+func (book *OrderBook) Index(ctx context.Context, network string, signHash common.Hash, wyvernOrder *wyvern.Order) (*model.Order, error) {
+	logger := book.logger.
+		WithField("sign_hash", signHash.Hex()).
+		WithField("maker", wyvernOrder.Maker.Address)
+
+	hashToSign, err := wyvernOrder.HashToSign()
+	if err != nil {
+		return nil, err
+	}
+
+	if hashToSign != signHash {
+		return nil, ErrApprovedOrderHashMismatch
+	}
+
+	tokenID, err := wyvernOrder.TokenID()
+	if err != nil {
+		return nil, err
+	}
+
+	asset, err := book.ds.Assets.GetByTokenID(ctx, tokenID.Int64())
+	if err != nil {
+		return nil, err
+	}
+
+	if network != "" && asset.Network != network {
+		return nil, ErrApprovedOrderForeignNetwork
+	}
+
+	if asset.ContractAddress.Valid && !strings.EqualFold(asset.ContractAddress.String, wyvernOrder.Target) {
+		return nil, ErrApprovedOrderForeignTarget
+	}
+
+	maker, err := book.getOrCreateAccount(ctx, wyvernOrder.Maker.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	order := &model.Order{
+		CreatedByID: maker.ID,
+		MakerID:     pointer.ToInt64(maker.ID),
+		Hash:        wyvernOrder.Hash,
+		WyvernOrder: wyvernOrder,
+		Network:     asset.Network,
+	}
+
+	if wyvernOrder.Taker.Address != wyvern.NullAddress {
+		taker, err := book.ds.Accounts.GetByAddress(ctx, wyvernOrder.Taker.Address)
+		if err != nil && err != datastore.ErrAccountNotFound {
+			return nil, err
+		}
+		if taker != nil {
+			order.TakerID = pointer.ToInt64(taker.ID)
+		}
+	}
+
+	quantity, err := wyvernOrder.TokenAmount()
+	if err != nil {
+		return nil, err
+	}
+
+	schema, err := wyvernOrder.Schema()
+	if err != nil {
+		return nil, err
+	}
+
+	order.Quantity = quantity.Int64()
+
+	wyvernOrder.Metadata = &wyvern.ExchangeMetadata{
+		Asset: &wyvern.WyvernNFTAsset{
+			ID:       tokenID.String(),
+			Address:  strings.ToLower(wyvernOrder.Target),
+			Quantity: quantity.String(),
+		},
+		Schema: schema,
+	}
+
+	err = book.ds.Orders.Create(ctx, order)
+	if err != nil {
+		return nil, err
+	}
+
+	err = book.ds.Orders.MarkStatusAsApproved(ctx, order)
+	if err != nil {
+		return nil, err
+	}
+
+	logger = logger.
+		WithField("order_id", order.ID).
+		WithField("asset_id", asset.ID)
+
+	err = book.redeemVoucher(ctx, logger, asset)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("approved order has been indexed")
+
+	return order, nil
+}
+
+// Reconcile marks an order posted through the api as approved and brings
+// its fields in line with the order approved on the exchange contract.
+func (book *OrderBook) Reconcile(ctx context.Context, order *model.Order, wyvernOrder *wyvern.Order) error {
+	if order.WyvernOrder != nil {
+		wyvernOrder.Metadata = order.WyvernOrder.Metadata
+		wyvernOrder.CreatedDate = order.WyvernOrder.CreatedDate
+		wyvernOrder.Quantity = order.WyvernOrder.Quantity
+		wyvernOrder.EnglishAuctionReservePrice = order.WyvernOrder.EnglishAuctionReservePrice
+		wyvernOrder.MakerReferrerFee = order.WyvernOrder.MakerReferrerFee
+		wyvernOrder.V = order.WyvernOrder.V
+		wyvernOrder.R = order.WyvernOrder.R
+		wyvernOrder.S = order.WyvernOrder.S
+
+		if order.WyvernOrder.Maker != nil {
+			wyvernOrder.Maker = order.WyvernOrder.Maker
+		}
+		if order.WyvernOrder.Taker != nil {
+			wyvernOrder.Taker = order.WyvernOrder.Taker
+		}
+	}
+
+	err := book.ds.Orders.UpdateWyvernOrder(ctx, order, wyvernOrder)
+	if err != nil {
+		return err
+	}
+
+	if order.Status != model.OrderStatusCreated {
+		return nil
+	}
+
+	return book.Approve(ctx, order)
+}
@@ -0,0 +1,197 @@
+package orderbook_test
+
+import (
+	"context"
+	"math/big"
+	"strconv"
+	"testing"
+
+	"github.com/gocraft/dbr/v2"
+	"github.com/videocoin/marketplace/internal/datastore"
+	"github.com/videocoin/marketplace/internal/datastore/dbtest"
+	"github.com/videocoin/marketplace/internal/model"
+	"github.com/videocoin/marketplace/internal/orderbook"
+	"github.com/videocoin/marketplace/internal/wyvern"
+)
+
+// TestProcessFillIsAppliedOnce applies the same match twice, the way a
+// replayed event or a retry after a crash would, and checks that the
+// editions change hands once.
+func TestProcessFillIsAppliedOnce(t *testing.T) {
+	ds := dbtest.Open(t)
+	ctx := context.Background()
+
+	seller := newAccount(ctx, t, ds, "0x00000000000000000000000000000000000000a1")
+	buyer := newAccount(ctx, t, ds, "0x00000000000000000000000000000000000000b2")
+
+	asset, order := newEditionOrder(ctx, t, ds, seller, 5)
+
+	book, err := orderbook.NewOderBook(ctx, orderbook.WithDatastore(ds))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fill := &orderbook.Fill{
+		Buyer:     buyer,
+		Seller:    seller,
+		Quantity:  2,
+		Price:     big.NewInt(1e18),
+		BlockHash: "0x2222222222222222222222222222222222222222222222222222222222222222",
+		TxHash:    "0x3333333333333333333333333333333333333333333333333333333333333333",
+		LogIndex:  3,
+	}
+
+	for i := 0; i < 2; i++ {
+		err = ds.InTx(ctx, func(ctx context.Context) error {
+			stored, err := ds.Orders.GetByHash(ctx, order.Hash)
+			if err != nil {
+				return err
+			}
+			return book.Process(ctx, stored, fill)
+		})
+		if err != nil {
+			t.Fatalf("process #%d: %s", i+1, err)
+		}
+	}
+
+	balance, err := ds.AssetHolders.GetBalance(ctx, asset.ID, buyer.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if balance != 2 {
+		t.Errorf("buyer balance = %d, want 2", balance)
+	}
+
+	order, err = ds.Orders.GetByHash(ctx, order.Hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order.FilledQuantity != 2 {
+		t.Errorf("filled quantity = %d, want 2", order.FilledQuantity)
+	}
+
+	count, err := ds.Ledger.Count(ctx, &datastore.LedgerFilter{AssetID: &asset.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Errorf("%d ledger entries have been recorded, want 1", count)
+	}
+}
+
+// TestProcessFillRevokesDelegatesOfFormerHolder sells all the editions of a
+// holder and checks that its delegates lose access while the ones of the
+// remaining holders keep it.
+func TestProcessFillRevokesDelegatesOfFormerHolder(t *testing.T) {
+	ds := dbtest.Open(t)
+	ctx := context.Background()
+
+	seller := newAccount(ctx, t, ds, "0x00000000000000000000000000000000000000a1")
+	holder := newAccount(ctx, t, ds, "0x00000000000000000000000000000000000000a2")
+	buyer := newAccount(ctx, t, ds, "0x00000000000000000000000000000000000000b2")
+	sellerDelegate := newAccount(ctx, t, ds, "0x00000000000000000000000000000000000000d1")
+	holderDelegate := newAccount(ctx, t, ds, "0x00000000000000000000000000000000000000d2")
+
+	asset, order := newEditionOrder(ctx, t, ds, seller, 2)
+	err := ds.AssetHolders.Credit(ctx, asset.ID, holder.ID, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	grants := map[*model.Account]*model.Account{seller: sellerDelegate, holder: holderDelegate}
+	for granter, delegate := range grants {
+		err = ds.AssetKeyEnvelopes.Put(ctx, &model.AssetKeyEnvelope{
+			AssetID:     asset.ID,
+			AccountID:   delegate.ID,
+			Role:        model.KeyEnvelopeRoleDelegate,
+			Envelope:    "envelope",
+			GrantedByID: dbr.NewNullInt64(granter.ID),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	book, err := orderbook.NewOderBook(ctx, orderbook.WithDatastore(ds))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = book.Process(ctx, order, &orderbook.Fill{
+		Buyer:     buyer,
+		Seller:    seller,
+		Quantity:  2,
+		Price:     big.NewInt(1e18),
+		BlockHash: "0x2222222222222222222222222222222222222222222222222222222222222222",
+		TxHash:    "0x4444444444444444444444444444444444444444444444444444444444444444",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = ds.AssetKeyEnvelopes.Get(ctx, asset.ID, sellerDelegate.ID, model.KeyEnvelopeRoleDelegate)
+	if err != datastore.ErrAssetKeyEnvelopeNotFound {
+		t.Errorf("delegate of the former holder returned %v, want %v", err, datastore.ErrAssetKeyEnvelopeNotFound)
+	}
+
+	_, err = ds.AssetKeyEnvelopes.Get(ctx, asset.ID, holderDelegate.ID, model.KeyEnvelopeRoleDelegate)
+	if err != nil {
+		t.Errorf("delegate of the remaining holder returned %v", err)
+	}
+}
+
+// newEditionOrder creates an edition asset the seller holds all of and a
+// sell order of all the editions.
+func newEditionOrder(ctx context.Context, t *testing.T, ds *datastore.Datastore, seller *model.Account, quantity int64) (*model.Asset, *model.Order) {
+	asset := &model.Asset{
+		CreatedByID: seller.ID,
+		OwnerID:     seller.ID,
+		Status:      model.AssetStatusReady,
+		Name:        dbr.NewNullString("Test"),
+		Schema:      model.ContractSchemaTypeERC1155,
+		OnSale:      true,
+		Price:       1,
+	}
+	if err := ds.Assets.Create(ctx, asset); err != nil {
+		t.Fatal(err)
+	}
+	err := ds.AssetHolders.Replace(ctx, asset.ID, []*model.AssetHolder{
+		{AssetID: asset.ID, AccountID: seller.ID, Balance: quantity},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	order := &model.Order{
+		CreatedByID: seller.ID,
+		MakerID:     &seller.ID,
+		Hash:        "0x1111111111111111111111111111111111111111111111111111111111111111",
+		Quantity:    quantity,
+		WyvernOrder: &wyvern.Order{
+			Side:      wyvern.Sell,
+			SaleKind:  wyvern.FixedPrice,
+			BasePrice: "1000000000000000000",
+			Metadata: &wyvern.ExchangeMetadata{
+				Asset: &wyvern.WyvernNFTAsset{
+					ID:       strconv.FormatInt(asset.ID, 10),
+					Quantity: strconv.FormatInt(quantity, 10),
+				},
+				Schema: wyvern.SchemaERC1155,
+			},
+		},
+	}
+	if err := ds.Orders.Create(ctx, order); err != nil {
+		t.Fatal(err)
+	}
+
+	return asset, order
+}
+
+func newAccount(ctx context.Context, t *testing.T, ds *datastore.Datastore, address string) *model.Account {
+	account := &model.Account{Address: address}
+	if err := ds.Accounts.Create(ctx, account); err != nil {
+		t.Fatal(err)
+	}
+
+	return account
+}
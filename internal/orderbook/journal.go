@@ -0,0 +1,197 @@
+package orderbook
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/AlekSi/pointer"
+	"github.com/gocraft/dbr/v2"
+	"github.com/videocoin/marketplace/internal/datastore"
+	"github.com/videocoin/marketplace/internal/model"
+)
+
+// Snapshot captures the order and asset state before an on-chain event is
+// applied, so the change can be reverted if its block gets orphaned.
+func (book *OrderBook) Snapshot(ctx context.Context, order *model.Order) (*model.ChainBlockChange, error) {
+	change := &model.ChainBlockChange{
+		OrderID:             dbr.NewNullInt64(order.ID),
+		OrderStatus:         order.Status,
+		OrderFilledQuantity: order.FilledQuantity,
+	}
+
+	asset, err := book.ds.Assets.GetByTokenID(ctx, order.TokenID)
+	if err != nil {
+		if err == datastore.ErrAssetNotFound {
+			return change, nil
+		}
+		return nil, err
+	}
+
+	change.Asset, err = book.snapshotAsset(ctx, asset)
+	if err != nil {
+		return nil, err
+	}
+
+	return change, nil
+}
+
+// SnapshotAsset captures the asset state before a token transfer made
+// outside of the exchange is applied.
+func (book *OrderBook) SnapshotAsset(ctx context.Context, asset *model.Asset) (*model.ChainBlockChange, error) {
+	snapshot, err := book.snapshotAsset(ctx, asset)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.ChainBlockChange{Asset: snapshot}, nil
+}
+
+func (book *OrderBook) snapshotAsset(ctx context.Context, asset *model.Asset) (*model.AssetSnapshot, error) {
+	mediaItems, err := book.ds.Media.ListByAssetID(ctx, asset.ID)
+	if err != nil {
+		return nil, err
+	}
+	asset.Media = mediaItems
+
+	holders, err := book.ds.AssetHolders.ListByAssetID(ctx, asset.ID)
+	if err != nil {
+		return nil, err
+	}
+	asset.Holders = holders
+
+	envelopes, err := book.ds.AssetKeyEnvelopes.ListByAssetID(ctx, asset.ID)
+	if err != nil {
+		return nil, err
+	}
+	asset.KeyEnvelopes = envelopes
+
+	orders, err := book.ds.Orders.List(ctx, &datastore.OrderFilter{
+		TokenID:   pointer.ToInt64(asset.ID),
+		IsArchive: pointer.ToBool(false),
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := model.NewAssetSnapshot(asset)
+	for _, o := range orders {
+		snapshot.ActiveOrderIDs = append(snapshot.ActiveOrderIDs, o.ID)
+	}
+
+	return snapshot, nil
+}
+
+// Revert restores the order and asset state saved by Snapshot or
+// SnapshotAsset. Orders which were indexed from the orphaned block are
+// removed.
+func (book *OrderBook) Revert(ctx context.Context, change *model.ChainBlockChange) error {
+	logger := book.logger.
+		WithField("order_id", change.OrderID.Int64).
+		WithField("height", change.Height).
+		WithField("block_hash", change.BlockHash)
+
+	if change.OrderID.Valid {
+		order := &model.Order{ID: change.OrderID.Int64}
+
+		if change.OrderStatus == "" {
+			err := book.ds.Orders.Delete(ctx, order)
+			if err != nil {
+				return fmt.Errorf("failed to delete indexed order: %s", err)
+			}
+
+			logger.Info("indexed order has been removed")
+			return nil
+		}
+
+		err := book.revertOrder(ctx, order, change)
+		if err != nil {
+			return err
+		}
+	}
+
+	if change.Asset != nil {
+		logger = logger.WithField("asset_id", change.Asset.ID)
+
+		err := book.ds.Assets.Restore(ctx, change.Asset)
+		if err != nil {
+			return fmt.Errorf("failed to restore asset: %s", err)
+		}
+
+		if change.Asset.Holders != nil {
+			err = book.ds.AssetHolders.Replace(ctx, change.Asset.ID, change.Asset.Holders)
+			if err != nil {
+				return fmt.Errorf("failed to restore asset holders: %s", err)
+			}
+		}
+
+		if change.Asset.Envelopes != nil {
+			err = book.ds.AssetKeyEnvelopes.Replace(ctx, change.Asset.ID, change.Asset.Envelopes)
+			if err != nil {
+				return fmt.Errorf("failed to restore drm key envelopes: %s", err)
+			}
+		}
+
+		for _, snapshot := range change.Asset.Media {
+			media := &model.Media{ID: snapshot.ID}
+			fields := datastore.MediaUpdatedFields{
+				EncryptedKey: pointer.ToString(snapshot.EncryptedKey),
+			}
+			if snapshot.EncryptedCID.Valid {
+				fields.EncryptedCID = pointer.ToString(snapshot.EncryptedCID.String)
+			}
+
+			err = book.ds.Media.Update(ctx, media, fields)
+			if err != nil {
+				return fmt.Errorf("failed to restore media #%s: %s", snapshot.ID, err)
+			}
+		}
+
+		err = book.ds.Orders.UnarchiveByIds(ctx, change.Asset.ActiveOrderIDs)
+		if err != nil {
+			return fmt.Errorf("failed to unarchive orders: %s", err)
+		}
+
+		// the published metadata and the chain token uri may be the ones of
+		// the reverted owner
+		asset, err := book.ds.Assets.GetByID(ctx, change.Asset.ID)
+		if err != nil {
+			return err
+		}
+
+		err = book.publishTransfer(ctx, asset, false)
+		if err != nil {
+			return err
+		}
+	}
+
+	logger.Info("block changes have been reverted")
+
+	return nil
+}
+
+func (book *OrderBook) revertOrder(ctx context.Context, order *model.Order, change *model.ChainBlockChange) error {
+	err := book.ds.Orders.MarkStatusAs(ctx, order, change.OrderStatus)
+	if err != nil {
+		return fmt.Errorf("failed to restore order status: %s", err)
+	}
+
+	err = book.ds.Orders.UpdateFilledQuantity(ctx, order, change.OrderFilledQuantity)
+	if err != nil {
+		return fmt.Errorf("failed to restore order filled quantity: %s", err)
+	}
+
+	err = book.ds.Activity.DeleteByOrderID(ctx, order.ID, []string{
+		model.ActivityTypePurchased,
+		model.ActivityTypeSold,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete order activity: %s", err)
+	}
+
+	err = book.ds.Ledger.DeleteByBlock(ctx, order.ID, change.BlockHash)
+	if err != nil {
+		return fmt.Errorf("failed to delete ledger entries: %s", err)
+	}
+
+	return nil
+}
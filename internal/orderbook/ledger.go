@@ -0,0 +1,83 @@
+package orderbook
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/gocraft/dbr/v2"
+	"github.com/videocoin/marketplace/internal/model"
+	"github.com/videocoin/marketplace/internal/wyvern"
+	"github.com/videocoin/marketplace/pkg/ethutil"
+)
+
+// recordSale records the sale of the fill in the ledger.
+func (book *OrderBook) recordSale(ctx context.Context, order *model.Order, asset *model.Asset, fill *Fill, quantity int64) error {
+	entry, err := newLedgerEntry(order, asset, fill, quantity)
+	if err != nil {
+		return err
+	}
+
+	return book.ds.Ledger.Create(ctx, entry)
+}
+
+// newLedgerEntry splits the matched price into the creator royalty, the
+// platform fee and the seller proceeds. The relayer fee of the sell order
+// is collected by the marketplace fee recipient, which owes the royalty part
+// of it to the creator. No royalty is due when the creator sells.
+func newLedgerEntry(order *model.Order, asset *model.Asset, fill *Fill, quantity int64) (*model.LedgerEntry, error) {
+	sellOrder := order
+	if order.WyvernOrder.Side != wyvern.Sell && fill.Counterpart != nil {
+		sellOrder = fill.Counterpart
+	}
+
+	price := fill.Price
+	if price == nil {
+		basePrice, err := ethutil.ParseBigInt(sellOrder.WyvernOrder.BasePrice)
+		if err != nil {
+			return nil, err
+		}
+		price = basePrice
+	}
+
+	relayerFee, protocolFee, err := sellOrder.WyvernOrder.SellerFees(price)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &model.LedgerEntry{
+		AssetID:             asset.ID,
+		OrderID:             order.ID,
+		BuyerID:             dbr.NewNullInt64(fill.Buyer.ID),
+		CreatorID:           dbr.NewNullInt64(asset.CreatedByID),
+		PaymentTokenAddress: sellOrder.PaymentTokenAddress,
+		Quantity:            quantity,
+		Price:               price.String(),
+		BlockHash:           fill.BlockHash,
+		TxHash:              fill.TxHash,
+		LogIndex:            dbr.NewNullInt64(int64(fill.LogIndex)),
+	}
+
+	royalty := new(big.Int)
+	if fill.Seller != nil {
+		entry.SellerID = dbr.NewNullInt64(fill.Seller.ID)
+
+		if fill.Seller.ID != asset.CreatedByID {
+			entry.RoyaltyBps = asset.RoyaltyBps()
+			royalty = wyvern.FeeOf(price, entry.RoyaltyBps)
+			if royalty.Cmp(relayerFee) > 0 {
+				royalty.Set(relayerFee)
+			}
+		}
+	}
+
+	platformFee := new(big.Int).Sub(relayerFee, royalty)
+	proceeds := new(big.Int).Sub(price, relayerFee)
+	proceeds.Sub(proceeds, protocolFee)
+
+	entry.Royalty = royalty.String()
+	entry.PlatformFee = platformFee.String()
+	entry.ProtocolFee = protocolFee.String()
+	entry.SellerProceeds = proceeds.String()
+
+	return entry, nil
+}
@@ -0,0 +1,120 @@
+package orderbook
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/videocoin/marketplace/internal/model"
+	"github.com/videocoin/marketplace/internal/wyvern"
+)
+
+func sellOrder(relayerBps, protocolBps string) *model.Order {
+	return &model.Order{
+		ID: 1,
+		WyvernOrder: &wyvern.Order{
+			Side:             wyvern.Sell,
+			FeeMethod:        wyvern.SplitFee,
+			FeeRecipient:     &wyvern.Account{Address: "0x00000000000000000000000000000000000000fe"},
+			BasePrice:        "20000",
+			MakerRelayerFee:  relayerBps,
+			MakerProtocolFee: protocolBps,
+		},
+	}
+}
+
+func TestNewLedgerEntry(t *testing.T) {
+	creator := &model.Account{ID: 1}
+	seller := &model.Account{ID: 2}
+	buyer := &model.Account{ID: 3}
+
+	buyOrder := &model.Order{
+		ID:          2,
+		WyvernOrder: &wyvern.Order{Side: wyvern.Buy, FeeMethod: wyvern.SplitFee},
+	}
+
+	tests := []struct {
+		name           string
+		order          *model.Order
+		royalty        uint
+		fill           *Fill
+		royaltyBps     int64
+		royaltyAmount  string
+		platformFee    string
+		protocolFee    string
+		sellerProceeds string
+	}{
+		{
+			name:           "resale",
+			order:          sellOrder("250", "100"),
+			royalty:        1,
+			fill:           &Fill{Buyer: buyer, Seller: seller, Price: big.NewInt(10000)},
+			royaltyBps:     100,
+			royaltyAmount:  "100",
+			platformFee:    "150",
+			protocolFee:    "100",
+			sellerProceeds: "9650",
+		},
+		{
+			name:           "royalty capped at the relayer fee",
+			order:          sellOrder("250", "0"),
+			royalty:        10,
+			fill:           &Fill{Buyer: buyer, Seller: seller, Price: big.NewInt(10000)},
+			royaltyBps:     1000,
+			royaltyAmount:  "250",
+			platformFee:    "0",
+			protocolFee:    "0",
+			sellerProceeds: "9750",
+		},
+		{
+			name:           "creator sells",
+			order:          sellOrder("250", "100"),
+			royalty:        10,
+			fill:           &Fill{Buyer: buyer, Seller: creator, Price: big.NewInt(10000)},
+			royaltyBps:     0,
+			royaltyAmount:  "0",
+			platformFee:    "250",
+			protocolFee:    "100",
+			sellerProceeds: "9650",
+		},
+		{
+			name:           "buy order at the base price of the sell order",
+			order:          buyOrder,
+			royalty:        1,
+			fill:           &Fill{Buyer: buyer, Seller: seller, Counterpart: sellOrder("250", "100")},
+			royaltyBps:     100,
+			royaltyAmount:  "200",
+			platformFee:    "300",
+			protocolFee:    "200",
+			sellerProceeds: "19300",
+		},
+	}
+
+	for _, tt := range tests {
+		asset := &model.Asset{ID: 1, CreatedByID: creator.ID, Royalty: tt.royalty}
+
+		entry, err := newLedgerEntry(tt.order, asset, tt.fill, 1)
+		if err != nil {
+			t.Errorf("%s: %s", tt.name, err)
+			continue
+		}
+
+		if entry.OrderID != tt.order.ID {
+			t.Errorf("%s: order id = %d, want %d", tt.name, entry.OrderID, tt.order.ID)
+		}
+		if entry.RoyaltyBps != tt.royaltyBps {
+			t.Errorf("%s: royalty bps = %d, want %d", tt.name, entry.RoyaltyBps, tt.royaltyBps)
+		}
+		if entry.Royalty != tt.royaltyAmount {
+			t.Errorf("%s: royalty = %s, want %s", tt.name, entry.Royalty, tt.royaltyAmount)
+		}
+		if entry.PlatformFee != tt.platformFee {
+			t.Errorf("%s: platform fee = %s, want %s", tt.name, entry.PlatformFee, tt.platformFee)
+		}
+		if entry.ProtocolFee != tt.protocolFee {
+			t.Errorf("%s: protocol fee = %s, want %s", tt.name, entry.ProtocolFee, tt.protocolFee)
+		}
+		if entry.SellerProceeds != tt.sellerProceeds {
+			t.Errorf("%s: seller proceeds = %s, want %s", tt.name, entry.SellerProceeds, tt.sellerProceeds)
+		}
+	}
+}
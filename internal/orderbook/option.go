@@ -3,9 +3,7 @@ package orderbook
 import (
 	"github.com/sirupsen/logrus"
 	"github.com/videocoin/marketplace/internal/datastore"
-	"github.com/videocoin/marketplace/internal/mediaprocessor"
 	"github.com/videocoin/marketplace/internal/minter"
-	"github.com/videocoin/marketplace/internal/storage"
 )
 
 type Option func(l *OrderBook) error
@@ -24,23 +22,11 @@ func WithDatastore(ds *datastore.Datastore) Option {
 	}
 }
 
-func WithMediaProcessor(mp *mediaprocessor.MediaProcessor) Option {
+// WithMinters sets the minter of every network, the minter of an asset is
+// the one of the network it is tagged with.
+func WithMinters(minters minter.Minters) Option {
 	return func(book *OrderBook) error {
-		book.mp = mp
-		return nil
-	}
-}
-
-func WithStorage(s *storage.Storage) Option {
-	return func(book *OrderBook) error {
-		book.storage = s
-		return nil
-	}
-}
-
-func WithMinter(m *minter.Minter) Option {
-	return func(book *OrderBook) error {
-		book.minter = m
+		book.minters = minters
 		return nil
 	}
 }
@@ -1,9 +1,9 @@
 package orderbook
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/AlekSi/pointer"
 	"github.com/gocraft/dbr/v2"
@@ -11,21 +11,16 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/videocoin/marketplace/internal/datastore"
 	"github.com/videocoin/marketplace/internal/drm"
-	"github.com/videocoin/marketplace/internal/mediaprocessor"
 	"github.com/videocoin/marketplace/internal/minter"
 	"github.com/videocoin/marketplace/internal/model"
-	"github.com/videocoin/marketplace/internal/storage"
-	"github.com/videocoin/marketplace/internal/token"
 	"github.com/videocoin/marketplace/pkg/ethutil"
-	"path"
+	"math/big"
 )
 
 type OrderBook struct {
-	logger  *logrus.Entry
-	ds      *datastore.Datastore
-	mp      *mediaprocessor.MediaProcessor
-	storage *storage.Storage
-	minter  *minter.Minter
+	logger *logrus.Entry
+	ds     *datastore.Datastore
+	minters minter.Minters
 }
 
 func NewOderBook(ctx context.Context, opts ...Option) (*OrderBook, error) {
@@ -51,10 +46,48 @@ func (book *OrderBook) Approve(ctx context.Context, order *model.Order) error {
 }
 
 func (book *OrderBook) Cancel(ctx context.Context, order *model.Order) error {
-	return book.ds.Orders.MarkStatusAsCanceled(ctx, order)
+	err := book.ds.Orders.MarkStatusAsCanceled(ctx, order)
+	if err != nil {
+		return err
+	}
+
+	auction, err := book.ds.Auctions.GetLatestByAssetID(ctx, order.TokenID)
+	if err != nil {
+		if err == datastore.ErrAuctionNotFound {
+			return nil
+		}
+		return err
+	}
+
+	if auction.SellOrderID != order.ID || (auction.Status != model.AuctionStatusOpen && auction.Status != model.AuctionStatusEnded) {
+		return nil
+	}
+
+	return book.ds.Auctions.MarkStatusAsCancelled(ctx, auction)
 }
 
-func (book *OrderBook) Process(ctx context.Context, order *model.Order, newOwner *model.Account) error {
+// Fill describes how an order has been matched on the exchange.
+type Fill struct {
+	Buyer  *model.Account
+	Seller *model.Account
+	// Quantity is the number of editions which changed hands, always one
+	// for ERC721 assets.
+	Quantity int64
+	// Counterpart is the other order of the match, if it is known.
+	Counterpart *model.Order
+	// Price is the price the orders were matched at, the base price of the
+	// sell order is used if it is not known.
+	Price     *big.Int
+	BlockHash string
+	TxHash    string
+	// LogIndex is the index of the match in the transaction, a fill is
+	// applied once per log.
+	LogIndex uint
+}
+
+func (book *OrderBook) Process(ctx context.Context, order *model.Order, fill *Fill) error {
+	newOwner := fill.Buyer
+
 	logger := book.logger
 	logger = logger.WithFields(logrus.Fields{
 		"hash":                   order.Hash,
@@ -83,6 +116,18 @@ func (book *OrderBook) Process(ctx context.Context, order *model.Order, newOwner
 		return nil
 	}
 
+	applied, err := book.ds.Ledger.HasFill(ctx, fill.TxHash, fill.LogIndex)
+	if err != nil {
+		return err
+	}
+	if applied {
+		logger.
+			WithField("tx_hash", fill.TxHash).
+			WithField("log_index", fill.LogIndex).
+			Info("fill has already been applied")
+		return nil
+	}
+
 	asset, err := book.ds.Assets.GetByTokenID(ctx, order.TokenID)
 	if err != nil {
 		return err
@@ -96,13 +141,6 @@ func (book *OrderBook) Process(ctx context.Context, order *model.Order, newOwner
 	}
 	asset.CreatedBy = account
 
-	mediaItems, err := book.ds.Media.ListByAssetID(ctx, asset.ID)
-	if err != nil {
-		return err
-	}
-
-	asset.Media = mediaItems
-
 	logger = logger.
 		WithField("asset_id", asset.ID).
 		WithField("on_sale", asset.OnSale)
@@ -112,6 +150,10 @@ func (book *OrderBook) Process(ctx context.Context, order *model.Order, newOwner
 		return nil
 	}
 
+	if asset.IsEdition() {
+		return book.processFill(ctx, logger, order, asset, fill)
+	}
+
 	logger.Info("marking order as processing")
 	err = book.ds.Orders.MarkStatusAsProcessing(ctx, order)
 	if err != nil {
@@ -125,18 +167,12 @@ func (book *OrderBook) Process(ctx context.Context, order *model.Order, newOwner
 		return fmt.Errorf("failed to mark asset as transferring: %s", err)
 	}
 
+	// the asset stays transferring until the job has published it
 	err = book.transferAsset(ctx, asset, newOwner)
 	if err != nil {
 		return fmt.Errorf("failed to transfer asset: %s", err)
 	}
 
-	logger.Info("marking asset as transferred")
-
-	err = book.ds.Assets.MarkStatusAsTransfered(ctx, asset)
-	if err != nil {
-		return fmt.Errorf("failed to mark asset as transferred: %s", err)
-	}
-
 	logger.Info("asset has been transferred")
 
 	basePrice, err := ethutil.ParseBigInt(order.WyvernOrder.BasePrice)
@@ -165,35 +201,211 @@ func (book *OrderBook) Process(ctx context.Context, order *model.Order, newOwner
 
 	logger.Info("order has been processed")
 
-	go func() {
-		err = book.ds.Activity.Create(ctx, &model.Activity{
-			IsNew:       true,
-			CreatedByID: newOwner.ID,
-			TypeID:      model.ActivityTypePurchased,
-			GroupID:     model.ActivityGroupPurchases,
-			AssetID:     dbr.NewNullInt64(asset.ID),
-			OrderID:     dbr.NewNullInt64(order.ID),
-		})
+	err = book.recordSale(ctx, order, asset, fill, 1)
+	if err != nil {
+		return fmt.Errorf("failed to record sale: %s", err)
+	}
+
+	err = book.settleAuction(ctx, asset)
+	if err != nil {
+		logger.WithError(err).Error("failed to settle auction")
+	}
+
+	err = book.ds.Activity.Create(ctx, &model.Activity{
+		IsNew:       true,
+		CreatedByID: newOwner.ID,
+		TypeID:      model.ActivityTypePurchased,
+		GroupID:     model.ActivityGroupPurchases,
+		AssetID:     dbr.NewNullInt64(asset.ID),
+		OrderID:     dbr.NewNullInt64(order.ID),
+	})
+	if err != nil {
+		logger.WithError(err).Error("failed to create activity item (purchased)")
+	}
+
+	err = book.ds.Activity.Create(ctx, &model.Activity{
+		IsNew:       true,
+		CreatedByID: oldOwnerID,
+		TypeID:      model.ActivityTypeSold,
+		GroupID:     model.ActivityGroupSales,
+		AssetID:     dbr.NewNullInt64(asset.ID),
+		OrderID:     dbr.NewNullInt64(order.ID),
+	})
+	if err != nil {
+		logger.WithError(err).Error("failed to create activity item (sold)")
+	}
+
+	return nil
+}
+
+// processFill moves the filled editions from the seller to the buyer. The
+// order stays open until its whole quantity has been filled. Editions share
+// the media encryption of the asset, so nothing is re-encrypted here.
+func (book *OrderBook) processFill(ctx context.Context, logger *logrus.Entry, order *model.Order, asset *model.Asset, fill *Fill) error {
+	if fill.Seller == nil {
+		return errors.New("edition seller is unknown")
+	}
+
+	quantity := fill.Quantity
+	if quantity <= 0 || quantity > order.RemainingQuantity() {
+		quantity = order.RemainingQuantity()
+	}
+
+	logger = logger.
+		WithField("seller_id", fill.Seller.ID).
+		WithField("buyer_id", fill.Buyer.ID).
+		WithField("quantity", quantity).
+		WithField("filled_quantity", order.FilledQuantity)
+
+	if quantity == 0 {
+		logger.Warning("order has already been filled")
+		return nil
+	}
+
+	basePrice, err := ethutil.ParseBigInt(order.WyvernOrder.BasePrice)
+	if err != nil {
+		return err
+	}
+
+	price := ethutil.WeiToEther(basePrice)
+	priceFloat, _ := price.Float64()
+
+	err = book.ds.InTx(ctx, func(ctx context.Context) error {
+		err := book.ds.AssetHolders.Transfer(ctx, asset.ID, fill.Seller.ID, fill.Buyer.ID, quantity)
 		if err != nil {
-			logger.WithError(err).Error("failed to create activity item (purchased)")
+			return fmt.Errorf("failed to transfer editions: %s", err)
 		}
 
-		err = book.ds.Activity.Create(ctx, &model.Activity{
-			IsNew:       true,
-			CreatedByID: oldOwnerID,
-			TypeID:      model.ActivityTypeSold,
-			GroupID:     model.ActivityGroupSales,
-			AssetID:     dbr.NewNullInt64(asset.ID),
-			OrderID:     dbr.NewNullInt64(order.ID),
-		})
+		balance, err := book.ds.AssetHolders.GetBalance(ctx, asset.ID, fill.Seller.ID)
 		if err != nil {
-			logger.WithError(err).Error("failed to create activity item (sold)")
+			return fmt.Errorf("failed to get seller balance: %s", err)
+		}
+
+		if balance == 0 {
+			err = book.revokeDelegates(ctx, logger, asset.ID, fill.Seller.ID)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = book.ds.Orders.UpdateFilledQuantity(ctx, order, order.FilledQuantity+quantity)
+		if err != nil {
+			return fmt.Errorf("failed to update filled quantity: %s", err)
+		}
+
+		if fill.Counterpart != nil {
+			err = book.ds.Orders.ArchiveByIds(ctx, []int64{fill.Counterpart.ID})
+			if err != nil {
+				return fmt.Errorf("failed to archive counterpart order: %s", err)
+			}
+		}
+
+		err = book.recordSale(ctx, order, asset, fill, quantity)
+		if err != nil {
+			return fmt.Errorf("failed to record sale: %s", err)
+		}
+
+		fields := datastore.AssetUpdatedFields{
+			PurchasedBid: pointer.ToFloat64(priceFloat),
+		}
+		if order.IsFilled() {
+			fields.OnSale = pointer.ToBool(false)
+		}
+
+		err = book.ds.Assets.Update(ctx, asset, fields)
+		if err != nil {
+			return fmt.Errorf("failed to update asset: %s", err)
+		}
+
+		if order.IsFilled() {
+			err = book.ds.Orders.ArchiveByIds(ctx, []int64{order.ID})
+			if err != nil {
+				return fmt.Errorf("failed to archive order: %s", err)
+			}
+
+			err = book.ds.Orders.MarkStatusAsProcessed(ctx, order)
+			if err != nil {
+				return fmt.Errorf("failed to mark order as processed: %s", err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if order.IsFilled() {
+		logger.Info("order has been filled")
+	} else {
+		logger.Info("order has been partially filled")
+	}
+
+	err = book.ds.Activity.Create(ctx, &model.Activity{
+		IsNew:       true,
+		CreatedByID: fill.Buyer.ID,
+		TypeID:      model.ActivityTypePurchased,
+		GroupID:     model.ActivityGroupPurchases,
+		AssetID:     dbr.NewNullInt64(asset.ID),
+		OrderID:     dbr.NewNullInt64(order.ID),
+	})
+	if err != nil {
+		logger.WithError(err).Error("failed to create activity item (purchased)")
+	}
+
+	err = book.ds.Activity.Create(ctx, &model.Activity{
+		IsNew:       true,
+		CreatedByID: fill.Seller.ID,
+		TypeID:      model.ActivityTypeSold,
+		GroupID:     model.ActivityGroupSales,
+		AssetID:     dbr.NewNullInt64(asset.ID),
+		OrderID:     dbr.NewNullInt64(order.ID),
+	})
+	if err != nil {
+		logger.WithError(err).Error("failed to create activity item (sold)")
+	}
+
+	return nil
+}
+
+func (book *OrderBook) settleAuction(ctx context.Context, asset *model.Asset) error {
+	auction, err := book.ds.Auctions.GetLatestByAssetID(ctx, asset.ID)
+	if err != nil {
+		if err == datastore.ErrAuctionNotFound {
+			return nil
 		}
-	}()
+		return err
+	}
+
+	if auction.Status != model.AuctionStatusOpen && auction.Status != model.AuctionStatusEnded {
+		return nil
+	}
+
+	return book.ds.Auctions.MarkStatusAsSettled(ctx, auction)
+}
+
+// revokeDelegates drops the delegates a former holder has authorized, they
+// lose access to the content along with the holder.
+func (book *OrderBook) revokeDelegates(ctx context.Context, logger *logrus.Entry, assetID, holderID int64) error {
+	count, err := book.ds.AssetKeyEnvelopes.DeleteGrantedBy(ctx, assetID, holderID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke delegates: %s", err)
+	}
+
+	if count > 0 {
+		logger.
+			WithField("holder_id", holderID).
+			WithField("delegates", count).
+			Info("delegates of the former holder have been revoked")
+	}
 
 	return nil
 }
 
+// transferAsset gives the asset a content key of the new owner. Only the
+// database is changed here, so a match which gets rolled back leaves no
+// trace: the media is re-encrypted and the metadata published by the asset
+// transfer job, which is queued in the same transaction.
 func (book *OrderBook) transferAsset(ctx context.Context, asset *model.Asset, newOwner *model.Account) error {
 	logger := book.logger.
 		WithField("new_owner_id", newOwner.ID).
@@ -207,67 +419,145 @@ func (book *OrderBook) transferAsset(ctx context.Context, asset *model.Asset, ne
 	}
 	drmMetaJSON, _ := json.Marshal(drmMeta)
 
-	for _, media := range asset.Media {
-		if media.Featured {
-			continue
-		}
-
-		logger.
-			WithField("media_id", media.ID).
-			Info("encrypting media")
-
-		assetMeta := model.NewAssetMeta(path.Base(media.GetUrl(false)), media.ContentType)
-		newEncryptedKey := assetMeta.DestEncKey
-		media.EncryptedKey = newEncryptedKey
-		err = book.mp.EncryptMedia(ctx, media, drmMeta)
-		if err != nil {
-			return fmt.Errorf("failed to encrypt media #%s: %s", media.ID, err)
-		}
-
-		err = book.ds.Media.Update(ctx, media, datastore.MediaUpdatedFields{
-			EncryptedKey: pointer.ToString(newEncryptedKey),
-		})
-		if err != nil {
-			return fmt.Errorf("failed to update media encrypted key #%s: %s", media.ID, err)
-		}
-	}
-
 	assetFields := datastore.AssetUpdatedFields{
 		DRMKey:  pointer.ToString(drmKey),
 		DRMMeta: pointer.ToString(string(drmMetaJSON)),
+		DRMKID:  pointer.ToString(drmMeta.KID),
 		OwnerID: pointer.ToInt64(newOwner.ID),
 		OnSale:  pointer.ToBool(false),
+
+		OwnerKeyMissing: pointer.ToBool(false),
 	}
 	err = book.ds.Assets.Update(ctx, asset, assetFields)
 	if err != nil {
 		return fmt.Errorf("failed to update asset: %s", err)
 	}
 
-	tokenURI := pointer.ToString("")
-	tokenJSON, _ := token.ToTokenJSON(asset)
-	tokenCID, err := book.storage.PushPath(
-		fmt.Sprintf("%d.json", asset.ID),
-		bytes.NewBuffer(tokenJSON),
-		true,
-	)
+	err = book.ds.AssetHolders.Replace(ctx, asset.ID, []*model.AssetHolder{
+		{AssetID: asset.ID, AccountID: newOwner.ID, Balance: 1},
+	})
 	if err != nil {
-		return fmt.Errorf("failed to upload token json to storage: %s", err)
+		return fmt.Errorf("failed to update asset holder: %s", err)
 	}
 
-	logger = book.logger.WithField("token_cid", tokenCID)
-	logger.Info("updating token url")
+	envelopes, err := book.sealKeyEnvelopes(ctx, asset, newOwner, drmKey, drmMeta)
+	if err != nil {
+		return fmt.Errorf("failed to seal drm key envelopes: %s", err)
+	}
 
-	err = book.ds.Assets.Update(ctx, asset, datastore.AssetUpdatedFields{
-		TokenCID: pointer.ToString(tokenCID),
+	err = book.ds.AssetKeyEnvelopes.Replace(ctx, asset.ID, envelopes)
+	if err != nil {
+		return fmt.Errorf("failed to update drm key envelopes: %s", err)
+	}
+
+	err = book.publishTransfer(ctx, asset, true)
+	if err != nil {
+		return err
+	}
+
+	logger.Info("asset transfer job has been queued")
+
+	return nil
+}
+
+// sealKeyEnvelopes seals the new content key to the new owner and to the
+// creator, who keeps access to the content after selling it. The delegates
+// of the previous owner are revoked.
+func (book *OrderBook) sealKeyEnvelopes(ctx context.Context, asset *model.Asset, newOwner *model.Account, drmKey string, drmMeta *drm.Metadata) ([]*model.AssetKeyEnvelope, error) {
+	envelopes := []*model.AssetKeyEnvelope{
+		{
+			AssetID:   asset.ID,
+			AccountID: newOwner.ID,
+			Role:      model.KeyEnvelopeRoleOwner,
+			Envelope:  drmKey,
+		},
+	}
+
+	if asset.CreatedByID == newOwner.ID {
+		return envelopes, nil
+	}
+
+	creator, err := book.ds.Accounts.GetByID(ctx, asset.CreatedByID)
+	if err != nil {
+		return nil, err
+	}
+
+	if creator.IsPlaceholder() {
+		book.logger.
+			WithField("asset_id", asset.ID).
+			WithField("creator_id", creator.ID).
+			Warning("creator has no encryption key, skipping drm key envelope")
+		return envelopes, nil
+	}
+
+	envelope, err := drm.SealKey(drmMeta, creator.EncryptionPublicKey.String)
+	if err != nil {
+		return nil, err
+	}
+
+	envelopes = append(envelopes, &model.AssetKeyEnvelope{
+		AssetID:   asset.ID,
+		AccountID: creator.ID,
+		Role:      model.KeyEnvelopeRoleCreator,
+		Envelope:  envelope,
 	})
+
+	return envelopes, nil
+}
+
+// publishTransfer schedules the storage work of a transfer, the job
+// publishes the asset with the content key it has now.
+func (book *OrderBook) publishTransfer(ctx context.Context, asset *model.Asset, reencrypt bool) error {
+	job, err := model.NewAssetTransferJob(asset, reencrypt)
 	if err != nil {
-		return fmt.Errorf("failed to update asset token cid: %s", err)
+		return fmt.Errorf("failed to schedule asset transfer: %s", err)
 	}
 
-	tokenURI = asset.GetTokenUrl()
-	logger.WithField("token_uri", tokenURI)
-	if tokenURI == nil {
-		return fmt.Errorf("failed to get asset token uri: %s", err)
+	err = book.ds.Jobs.Create(ctx, job)
+	if err != nil {
+		return fmt.Errorf("failed to schedule asset transfer: %s", err)
+	}
+
+	return nil
+}
+
+// redeemVoucher schedules minting of a lazily minted asset to its creator
+// once an order has been placed for it, so that the token exists before the
+// order can be matched. The voucher has to be signed by the minter of the
+// network the asset is tagged with.
+func (book *OrderBook) redeemVoucher(ctx context.Context, logger *logrus.Entry, asset *model.Asset) error {
+	if !asset.IsUnminted() {
+		return nil
+	}
+
+	logger = logger.WithField("network", asset.Network)
+
+	m, err := book.minters.Get(asset.Network)
+	if err != nil {
+		return fmt.Errorf("failed to get minter: %s", err)
+	}
+
+	if asset.MintVoucher == nil {
+		logger.Warning("asset has no mint voucher")
+		return nil
+	}
+
+	err = m.VerifyVoucher(asset.MintVoucher)
+	if err != nil {
+		logger.WithError(err).Warning("mint voucher has not been signed for the network")
+		return nil
+	}
+
+	logger.Info("scheduling mint voucher redemption")
+
+	job, err := model.NewAssetRedeemJob(asset)
+	if err != nil {
+		return fmt.Errorf("failed to schedule mint voucher redemption: %s", err)
+	}
+
+	err = book.ds.Jobs.Create(ctx, job)
+	if err != nil {
+		return fmt.Errorf("failed to schedule mint voucher redemption: %s", err)
 	}
 
 	return nil
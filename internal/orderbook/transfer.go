@@ -0,0 +1,142 @@
+package orderbook
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/AlekSi/pointer"
+	"github.com/videocoin/marketplace/internal/datastore"
+	"github.com/videocoin/marketplace/internal/model"
+)
+
+// ProcessTransfer reconciles the ownership of a token transferred outside of
+// the exchange. The recipient gets a placeholder account if it hasn't signed
+// up yet. Without an encryption key of the new owner the media can't be
+// re-encrypted, so the asset is flagged instead and keeps the keys of the
+// previous owner.
+func (book *OrderBook) ProcessTransfer(ctx context.Context, asset *model.Asset, toAddress string) error {
+	logger := book.logger.
+		WithField("asset_id", asset.ID).
+		WithField("to", toAddress)
+
+	if asset.IsEdition() {
+		logger.Warning("editions are not tracked by token transfers")
+		return nil
+	}
+
+	newOwner, err := book.getOrCreateAccount(ctx, toAddress)
+	if err != nil {
+		return fmt.Errorf("failed to get new owner: %s", err)
+	}
+
+	logger = logger.WithField("new_owner_id", newOwner.ID)
+
+	if asset.OwnerID == newOwner.ID {
+		logger.Info("asset is already owned by recipient")
+		return nil
+	}
+
+	account, err := book.ds.Accounts.GetByID(ctx, asset.CreatedByID)
+	if err != nil {
+		return err
+	}
+	asset.CreatedBy = account
+
+	err = book.ds.Assets.MarkStatusAsTransferring(ctx, asset)
+	if err != nil {
+		return fmt.Errorf("failed to mark asset as transferring: %s", err)
+	}
+
+	if newOwner.IsPlaceholder() {
+		logger.Warning("new owner has no encryption key")
+
+		err = book.revokeDelegates(ctx, logger, asset.ID, asset.OwnerID)
+		if err != nil {
+			return err
+		}
+
+		err = book.ds.Assets.Update(ctx, asset, datastore.AssetUpdatedFields{
+			OwnerID:         pointer.ToInt64(newOwner.ID),
+			OnSale:          pointer.ToBool(false),
+			OwnerKeyMissing: pointer.ToBool(true),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update asset: %s", err)
+		}
+
+		err = book.ds.AssetHolders.Replace(ctx, asset.ID, []*model.AssetHolder{
+			{AssetID: asset.ID, AccountID: newOwner.ID, Balance: 1},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update asset holder: %s", err)
+		}
+
+		err = book.ds.Assets.MarkStatusAsTransfered(ctx, asset)
+		if err != nil {
+			return fmt.Errorf("failed to mark asset as transferred: %s", err)
+		}
+	} else {
+		// the asset transfer job marks the asset as transferred
+		err = book.transferAsset(ctx, asset, newOwner)
+		if err != nil {
+			return fmt.Errorf("failed to transfer asset: %s", err)
+		}
+	}
+
+	// orders of the previous owner can't be matched anymore
+	err = book.ds.Orders.ArchiveByTokenID(ctx, asset.ID)
+	if err != nil {
+		return fmt.Errorf("failed to archive orders: %s", err)
+	}
+
+	err = book.cancelAuction(ctx, asset)
+	if err != nil {
+		logger.WithError(err).Error("failed to cancel auction")
+	}
+
+	logger.Info("asset ownership has been reconciled")
+
+	return nil
+}
+
+// cancelAuction closes the auction of the previous owner, its winning bid
+// can't be matched anymore.
+func (book *OrderBook) cancelAuction(ctx context.Context, asset *model.Asset) error {
+	auction, err := book.ds.Auctions.GetLatestByAssetID(ctx, asset.ID)
+	if err != nil {
+		if err == datastore.ErrAuctionNotFound {
+			return nil
+		}
+		return err
+	}
+
+	if auction.Status != model.AuctionStatusOpen && auction.Status != model.AuctionStatusEnded {
+		return nil
+	}
+
+	return book.ds.Auctions.MarkStatusAsCancelled(ctx, auction)
+}
+
+func (book *OrderBook) getOrCreateAccount(ctx context.Context, address string) (*model.Account, error) {
+	account, err := book.ds.Accounts.GetByAddress(ctx, address)
+	if err == nil {
+		return account, nil
+	}
+	if err != datastore.ErrAccountNotFound {
+		return nil, err
+	}
+
+	account = &model.Account{Address: strings.ToLower(address)}
+	err = book.ds.Accounts.Create(ctx, account)
+	if err != nil {
+		return nil, err
+	}
+
+	book.logger.
+		WithField("account_id", account.ID).
+		WithField("address", account.Address).
+		Info("placeholder account has been created")
+
+	return account, nil
+}
@@ -0,0 +1,75 @@
+package orderbook
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/gocraft/dbr/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/videocoin/marketplace/internal/minter"
+	"github.com/videocoin/marketplace/internal/model"
+)
+
+func newLazyMinter(t *testing.T, network string) *minter.Minter {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := minter.NewMinterWithBackend(
+		nil,
+		&bind.TransactOpts{},
+		"0x00000000000000000000000000000000000000c1",
+		"",
+		minter.WithNetwork(network),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.EnableLazyMint(key, big.NewInt(1337))
+
+	return m
+}
+
+// TestRedeemVoucherSelectsNetworkMinter checks that the voucher of an asset
+// is verified by the minter of the network the asset is tagged with.
+func TestRedeemVoucherSelectsNetworkMinter(t *testing.T) {
+	ctx := context.Background()
+	logger := logrus.NewEntry(logrus.StandardLogger())
+
+	minters := minter.Minters{
+		"mainnet": newLazyMinter(t, "mainnet"),
+		"polygon": newLazyMinter(t, "polygon"),
+	}
+	book := &OrderBook{logger: logger, minters: minters}
+
+	voucher := &model.MintVoucher{
+		TokenID: "7",
+		URI:     "ipfs://token",
+		Creator: "0x00000000000000000000000000000000000000a1",
+		Price:   "1000",
+	}
+	if err := minters["mainnet"].SignVoucher(voucher); err != nil {
+		t.Fatal(err)
+	}
+
+	// the voucher of another network is skipped before anything is queued
+	asset := &model.Asset{ID: 7, LazyMint: true, MintVoucher: voucher, Network: "polygon"}
+	if err := book.redeemVoucher(ctx, logger, asset); err != nil {
+		t.Errorf("voucher of another network: %s", err)
+	}
+
+	asset.Network = "unknown"
+	if err := book.redeemVoucher(ctx, logger, asset); err == nil {
+		t.Error("voucher of an unknown network has been redeemed")
+	}
+
+	// minted assets have nothing to redeem
+	asset.MintTxID = dbr.NewNullString("0x1")
+	if err := book.redeemVoucher(ctx, logger, asset); err != nil {
+		t.Errorf("minted asset: %s", err)
+	}
+}
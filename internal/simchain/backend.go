@@ -0,0 +1,468 @@
+package simchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// PriceBump is the percentage a replacement transaction has to pay above
+// the pending one, the default of the go-ethereum tx pool.
+const PriceBump = 10
+
+var (
+	ErrNonceMismatch     = errors.New("invalid transaction nonce")
+	ErrUnknownBlock      = errors.New("unknown block")
+	ErrSubscriptionUnsup = errors.New("log subscriptions are not supported")
+)
+
+// SimulatedBackend is an in-memory chain which mines the pending
+// transactions when Commit is called. Fork starts the next block on an older
+// parent, committing past the canonical head reorganizes the chain.
+type SimulatedBackend struct {
+	mu sync.Mutex
+
+	database   ethdb.Database
+	blockchain *core.BlockChain
+	config     *params.ChainConfig
+
+	parent       *types.Block
+	pendingTxs   []*types.Transaction
+	pendingBlock *types.Block
+	pendingState *state.StateDB
+}
+
+// NewSimulatedBackend creates a chain with the alloc in its genesis block.
+func NewSimulatedBackend(alloc core.GenesisAlloc, gasLimit uint64) (*SimulatedBackend, error) {
+	genesisConfig := *params.AllEthashProtocolChanges
+	genesisConfig.ChainID = ChainID
+
+	database := rawdb.NewMemoryDatabase()
+	genesis := core.Genesis{Config: &genesisConfig, GasLimit: gasLimit, Alloc: alloc}
+	genesisBlock := genesis.MustCommit(database)
+
+	// the unoptimized dev build of the exchange is larger than the EIP-170
+	// code size limit, the chain runs without it like ganache does with
+	// allowUnlimitedContractSize. The genesis is committed with the limit,
+	// the fork ordering check rejects the config without it.
+	config := genesisConfig
+	config.EIP158Block = nil
+
+	blockchain, err := core.NewBlockChain(database, nil, &config, ethash.NewFaker(), vm.Config{}, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &SimulatedBackend{
+		database:   database,
+		blockchain: blockchain,
+		config:     &config,
+		parent:     genesisBlock,
+	}
+	if err := b.rebuildPending(); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// Close stops the chain.
+func (b *SimulatedBackend) Close() {
+	b.blockchain.Stop()
+}
+
+// Commit mines the pending transactions into a block on top of the parent.
+func (b *SimulatedBackend) Commit() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, err := b.blockchain.InsertChain([]*types.Block{b.pendingBlock}); err != nil {
+		panic(err)
+	}
+
+	b.parent = b.pendingBlock
+	b.pendingTxs = nil
+	if err := b.rebuildPending(); err != nil {
+		panic(err)
+	}
+}
+
+// Fork drops the pending transactions and starts the next block on the
+// block with the hash, the canonical chain switches to the fork once it gets
+// longer than the current one.
+func (b *SimulatedBackend) Fork(parentHash common.Hash) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	parent := b.blockchain.GetBlockByHash(parentHash)
+	if parent == nil {
+		return ErrUnknownBlock
+	}
+
+	b.parent = parent
+	b.pendingTxs = nil
+
+	return b.rebuildPending()
+}
+
+// rebuildPending generates the pending block from the pending transactions,
+// GenerateChain panics on transactions that cannot be included.
+func (b *SimulatedBackend) rebuildPending() (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("invalid transaction: %v", r)
+		}
+	}()
+
+	blocks, _ := core.GenerateChain(b.config, b.parent, ethash.NewFaker(), b.database, 1, func(i int, block *core.BlockGen) {
+		for _, tx := range b.pendingTxs {
+			block.AddTxWithChain(b.blockchain, tx)
+		}
+	})
+
+	pendingState, err := state.New(blocks[0].Root(), b.blockchain.StateCache(), nil)
+	if err != nil {
+		return err
+	}
+
+	b.pendingBlock = blocks[0]
+	b.pendingState = pendingState
+
+	return nil
+}
+
+func (b *SimulatedBackend) stateAt(blockNumber *big.Int) (*state.StateDB, error) {
+	if blockNumber == nil || blockNumber.Cmp(b.blockchain.CurrentBlock().Number()) == 0 {
+		return b.blockchain.State()
+	}
+
+	block := b.blockchain.GetBlockByNumber(blockNumber.Uint64())
+	if block == nil {
+		return nil, ErrUnknownBlock
+	}
+
+	return b.blockchain.StateAt(block.Root())
+}
+
+func (b *SimulatedBackend) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	statedb, err := b.stateAt(blockNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	return statedb.GetCode(contract), nil
+}
+
+func (b *SimulatedBackend) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	statedb, err := b.stateAt(blockNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	return statedb.GetBalance(account), nil
+}
+
+func (b *SimulatedBackend) PendingCodeAt(ctx context.Context, contract common.Address) ([]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.pendingState.GetCode(contract), nil
+}
+
+func (b *SimulatedBackend) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.pendingState.GetNonce(account), nil
+}
+
+func (b *SimulatedBackend) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return big.NewInt(1), nil
+}
+
+func (b *SimulatedBackend) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	statedb, err := b.stateAt(blockNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := b.callContract(call, b.blockchain.CurrentBlock().Header(), statedb)
+	if err != nil {
+		return nil, err
+	}
+	if res.Err != nil {
+		return nil, res.Err
+	}
+
+	return res.Return(), nil
+}
+
+func (b *SimulatedBackend) PendingCallContract(ctx context.Context, call ethereum.CallMsg) ([]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	res, err := b.callContract(call, b.pendingBlock.Header(), b.pendingState.Copy())
+	if err != nil {
+		return nil, err
+	}
+	if res.Err != nil {
+		return nil, res.Err
+	}
+
+	return res.Return(), nil
+}
+
+// EstimateGas searches for the lowest gas limit the call succeeds with on
+// the pending state.
+func (b *SimulatedBackend) EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	hi := b.pendingBlock.GasLimit()
+	if call.Gas >= params.TxGas {
+		hi = call.Gas
+	}
+	lo := params.TxGas - 1
+
+	executable := func(gas uint64) (bool, error) {
+		call.Gas = gas
+		res, err := b.callContract(call, b.pendingBlock.Header(), b.pendingState.Copy())
+		if err != nil {
+			if errors.Is(err, core.ErrIntrinsicGas) {
+				return false, nil
+			}
+			return false, err
+		}
+		return !res.Failed(), nil
+	}
+
+	call.Gas = hi
+	res, err := b.callContract(call, b.pendingBlock.Header(), b.pendingState.Copy())
+	if err != nil {
+		return 0, err
+	}
+	if res.Failed() {
+		return 0, res.Err
+	}
+
+	for lo+1 < hi {
+		mid := (hi + lo) / 2
+		ok, err := executable(mid)
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+
+	return hi, nil
+}
+
+func (b *SimulatedBackend) callContract(call ethereum.CallMsg, header *types.Header, statedb *state.StateDB) (*core.ExecutionResult, error) {
+	if call.GasPrice == nil {
+		call.GasPrice = big.NewInt(0)
+	}
+	if call.Gas == 0 {
+		call.Gas = header.GasLimit
+	}
+	if call.Value == nil {
+		call.Value = big.NewInt(0)
+	}
+
+	// calls are free, the sender only needs the value
+	statedb.SetBalance(call.From, new(big.Int).Add(call.Value, big.NewInt(math.MaxInt64)))
+
+	msg := types.NewMessage(call.From, call.To, 0, call.Value, call.Gas, call.GasPrice, call.Data, nil, false)
+	blockContext := core.NewEVMBlockContext(header, b.blockchain, nil)
+	evm := vm.NewEVM(blockContext, core.NewEVMTxContext(msg), statedb, b.config, vm.Config{})
+	gasPool := new(core.GasPool).AddGas(math.MaxUint64)
+
+	return core.NewStateTransition(evm, msg, gasPool).TransitionDb()
+}
+
+// SendTransaction adds the transaction to the pending block. A pending
+// transaction with the same nonce is replaced the way the go-ethereum tx
+// pool does it, if the new one pays PriceBump percent more.
+func (b *SimulatedBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	signer := types.LatestSignerForChainID(ChainID)
+	sender, err := types.Sender(signer, tx)
+	if err != nil {
+		return err
+	}
+
+	for i, pending := range b.pendingTxs {
+		from, _ := types.Sender(signer, pending)
+		if from != sender || pending.Nonce() != tx.Nonce() {
+			continue
+		}
+
+		threshold := new(big.Int).Mul(pending.GasPrice(), big.NewInt(100+PriceBump))
+		threshold.Div(threshold, big.NewInt(100))
+		if tx.GasPrice().Cmp(pending.GasPrice()) <= 0 || tx.GasPrice().Cmp(threshold) < 0 {
+			return core.ErrReplaceUnderpriced
+		}
+
+		b.pendingTxs[i] = tx
+		if err := b.rebuildPending(); err != nil {
+			b.pendingTxs[i] = pending
+			return err
+		}
+
+		return nil
+	}
+
+	if tx.Nonce() != b.pendingState.GetNonce(sender) {
+		return ErrNonceMismatch
+	}
+
+	b.pendingTxs = append(b.pendingTxs, tx)
+	if err := b.rebuildPending(); err != nil {
+		b.pendingTxs = b.pendingTxs[:len(b.pendingTxs)-1]
+		return err
+	}
+
+	return nil
+}
+
+// TransactionReceipt returns the receipt of a transaction of the canonical
+// chain.
+func (b *SimulatedBackend) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	receipt, _, _, _ := rawdb.ReadReceipt(b.database, txHash, b.config)
+	if receipt == nil {
+		return nil, ethereum.NotFound
+	}
+
+	return receipt, nil
+}
+
+func (b *SimulatedBackend) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if number == nil {
+		return b.blockchain.CurrentHeader(), nil
+	}
+
+	header := b.blockchain.GetHeaderByNumber(number.Uint64())
+	if header == nil {
+		return nil, ethereum.NotFound
+	}
+
+	return header, nil
+}
+
+// FilterLogs returns the logs of the canonical chain matching the query.
+func (b *SimulatedBackend) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var blocks []*types.Block
+	if q.BlockHash != nil {
+		block := b.blockchain.GetBlockByHash(*q.BlockHash)
+		if block == nil {
+			return nil, ErrUnknownBlock
+		}
+		blocks = append(blocks, block)
+	} else {
+		from := uint64(0)
+		if q.FromBlock != nil {
+			from = q.FromBlock.Uint64()
+		}
+		to := b.blockchain.CurrentBlock().NumberU64()
+		if q.ToBlock != nil && q.ToBlock.Uint64() < to {
+			to = q.ToBlock.Uint64()
+		}
+		for n := from; n <= to; n++ {
+			block := b.blockchain.GetBlockByNumber(n)
+			if block == nil {
+				break
+			}
+			blocks = append(blocks, block)
+		}
+	}
+
+	logs := make([]types.Log, 0)
+	for _, block := range blocks {
+		for _, receipt := range b.blockchain.GetReceiptsByHash(block.Hash()) {
+			for _, log := range receipt.Logs {
+				if matchLog(log, q) {
+					logs = append(logs, *log)
+				}
+			}
+		}
+	}
+
+	return logs, nil
+}
+
+func (b *SimulatedBackend) SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	return nil, ErrSubscriptionUnsup
+}
+
+func matchLog(log *types.Log, q ethereum.FilterQuery) bool {
+	if len(q.Addresses) > 0 {
+		found := false
+		for _, addr := range q.Addresses {
+			if addr == log.Address {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if len(q.Topics) > len(log.Topics) {
+		return false
+	}
+	for i, topics := range q.Topics {
+		if len(topics) == 0 {
+			continue
+		}
+		found := false
+		for _, topic := range topics {
+			if topic == log.Topics[i] {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,149 @@
+package simchain_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/videocoin/marketplace/internal/simchain"
+)
+
+func TestForkReorganizesChain(t *testing.T) {
+	ctx := context.Background()
+
+	owner, _, err := simchain.NewTransactor()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	backend, err := simchain.NewSimulatedBackend(core.GenesisAlloc{
+		owner.From: {Balance: new(big.Int).Lsh(big.NewInt(1), 100)},
+	}, 30000000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer backend.Close()
+
+	chain, err := simchain.Deploy(backend, owner, &simchain.DeployConfig{Name: "Test", Symbol: "TST"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parent, err := backend.HeaderByNumber(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx, err := chain.NFT721.SetApprovalForAll(chain.Owner, chain.ExchangeAddr, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	receipt, err := backend.TransactionReceipt(ctx, tx.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logs, err := backend.FilterLogs(ctx, ethereumQuery(receipt.BlockNumber))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logs) != 1 || logs[0].TxHash != tx.Hash() {
+		t.Fatalf("logs of the block = %v, want the approval", logs)
+	}
+
+	if err := backend.Fork(parent.Hash()); err != nil {
+		t.Fatal(err)
+	}
+
+	// the fork replaces the chain once it is longer
+	backend.Commit()
+	backend.Commit()
+	if _, err := backend.TransactionReceipt(ctx, tx.Hash()); err == nil {
+		t.Fatal("the transaction of the orphaned block has a receipt")
+	}
+
+	head, err := backend.HeaderByNumber(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if head.Number.Uint64() != parent.Number.Uint64()+2 {
+		t.Errorf("head = %d, want %d", head.Number.Uint64(), parent.Number.Uint64()+2)
+	}
+
+	logs, err = backend.FilterLogs(ctx, ethereumQuery(receipt.BlockNumber))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logs) != 0 {
+		t.Errorf("logs of the orphaned block are still returned: %v", logs)
+	}
+}
+
+func TestReplacementNeedsPriceBump(t *testing.T) {
+	ctx := context.Background()
+
+	owner, key, err := simchain.NewTransactor()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	backend, err := simchain.NewSimulatedBackend(core.GenesisAlloc{
+		owner.From: {Balance: new(big.Int).Lsh(big.NewInt(1), 100)},
+	}, 30000000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer backend.Close()
+
+	signer := types.LatestSignerForChainID(simchain.ChainID)
+	to := common.HexToAddress("0x00000000000000000000000000000000000000b2")
+	send := func(gasPrice int64) (*types.Transaction, error) {
+		tx, err := types.SignTx(types.NewTransaction(0, to, big.NewInt(1), 21000, big.NewInt(gasPrice), nil), signer, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tx, backend.SendTransaction(ctx, tx)
+	}
+
+	if _, err := send(1000); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, gasPrice := range []int64{1000, 1099} {
+		if _, err := send(gasPrice); err != core.ErrReplaceUnderpriced {
+			t.Errorf("replacement at %d returned %v, want %v", gasPrice, err, core.ErrReplaceUnderpriced)
+		}
+	}
+
+	replacement, err := send(1100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	backend.Commit()
+
+	receipt, err := backend.TransactionReceipt(ctx, replacement.Hash())
+	if err != nil {
+		t.Fatalf("replacement has not been mined: %s", err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		t.Error("replacement has failed")
+	}
+
+	nonce, err := backend.PendingNonceAt(ctx, owner.From)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nonce != 1 {
+		t.Errorf("nonce = %d, want 1", nonce)
+	}
+}
+
+func ethereumQuery(number *big.Int) ethereum.FilterQuery {
+	return ethereum.FilterQuery{FromBlock: number, ToBlock: number}
+}
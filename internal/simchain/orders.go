@@ -0,0 +1,236 @@
+package simchain
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/videocoin/marketplace/internal/wyvern"
+)
+
+// DefaultSellerFeeBps is the relayer fee of the orders built by SellOrder.
+const DefaultSellerFeeBps = 250
+
+var (
+	ErrTxFailed = errors.New("transaction failed")
+
+	transferFromSelector = crypto.Keccak256([]byte("transferFrom(address,address,uint256)"))[:4]
+)
+
+// SellOrder returns an order of the maker to sell the NFT721 token for the
+// price in ether, the owner is the fee recipient. Its listing time is zero,
+// blocks of the simulated chain start at the unix epoch.
+func (c *Chain) SellOrder(maker common.Address, tokenID *big.Int, price *big.Int) *wyvern.Order {
+	calldata := erc721TransferCalldata(maker, common.Address{}, tokenID)
+	replacementPattern := make([]byte, len(calldata))
+	fill(replacementPattern[4+common.HashLength : 4+2*common.HashLength])
+
+	return &wyvern.Order{
+		Exchange:           strings.ToLower(c.ExchangeAddr.Hex()),
+		Maker:              &wyvern.Account{Address: strings.ToLower(maker.Hex())},
+		Taker:              &wyvern.Account{Address: wyvern.NullAddress},
+		FeeRecipient:       &wyvern.Account{Address: strings.ToLower(c.Owner.From.Hex())},
+		MakerRelayerFee:    big.NewInt(DefaultSellerFeeBps).String(),
+		TakerRelayerFee:    "0",
+		MakerProtocolFee:   "0",
+		TakerProtocolFee:   "0",
+		FeeMethod:          wyvern.SplitFee,
+		Side:               wyvern.Sell,
+		SaleKind:           wyvern.FixedPrice,
+		Target:             strings.ToLower(c.NFT721Addr.Hex()),
+		HowToCall:          wyvern.Call,
+		Calldata:           hexutil.Encode(calldata),
+		ReplacementPattern: hexutil.Encode(replacementPattern),
+		StaticTarget:       wyvern.NullAddress,
+		StaticExtradata:    "0x",
+		PaymentToken:       wyvern.NullAddress,
+		BasePrice:          price.String(),
+		Extra:              "0",
+		ListingTime:        "0",
+		ExpirationTime:     "0",
+		Salt:               randomSalt().String(),
+	}
+}
+
+// BuyOrder returns the order of the taker matching the sell order.
+func (c *Chain) BuyOrder(sell *wyvern.Order, taker common.Address) (*wyvern.Order, error) {
+	tokenID, err := sell.TokenID()
+	if err != nil {
+		return nil, err
+	}
+
+	calldata := erc721TransferCalldata(common.Address{}, taker, tokenID)
+	replacementPattern := make([]byte, len(calldata))
+	fill(replacementPattern[4 : 4+common.HashLength])
+
+	buy := *sell
+	buy.Maker = &wyvern.Account{Address: strings.ToLower(taker.Hex())}
+	buy.FeeRecipient = &wyvern.Account{Address: wyvern.NullAddress}
+	buy.Side = wyvern.Buy
+	buy.Calldata = hexutil.Encode(calldata)
+	buy.ReplacementPattern = hexutil.Encode(replacementPattern)
+	buy.Salt = randomSalt().String()
+	buy.Hash = ""
+	buy.V, buy.R, buy.S = 0, "", ""
+
+	return &buy, nil
+}
+
+// SignOrder sets the hash and the signature of the maker on the order.
+func SignOrder(o *wyvern.Order, key *ecdsa.PrivateKey) error {
+	hash, err := o.OrderHash()
+	if err != nil {
+		return err
+	}
+
+	hashToSign, err := o.HashToSign()
+	if err != nil {
+		return err
+	}
+
+	sig, err := crypto.Sign(hashToSign.Bytes(), key)
+	if err != nil {
+		return err
+	}
+
+	o.Hash = strings.ToLower(hash.Hex())
+	o.R = hexutil.Encode(sig[:32])
+	o.S = hexutil.Encode(sig[32:64])
+	o.V = int(sig[64]) + 27
+
+	return nil
+}
+
+// ApproveOrder approves the order on the exchange, opts has to be the
+// maker's.
+func (c *Chain) ApproveOrder(opts *bind.TransactOpts, o *wyvern.Order) (*types.Transaction, error) {
+	addrs, uints, err := o.ContractArgs()
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := c.Exchange.ApproveOrder(
+		opts, addrs, uints,
+		uint8(o.FeeMethod), uint8(o.Side), uint8(o.SaleKind), uint8(o.HowToCall),
+		hexutil.MustDecode(o.Calldata), hexutil.MustDecode(o.ReplacementPattern), hexutil.MustDecode(o.StaticExtradata),
+		true,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return tx, c.checkTx(tx)
+}
+
+// ApproveProxy lets the stub proxy transfer the NFT721 tokens of the maker,
+// which the exchange needs to match the sell orders of the maker.
+func (c *Chain) ApproveProxy(opts *bind.TransactOpts) error {
+	tx, err := c.NFT721.SetApprovalForAll(opts, c.ProxyAddr, true)
+	if err != nil {
+		return err
+	}
+
+	return c.checkTx(tx)
+}
+
+// AtomicMatch matches the signed sell order with the buy order, opts has to
+// be the buyer's. The buy order is not signed, the exchange takes its maker
+// sending the transaction as the approval. The current price of the sell
+// order is paid in ether.
+func (c *Chain) AtomicMatch(opts *bind.TransactOpts, buy, sell *wyvern.Order) (*types.Transaction, error) {
+	buyAddrs, buyUints, err := buy.ContractArgs()
+	if err != nil {
+		return nil, err
+	}
+
+	sellAddrs, sellUints, err := sell.ContractArgs()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		addrs [14]common.Address
+		uints [18]*big.Int
+		rss   [5][32]byte
+	)
+	copy(addrs[:7], buyAddrs[:])
+	copy(addrs[7:], sellAddrs[:])
+	copy(uints[:9], buyUints[:])
+	copy(uints[9:], sellUints[:])
+	copy(rss[2][:], hexutil.MustDecode(sell.R))
+	copy(rss[3][:], hexutil.MustDecode(sell.S))
+
+	head, err := c.Backend.HeaderByNumber(context.Background(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	price, err := sell.CurrentPrice(time.Unix(int64(head.Time), 0))
+	if err != nil {
+		return nil, err
+	}
+
+	txOpts := *opts
+	txOpts.Value = price
+
+	tx, err := c.Exchange.AtomicMatch(
+		&txOpts, addrs, uints,
+		[8]uint8{
+			uint8(buy.FeeMethod), uint8(buy.Side), uint8(buy.SaleKind), uint8(buy.HowToCall),
+			uint8(sell.FeeMethod), uint8(sell.Side), uint8(sell.SaleKind), uint8(sell.HowToCall),
+		},
+		hexutil.MustDecode(buy.Calldata), hexutil.MustDecode(sell.Calldata),
+		hexutil.MustDecode(buy.ReplacementPattern), hexutil.MustDecode(sell.ReplacementPattern),
+		hexutil.MustDecode(buy.StaticExtradata), hexutil.MustDecode(sell.StaticExtradata),
+		[2]uint8{uint8(buy.V), uint8(sell.V)},
+		rss,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return tx, c.checkTx(tx)
+}
+
+func (c *Chain) checkTx(tx *types.Transaction) error {
+	receipt, err := c.Backend.TransactionReceipt(context.Background(), tx.Hash())
+	if err != nil {
+		return err
+	}
+
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return ErrTxFailed
+	}
+
+	return nil
+}
+
+func erc721TransferCalldata(from, to common.Address, tokenID *big.Int) []byte {
+	calldata := make([]byte, 0, 4+3*common.HashLength)
+	calldata = append(calldata, transferFromSelector...)
+	calldata = append(calldata, common.LeftPadBytes(from.Bytes(), common.HashLength)...)
+	calldata = append(calldata, common.LeftPadBytes(to.Bytes(), common.HashLength)...)
+	calldata = append(calldata, math.U256Bytes(new(big.Int).Set(tokenID))...)
+	return calldata
+}
+
+func fill(b []byte) {
+	for i := range b {
+		b[i] = 0xff
+	}
+}
+
+func randomSalt() *big.Int {
+	salt, _ := rand.Int(rand.Reader, math.MaxBig256)
+	return salt
+}
@@ -0,0 +1,235 @@
+package simchain_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/videocoin/marketplace/internal/contracts/dev/exchange"
+	"github.com/videocoin/marketplace/internal/model"
+	"github.com/videocoin/marketplace/internal/simchain"
+)
+
+func TestAtomicMatchTransfersToken(t *testing.T) {
+	ctx := context.Background()
+
+	owner, _, err := simchain.NewTransactor()
+	if err != nil {
+		t.Fatal(err)
+	}
+	seller, sellerKey, err := simchain.NewTransactor()
+	if err != nil {
+		t.Fatal(err)
+	}
+	buyer, _, err := simchain.NewTransactor()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	funds := new(big.Int).Lsh(big.NewInt(1), 100)
+	backend, err := simchain.NewSimulatedBackend(core.GenesisAlloc{
+		owner.From:  {Balance: funds},
+		seller.From: {Balance: funds},
+		buyer.From:  {Balance: funds},
+	}, 30000000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer backend.Close()
+
+	chain, err := simchain.Deploy(backend, owner, &simchain.DeployConfig{Name: "Test", Symbol: "TST"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tokenID := big.NewInt(7)
+	if _, err := chain.NFT721.Mint(owner, seller.From, tokenID, "ipfs://token"); err != nil {
+		t.Fatal(err)
+	}
+	if err := chain.ApproveProxy(seller); err != nil {
+		t.Fatal(err)
+	}
+
+	price := big.NewInt(1e18)
+	sell := chain.SellOrder(seller.From, tokenID, price)
+	if err := simchain.SignOrder(sell, sellerKey); err != nil {
+		t.Fatal(err)
+	}
+
+	buy, err := chain.BuyOrder(sell, buyer.From)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sellerBalance, err := backend.BalanceAt(ctx, seller.From, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	feeBalance, err := backend.BalanceAt(ctx, owner.From, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx, err := chain.AtomicMatch(buyer, buy, sell)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tokenOwner, err := chain.NFT721.OwnerOf(&bind.CallOpts{}, tokenID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tokenOwner != buyer.From {
+		t.Errorf("token owner = %s, want the buyer %s", tokenOwner.Hex(), buyer.From.Hex())
+	}
+
+	fee := new(big.Int).Div(new(big.Int).Mul(price, big.NewInt(simchain.DefaultSellerFeeBps)), big.NewInt(10000))
+	wantSeller := new(big.Int).Add(sellerBalance, new(big.Int).Sub(price, fee))
+	if got, _ := backend.BalanceAt(ctx, seller.From, nil); got.Cmp(wantSeller) != 0 {
+		t.Errorf("seller balance = %s, want %s", got, wantSeller)
+	}
+	wantFee := new(big.Int).Add(feeBalance, fee)
+	if got, _ := backend.BalanceAt(ctx, owner.From, nil); got.Cmp(wantFee) != 0 {
+		t.Errorf("fee recipient balance = %s, want %s", got, wantFee)
+	}
+
+	receipt, err := backend.TransactionReceipt(ctx, tx.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	filterer, err := exchange.NewWyvernExchangeFilterer(chain.ExchangeAddr, backend)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	matched := 0
+	for _, l := range receipt.Logs {
+		if l.Address != chain.ExchangeAddr {
+			continue
+		}
+		event, err := filterer.ParseOrdersMatched(*l)
+		if err != nil {
+			continue
+		}
+		matched++
+		if event.Price.Cmp(price) != 0 {
+			t.Errorf("matched price = %s, want %s", event.Price, price)
+		}
+		if event.Maker != seller.From || event.Taker != buyer.From {
+			t.Errorf("matched maker %s and taker %s, want the seller and the buyer", event.Maker.Hex(), event.Taker.Hex())
+		}
+	}
+	if matched != 1 {
+		t.Errorf("%d OrdersMatched events, want 1", matched)
+	}
+
+	// the sell order is finalized, it can't be matched twice
+	if _, err := chain.AtomicMatch(buyer, buy, sell); err == nil {
+		t.Error("the sell order has been matched twice")
+	}
+}
+
+// TestRedeemedVoucherIsMatched lists a lazily minted token: the exchange
+// can't transfer it before the platform has minted it to its creator, once
+// the voucher is redeemed the sale goes through.
+func TestRedeemedVoucherIsMatched(t *testing.T) {
+	owner, ownerKey, err := simchain.NewTransactor()
+	if err != nil {
+		t.Fatal(err)
+	}
+	creator, creatorKey, err := simchain.NewTransactor()
+	if err != nil {
+		t.Fatal(err)
+	}
+	buyer, _, err := simchain.NewTransactor()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	funds := new(big.Int).Lsh(big.NewInt(1), 100)
+	backend, err := simchain.NewSimulatedBackend(core.GenesisAlloc{
+		owner.From:   {Balance: funds},
+		creator.From: {Balance: funds},
+		buyer.From:   {Balance: funds},
+	}, 30000000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer backend.Close()
+
+	chain, err := simchain.Deploy(backend, owner, &simchain.DeployConfig{Name: "Test", Symbol: "TST"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := chain.Minter()
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.EnableLazyMint(ownerKey, simchain.ChainID)
+
+	tokenID := big.NewInt(7)
+	voucher := &model.MintVoucher{
+		TokenID: tokenID.String(),
+		URI:     "ipfs://token",
+		Creator: creator.From.Hex(),
+		Price:   "1000000000000000000",
+	}
+	if err := m.SignVoucher(voucher); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := chain.ApproveProxy(creator); err != nil {
+		t.Fatal(err)
+	}
+
+	price := big.NewInt(1e18)
+	sell := chain.SellOrder(creator.From, tokenID, price)
+	if err := simchain.SignOrder(sell, creatorKey); err != nil {
+		t.Fatal(err)
+	}
+
+	buy, err := chain.BuyOrder(sell, buyer.From)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := chain.AtomicMatch(buyer, buy, sell); err == nil {
+		t.Fatal("an order of an unminted token has been matched")
+	}
+
+	if _, err := m.Redeem(context.Background(), voucher); err != nil {
+		t.Fatal(err)
+	}
+
+	tokenOwner, err := chain.NFT721.OwnerOf(&bind.CallOpts{}, tokenID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tokenOwner != creator.From {
+		t.Fatalf("token owner = %s, want the creator %s", tokenOwner.Hex(), creator.From.Hex())
+	}
+
+	tokenURI, err := chain.NFT721.TokenURI(&bind.CallOpts{}, tokenID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tokenURI != voucher.URI {
+		t.Errorf("token uri = %s, want %s", tokenURI, voucher.URI)
+	}
+
+	if _, err := chain.AtomicMatch(buyer, buy, sell); err != nil {
+		t.Fatal(err)
+	}
+
+	tokenOwner, err = chain.NFT721.OwnerOf(&bind.CallOpts{}, tokenID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tokenOwner != buyer.From {
+		t.Errorf("token owner = %s, want the buyer %s", tokenOwner.Hex(), buyer.From.Hex())
+	}
+}
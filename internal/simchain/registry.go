@@ -0,0 +1,93 @@
+package simchain
+
+import (
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// proxySelector is AuthenticatedProxy.proxy(address,uint8,bytes).
+var proxySelector = crypto.Keccak256([]byte("proxy(address,uint8,bytes)"))[:4]
+
+// proxyRegistryCode returns the creation code of a contract standing in for
+// both the wyvern ProxyRegistry and the user proxies it registers:
+//
+//   - proxy(dest, howToCall, calldata) calls dest with the calldata and
+//     returns whether the call succeeded, howToCall is ignored;
+//   - any other call returns the address of the contract, which answers
+//     proxies(maker), implementation() and delegateProxyImplementation()
+//     the way the exchange expects.
+//
+// Every maker shares the same proxy, which has to be approved to transfer
+// the tokens.
+func proxyRegistryCode() []byte {
+	runtime := []byte{
+		0x60, 0x00, // PUSH1 0
+		0x35,       // CALLDATALOAD
+		0x60, 0xe0, // PUSH1 224
+		0x1c, // SHR
+		// PUSH4 selector
+		0x63, proxySelector[0], proxySelector[1], proxySelector[2], proxySelector[3],
+		0x14,       // EQ
+		0x60, 0x18, // PUSH1 proxy
+		0x57,       // JUMPI
+		0x30,       // ADDRESS
+		0x60, 0x00, // PUSH1 0
+		0x52,       // MSTORE
+		0x60, 0x20, // PUSH1 32
+		0x60, 0x00, // PUSH1 0
+		0xf3, // RETURN
+		// proxy:
+		0x5b,       // JUMPDEST
+		0x60, 0x44, // PUSH1 68, the offset of calldata
+		0x35,       // CALLDATALOAD
+		0x60, 0x04, // PUSH1 4
+		0x01,       // ADD
+		0x80,       // DUP1
+		0x35,       // CALLDATALOAD, the length of calldata
+		0x90,       // SWAP1
+		0x60, 0x20, // PUSH1 32
+		0x01,       // ADD
+		0x81,       // DUP2
+		0x90,       // SWAP1
+		0x60, 0x00, // PUSH1 0
+		0x37,       // CALLDATACOPY
+		0x60, 0x00, // PUSH1 0, retSize
+		0x60, 0x00, // PUSH1 0, retOffset
+		0x82,       // DUP3, argsSize
+		0x60, 0x00, // PUSH1 0, argsOffset
+		0x60, 0x00, // PUSH1 0, value
+		0x60, 0x04, // PUSH1 4
+		0x35,       // CALLDATALOAD, dest
+		0x5a,       // GAS
+		0xf1,       // CALL
+		0x60, 0x00, // PUSH1 0
+		0x52,       // MSTORE
+		0x60, 0x20, // PUSH1 32
+		0x60, 0x00, // PUSH1 0
+		0xf3, // RETURN
+	}
+
+	// copies the runtime code after the 11 bytes of the constructor
+	code := []byte{
+		0x60, byte(len(runtime)), // PUSH1 len
+		0x80,       // DUP1
+		0x60, 0x0b, // PUSH1 11
+		0x60, 0x00, // PUSH1 0
+		0x39,       // CODECOPY
+		0x60, 0x00, // PUSH1 0
+		0xf3, // RETURN
+	}
+
+	return append(code, runtime...)
+}
+
+func (c *Chain) deployProxyRegistry() (common.Address, error) {
+	addr, tx, _, err := bind.DeployContract(c.Owner, abi.ABI{}, proxyRegistryCode(), c.Backend)
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	return addr, c.checkDeployed(tx)
+}
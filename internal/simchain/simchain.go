@@ -0,0 +1,185 @@
+package simchain
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/videocoin/marketplace/internal/contracts/dev/exchange"
+	"github.com/videocoin/marketplace/internal/contracts/dev/nft"
+	"github.com/videocoin/marketplace/internal/listener"
+	"github.com/videocoin/marketplace/internal/minter"
+)
+
+// ChainID is the chain id used by backends.SimulatedBackend.
+var ChainID = big.NewInt(1337)
+
+var (
+	ErrDeployFailed = errors.New("contract deployment failed")
+)
+
+// Backend is a chain which mines pending transactions on demand, such as
+// backends.SimulatedBackend.
+type Backend interface {
+	minter.Backend
+	listener.Backend
+	Commit()
+}
+
+// Chain holds the marketplace contracts deployed on a simulated backend.
+// Transactions sent through it are mined right away, so the minter waiting
+// for receipts never blocks.
+//
+// The exchange is deployed with a stub proxy registry unless a registry is
+// given. The stub is the proxy of every maker, orders are matched once the
+// maker approves it on the token contract, see ApproveProxy. Orders paid
+// with ERC20 tokens require the wyvern token transfer proxy.
+type Chain struct {
+	Backend      Backend
+	Owner        *bind.TransactOpts
+	NFT721       *nft.NFT721
+	NFT721Addr   common.Address
+	NFT1155      *nft.NFT1155
+	NFT1155Addr  common.Address
+	Exchange     *exchange.WyvernExchange
+	ExchangeAddr common.Address
+	// ProxyAddr is the stub registry and proxy, it is zero when the
+	// exchange uses the given registry.
+	ProxyAddr common.Address
+}
+
+type DeployConfig struct {
+	Name                      string
+	Symbol                    string
+	URI                       string
+	RegistryAddress           common.Address
+	TokenTransferProxyAddress common.Address
+	TokenAddress              common.Address
+	ProtocolFeeAddress        common.Address
+}
+
+// NewTransactor creates a transactor for a fresh key, the address has to be
+// funded in the genesis alloc of the simulated backend.
+func NewTransactor() (*bind.TransactOpts, *ecdsa.PrivateKey, error) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	opts, err := bind.NewKeyedTransactorWithChainID(key, ChainID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return opts, key, nil
+}
+
+// Deploy deploys the NFT721, NFT1155 and WyvernExchange contracts from the
+// owner account, along with the stub proxy registry when the config has no
+// registry address.
+func Deploy(backend Backend, owner *bind.TransactOpts, config *DeployConfig) (*Chain, error) {
+	c := &Chain{
+		Backend: &autoCommit{Backend: backend},
+		Owner:   owner,
+	}
+
+	var (
+		tx  *types.Transaction
+		err error
+	)
+
+	c.NFT721Addr, tx, c.NFT721, err = nft.DeployNFT721(owner, c.Backend, config.Name, config.Symbol, owner.From)
+	if err != nil {
+		return nil, err
+	}
+	if err = c.checkDeployed(tx); err != nil {
+		return nil, fmt.Errorf("nft721: %s", err)
+	}
+
+	c.NFT1155Addr, tx, c.NFT1155, err = nft.DeployNFT1155(owner, c.Backend, config.URI)
+	if err != nil {
+		return nil, err
+	}
+	if err = c.checkDeployed(tx); err != nil {
+		return nil, fmt.Errorf("nft1155: %s", err)
+	}
+
+	registryAddr := config.RegistryAddress
+	if registryAddr == (common.Address{}) {
+		c.ProxyAddr, err = c.deployProxyRegistry()
+		if err != nil {
+			return nil, fmt.Errorf("proxy registry: %s", err)
+		}
+		registryAddr = c.ProxyAddr
+	}
+
+	c.ExchangeAddr, tx, c.Exchange, err = exchange.DeployWyvernExchange(
+		owner,
+		c.Backend,
+		registryAddr,
+		config.TokenTransferProxyAddress,
+		config.TokenAddress,
+		config.ProtocolFeeAddress,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err = c.checkDeployed(tx); err != nil {
+		return nil, fmt.Errorf("exchange: %s", err)
+	}
+
+	return c, nil
+}
+
+// Minter returns a minter for the deployed contracts which signs with the
+// owner account.
+func (c *Chain) Minter(opts ...minter.Option) (*minter.Minter, error) {
+	return minter.NewMinterWithBackend(c.Backend, c.Owner, c.NFT721Addr.Hex(), c.NFT1155Addr.Hex(), opts...)
+}
+
+// Listener returns an exchange listener which reads the events of the
+// deployed exchange, use ExchangeListener.Poll to process new blocks.
+func (c *Chain) Listener(ctx context.Context, opts ...listener.ExchangeListenerOption) (*listener.ExchangeListener, error) {
+	opts = append(
+		opts,
+		listener.WithBackend(c.Backend),
+		listener.WithContractAddress(c.ExchangeAddr.Hex()),
+		listener.WithNFTContractAddress(c.NFT721Addr.Hex()),
+	)
+
+	return listener.NewExchangeListener(ctx, opts...)
+}
+
+func (c *Chain) checkDeployed(tx *types.Transaction) error {
+	receipt, err := c.Backend.TransactionReceipt(context.Background(), tx.Hash())
+	if err != nil {
+		return err
+	}
+
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return ErrDeployFailed
+	}
+
+	return nil
+}
+
+// autoCommit mines a block for every sent transaction.
+type autoCommit struct {
+	Backend
+}
+
+func (b *autoCommit) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	err := b.Backend.SendTransaction(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	b.Backend.Commit()
+	return nil
+}
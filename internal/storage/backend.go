@@ -0,0 +1,52 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+var (
+	ErrNotSupported = errors.New("operation is not supported by storage backend")
+)
+
+// Backend is a storage driver. The primary backend keeps the content the
+// tokens point to, the cache backend keeps copies which are served to the
+// users and read back by the media processor.
+type Backend interface {
+	Name() string
+	// RootPath is the value stored with the media to resolve its urls:
+	// a bucket key or name for the hosted drivers, a base url for the
+	// local and S3 drivers.
+	RootPath() string
+	// Push stores the object under path and returns its id, the content id
+	// for IPFS backed drivers and the path otherwise.
+	Push(ctx context.Context, path string, src io.Reader, public bool) (string, error)
+	// MultiPush stores several objects and returns the id of the first one,
+	// drivers which support it store them under a single directory id.
+	MultiPush(ctx context.Context, paths []string, srcs []io.Reader) (string, error)
+	Read(ctx context.Context, path string) (io.ReadCloser, error)
+	MakePublic(ctx context.Context, path string) error
+	URL(id string, path string) string
+}
+
+func multiPushEach(ctx context.Context, b Backend, paths []string, srcs []io.Reader) (string, error) {
+	if len(paths) != len(srcs) {
+		return "", errors.New("different number of input/output paths")
+	}
+
+	ids := make([]string, 0, len(paths))
+	for idx, path := range paths {
+		id, err := b.Push(ctx, path, srcs[idx], true)
+		if err != nil {
+			return "", err
+		}
+		ids = append(ids, id)
+	}
+
+	if len(ids) == 0 {
+		return "", errors.New("nothing to push")
+	}
+
+	return ids[0], nil
+}
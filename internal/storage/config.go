@@ -9,4 +9,17 @@ type TextileConfig struct {
 
 type NftStorageConfig struct {
 	ApiKey string
-}
\ No newline at end of file
+}
+type LocalConfig struct {
+	Dir     string
+	BaseURL string
+}
+
+type S3Config struct {
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	PublicURL string
+}
@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	gcpstorage "cloud.google.com/go/storage"
+)
+
+const gcsGateway = "https://storage.googleapis.com/%s/%s"
+
+type GCSBackend struct {
+	bucket string
+	cli    *gcpstorage.Client
+	bh     *gcpstorage.BucketHandle
+}
+
+func NewGCSBackend(bucket string) (*GCSBackend, error) {
+	cli, err := gcpstorage.NewClient(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return &GCSBackend{
+		bucket: bucket,
+		cli:    cli,
+		bh:     cli.Bucket(bucket),
+	}, nil
+}
+
+func (b *GCSBackend) Name() string {
+	return GCS
+}
+
+func (b *GCSBackend) RootPath() string {
+	return b.bucket
+}
+
+func (b *GCSBackend) Push(ctx context.Context, path string, src io.Reader, public bool) (string, error) {
+	w := b.bh.Object(path).NewWriter(ctx)
+	if public {
+		w.ACL = []gcpstorage.ACLRule{
+			{
+				Entity: gcpstorage.AllUsers,
+				Role:   gcpstorage.RoleReader,
+			},
+		}
+	}
+
+	_, err := io.Copy(w, src)
+	if err != nil {
+		_ = w.Close()
+		return "", err
+	}
+
+	err = w.Close()
+	if err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
+
+func (b *GCSBackend) MultiPush(ctx context.Context, paths []string, srcs []io.Reader) (string, error) {
+	return multiPushEach(ctx, b, paths, srcs)
+}
+
+func (b *GCSBackend) Read(ctx context.Context, path string) (io.ReadCloser, error) {
+	return b.bh.Object(path).NewReader(ctx)
+}
+
+func (b *GCSBackend) MakePublic(ctx context.Context, path string) error {
+	acl := b.bh.Object(path).ACL()
+	return acl.Set(ctx, gcpstorage.AllUsers, gcpstorage.RoleReader)
+}
+
+func (b *GCSBackend) URL(id string, path string) string {
+	return fmt.Sprintf(gcsGateway, b.bucket, path)
+}
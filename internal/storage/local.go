@@ -0,0 +1,118 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	ErrInvalidPath = errors.New("invalid storage path")
+)
+
+// LocalBackend keeps the objects in a directory on the local filesystem,
+// the api serves the directory under the base url. It lets the marketplace
+// run without any hosted storage.
+type LocalBackend struct {
+	dir     string
+	baseURL string
+}
+
+func NewLocalBackend(config *LocalConfig) (*LocalBackend, error) {
+	dir, err := filepath.Abs(config.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	err = os.MkdirAll(dir, 0755)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LocalBackend{
+		dir:     dir,
+		baseURL: strings.TrimSuffix(config.BaseURL, "/"),
+	}, nil
+}
+
+func (b *LocalBackend) Name() string {
+	return Local
+}
+
+func (b *LocalBackend) RootPath() string {
+	return b.baseURL
+}
+
+func (b *LocalBackend) Dir() string {
+	return b.dir
+}
+
+func (b *LocalBackend) Push(ctx context.Context, path string, src io.Reader, public bool) (string, error) {
+	dest, err := b.filepath(path)
+	if err != nil {
+		return "", err
+	}
+
+	err = os.MkdirAll(filepath.Dir(dest), 0755)
+	if err != nil {
+		return "", err
+	}
+
+	// write to a temporary file first, so readers never see partial objects
+	tmp, err := ioutil.TempFile(filepath.Dir(dest), ".push-")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = io.Copy(tmp, src)
+	if err != nil {
+		_ = tmp.Close()
+		return "", err
+	}
+
+	err = tmp.Close()
+	if err != nil {
+		return "", err
+	}
+
+	err = os.Rename(tmp.Name(), dest)
+	if err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
+
+func (b *LocalBackend) MultiPush(ctx context.Context, paths []string, srcs []io.Reader) (string, error) {
+	return multiPushEach(ctx, b, paths, srcs)
+}
+
+func (b *LocalBackend) Read(ctx context.Context, path string) (io.ReadCloser, error) {
+	src, err := b.filepath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return os.Open(src)
+}
+
+func (b *LocalBackend) MakePublic(ctx context.Context, path string) error {
+	return nil
+}
+
+func (b *LocalBackend) URL(id string, path string) string {
+	return b.baseURL + "/" + strings.TrimPrefix(path, "/")
+}
+
+func (b *LocalBackend) filepath(path string) (string, error) {
+	p := filepath.Join(b.dir, filepath.FromSlash(path))
+	if p != b.dir && !strings.HasPrefix(p, b.dir+string(filepath.Separator)) {
+		return "", ErrInvalidPath
+	}
+	return p, nil
+}
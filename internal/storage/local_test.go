@@ -0,0 +1,185 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newTestLocal(t *testing.T) *LocalBackend {
+	b, err := NewLocalBackend(&LocalConfig{
+		Dir:     filepath.Join(t.TempDir(), "objects"),
+		BaseURL: "http://localhost:8088/files/",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestLocalBackendPushRead(t *testing.T) {
+	b := newTestLocal(t)
+	ctx := context.Background()
+
+	content := bytes.Repeat([]byte("0123456789"), 1000)
+	id, err := b.Push(ctx, "a1/b2/video.mp4", bytes.NewReader(content), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "a1/b2/video.mp4" {
+		t.Errorf("push returned id %q, want the path", id)
+	}
+
+	r, err := b.Read(ctx, "a1/b2/video.mp4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ioutil.ReadAll(r)
+	_ = r.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, content) {
+		t.Errorf("read returned %d bytes, want %d", len(got), len(content))
+	}
+
+	// pushing again replaces the object and leaves no temporary files
+	_, err = b.Push(ctx, "a1/b2/video.mp4", strings.NewReader("new"), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := ioutil.ReadDir(filepath.Join(b.Dir(), "a1", "b2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("object directory has %d entries, want 1", len(entries))
+	}
+
+	got, err = ioutil.ReadFile(filepath.Join(b.Dir(), "a1", "b2", "video.mp4"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "new" {
+		t.Errorf("object = %q after the second push", got)
+	}
+}
+
+func TestLocalBackendRejectsPathsOutsideDir(t *testing.T) {
+	b := newTestLocal(t)
+	ctx := context.Background()
+
+	for _, path := range []string{"../escape", "a/../../escape", "../objects-other/x"} {
+		_, err := b.Push(ctx, path, strings.NewReader("x"), true)
+		if err != ErrInvalidPath {
+			t.Errorf("push %q returned %v, want %v", path, err, ErrInvalidPath)
+		}
+
+		_, err = b.Read(ctx, path)
+		if err != ErrInvalidPath {
+			t.Errorf("read %q returned %v, want %v", path, err, ErrInvalidPath)
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(filepath.Dir(b.Dir()), "escape")); !os.IsNotExist(err) {
+		t.Error("push wrote outside of the storage directory")
+	}
+}
+
+func TestLocalBackendReadMissing(t *testing.T) {
+	b := newTestLocal(t)
+
+	_, err := b.Read(context.Background(), "missing")
+	if !os.IsNotExist(err) {
+		t.Errorf("read of a missing object returned %v", err)
+	}
+}
+
+func TestLocalBackendURL(t *testing.T) {
+	b := newTestLocal(t)
+
+	if got := b.URL("", "/a/b.mp4"); got != "http://localhost:8088/files/a/b.mp4" {
+		t.Errorf("url = %q", got)
+	}
+	if got := b.RootPath(); got != "http://localhost:8088/files" {
+		t.Errorf("root path = %q", got)
+	}
+}
+
+func TestStoragePushPathFillsCache(t *testing.T) {
+	primary := newTestLocal(t)
+	cache := newTestLocal(t)
+
+	s, err := NewStorage(WithBackend(primary), WithCache(cache))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id, err := s.PushPath("a/b.bin", strings.NewReader("content"), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "a/b.bin" {
+		t.Errorf("push returned id %q", id)
+	}
+
+	for name, b := range map[string]*LocalBackend{"primary": primary, "cache": cache} {
+		got, err := ioutil.ReadFile(filepath.Join(b.Dir(), "a", "b.bin"))
+		if err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+		if string(got) != "content" {
+			t.Errorf("%s object = %q", name, got)
+		}
+	}
+}
+
+func TestStoragePushPathStreamsToCache(t *testing.T) {
+	primary := newTestLocal(t)
+	cache := newTestLocal(t)
+
+	s, err := NewStorage(WithBackend(primary), WithCache(cache))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the reader can't be seeked back, it is streamed to both backends
+	content := bytes.Repeat([]byte("0123456789"), 100000)
+	_, err = s.PushPath("a/b.bin", io.MultiReader(bytes.NewReader(content)), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, b := range map[string]*LocalBackend{"primary": primary, "cache": cache} {
+		got, err := ioutil.ReadFile(filepath.Join(b.Dir(), "a", "b.bin"))
+		if err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+		if !bytes.Equal(got, content) {
+			t.Errorf("%s object has %d bytes, want %d", name, len(got), len(content))
+		}
+	}
+}
+
+func TestStoragePushPathCacheFailure(t *testing.T) {
+	primary := newTestLocal(t)
+	cache, _ := newTestS3(t)
+	cache.secretKey = "wrong"
+
+	s, err := NewStorage(WithBackend(primary), WithCache(cache))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content := bytes.Repeat([]byte("0123456789"), 100000)
+	_, err = s.PushPath("a/b.bin", io.MultiReader(bytes.NewReader(content)), true)
+	if err == nil || !strings.Contains(err.Error(), "403") {
+		t.Errorf("push with a failing cache returned %v", err)
+	}
+}
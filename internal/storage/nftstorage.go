@@ -2,6 +2,7 @@ package storage
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -114,3 +115,34 @@ func (s *NftStorageClient) PushPaths(paths []string, sources []io.Reader) (strin
 
 	return uploadResp.Value.CID, nil
 }
+
+const ipfsGateway = "https://%s.ipfs.dweb.link/%s"
+
+func (s *NftStorageClient) Name() string {
+	return NftStorage
+}
+
+func (s *NftStorageClient) RootPath() string {
+	return ""
+}
+
+func (s *NftStorageClient) Push(ctx context.Context, path string, src io.Reader, public bool) (string, error) {
+	return s.PushPath(path, src)
+}
+
+func (s *NftStorageClient) MultiPush(ctx context.Context, paths []string, srcs []io.Reader) (string, error) {
+	return s.PushPaths(paths, srcs)
+}
+
+// Read is not supported, nft.storage content is addressed by its cid only.
+func (s *NftStorageClient) Read(ctx context.Context, path string) (io.ReadCloser, error) {
+	return nil, ErrNotSupported
+}
+
+func (s *NftStorageClient) MakePublic(ctx context.Context, path string) error {
+	return nil
+}
+
+func (s *NftStorageClient) URL(id string, path string) string {
+	return fmt.Sprintf(ipfsGateway, id, filepath.Base(path))
+}
@@ -2,24 +2,92 @@ package storage
 
 type Option func(storage *Storage) error
 
+func WithBackend(b Backend) Option {
+	return func(s *Storage) error {
+		s.backend = b
+		return nil
+	}
+}
+
+func WithCache(b Backend) Option {
+	return func(s *Storage) error {
+		s.cache = b
+		return nil
+	}
+}
+
 func WithTextile(config *TextileConfig) Option {
 	return func(s *Storage) error {
-		s.textileConfig = config
+		b, err := NewTextileBackend(config)
+		if err != nil {
+			return err
+		}
+		s.backend = b
 		return nil
 	}
 }
 
 func WithNftStorage(config *NftStorageConfig) Option {
 	return func(s *Storage) error {
-		s.nftStorageConfig = config
+		s.backend = NewNftStorageClient(config.ApiKey)
+		return nil
+	}
+}
+
+func WithLocal(config *LocalConfig) Option {
+	return func(s *Storage) error {
+		b, err := NewLocalBackend(config)
+		if err != nil {
+			return err
+		}
+		s.backend = b
+		return nil
+	}
+}
+
+func WithS3(config *S3Config) Option {
+	return func(s *Storage) error {
+		b, err := NewS3Backend(config)
+		if err != nil {
+			return err
+		}
+		s.backend = b
 		return nil
 	}
 }
 
 func WithGCPStorage(bucket string) Option {
 	return func(s *Storage) error {
-		s.gcpBucket = bucket
+		if bucket == "" {
+			return nil
+		}
+		b, err := NewGCSBackend(bucket)
+		if err != nil {
+			return err
+		}
+		s.cache = b
+		return nil
+	}
+}
+
+func WithLocalCache(config *LocalConfig) Option {
+	return func(s *Storage) error {
+		b, err := NewLocalBackend(config)
+		if err != nil {
+			return err
+		}
+		s.cache = b
 		return nil
 	}
 }
 
+func WithS3Cache(config *S3Config) Option {
+	return func(s *Storage) error {
+		b, err := NewS3Backend(config)
+		if err != nil {
+			return err
+		}
+		s.cache = b
+		return nil
+	}
+}
@@ -0,0 +1,493 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	s3UnsignedPayload = "UNSIGNED-PAYLOAD"
+	s3Algorithm       = "AWS4-HMAC-SHA256"
+	s3TimeFormat      = "20060102T150405Z"
+	s3DateFormat      = "20060102"
+
+	// s3MinPartSize is the smallest part S3 accepts but for the last one.
+	s3MinPartSize = 5 * 1024 * 1024
+)
+
+// S3Backend talks to AWS S3 or any S3 compatible service such as MinIO
+// using path style requests signed with AWS Signature Version 4.
+type S3Backend struct {
+	endpoint  *url.URL
+	region    string
+	bucket    string
+	accessKey string
+	secretKey string
+	publicURL string
+	partSize  int
+	cli       *http.Client
+}
+
+func NewS3Backend(config *S3Config) (*S3Backend, error) {
+	endpoint, err := url.Parse(strings.TrimSuffix(config.Endpoint, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid s3 endpoint: %s", err)
+	}
+
+	if endpoint.Scheme == "" || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid s3 endpoint: %s", config.Endpoint)
+	}
+
+	if config.Bucket == "" {
+		return nil, fmt.Errorf("missing s3 bucket")
+	}
+
+	region := config.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	publicURL := strings.TrimSuffix(config.PublicURL, "/")
+	if publicURL == "" {
+		publicURL = endpoint.String() + "/" + config.Bucket
+	}
+
+	return &S3Backend{
+		endpoint:  endpoint,
+		region:    region,
+		bucket:    config.Bucket,
+		accessKey: config.AccessKey,
+		secretKey: config.SecretKey,
+		publicURL: publicURL,
+		partSize:  s3MinPartSize,
+		cli:       &http.Client{},
+	}, nil
+}
+
+func (b *S3Backend) Name() string {
+	return S3
+}
+
+func (b *S3Backend) RootPath() string {
+	return b.publicURL
+}
+
+// Push uploads the object with a single request when the size of src is
+// known or it fits in a part, and streams it with a multipart upload
+// otherwise. At most a part is held in memory.
+func (b *S3Backend) Push(ctx context.Context, path string, src io.Reader, public bool) (string, error) {
+	if size, ok := readerSize(src); ok {
+		return path, b.putObject(ctx, path, src, size, public)
+	}
+
+	part := make([]byte, b.partSize)
+	n, err := io.ReadFull(src, part)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return path, b.putObject(ctx, path, bytes.NewReader(part[:n]), int64(n), public)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return path, b.pushMultipart(ctx, path, part, src, public)
+}
+
+func (b *S3Backend) putObject(ctx context.Context, path string, body io.Reader, size int64, public bool) error {
+	req, err := b.newRequest(ctx, http.MethodPut, path, "", body)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	if size == 0 {
+		req.Body = http.NoBody
+	}
+	if public {
+		req.Header.Set("x-amz-acl", "public-read")
+	}
+
+	return b.do(req, nil)
+}
+
+type s3CompletedPart struct {
+	PartNumber int    `xml:"PartNumber"`
+	ETag       string `xml:"ETag"`
+}
+
+// pushMultipart uploads the first part, which is full, and the rest of src
+// a part at a time. The upload is aborted when a part fails so S3 doesn't
+// keep the parts.
+func (b *S3Backend) pushMultipart(ctx context.Context, path string, part []byte, src io.Reader, public bool) error {
+	uploadID, err := b.createMultipartUpload(ctx, path, public)
+	if err != nil {
+		return err
+	}
+
+	parts := make([]s3CompletedPart, 0)
+	n := len(part)
+	for {
+		etag, err := b.uploadPart(ctx, path, uploadID, len(parts)+1, part[:n])
+		if err != nil {
+			b.abortMultipartUpload(path, uploadID)
+			return err
+		}
+		parts = append(parts, s3CompletedPart{PartNumber: len(parts) + 1, ETag: etag})
+
+		n, err = io.ReadFull(src, part)
+		if err == io.EOF {
+			break
+		}
+		if err != nil && err != io.ErrUnexpectedEOF {
+			b.abortMultipartUpload(path, uploadID)
+			return err
+		}
+	}
+
+	err = b.completeMultipartUpload(ctx, path, uploadID, parts)
+	if err != nil {
+		b.abortMultipartUpload(path, uploadID)
+		return err
+	}
+
+	return nil
+}
+
+func (b *S3Backend) createMultipartUpload(ctx context.Context, path string, public bool) (string, error) {
+	req, err := b.newRequest(ctx, http.MethodPost, path, "uploads=", nil)
+	if err != nil {
+		return "", err
+	}
+	if public {
+		req.Header.Set("x-amz-acl", "public-read")
+	}
+
+	var body io.ReadCloser
+	err = b.do(req, &body)
+	if err != nil {
+		return "", err
+	}
+	defer body.Close()
+
+	result := struct {
+		UploadID string `xml:"UploadId"`
+	}{}
+	err = xml.NewDecoder(body).Decode(&result)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode s3 multipart upload: %s", err)
+	}
+	if result.UploadID == "" {
+		return "", fmt.Errorf("s3 multipart upload of %s has no id", path)
+	}
+
+	return result.UploadID, nil
+}
+
+func (b *S3Backend) uploadPart(ctx context.Context, path, uploadID string, number int, data []byte) (string, error) {
+	query := url.Values{
+		"partNumber": []string{strconv.Itoa(number)},
+		"uploadId":   []string{uploadID},
+	}
+	req, err := b.newRequest(ctx, http.MethodPut, path, query.Encode(), bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	req.ContentLength = int64(len(data))
+
+	resp, err := b.send(req)
+	if err != nil {
+		return "", err
+	}
+	_ = resp.Body.Close()
+
+	etag := resp.Header.Get("ETag")
+	if etag == "" {
+		return "", fmt.Errorf("s3 part %d of %s has no etag", number, path)
+	}
+
+	return etag, nil
+}
+
+func (b *S3Backend) completeMultipartUpload(ctx context.Context, path, uploadID string, parts []s3CompletedPart) error {
+	payload, err := xml.Marshal(struct {
+		XMLName xml.Name          `xml:"CompleteMultipartUpload"`
+		Parts   []s3CompletedPart `xml:"Part"`
+	}{Parts: parts})
+	if err != nil {
+		return err
+	}
+
+	query := url.Values{"uploadId": []string{uploadID}}
+	req, err := b.newRequest(ctx, http.MethodPost, path, query.Encode(), bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.ContentLength = int64(len(payload))
+
+	var body io.ReadCloser
+	err = b.do(req, &body)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	// the completion can fail after the response status has been sent
+	result := struct {
+		XMLName xml.Name
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	}{}
+	err = xml.NewDecoder(body).Decode(&result)
+	if err != nil {
+		return fmt.Errorf("failed to decode s3 multipart upload completion: %s", err)
+	}
+	if result.XMLName.Local == "Error" {
+		return fmt.Errorf("s3 multipart upload of %s failed: %s %s", path, result.Code, result.Message)
+	}
+
+	return nil
+}
+
+// abortMultipartUpload drops the parts of a failed upload, it is sent even
+// when the context of the upload has been canceled.
+func (b *S3Backend) abortMultipartUpload(path, uploadID string) {
+	query := url.Values{"uploadId": []string{uploadID}}
+	req, err := b.newRequest(context.Background(), http.MethodDelete, path, query.Encode(), nil)
+	if err != nil {
+		return
+	}
+
+	_ = b.do(req, nil)
+}
+
+func (b *S3Backend) MultiPush(ctx context.Context, paths []string, srcs []io.Reader) (string, error) {
+	return multiPushEach(ctx, b, paths, srcs)
+}
+
+func (b *S3Backend) Read(ctx context.Context, path string) (io.ReadCloser, error) {
+	req, err := b.newRequest(ctx, http.MethodGet, path, "", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var body io.ReadCloser
+	err = b.do(req, &body)
+	if err != nil {
+		return nil, err
+	}
+
+	return body, nil
+}
+
+func (b *S3Backend) MakePublic(ctx context.Context, path string) error {
+	req, err := b.newRequest(ctx, http.MethodPut, path, "acl=", nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("x-amz-acl", "public-read")
+
+	return b.do(req, nil)
+}
+
+func (b *S3Backend) URL(id string, path string) string {
+	return b.publicURL + "/" + strings.TrimPrefix(path, "/")
+}
+
+func (b *S3Backend) newRequest(ctx context.Context, method, path, query string, body io.Reader) (*http.Request, error) {
+	u := *b.endpoint
+	u.Path = "/" + b.bucket + "/" + strings.TrimPrefix(path, "/")
+	u.RawPath = s3EscapePath(u.Path)
+	u.RawQuery = query
+
+	// the transport closes request bodies, sources belong to the caller
+	if body != nil {
+		body = ioutil.NopCloser(body)
+	}
+
+	return http.NewRequestWithContext(ctx, method, u.String(), body)
+}
+
+// do signs and sends the request. The response body is handed over when
+// body is not nil, it is closed otherwise.
+func (b *S3Backend) do(req *http.Request, body *io.ReadCloser) error {
+	resp, err := b.send(req)
+	if err != nil {
+		return err
+	}
+
+	if body == nil {
+		_ = resp.Body.Close()
+		return nil
+	}
+
+	*body = resp.Body
+	return nil
+}
+
+// send signs and sends the request, the response is returned unless its
+// status is an error.
+func (b *S3Backend) send(req *http.Request) (*http.Response, error) {
+	b.sign(req, time.Now().UTC())
+
+	resp, err := b.cli.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		defer resp.Body.Close()
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("s3 %s %s failed with status %d: %s", req.Method, req.URL.Path, resp.StatusCode, msg)
+	}
+
+	return resp, nil
+}
+
+// sign adds the Signature Version 4 headers to the request. The payload is
+// left unsigned unless its hash is already set in x-amz-content-sha256.
+func (b *S3Backend) sign(req *http.Request, now time.Time) {
+	amzDate := now.Format(s3TimeFormat)
+	date := now.Format(s3DateFormat)
+
+	payloadHash := req.Header.Get("x-amz-content-sha256")
+	if payloadHash == "" {
+		payloadHash = s3UnsignedPayload
+	}
+
+	req.Header.Set("x-amz-date", amzDate)
+	req.Header.Set("x-amz-content-sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		name = strings.ToLower(name)
+		if name == "host" || strings.HasPrefix(name, "x-amz-") || name == "content-type" {
+			headers[name] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		s3CanonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := strings.Join([]string{date, b.region, "s3", "aws4_request"}, "/")
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		s3Algorithm,
+		amzDate,
+		scope,
+		hex.EncodeToString(requestHash[:]),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+b.secretKey), date)
+	key = hmacSHA256(key, b.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3Algorithm, b.accessKey, scope, signedHeaders, signature,
+	))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func s3CanonicalQuery(values url.Values) string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		vals := append([]string{}, values[key]...)
+		sort.Strings(vals)
+		for _, val := range vals {
+			pairs = append(pairs, s3Escape(key, true)+"="+s3Escape(val, true))
+		}
+	}
+
+	return strings.Join(pairs, "&")
+}
+
+func s3EscapePath(path string) string {
+	return s3Escape(path, false)
+}
+
+// s3Escape percent-encodes everything but the unreserved characters, as
+// required for the canonical request.
+func s3Escape(s string, encodeSlash bool) string {
+	var buf strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~':
+			buf.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			buf.WriteByte(c)
+		default:
+			fmt.Fprintf(&buf, "%%%02X", c)
+		}
+	}
+	return buf.String()
+}
+
+// readerSize returns the number of bytes left in src when it is known
+// without reading it.
+func readerSize(src io.Reader) (int64, bool) {
+	switch r := src.(type) {
+	case *bytes.Buffer:
+		return int64(r.Len()), true
+	case *bytes.Reader:
+		return int64(r.Len()), true
+	case *strings.Reader:
+		return int64(r.Len()), true
+	case *os.File:
+		info, err := r.Stat()
+		if err != nil || !info.Mode().IsRegular() {
+			return 0, false
+		}
+		offset, err := r.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return 0, false
+		}
+		return info.Size() - offset, true
+	}
+
+	return 0, false
+}
@@ -1,43 +1,28 @@
 package storage
 
 import (
-	"bytes"
-	gcpstorage "cloud.google.com/go/storage"
 	"context"
-	"crypto/tls"
 	"errors"
-	"fmt"
-	"github.com/textileio/go-threads/core/thread"
-	bucketsd "github.com/textileio/textile/v2/api/bucketsd/client"
-	bucketspb "github.com/textileio/textile/v2/api/bucketsd/pb"
-	"github.com/textileio/textile/v2/api/common"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
 	"io"
 	"os"
-	"time"
 )
 
 const (
 	Textile    = "textile"
 	NftStorage = "nftstorage"
+	GCS        = "gcs"
+	Local      = "local"
+	S3         = "s3"
 )
 
 var (
 	ErrUnknownStorageBackend = errors.New("unknown storage backend")
+	ErrNoCacheBackend        = errors.New("cache storage backend is not configured")
 )
 
 type Storage struct {
-	backend          string
-	textileConfig    *TextileConfig
-	nftStorageConfig *NftStorageConfig
-	gcpBucket        string
-	authCtx          context.Context
-	ttCli            *bucketsd.Client
-	ttRoot           *bucketspb.RootResponse
-	nsCli            *NftStorageClient
-	gcpStorage       *gcpstorage.Client
-	gcpBh            *gcpstorage.BucketHandle
+	backend Backend
+	cache   Backend
 }
 
 func NewStorage(opts ...Option) (*Storage, error) {
@@ -48,111 +33,81 @@ func NewStorage(opts ...Option) (*Storage, error) {
 		}
 	}
 
-	if s.nftStorageConfig != nil {
-		s.backend = NftStorage
-		s.nsCli = NewNftStorageClient(s.nftStorageConfig.ApiKey)
+	if s.backend == nil {
+		return nil, ErrUnknownStorageBackend
 	}
 
-	if s.textileConfig != nil {
-		s.backend = Textile
-
-		dialOpts := []grpc.DialOption{
-			grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{})),
-			grpc.WithPerRPCCredentials(common.Credentials{}),
-		}
-		cli, err := bucketsd.NewClient("api.hub.textile.io:443", dialOpts...)
-		if err != nil {
-			return nil, err
-		}
-		s.ttCli = cli
-
-		authCtx, err := common.CreateAPISigContext(
-			common.NewAPIKeyContext(context.Background(), s.textileConfig.AuthKey),
-			time.Now().Add(time.Minute),
-			s.textileConfig.AuthSecret,
-		)
-		if err != nil {
-			return nil, err
-		}
-
-		tid, err := thread.Decode(s.textileConfig.ThreadID)
-		if err != nil {
-			return nil, fmt.Errorf("invalid textile thread id: %s", err)
-		}
-		authCtx = common.NewThreadIDContext(authCtx, tid)
-
-		s.authCtx = authCtx
-
-		root, err := s.ttCli.Root(s.authCtx, s.textileConfig.BucketRootKey)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get bucket root: %s", err)
-		}
-
-		s.ttRoot = root
-	}
+	return s, nil
+}
 
-	if s.gcpBucket != "" {
-		gcpCli, err := gcpstorage.NewClient(context.Background())
-		if err != nil {
-			return nil, err
-		}
-		s.gcpStorage = gcpCli
-		s.gcpBh = s.gcpStorage.Bucket(s.gcpBucket)
-	}
+func (s *Storage) Backend() Backend {
+	return s.backend
+}
 
-	return s, nil
+func (s *Storage) Cache() Backend {
+	return s.cache
 }
 
 func (s *Storage) RootPath() string {
-	if s.textileConfig != nil {
-		return s.textileConfig.BucketRootKey
-	}
-	return ""
+	return s.backend.RootPath()
 }
 
 func (s *Storage) CacheRootPath() string {
-	return s.gcpBucket
+	if s.cache == nil {
+		return ""
+	}
+	return s.cache.RootPath()
 }
 
+// PushPath pushes the object to the backend and to the cache, if there is
+// one. A seekable source is read again for the cache, any other source is
+// streamed to both at once, so it is never held in memory.
 func (s *Storage) PushPath(path string, src io.Reader, public bool) (string, error) {
-	var (
-		buf bytes.Buffer
-		cid string
-	)
+	ctx := context.Background()
+	if s.cache == nil {
+		return s.backend.Push(ctx, path, src, public)
+	}
 
-	r := io.TeeReader(src, &buf)
+	if seeker, ok := src.(io.Seeker); ok {
+		offset, err := seeker.Seek(0, io.SeekCurrent)
+		if err == nil {
+			cid, err := s.backend.Push(ctx, path, src, public)
+			if err != nil {
+				return "", err
+			}
 
-	if s.backend == NftStorage {
-		result, err := s.nsCli.PushPath(path, r)
-		if err != nil {
-			return "", err
-		}
-		cid = result
-	} else if s.backend == Textile {
-		result, _, err := s.ttCli.PushPath(s.authCtx, s.ttRoot.Root.Key, path, r)
-		if err != nil {
-			return "", err
-		}
-		cid = result.Cid().String()
-	} else {
-		return "", ErrUnknownStorageBackend
-	}
+			_, err = seeker.Seek(offset, io.SeekStart)
+			if err != nil {
+				return "", err
+			}
 
-	if s.gcpBh != nil {
-		w := s.gcpBh.Object(path).NewWriter(context.Background())
-		if public {
-			w.ACL = []gcpstorage.ACLRule{
-				{
-					Entity: gcpstorage.AllUsers,
-					Role:   gcpstorage.RoleReader,
-				},
+			_, err = s.cache.Push(ctx, path, src, public)
+			if err != nil {
+				return "", err
 			}
+
+			return cid, nil
 		}
-		_, err := io.Copy(w, &buf)
-		if err != nil {
-			return "", err
-		}
-		_ = w.Close()
+	}
+
+	pr, pw := io.Pipe()
+	cacheErr := make(chan error, 1)
+	go func() {
+		_, err := s.cache.Push(ctx, path, pr, public)
+		// a failed cache push stops the backend one instead of blocking it
+		_ = pr.CloseWithError(err)
+		cacheErr <- err
+	}()
+
+	cid, err := s.backend.Push(ctx, path, io.TeeReader(src, pw), public)
+	_ = pw.CloseWithError(err)
+
+	cerr := <-cacheErr
+	if err != nil {
+		return "", err
+	}
+	if cerr != nil {
+		return "", cerr
 	}
 
 	return cid, nil
@@ -174,72 +129,58 @@ func (s *Storage) Upload(input string, to string) (string, error) {
 }
 
 func (s *Storage) MultiUpload(inputs []string, to []string) (string, error) {
-	cids := make([]string, 0)
 	if len(inputs) != len(to) {
 		return "", errors.New("different number of input/output paths")
 	}
 
-	if s.backend == Textile {
-		for idx, input := range inputs {
-			cid, err := s.Upload(input, to[idx])
-			if err != nil {
-				return "", err
-			}
-			cids = append(cids, cid)
+	srcs := make([]io.Reader, 0, len(inputs))
+	for idx, input := range inputs {
+		f, err := os.Open(input)
+		if err != nil {
+			return "", err
 		}
-	}
-
-	if s.backend == NftStorage {
-		srcs := make([]io.Reader, 0)
-		for idx, input := range inputs {
-			f, err := os.Open(input)
-			if err != nil {
-				return "", err
-			}
+		defer f.Close()
 
-			err = s.UploadToCloud(f, to[idx])
-			if err != nil {
-				return "", err
-			}
-
-			f.Seek(0, 0)
-			srcs = append(srcs, f)
+		err = s.UploadToCloud(f, to[idx])
+		if err != nil && err != ErrNoCacheBackend {
+			return "", err
 		}
-		cid, err := s.nsCli.PushPaths(inputs, srcs)
+
+		_, err = f.Seek(0, io.SeekStart)
 		if err != nil {
 			return "", err
 		}
-		cids = append(cids, cid)
+
+		srcs = append(srcs, f)
 	}
 
-	return cids[0], nil
+	return s.backend.MultiPush(context.Background(), to, srcs)
 }
 
 func (s *Storage) MakePublic(path string) error {
-	if s.gcpBh != nil {
-		acl := s.gcpBh.Object(path).ACL()
-		return acl.Set(context.Background(), gcpstorage.AllUsers, gcpstorage.RoleReader)
+	if s.cache == nil {
+		return nil
 	}
 
-	return nil
+	return s.cache.MakePublic(context.Background(), path)
 }
 
+// UploadToCloud stores a public object in the cache backend only.
 func (s *Storage) UploadToCloud(src io.Reader, path string) error {
-	w := s.gcpBh.Object(path).NewWriter(context.Background())
-	w.ACL = []gcpstorage.ACLRule{
-		{
-			Entity: gcpstorage.AllUsers,
-			Role:   gcpstorage.RoleReader,
-		},
-	}
-	_, err := io.Copy(w, src)
-	if err != nil {
-		return err
+	if s.cache == nil {
+		return ErrNoCacheBackend
 	}
 
-	return w.Close()
+	_, err := s.cache.Push(context.Background(), path, src, true)
+	return err
 }
 
-func (s *Storage) ObjReader(objKey string) (*gcpstorage.Reader, error) {
-	return s.gcpBh.Object(objKey).NewReader(context.Background())
+// ObjReader reads an object back from the cache backend, falling back to
+// the primary backend when there is no cache.
+func (s *Storage) ObjReader(objKey string) (io.ReadCloser, error) {
+	if s.cache != nil {
+		return s.cache.Read(context.Background(), objKey)
+	}
+
+	return s.backend.Read(context.Background(), objKey)
 }
@@ -0,0 +1,102 @@
+package storage
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/textileio/go-threads/core/thread"
+	bucketsd "github.com/textileio/textile/v2/api/bucketsd/client"
+	bucketspb "github.com/textileio/textile/v2/api/bucketsd/pb"
+	"github.com/textileio/textile/v2/api/common"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+const textileIpnsGateway = "https://%s.textile.space/%s"
+
+type TextileBackend struct {
+	config  *TextileConfig
+	authCtx context.Context
+	cli     *bucketsd.Client
+	root    *bucketspb.RootResponse
+}
+
+func NewTextileBackend(config *TextileConfig) (*TextileBackend, error) {
+	dialOpts := []grpc.DialOption{
+		grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{})),
+		grpc.WithPerRPCCredentials(common.Credentials{}),
+	}
+	cli, err := bucketsd.NewClient("api.hub.textile.io:443", dialOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	authCtx, err := common.CreateAPISigContext(
+		common.NewAPIKeyContext(context.Background(), config.AuthKey),
+		time.Now().Add(time.Minute),
+		config.AuthSecret,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	tid, err := thread.Decode(config.ThreadID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid textile thread id: %s", err)
+	}
+	authCtx = common.NewThreadIDContext(authCtx, tid)
+
+	root, err := cli.Root(authCtx, config.BucketRootKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bucket root: %s", err)
+	}
+
+	return &TextileBackend{
+		config:  config,
+		authCtx: authCtx,
+		cli:     cli,
+		root:    root,
+	}, nil
+}
+
+func (b *TextileBackend) Name() string {
+	return Textile
+}
+
+func (b *TextileBackend) RootPath() string {
+	return b.config.BucketRootKey
+}
+
+func (b *TextileBackend) Push(ctx context.Context, path string, src io.Reader, public bool) (string, error) {
+	result, _, err := b.cli.PushPath(b.authCtx, b.root.Root.Key, path, src)
+	if err != nil {
+		return "", err
+	}
+
+	return result.Cid().String(), nil
+}
+
+func (b *TextileBackend) MultiPush(ctx context.Context, paths []string, srcs []io.Reader) (string, error) {
+	return multiPushEach(ctx, b, paths, srcs)
+}
+
+func (b *TextileBackend) Read(ctx context.Context, path string) (io.ReadCloser, error) {
+	pr, pw := io.Pipe()
+	go func() {
+		err := b.cli.PullPath(b.authCtx, b.root.Root.Key, path, pw)
+		_ = pw.CloseWithError(err)
+	}()
+
+	return pr, nil
+}
+
+func (b *TextileBackend) MakePublic(ctx context.Context, path string) error {
+	return nil
+}
+
+func (b *TextileBackend) URL(id string, path string) string {
+	return fmt.Sprintf(textileIpnsGateway, b.config.BucketRootKey, path)
+}
@@ -11,8 +11,10 @@ type DRMType string
 type DRMVersion string
 
 const (
-	DRMTypeNacl DRMType    = "nacl"
-	DRMVersion1 DRMVersion = "1.0"
+	DRMTypeNacl     DRMType    = "nacl"
+	DRMTypeClearKey DRMType    = "clearkey"
+	DRMVersion1     DRMVersion = "1.0"
+	DRMVersion2     DRMVersion = "2.0"
 
 	VisibilityPublic  = "public"
 	VisibilityPrivate = "private"
@@ -21,14 +23,23 @@ const (
 var (
 	CurrentSchemaVersion = "1.0"
 
-	CurrentDRMType    DRMType    = DRMTypeNacl
-	CurrentDRMVersion DRMVersion = DRMVersion1
+	// the content key is handed out by the license endpoint to the current
+	// holders, the metadata published for good only references its kid
+	CurrentDRMType    DRMType    = DRMTypeClearKey
+	CurrentDRMVersion DRMVersion = DRMVersion2
 )
 
 type MediaData struct {
 	FullMedia      *string `json:"full_media"`
 	Thumbnail      *string `json:"thumbnail"`
 	EncryptedMedia *string `json:"encrypted_media"`
+	// EncryptedHLSMedia is the HLS master playlist of the encrypted media,
+	// EncryptedMedia is its dash manifest. Both are protected with Clear
+	// Key, the HLS one plays in EME players such as Shaka Player and hls.js.
+	// Safari and iOS play the AES-128 packaging the marketplace serves to
+	// the holders.
+	EncryptedHLSMedia *string `json:"encrypted_hls_media,omitempty"`
+	Preview           *string `json:"preview,omitempty"`
 }
 
 type IPFSData struct {
@@ -45,6 +56,8 @@ type MediaMetadata struct {
 	CloudData  *IPFSData  `json:"cloud_data"`
 	DateAdded  *time.Time `json:"date_added"`
 	AddedBy    string     `json:"added_by"`
+
+	FileEncryption *string `json:"file_encryption,omitempty"`
 }
 
 type Metadata struct {
@@ -62,10 +75,14 @@ type Metadata struct {
 	IpfsThumbnailUrl *string `json:"ipfs_thumbnail_url"`
 	IpfsEncryptedUrl *string `json:"ipfs_encrypted_url"`
 
-	DRMKey     *string `json:"drm_key"`
+	DRMKID     *string `json:"drm_kid"`
 	DRMVersion *string `json:"drm_version"`
 	DRMType    *string `json:"drm_type"`
 
+	// FileEncryption is the format the encrypted file of locked media that
+	// is not streamed is in, see drm.FileEncryptionChunkedV1
+	FileEncryption *string `json:"file_encryption,omitempty"`
+
 	Media []*MediaMetadata `json:"media"`
 }
 
@@ -80,8 +97,8 @@ func ToMetadata(asset *model.Asset) *Metadata {
 		Media:      make([]*MediaMetadata, 0),
 	}
 
-	if asset.DRMKey != "" {
-		resp.DRMKey = pointer.ToString(asset.DRMKey)
+	if asset.DRMKID.Valid {
+		resp.DRMKID = pointer.ToString(asset.DRMKID.String)
 	}
 
 	if asset.Name.Valid {
@@ -98,6 +115,10 @@ func ToMetadata(asset *model.Asset) *Metadata {
 	resp.EncryptedUrl = asset.GetEncryptedUrl()
 	resp.IpfsEncryptedUrl = asset.GetIpfsEncryptedUrl()
 
+	if media := asset.GetFirstPrivateMedia(); media != nil && media.FileEncryption != "" {
+		resp.FileEncryption = pointer.ToString(media.FileEncryption)
+	}
+
 	for _, media := range asset.Media {
 		visibility := VisibilityPrivate
 		if media.Featured {
@@ -135,6 +156,23 @@ func ToMetadata(asset *model.Asset) *Metadata {
 					EncryptedMedia: pointer.ToString(media.GetCachedEncryptedUrl()),
 				},
 			}
+
+			if media.HasEncryptedHLS() {
+				ipfsData.Private.EncryptedHLSMedia = pointer.ToString(media.GetEncryptedHLSUrl())
+				cloudData.Private.EncryptedHLSMedia = pointer.ToString(media.GetCachedEncryptedHLSUrl())
+			}
+
+			// the teaser of locked media is public so buyers can sample it
+			if media.HasPreview() {
+				ipfsData.Public = &MediaData{
+					Thumbnail: pointer.ToString(media.GetThumbnailUrl(asset.Locked)),
+					Preview:   pointer.ToString(media.GetIpfsPreviewUrl()),
+				}
+				cloudData.Public = &MediaData{
+					Thumbnail: pointer.ToString(media.GetCachedThumbnailUrl(asset.Locked)),
+					Preview:   pointer.ToString(media.GetCachedPreviewUrl()),
+				}
+			}
 		}
 
 		mediaItem := &MediaMetadata{
@@ -149,6 +187,9 @@ func ToMetadata(asset *model.Asset) *Metadata {
 		if media.CreatedBy != nil {
 			mediaItem.AddedBy = media.CreatedBy.Address
 		}
+		if !media.Featured && media.FileEncryption != "" {
+			mediaItem.FileEncryption = pointer.ToString(media.FileEncryption)
+		}
 
 		resp.Media = append(resp.Media, mediaItem)
 	}
@@ -0,0 +1,78 @@
+package wyvern
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	SchemaERC721  = "ERC721"
+	SchemaERC1155 = "ERC1155"
+)
+
+var (
+	transferFromSelector            = crypto.Keccak256([]byte("transferFrom(address,address,uint256)"))[:4]
+	safeTransferFromSelector        = crypto.Keccak256([]byte("safeTransferFrom(address,address,uint256)"))[:4]
+	erc1155SafeTransferFromSelector = crypto.Keccak256([]byte("safeTransferFrom(address,address,uint256,uint256,bytes)"))[:4]
+)
+
+// TokenID extracts the token id from the order calldata, which is expected to
+// be an ERC721 transferFrom/safeTransferFrom or an ERC1155 safeTransferFrom
+// call on the target contract.
+func (o *Order) TokenID() (*big.Int, error) {
+	calldata, _, err := o.transferCalldata()
+	if err != nil {
+		return nil, err
+	}
+
+	return new(big.Int).SetBytes(calldata[4+2*common.HashLength : 4+3*common.HashLength]), nil
+}
+
+// TokenAmount extracts the number of tokens the order transfers, which is
+// always one for ERC721 calldata.
+func (o *Order) TokenAmount() (*big.Int, error) {
+	calldata, schema, err := o.transferCalldata()
+	if err != nil {
+		return nil, err
+	}
+
+	if schema != SchemaERC1155 {
+		return big.NewInt(1), nil
+	}
+
+	return new(big.Int).SetBytes(calldata[4+3*common.HashLength : 4+4*common.HashLength]), nil
+}
+
+// Schema returns the token standard of the order calldata.
+func (o *Order) Schema() (string, error) {
+	_, schema, err := o.transferCalldata()
+	return schema, err
+}
+
+func (o *Order) transferCalldata() ([]byte, string, error) {
+	calldata, err := parseBytes(o.Calldata)
+	if err != nil || len(calldata) < 4 {
+		return nil, "", fmt.Errorf("%w: calldata", ErrInvalidOrderField)
+	}
+
+	selector := calldata[:4]
+	switch {
+	case bytes.Equal(selector, transferFromSelector), bytes.Equal(selector, safeTransferFromSelector):
+		if len(calldata) != 4+3*common.HashLength {
+			return nil, "", fmt.Errorf("%w: calldata", ErrInvalidOrderField)
+		}
+		return calldata, SchemaERC721, nil
+	case bytes.Equal(selector, erc1155SafeTransferFromSelector):
+		// from, to, id, amount and the offset of the bytes data
+		if len(calldata) < 4+5*common.HashLength {
+			return nil, "", fmt.Errorf("%w: calldata", ErrInvalidOrderField)
+		}
+		return calldata, SchemaERC1155, nil
+	}
+
+	return nil, "", fmt.Errorf("%w: calldata", ErrInvalidOrderField)
+}
@@ -0,0 +1,9 @@
+package wyvern
+
+import "errors"
+
+var (
+	ErrInvalidOrderField     = errors.New("invalid order field")
+	ErrInvalidOrderHash      = errors.New("invalid order hash")
+	ErrInvalidOrderSignature = errors.New("invalid order signature")
+)
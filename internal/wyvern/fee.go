@@ -0,0 +1,75 @@
+package wyvern
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// FeeDenominator is the basis points denominator used by the exchange for
+// relayer and protocol fees.
+const FeeDenominator = 10000
+
+// ValidateSellerFees checks that a sell order pays at least minBps of its
+// price to the fee recipient. Only the split fee method takes the relayer
+// fee out of the price, so it is required whenever a fee is due.
+func (o *Order) ValidateSellerFees(feeRecipient string, minBps int64) error {
+	if o.Side != Sell || minBps <= 0 {
+		return nil
+	}
+
+	if o.FeeMethod != SplitFee {
+		return fmt.Errorf("%w: feeMethod", ErrInvalidOrderField)
+	}
+
+	if o.FeeRecipient == nil || !strings.EqualFold(o.FeeRecipient.Address, feeRecipient) {
+		return fmt.Errorf("%w: feeRecipient", ErrInvalidOrderField)
+	}
+
+	makerRelayerFee, err := parseUint256(o.MakerRelayerFee)
+	if err != nil || makerRelayerFee.Cmp(big.NewInt(minBps)) < 0 {
+		return fmt.Errorf("%w: makerRelayerFee", ErrInvalidOrderField)
+	}
+
+	return nil
+}
+
+// SellerFees returns the relayer and protocol fees the maker of a sell order
+// pays out of the matched price, the same way
+// ExchangeCore.executeFundsTransfer does for split fee orders.
+func (o *Order) SellerFees(price *big.Int) (*big.Int, *big.Int, error) {
+	relayerFee, protocolFee := new(big.Int), new(big.Int)
+
+	if o.Side != Sell || o.FeeMethod != SplitFee {
+		return relayerFee, protocolFee, nil
+	}
+
+	if o.FeeRecipient == nil || o.FeeRecipient.Address == "" || o.FeeRecipient.Address == NullAddress {
+		return relayerFee, protocolFee, nil
+	}
+
+	makerRelayerFee, err := parseUint256(o.MakerRelayerFee)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: makerRelayerFee", ErrInvalidOrderField)
+	}
+
+	makerProtocolFee, err := parseUint256(o.MakerProtocolFee)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: makerProtocolFee", ErrInvalidOrderField)
+	}
+
+	relayerFee = feeOf(price, makerRelayerFee)
+	protocolFee = feeOf(price, makerProtocolFee)
+
+	return relayerFee, protocolFee, nil
+}
+
+// FeeOf returns bps basis points of the amount.
+func FeeOf(amount *big.Int, bps int64) *big.Int {
+	return feeOf(amount, big.NewInt(bps))
+}
+
+func feeOf(amount *big.Int, bps *big.Int) *big.Int {
+	fee := new(big.Int).Mul(amount, bps)
+	return fee.Div(fee, big.NewInt(FeeDenominator))
+}
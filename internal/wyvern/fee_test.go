@@ -0,0 +1,89 @@
+package wyvern_test
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/videocoin/marketplace/internal/wyvern"
+)
+
+const feeRecipient = "0x00000000000000000000000000000000000000fe"
+
+func feeOrder(side wyvern.OrderSide, feeMethod wyvern.FeeMethod, recipient string, relayerBps, protocolBps string) *wyvern.Order {
+	return &wyvern.Order{
+		Side:             side,
+		FeeMethod:        feeMethod,
+		FeeRecipient:     &wyvern.Account{Address: recipient},
+		MakerRelayerFee:  relayerBps,
+		MakerProtocolFee: protocolBps,
+	}
+}
+
+func TestValidateSellerFees(t *testing.T) {
+	tests := []struct {
+		name  string
+		order *wyvern.Order
+		valid bool
+	}{
+		{"minimum fee", feeOrder(wyvern.Sell, wyvern.SplitFee, feeRecipient, "250", "0"), true},
+		{"fee above the minimum", feeOrder(wyvern.Sell, wyvern.SplitFee, feeRecipient, "1000", "0"), true},
+		{"recipient in another case", feeOrder(wyvern.Sell, wyvern.SplitFee, "0x00000000000000000000000000000000000000FE", "250", "0"), true},
+		{"buy order", feeOrder(wyvern.Buy, wyvern.ProtocolFee, wyvern.NullAddress, "0", "0"), true},
+		{"fee below the minimum", feeOrder(wyvern.Sell, wyvern.SplitFee, feeRecipient, "249", "0"), false},
+		{"invalid fee", feeOrder(wyvern.Sell, wyvern.SplitFee, feeRecipient, "x", "0"), false},
+		{"protocol fee method", feeOrder(wyvern.Sell, wyvern.ProtocolFee, feeRecipient, "250", "0"), false},
+		{"another recipient", feeOrder(wyvern.Sell, wyvern.SplitFee, wyvern.NullAddress, "250", "0"), false},
+	}
+
+	for _, tt := range tests {
+		err := tt.order.ValidateSellerFees(feeRecipient, 250)
+		if tt.valid && err != nil {
+			t.Errorf("%s: %s", tt.name, err)
+		}
+		if !tt.valid && !errors.Is(err, wyvern.ErrInvalidOrderField) {
+			t.Errorf("%s: err = %v, want %v", tt.name, err, wyvern.ErrInvalidOrderField)
+		}
+	}
+
+	// no fee is required without a minimum
+	o := feeOrder(wyvern.Sell, wyvern.ProtocolFee, wyvern.NullAddress, "0", "0")
+	if err := o.ValidateSellerFees(feeRecipient, 0); err != nil {
+		t.Errorf("no minimum: %s", err)
+	}
+}
+
+func TestSellerFees(t *testing.T) {
+	tests := []struct {
+		name        string
+		order       *wyvern.Order
+		price       int64
+		relayerFee  int64
+		protocolFee int64
+	}{
+		{"split fee", feeOrder(wyvern.Sell, wyvern.SplitFee, feeRecipient, "250", "100"), 10000, 250, 100},
+		{"rounds down", feeOrder(wyvern.Sell, wyvern.SplitFee, feeRecipient, "250", "100"), 399, 9, 3},
+		{"buy order", feeOrder(wyvern.Buy, wyvern.SplitFee, feeRecipient, "250", "100"), 10000, 0, 0},
+		{"protocol fee method", feeOrder(wyvern.Sell, wyvern.ProtocolFee, feeRecipient, "250", "100"), 10000, 0, 0},
+		{"no recipient", feeOrder(wyvern.Sell, wyvern.SplitFee, wyvern.NullAddress, "250", "100"), 10000, 0, 0},
+	}
+
+	for _, tt := range tests {
+		relayerFee, protocolFee, err := tt.order.SellerFees(big.NewInt(tt.price))
+		if err != nil {
+			t.Errorf("%s: %s", tt.name, err)
+			continue
+		}
+		if relayerFee.Cmp(big.NewInt(tt.relayerFee)) != 0 {
+			t.Errorf("%s: relayer fee = %s, want %d", tt.name, relayerFee, tt.relayerFee)
+		}
+		if protocolFee.Cmp(big.NewInt(tt.protocolFee)) != 0 {
+			t.Errorf("%s: protocol fee = %s, want %d", tt.name, protocolFee, tt.protocolFee)
+		}
+	}
+
+	_, _, err := feeOrder(wyvern.Sell, wyvern.SplitFee, feeRecipient, "250", "x").SellerFees(big.NewInt(10000))
+	if !errors.Is(err, wyvern.ErrInvalidOrderField) {
+		t.Errorf("invalid protocol fee: err = %v, want %v", err, wyvern.ErrInvalidOrderField)
+	}
+}
@@ -0,0 +1,227 @@
+package wyvern
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/videocoin/marketplace/pkg/ethutil"
+)
+
+// OrderHash computes the canonical order hash the same way as
+// WyvernExchange.hashOrder does: keccak256 over the tightly packed order fields.
+func (o *Order) OrderHash() (common.Hash, error) {
+	packed, err := o.pack()
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	return crypto.Keccak256Hash(packed), nil
+}
+
+// HashToSign returns the personal-sign digest of the order hash, that is
+// the digest the maker signs and WyvernExchange.hashToSign returns.
+func (o *Order) HashToSign() (common.Hash, error) {
+	hash, err := o.OrderHash()
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	return common.BytesToHash(ethutil.SignHash(hash.Bytes())), nil
+}
+
+// RecoverMaker recovers the address which signed the order.
+func (o *Order) RecoverMaker() (common.Address, error) {
+	hashToSign, err := o.HashToSign()
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	r, err := hexutil.Decode(o.R)
+	if err != nil || len(r) != 32 {
+		return common.Address{}, ErrInvalidOrderSignature
+	}
+
+	s, err := hexutil.Decode(o.S)
+	if err != nil || len(s) != 32 {
+		return common.Address{}, ErrInvalidOrderSignature
+	}
+
+	if o.V < 0 || o.V > 255 {
+		return common.Address{}, ErrInvalidOrderSignature
+	}
+
+	sig := make([]byte, 0, crypto.SignatureLength)
+	sig = append(sig, r...)
+	sig = append(sig, s...)
+	sig = append(sig, byte(o.V))
+
+	addr, err := ethutil.RecoverAddress(hashToSign.Bytes(), sig)
+	if err != nil {
+		return common.Address{}, ErrInvalidOrderSignature
+	}
+
+	return addr, nil
+}
+
+// Verify checks that the submitted order hash matches the canonical one and
+// that the order is signed by its maker.
+func (o *Order) Verify() error {
+	if o.Maker == nil || !common.IsHexAddress(o.Maker.Address) {
+		return fmt.Errorf("%w: maker", ErrInvalidOrderField)
+	}
+
+	hash, err := o.OrderHash()
+	if err != nil {
+		return err
+	}
+
+	if o.Hash != "" && !strings.EqualFold(o.Hash, hash.Hex()) {
+		return ErrInvalidOrderHash
+	}
+
+	maker, err := o.RecoverMaker()
+	if err != nil {
+		return err
+	}
+
+	if maker != common.HexToAddress(o.Maker.Address) {
+		return ErrInvalidOrderSignature
+	}
+
+	return nil
+}
+
+func (o *Order) pack() ([]byte, error) {
+	uints := []struct {
+		name  string
+		value string
+	}{
+		{"makerRelayerFee", o.MakerRelayerFee},
+		{"takerRelayerFee", o.TakerRelayerFee},
+		{"makerProtocolFee", o.MakerProtocolFee},
+		{"takerProtocolFee", o.TakerProtocolFee},
+		{"basePrice", o.BasePrice},
+		{"extra", o.Extra},
+		{"listingTime", o.ListingTime},
+		{"expirationTime", o.ExpirationTime},
+		{"salt", o.Salt},
+	}
+
+	values := map[string]*big.Int{}
+	for _, item := range uints {
+		value, err := parseUint256(item.value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidOrderField, item.name)
+		}
+		values[item.name] = value
+	}
+
+	calldata, err := parseBytes(o.Calldata)
+	if err != nil {
+		return nil, fmt.Errorf("%w: calldata", ErrInvalidOrderField)
+	}
+
+	replacementPattern, err := parseBytes(o.ReplacementPattern)
+	if err != nil {
+		return nil, fmt.Errorf("%w: replacementPattern", ErrInvalidOrderField)
+	}
+
+	staticExtradata, err := parseBytes(o.StaticExtradata)
+	if err != nil {
+		return nil, fmt.Errorf("%w: staticExtradata", ErrInvalidOrderField)
+	}
+
+	var packed []byte
+	packed = append(packed, addressBytes(o.Exchange)...)
+	packed = append(packed, addressBytes(addressOf(o.Maker))...)
+	packed = append(packed, addressBytes(addressOf(o.Taker))...)
+	packed = append(packed, math.U256Bytes(values["makerRelayerFee"])...)
+	packed = append(packed, math.U256Bytes(values["takerRelayerFee"])...)
+	packed = append(packed, math.U256Bytes(values["makerProtocolFee"])...)
+	packed = append(packed, math.U256Bytes(values["takerProtocolFee"])...)
+	packed = append(packed, addressBytes(addressOf(o.FeeRecipient))...)
+	packed = append(packed, byte(o.FeeMethod), byte(o.Side), byte(o.SaleKind))
+	packed = append(packed, addressBytes(o.Target)...)
+	packed = append(packed, byte(o.HowToCall))
+	packed = append(packed, calldata...)
+	packed = append(packed, replacementPattern...)
+	packed = append(packed, addressBytes(o.StaticTarget)...)
+	packed = append(packed, staticExtradata...)
+	packed = append(packed, addressBytes(o.PaymentToken)...)
+	packed = append(packed, math.U256Bytes(values["basePrice"])...)
+	packed = append(packed, math.U256Bytes(values["extra"])...)
+	packed = append(packed, math.U256Bytes(values["listingTime"])...)
+	packed = append(packed, math.U256Bytes(values["expirationTime"])...)
+	packed = append(packed, math.U256Bytes(values["salt"])...)
+
+	return packed, nil
+}
+
+// ContractArgs returns the address and uint arguments of the order in the
+// order the WyvernExchange functions taking an order expect them.
+func (o *Order) ContractArgs() ([7]common.Address, [9]*big.Int, error) {
+	addrs := [7]common.Address{
+		common.HexToAddress(o.Exchange),
+		common.HexToAddress(addressOf(o.Maker)),
+		common.HexToAddress(addressOf(o.Taker)),
+		common.HexToAddress(addressOf(o.FeeRecipient)),
+		common.HexToAddress(o.Target),
+		common.HexToAddress(o.StaticTarget),
+		common.HexToAddress(o.PaymentToken),
+	}
+
+	var uints [9]*big.Int
+	for idx, value := range []string{
+		o.MakerRelayerFee, o.TakerRelayerFee, o.MakerProtocolFee, o.TakerProtocolFee,
+		o.BasePrice, o.Extra, o.ListingTime, o.ExpirationTime, o.Salt,
+	} {
+		v, err := parseUint256(value)
+		if err != nil {
+			return addrs, uints, ErrInvalidOrderField
+		}
+		uints[idx] = v
+	}
+
+	return addrs, uints, nil
+}
+
+func addressOf(account *Account) string {
+	if account == nil {
+		return ""
+	}
+	return account.Address
+}
+
+func addressBytes(address string) []byte {
+	return common.HexToAddress(address).Bytes()
+}
+
+func parseUint256(value string) (*big.Int, error) {
+	if value == "" {
+		return new(big.Int), nil
+	}
+
+	v, err := ethutil.ParseBigInt(value)
+	if err != nil {
+		return nil, err
+	}
+
+	if v.Sign() < 0 || v.BitLen() > 256 {
+		return nil, ErrInvalidOrderField
+	}
+
+	return v, nil
+}
+
+func parseBytes(value string) ([]byte, error) {
+	if value == "" || value == "0x" {
+		return []byte{}, nil
+	}
+
+	return hexutil.Decode(value)
+}
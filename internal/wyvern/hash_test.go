@@ -0,0 +1,210 @@
+package wyvern_test
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/videocoin/marketplace/internal/simchain"
+	"github.com/videocoin/marketplace/internal/wyvern"
+)
+
+// knownOrder is hashed by WyvernExchange.hashOrder to knownOrderHash, the
+// exchange tests check the vector against the deployed contract.
+var (
+	knownOrder = wyvern.Order{
+		Exchange:           "0x5206e78b21ce315ce284fb24cf05e0585a93b1d9",
+		Maker:              &wyvern.Account{Address: "0x6f1e5c0c4e1a0e6f1a3a2c7b8f5e4d3c2b1a0f9e"},
+		Taker:              &wyvern.Account{Address: wyvern.NullAddress},
+		FeeRecipient:       &wyvern.Account{Address: "0x5b3256965e7c3cf26e11fcaf296dfc8807c01073"},
+		MakerRelayerFee:    "250",
+		TakerRelayerFee:    "0",
+		MakerProtocolFee:   "0",
+		TakerProtocolFee:   "0",
+		FeeMethod:          wyvern.SplitFee,
+		Side:               wyvern.Sell,
+		SaleKind:           wyvern.FixedPrice,
+		Target:             "0x2fb5d7dda4f1f20f974a0fdd547c38674e8d940c",
+		HowToCall:          wyvern.Call,
+		Calldata:           "0x23b872dd0000000000000000000000006f1e5c0c4e1a0e6f1a3a2c7b8f5e4d3c2b1a0f9e00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000007",
+		ReplacementPattern: "0x000000000000000000000000000000000000000000000000000000000000000000000000ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff0000000000000000000000000000000000000000000000000000000000000000",
+		StaticTarget:       wyvern.NullAddress,
+		StaticExtradata:    "0x",
+		PaymentToken:       "0xc778417e063141139fce010982780140aa0cd5ab",
+		BasePrice:          "1000000000000000000",
+		Extra:              "0",
+		ListingTime:        "1617000000",
+		ExpirationTime:     "0",
+		Salt:               "83006245783548033686093530747847303952463217644495033304999143031082661844460",
+	}
+	knownOrderHash = "0xb01b25107d444ce1d45e48cc59dde203d5d658888e83197b74c4b69056168093"
+)
+
+func newExchange(t *testing.T) (*simchain.Chain, *ecdsa.PrivateKey) {
+	owner, key, err := simchain.NewTransactor()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	backend, err := simchain.NewSimulatedBackend(core.GenesisAlloc{
+		owner.From: {Balance: new(big.Int).Lsh(big.NewInt(1), 100)},
+	}, 30000000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(backend.Close)
+
+	chain, err := simchain.Deploy(backend, owner, &simchain.DeployConfig{Name: "Test", Symbol: "TST"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return chain, key
+}
+
+func contractHashes(t *testing.T, exchange *simchain.Chain, o *wyvern.Order) (common.Hash, common.Hash) {
+	addrs, uints, err := o.ContractArgs()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	calldata := hexutil.MustDecode(o.Calldata)
+	replacementPattern := hexutil.MustDecode(o.ReplacementPattern)
+	staticExtradata := []byte{}
+
+	opts := &bind.CallOpts{}
+	orderHash, err := exchange.Exchange.HashOrder(
+		opts, addrs, uints,
+		uint8(o.FeeMethod), uint8(o.Side), uint8(o.SaleKind), uint8(o.HowToCall),
+		calldata, replacementPattern, staticExtradata,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hashToSign, err := exchange.Exchange.HashToSign(
+		opts, addrs, uints,
+		uint8(o.FeeMethod), uint8(o.Side), uint8(o.SaleKind), uint8(o.HowToCall),
+		calldata, replacementPattern, staticExtradata,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return orderHash, hashToSign
+}
+
+func TestOrderHashMatchesExchange(t *testing.T) {
+	chain, _ := newExchange(t)
+
+	orders := map[string]wyvern.Order{"known": knownOrder}
+
+	buy := knownOrder
+	buy.Side = wyvern.Buy
+	buy.Maker = &wyvern.Account{Address: "0x1111111111111111111111111111111111111111"}
+	buy.FeeRecipient = &wyvern.Account{Address: wyvern.NullAddress}
+	buy.SaleKind = wyvern.DutchAuction
+	buy.Extra = "500000000000000000"
+	buy.ExpirationTime = "1617086400"
+	buy.Calldata = "0x"
+	buy.ReplacementPattern = "0x"
+	orders["dutch buy"] = buy
+
+	for name, o := range orders {
+		o := o
+		t.Run(name, func(t *testing.T) {
+			wantHash, wantHashToSign := contractHashes(t, chain, &o)
+
+			hash, err := o.OrderHash()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if hash != wantHash {
+				t.Errorf("order hash = %s, exchange hashOrder = %s", hash.Hex(), wantHash.Hex())
+			}
+
+			hashToSign, err := o.HashToSign()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if hashToSign != wantHashToSign {
+				t.Errorf("hash to sign = %s, exchange hashToSign = %s", hashToSign.Hex(), wantHashToSign.Hex())
+			}
+		})
+	}
+}
+
+func TestKnownOrderHash(t *testing.T) {
+	hash, err := knownOrder.OrderHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.EqualFold(hash.Hex(), knownOrderHash) {
+		t.Errorf("order hash = %s, want %s", hash.Hex(), knownOrderHash)
+	}
+}
+
+func TestVerifySignedOrder(t *testing.T) {
+	chain, key := newExchange(t)
+
+	o := knownOrder
+	o.Exchange = strings.ToLower(chain.ExchangeAddr.Hex())
+	o.Maker = &wyvern.Account{Address: strings.ToLower(crypto.PubkeyToAddress(key.PublicKey).Hex())}
+
+	hash, err := o.OrderHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	o.Hash = hash.Hex()
+
+	hashToSign, err := o.HashToSign()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sig, err := crypto.Sign(hashToSign.Bytes(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	o.R = hexutil.Encode(sig[:32])
+	o.S = hexutil.Encode(sig[32:64])
+	o.V = int(sig[64]) + 27
+
+	if err := o.Verify(); err != nil {
+		t.Fatalf("verify signed order: %s", err)
+	}
+
+	addrs, uints, err := o.ContractArgs()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var r, s [32]byte
+	copy(r[:], sig[:32])
+	copy(s[:], sig[32:64])
+	valid, err := chain.Exchange.ValidateOrder(
+		&bind.CallOpts{}, addrs, uints,
+		uint8(o.FeeMethod), uint8(o.Side), uint8(o.SaleKind), uint8(o.HowToCall),
+		hexutil.MustDecode(o.Calldata), hexutil.MustDecode(o.ReplacementPattern), []byte{},
+		uint8(o.V), r, s,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !valid {
+		t.Error("exchange rejected the signature Verify accepted")
+	}
+
+	o.Maker = &wyvern.Account{Address: "0x1111111111111111111111111111111111111111"}
+	o.Hash = ""
+	if err := o.Verify(); err == nil {
+		t.Error("verify accepted an order signed by another account")
+	}
+}
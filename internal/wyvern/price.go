@@ -0,0 +1,114 @@
+package wyvern
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"time"
+)
+
+// CurrentPrice calculates the price of the order at the given time the same
+// way SaleKindInterface.calculateFinalPrice does. A Dutch auction moves from
+// the base price by extra over the listing period: down for sell orders and
+// up for buy orders.
+func (o *Order) CurrentPrice(now time.Time) (*big.Int, error) {
+	basePrice, err := parseUint256(o.BasePrice)
+	if err != nil {
+		return nil, fmt.Errorf("%w: basePrice", ErrInvalidOrderField)
+	}
+
+	if o.SaleKind != DutchAuction {
+		return basePrice, nil
+	}
+
+	extra, err := parseUint256(o.Extra)
+	if err != nil {
+		return nil, fmt.Errorf("%w: extra", ErrInvalidOrderField)
+	}
+
+	listingTime, err := strconv.ParseInt(o.ListingTime, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: listingTime", ErrInvalidOrderField)
+	}
+
+	expirationTime, err := strconv.ParseInt(o.ExpirationTime, 10, 64)
+	if err != nil || expirationTime <= listingTime {
+		return nil, fmt.Errorf("%w: expirationTime", ErrInvalidOrderField)
+	}
+
+	elapsed := now.Unix() - listingTime
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	if elapsed > expirationTime-listingTime {
+		elapsed = expirationTime - listingTime
+	}
+
+	diff := new(big.Int).Mul(extra, big.NewInt(elapsed))
+	diff.Div(diff, big.NewInt(expirationTime-listingTime))
+
+	if o.Side == Sell {
+		return basePrice.Sub(basePrice, diff), nil
+	}
+
+	return basePrice.Add(basePrice, diff), nil
+}
+
+// IsEnglishAuction reports whether the order lists the token for an English
+// auction, which is marked by its reserve price.
+func (o *Order) IsEnglishAuction() bool {
+	return o.EnglishAuctionReservePrice != ""
+}
+
+// ValidateEnglishAuction checks the English auction parameters of an order:
+// it has to be a fixed price sell order which expires, bids are taken until
+// the expiration time.
+func (o *Order) ValidateEnglishAuction() error {
+	if !o.IsEnglishAuction() {
+		return nil
+	}
+
+	if o.Side != Sell || o.SaleKind != FixedPrice {
+		return fmt.Errorf("%w: englishAuctionReservePrice", ErrInvalidOrderField)
+	}
+
+	_, err := parseUint256(o.EnglishAuctionReservePrice)
+	if err != nil {
+		return fmt.Errorf("%w: englishAuctionReservePrice", ErrInvalidOrderField)
+	}
+
+	listingTime, err := strconv.ParseInt(o.ListingTime, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: listingTime", ErrInvalidOrderField)
+	}
+
+	expirationTime, err := strconv.ParseInt(o.ExpirationTime, 10, 64)
+	if err != nil || expirationTime <= 0 || expirationTime <= listingTime {
+		return fmt.Errorf("%w: expirationTime", ErrInvalidOrderField)
+	}
+
+	return nil
+}
+
+// ValidateDutchAuction checks the Dutch auction parameters of a sell order,
+// the price must not go below zero within the listing period.
+func (o *Order) ValidateDutchAuction() error {
+	if o.SaleKind != DutchAuction {
+		return nil
+	}
+
+	_, err := o.CurrentPrice(time.Now())
+	if err != nil {
+		return err
+	}
+
+	if o.Side == Sell {
+		basePrice, _ := parseUint256(o.BasePrice)
+		extra, _ := parseUint256(o.Extra)
+		if extra.Cmp(basePrice) > 0 {
+			return fmt.Errorf("%w: extra", ErrInvalidOrderField)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,155 @@
+package wyvern_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/videocoin/marketplace/internal/wyvern"
+)
+
+func dutchOrder(side wyvern.OrderSide, basePrice, extra string, listingTime, expirationTime int64) *wyvern.Order {
+	return &wyvern.Order{
+		Side:           side,
+		SaleKind:       wyvern.DutchAuction,
+		BasePrice:      basePrice,
+		Extra:          extra,
+		ListingTime:    strconv.FormatInt(listingTime, 10),
+		ExpirationTime: strconv.FormatInt(expirationTime, 10),
+	}
+}
+
+func TestCurrentPrice(t *testing.T) {
+	fixed := dutchOrder(wyvern.Sell, "1000", "400", 1000, 2000)
+	fixed.SaleKind = wyvern.FixedPrice
+
+	tests := []struct {
+		name  string
+		order *wyvern.Order
+		now   int64
+		want  int64
+	}{
+		{"fixed price", fixed, 1500, 1000},
+		{"sell before listing", dutchOrder(wyvern.Sell, "1000", "400", 1000, 2000), 500, 1000},
+		{"sell at listing", dutchOrder(wyvern.Sell, "1000", "400", 1000, 2000), 1000, 1000},
+		{"sell a quarter in", dutchOrder(wyvern.Sell, "1000", "400", 1000, 2000), 1250, 900},
+		{"sell mid-period", dutchOrder(wyvern.Sell, "1000", "400", 1000, 2000), 1500, 800},
+		{"sell at expiration", dutchOrder(wyvern.Sell, "1000", "400", 1000, 2000), 2000, 600},
+		{"sell after expiration", dutchOrder(wyvern.Sell, "1000", "400", 1000, 2000), 5000, 600},
+		{"sell rounds the decrease down", dutchOrder(wyvern.Sell, "1000", "3", 1000, 2000), 1500, 999},
+		{"sell down to zero", dutchOrder(wyvern.Sell, "1000", "1000", 1000, 2000), 2000, 0},
+		{"buy before listing", dutchOrder(wyvern.Buy, "1000", "400", 1000, 2000), 500, 1000},
+		{"buy mid-period", dutchOrder(wyvern.Buy, "1000", "400", 1000, 2000), 1500, 1200},
+		{"buy after expiration", dutchOrder(wyvern.Buy, "1000", "400", 1000, 2000), 5000, 1400},
+		{"buy extra above base price", dutchOrder(wyvern.Buy, "1000", "3000", 1000, 2000), 1500, 2500},
+	}
+
+	for _, tt := range tests {
+		price, err := tt.order.CurrentPrice(time.Unix(tt.now, 0))
+		if err != nil {
+			t.Errorf("%s: %s", tt.name, err)
+			continue
+		}
+		if price.Cmp(big.NewInt(tt.want)) != 0 {
+			t.Errorf("%s: price = %s, want %d", tt.name, price, tt.want)
+		}
+	}
+}
+
+func TestCurrentPriceRejectsInvalidPeriod(t *testing.T) {
+	orders := map[string]*wyvern.Order{
+		"no expiration":             dutchOrder(wyvern.Sell, "1000", "400", 1000, 0),
+		"expiration before listing": dutchOrder(wyvern.Sell, "1000", "400", 2000, 1000),
+		"empty period":              dutchOrder(wyvern.Sell, "1000", "400", 1000, 1000),
+		"invalid base price":        dutchOrder(wyvern.Sell, "-1", "400", 1000, 2000),
+		"invalid extra":             dutchOrder(wyvern.Sell, "1000", "x", 1000, 2000),
+	}
+
+	for name, o := range orders {
+		_, err := o.CurrentPrice(time.Unix(1500, 0))
+		if !errors.Is(err, wyvern.ErrInvalidOrderField) {
+			t.Errorf("%s: err = %v, want %v", name, err, wyvern.ErrInvalidOrderField)
+		}
+	}
+}
+
+func TestValidateDutchAuction(t *testing.T) {
+	now := time.Now().Unix()
+
+	fixed := dutchOrder(wyvern.Sell, "1000", "4000", 0, 0)
+	fixed.SaleKind = wyvern.FixedPrice
+
+	tests := []struct {
+		name  string
+		order *wyvern.Order
+		valid bool
+	}{
+		{"fixed price", fixed, true},
+		{"sell", dutchOrder(wyvern.Sell, "1000", "400", now, now+3600), true},
+		{"sell down to zero", dutchOrder(wyvern.Sell, "1000", "1000", now, now+3600), true},
+		{"sell extra above base price", dutchOrder(wyvern.Sell, "1000", "1001", now, now+3600), false},
+		{"buy extra above base price", dutchOrder(wyvern.Buy, "1000", "4000", now, now+3600), true},
+		{"no expiration", dutchOrder(wyvern.Sell, "1000", "400", now, 0), false},
+	}
+
+	for _, tt := range tests {
+		err := tt.order.ValidateDutchAuction()
+		if tt.valid && err != nil {
+			t.Errorf("%s: %s", tt.name, err)
+		}
+		if !tt.valid && !errors.Is(err, wyvern.ErrInvalidOrderField) {
+			t.Errorf("%s: err = %v, want %v", tt.name, err, wyvern.ErrInvalidOrderField)
+		}
+	}
+}
+
+// TestCurrentPriceMatchesExchange checks the price against
+// SaleKindInterface.calculateFinalPrice within the listing period, the
+// exchange reverts before it and doesn't match orders after it.
+func TestCurrentPriceMatchesExchange(t *testing.T) {
+	chain, _ := newExchange(t)
+	ctx := context.Background()
+
+	head, err := chain.Backend.HeaderByNumber(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for head.Time < 1000 {
+		chain.Backend.Commit()
+		head, err = chain.Backend.HeaderByNumber(ctx, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	now := int64(head.Time)
+
+	const period = 900
+	for _, side := range []wyvern.OrderSide{wyvern.Sell, wyvern.Buy} {
+		for _, elapsed := range []int64{0, 1, period / 3, period / 2, period} {
+			o := dutchOrder(side, "1000000000000000000", "333333333333333333", now-elapsed, now-elapsed+period)
+
+			price, err := o.CurrentPrice(time.Unix(now, 0))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			want, err := chain.Exchange.CalculateFinalPrice(
+				&bind.CallOpts{},
+				uint8(o.Side), uint8(o.SaleKind),
+				big.NewInt(1e18), big.NewInt(333333333333333333),
+				big.NewInt(now-elapsed), big.NewInt(now-elapsed+period),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if price.Cmp(want) != 0 {
+				t.Errorf("side %d, %ds in: price = %s, exchange calculateFinalPrice = %s", side, elapsed, price, want)
+			}
+		}
+	}
+}
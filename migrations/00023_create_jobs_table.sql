@@ -0,0 +1,31 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+CREATE TABLE IF NOT EXISTS jobs (
+  id             UUID PRIMARY KEY,
+  created_at     TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+  updated_at     TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+  created_by_id  INT NOT NULL,
+  type           VARCHAR(100) NOT NULL,
+  status         VARCHAR(50) DEFAULT 'PENDING',
+  payload        JSONB NOT NULL DEFAULT '{}',
+  checkpoints    JSONB NOT NULL DEFAULT '[]',
+  attempts       INT NOT NULL DEFAULT 0,
+  max_attempts   INT NOT NULL DEFAULT 5,
+  run_at         TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+  locked_by      VARCHAR(255) DEFAULT NULL,
+  locked_until   TIMESTAMP WITH TIME ZONE DEFAULT NULL,
+  last_error     TEXT DEFAULT NULL,
+
+  FOREIGN KEY (created_by_id) REFERENCES accounts(id) ON DELETE CASCADE
+);
+CREATE INDEX jobs_idx_status_run_at ON jobs (status, run_at);
+
+ALTER TABLE media ADD COLUMN job_id UUID DEFAULT NULL;
+ALTER TABLE assets ADD COLUMN job_id UUID DEFAULT NULL;
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+ALTER TABLE assets DROP COLUMN job_id;
+ALTER TABLE media DROP COLUMN job_id;
+DROP INDEX IF EXISTS jobs_idx_status_run_at;
+DROP TABLE jobs;
@@ -0,0 +1,29 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+CREATE TABLE IF NOT EXISTS chain_blocks
+(
+    chain_id    VARCHAR(255) NOT NULL,
+    height      BIGINT       NOT NULL,
+    hash        VARCHAR(66)  NOT NULL,
+    parent_hash VARCHAR(66)  NOT NULL,
+    PRIMARY KEY (chain_id, height)
+);
+
+CREATE TABLE IF NOT EXISTS chain_block_changes
+(
+    id           SERIAL PRIMARY KEY,
+    created_at   TIMESTAMP    NOT NULL DEFAULT now(),
+    chain_id     VARCHAR(255) NOT NULL,
+    height       BIGINT       NOT NULL,
+    block_hash   VARCHAR(66)  NOT NULL,
+    order_id     INTEGER      NOT NULL REFERENCES orders (id) ON DELETE CASCADE,
+    order_status VARCHAR(255) NOT NULL,
+    asset        JSONB
+);
+
+CREATE INDEX chain_block_changes_idx_chain_id_height ON chain_block_changes (chain_id, height);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE chain_block_changes;
+DROP TABLE chain_blocks;
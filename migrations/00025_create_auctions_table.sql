@@ -0,0 +1,42 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+CREATE TABLE IF NOT EXISTS auctions
+(
+    id                    SERIAL PRIMARY KEY,
+    created_at            TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    asset_id              INTEGER      NOT NULL REFERENCES assets (id) ON DELETE CASCADE,
+    seller_id             INTEGER      NOT NULL REFERENCES accounts (id) ON DELETE CASCADE,
+    sell_order_id         INTEGER      NOT NULL REFERENCES orders (id) ON DELETE CASCADE,
+    status                VARCHAR(50)  NOT NULL DEFAULT 'OPEN',
+    payment_token_address VARCHAR(100) NOT NULL,
+    start_price           NUMERIC(78)  NOT NULL DEFAULT 0,
+    reserve_price         NUMERIC(78)  NOT NULL DEFAULT 0,
+    min_bid_increment_bps INTEGER      NOT NULL DEFAULT 500,
+    extension_window      INTEGER      NOT NULL DEFAULT 600,
+    start_at              TIMESTAMP WITH TIME ZONE NOT NULL,
+    end_at                TIMESTAMP WITH TIME ZONE NOT NULL,
+    winning_bid_id        INTEGER DEFAULT NULL
+);
+
+CREATE INDEX auctions_idx_asset_id ON auctions (asset_id);
+CREATE INDEX auctions_idx_status_end_at ON auctions (status, end_at);
+
+CREATE TABLE IF NOT EXISTS auction_bids
+(
+    id         SERIAL PRIMARY KEY,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    auction_id INTEGER     NOT NULL REFERENCES auctions (id) ON DELETE CASCADE,
+    order_id   INTEGER     NOT NULL REFERENCES orders (id) ON DELETE CASCADE,
+    bidder_id  INTEGER     NOT NULL REFERENCES accounts (id) ON DELETE CASCADE,
+    amount     NUMERIC(78) NOT NULL
+);
+
+CREATE INDEX auction_bids_idx_auction_id ON auction_bids (auction_id);
+
+ALTER TABLE auctions ADD CONSTRAINT auctions_fk_winning_bid_id FOREIGN KEY (winning_bid_id) REFERENCES auction_bids (id) ON DELETE SET NULL;
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+ALTER TABLE auctions DROP CONSTRAINT auctions_fk_winning_bid_id;
+DROP TABLE auction_bids;
+DROP TABLE auctions;
@@ -0,0 +1,13 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+ALTER TABLE assets ADD COLUMN sale_kind INT NOT NULL DEFAULT 0;
+ALTER TABLE assets ADD COLUMN price_extra NUMERIC NOT NULL DEFAULT 0;
+ALTER TABLE assets ADD COLUMN listing_time TIMESTAMP WITH TIME ZONE DEFAULT NULL;
+ALTER TABLE assets ADD COLUMN expiration_time TIMESTAMP WITH TIME ZONE DEFAULT NULL;
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+ALTER TABLE assets DROP COLUMN expiration_time;
+ALTER TABLE assets DROP COLUMN listing_time;
+ALTER TABLE assets DROP COLUMN price_extra;
+ALTER TABLE assets DROP COLUMN sale_kind;
@@ -0,0 +1,32 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+ALTER TABLE assets ADD COLUMN schema VARCHAR(50) NOT NULL DEFAULT 'ERC721';
+ALTER TABLE assets ADD COLUMN supply BIGINT NOT NULL DEFAULT 1;
+
+ALTER TABLE orders ADD COLUMN quantity BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE orders ADD COLUMN filled_quantity BIGINT NOT NULL DEFAULT 0;
+
+ALTER TABLE chain_block_changes ADD COLUMN order_filled_quantity BIGINT NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS asset_holders
+(
+    asset_id   INTEGER NOT NULL REFERENCES assets (id) ON DELETE CASCADE,
+    account_id INTEGER NOT NULL REFERENCES accounts (id) ON DELETE CASCADE,
+    balance    BIGINT  NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (asset_id, account_id)
+);
+
+CREATE INDEX asset_holders_idx_account_id ON asset_holders (account_id);
+
+INSERT INTO asset_holders (asset_id, account_id, balance)
+SELECT id, owner_id, supply FROM assets;
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE asset_holders;
+ALTER TABLE chain_block_changes DROP COLUMN order_filled_quantity;
+ALTER TABLE orders DROP COLUMN filled_quantity;
+ALTER TABLE orders DROP COLUMN quantity;
+ALTER TABLE assets DROP COLUMN supply;
+ALTER TABLE assets DROP COLUMN schema;
@@ -0,0 +1,31 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+CREATE TABLE IF NOT EXISTS ledger_entries
+(
+    id                    SERIAL PRIMARY KEY,
+    created_at            TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    asset_id              INTEGER      NOT NULL REFERENCES assets (id) ON DELETE CASCADE,
+    order_id              INTEGER      NOT NULL REFERENCES orders (id) ON DELETE CASCADE,
+    seller_id             INTEGER      DEFAULT NULL REFERENCES accounts (id) ON DELETE SET NULL,
+    buyer_id              INTEGER      DEFAULT NULL REFERENCES accounts (id) ON DELETE SET NULL,
+    creator_id            INTEGER      DEFAULT NULL REFERENCES accounts (id) ON DELETE SET NULL,
+    payment_token_address VARCHAR(100) NOT NULL,
+    quantity              BIGINT       NOT NULL DEFAULT 1,
+    price                 NUMERIC(78)  NOT NULL DEFAULT 0,
+    royalty_bps           INTEGER      NOT NULL DEFAULT 0,
+    royalty               NUMERIC(78)  NOT NULL DEFAULT 0,
+    platform_fee          NUMERIC(78)  NOT NULL DEFAULT 0,
+    protocol_fee          NUMERIC(78)  NOT NULL DEFAULT 0,
+    seller_proceeds       NUMERIC(78)  NOT NULL DEFAULT 0,
+    block_hash            VARCHAR(100) NOT NULL,
+    tx_hash               VARCHAR(100) NOT NULL,
+    UNIQUE (tx_hash, order_id)
+);
+
+CREATE INDEX ledger_entries_idx_asset_id ON ledger_entries (asset_id);
+CREATE INDEX ledger_entries_idx_seller_id ON ledger_entries (seller_id);
+CREATE INDEX ledger_entries_idx_creator_id ON ledger_entries (creator_id);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE ledger_entries;
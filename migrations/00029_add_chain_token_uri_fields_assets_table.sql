@@ -0,0 +1,9 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+ALTER TABLE assets ADD COLUMN chain_token_uri VARCHAR(1024) DEFAULT NULL;
+ALTER TABLE assets ADD COLUMN token_uri_tx_id VARCHAR(255) DEFAULT NULL;
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+ALTER TABLE assets DROP COLUMN token_uri_tx_id;
+ALTER TABLE assets DROP COLUMN chain_token_uri;
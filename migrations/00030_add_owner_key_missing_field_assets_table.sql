@@ -0,0 +1,10 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+ALTER TABLE assets ADD COLUMN owner_key_missing BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE chain_block_changes ALTER COLUMN order_id DROP NOT NULL;
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DELETE FROM chain_block_changes WHERE order_id IS NULL;
+ALTER TABLE chain_block_changes ALTER COLUMN order_id SET NOT NULL;
+ALTER TABLE assets DROP COLUMN owner_key_missing;
@@ -0,0 +1,29 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+CREATE TABLE IF NOT EXISTS chain_events
+(
+    id           SERIAL PRIMARY KEY,
+    created_at   TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at   TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    chain_id     VARCHAR(255) NOT NULL,
+    height       BIGINT       NOT NULL,
+    block_hash   VARCHAR(66)  NOT NULL,
+    tx_hash      VARCHAR(66)  NOT NULL,
+    log_index    INT          NOT NULL,
+    type         VARCHAR(100) NOT NULL,
+    payload      JSONB        NOT NULL DEFAULT '{}',
+    status       VARCHAR(50)  NOT NULL DEFAULT 'PENDING',
+    attempts     INT          NOT NULL DEFAULT 0,
+    last_error   TEXT                  DEFAULT NULL,
+    processed_at TIMESTAMP WITH TIME ZONE DEFAULT NULL,
+    UNIQUE (chain_id, tx_hash, log_index)
+);
+
+CREATE INDEX chain_events_idx_chain_id_status ON chain_events (chain_id, status);
+CREATE INDEX chain_events_idx_chain_id_height ON chain_events (chain_id, height, log_index);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP INDEX IF EXISTS chain_events_idx_chain_id_height;
+DROP INDEX IF EXISTS chain_events_idx_chain_id_status;
+DROP TABLE chain_events;
@@ -0,0 +1,14 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+ALTER TABLE assets ADD COLUMN network VARCHAR(64) NOT NULL DEFAULT 'default';
+ALTER TABLE orders ADD COLUMN network VARCHAR(64) NOT NULL DEFAULT 'default';
+
+CREATE INDEX assets_idx_network ON assets (network);
+CREATE INDEX orders_idx_network ON orders (network);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP INDEX IF EXISTS orders_idx_network;
+DROP INDEX IF EXISTS assets_idx_network;
+ALTER TABLE orders DROP COLUMN network;
+ALTER TABLE assets DROP COLUMN network;
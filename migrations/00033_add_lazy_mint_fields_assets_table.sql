@@ -0,0 +1,9 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+ALTER TABLE assets ADD COLUMN lazy_mint BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE assets ADD COLUMN mint_voucher JSONB DEFAULT NULL;
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+ALTER TABLE assets DROP COLUMN mint_voucher;
+ALTER TABLE assets DROP COLUMN lazy_mint;
@@ -0,0 +1,36 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+CREATE TABLE IF NOT EXISTS chain_txs
+(
+    id           SERIAL PRIMARY KEY,
+    created_at   TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at   TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    network      VARCHAR(64)   NOT NULL,
+    from_address VARCHAR(42)   NOT NULL,
+    to_address   VARCHAR(42)   NOT NULL,
+    nonce        BIGINT        NOT NULL,
+    kind         VARCHAR(50)   NOT NULL,
+    asset_id     BIGINT        NOT NULL,
+    token_uri    VARCHAR(1024)          DEFAULT NULL,
+    data         TEXT          NOT NULL,
+    gas_limit    BIGINT        NOT NULL,
+    gas_price    NUMERIC(78)   NOT NULL,
+    tx_hash      VARCHAR(66)   NOT NULL,
+    hashes       JSONB         NOT NULL DEFAULT '[]',
+    bumps        INT           NOT NULL DEFAULT 0,
+    status       VARCHAR(50)   NOT NULL DEFAULT 'PENDING',
+    last_error   TEXT                   DEFAULT NULL,
+    sent_at      TIMESTAMP WITH TIME ZONE DEFAULT NULL,
+    block_number BIGINT                 DEFAULT NULL,
+    confirmed_at TIMESTAMP WITH TIME ZONE DEFAULT NULL,
+    UNIQUE (network, from_address, nonce)
+);
+
+CREATE INDEX chain_txs_idx_network_status ON chain_txs (network, from_address, status);
+CREATE INDEX chain_txs_idx_asset_id_kind ON chain_txs (asset_id, kind);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP INDEX IF EXISTS chain_txs_idx_asset_id_kind;
+DROP INDEX IF EXISTS chain_txs_idx_network_status;
+DROP TABLE chain_txs;
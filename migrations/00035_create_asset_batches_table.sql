@@ -0,0 +1,24 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+CREATE TABLE IF NOT EXISTS asset_batches
+(
+    id            SERIAL PRIMARY KEY,
+    created_at    TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at    TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    created_by_id INT         NOT NULL REFERENCES accounts (id),
+    network       VARCHAR(64) NOT NULL,
+    size          INT         NOT NULL,
+    mint_job_id   VARCHAR(36)          DEFAULT NULL
+);
+
+ALTER TABLE assets ADD COLUMN batch_id INT DEFAULT NULL REFERENCES asset_batches (id);
+ALTER TABLE chain_txs ADD COLUMN asset_ids JSONB NOT NULL DEFAULT '[]';
+
+CREATE INDEX assets_idx_batch_id ON assets (batch_id);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP INDEX IF EXISTS assets_idx_batch_id;
+ALTER TABLE chain_txs DROP COLUMN asset_ids;
+ALTER TABLE assets DROP COLUMN batch_id;
+DROP TABLE asset_batches;
@@ -0,0 +1,12 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+ALTER TABLE assets ADD COLUMN drm_kid VARCHAR(32) DEFAULT NULL;
+
+UPDATE assets SET drm_kid = drm_meta::jsonb->>'kid' WHERE drm_meta LIKE '{%';
+
+CREATE INDEX assets_idx_drm_kid ON assets (drm_kid);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP INDEX IF EXISTS assets_idx_drm_kid;
+ALTER TABLE assets DROP COLUMN drm_kid;
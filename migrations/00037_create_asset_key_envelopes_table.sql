@@ -0,0 +1,21 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+CREATE TABLE IF NOT EXISTS asset_key_envelopes
+(
+    asset_id      INTEGER     NOT NULL REFERENCES assets (id) ON DELETE CASCADE,
+    account_id    INTEGER     NOT NULL REFERENCES accounts (id) ON DELETE CASCADE,
+    role          VARCHAR(50) NOT NULL,
+    envelope      TEXT        NOT NULL,
+    granted_by_id INTEGER              DEFAULT NULL REFERENCES accounts (id) ON DELETE SET NULL,
+    created_at    TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (asset_id, account_id, role)
+);
+
+CREATE INDEX asset_key_envelopes_idx_account_id ON asset_key_envelopes (account_id);
+
+INSERT INTO asset_key_envelopes (asset_id, account_id, role, envelope)
+SELECT id, owner_id, 'OWNER', drm_key FROM assets WHERE drm_key IS NOT NULL AND drm_key != '';
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE asset_key_envelopes;
@@ -0,0 +1,9 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+ALTER TABLE media ADD COLUMN preview_key VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE media ADD COLUMN preview_cid VARCHAR(255) DEFAULT NULL;
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+ALTER TABLE media DROP COLUMN preview_cid;
+ALTER TABLE media DROP COLUMN preview_key;
@@ -0,0 +1,7 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+ALTER TABLE media ADD COLUMN renditions JSONB NOT NULL DEFAULT '[]';
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+ALTER TABLE media DROP COLUMN renditions;
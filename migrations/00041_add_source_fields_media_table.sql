@@ -0,0 +1,11 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+ALTER TABLE media ADD COLUMN source_content_type VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE media ADD COLUMN source_key VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE media ADD COLUMN source_cid VARCHAR(255) DEFAULT NULL;
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+ALTER TABLE media DROP COLUMN source_cid;
+ALTER TABLE media DROP COLUMN source_key;
+ALTER TABLE media DROP COLUMN source_content_type;
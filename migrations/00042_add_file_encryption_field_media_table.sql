@@ -0,0 +1,9 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+ALTER TABLE media ADD COLUMN file_encryption VARCHAR(255) NOT NULL DEFAULT '';
+UPDATE media SET file_encryption = 'aes-128-cbc-base64'
+    WHERE encrypted_cid IS NOT NULL AND encrypted_key NOT LIKE '%.mpd';
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+ALTER TABLE media DROP COLUMN file_encryption;
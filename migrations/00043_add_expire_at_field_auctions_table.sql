@@ -0,0 +1,7 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+ALTER TABLE auctions ADD COLUMN expire_at TIMESTAMP WITH TIME ZONE DEFAULT NULL;
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+ALTER TABLE auctions DROP COLUMN expire_at;
@@ -0,0 +1,11 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+ALTER TABLE ledger_entries ADD COLUMN log_index BIGINT DEFAULT NULL;
+ALTER TABLE ledger_entries DROP CONSTRAINT ledger_entries_tx_hash_order_id_key;
+CREATE UNIQUE INDEX ledger_entries_idx_tx_hash_log_index ON ledger_entries (tx_hash, log_index);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP INDEX ledger_entries_idx_tx_hash_log_index;
+ALTER TABLE ledger_entries ADD CONSTRAINT ledger_entries_tx_hash_order_id_key UNIQUE (tx_hash, order_id);
+ALTER TABLE ledger_entries DROP COLUMN log_index;
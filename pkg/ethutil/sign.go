@@ -1,12 +1,49 @@
 package ethutil
 
 import (
+	"crypto/ecdsa"
+	"errors"
 	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 )
 
+var (
+	ErrInvalidSignature = errors.New("invalid signature")
+)
+
 func SignHash(data []byte) []byte {
 	msg := fmt.Sprintf("\x19Ethereum Signed Message:\n%d%s", len(data), data)
 	return crypto.Keccak256([]byte(msg))
 }
 
+// RecoverPubKey returns the public key that produced the 65 bytes
+// [R || S || V] signature of hash. V is accepted both as 0/1 and as 27/28.
+func RecoverPubKey(hash []byte, sig []byte) (*ecdsa.PublicKey, error) {
+	if len(sig) != crypto.SignatureLength {
+		return nil, ErrInvalidSignature
+	}
+
+	normalized := make([]byte, crypto.SignatureLength)
+	copy(normalized, sig)
+	if normalized[crypto.RecoveryIDOffset] >= 27 {
+		normalized[crypto.RecoveryIDOffset] -= 27
+	}
+	if normalized[crypto.RecoveryIDOffset] > 1 {
+		return nil, ErrInvalidSignature
+	}
+
+	return crypto.SigToPub(hash, normalized)
+}
+
+// RecoverAddress returns the address that produced the signature of hash,
+// see RecoverPubKey.
+func RecoverAddress(hash []byte, sig []byte) (common.Address, error) {
+	pubKey, err := RecoverPubKey(hash, sig)
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	return crypto.PubkeyToAddress(*pubKey), nil
+}
@@ -21,3 +21,12 @@ func WeiToEther(wei *big.Int) *big.Float {
 	fWei.SetMode(big.ToNearestEven)
 	return f.Quo(fWei.SetInt(wei), big.NewFloat(params.Ether))
 }
+
+func EtherToWei(eth float64) *big.Int {
+	f := new(big.Float)
+	f.SetPrec(236)
+	f.SetMode(big.ToNearestEven)
+	f.Mul(big.NewFloat(eth), big.NewFloat(params.Ether))
+	wei, _ := f.Int(nil)
+	return wei
+}